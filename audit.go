@@ -0,0 +1,35 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package autoscaler
+
+import "context"
+
+// An AuditStore persists the audit log of the mutating api
+// requests.
+type AuditStore interface {
+	// List returns the audit events created at or after the
+	// timestamp, oldest first.
+	List(ctx context.Context, since int64) ([]*AuditEvent, error)
+
+	// Create records an audit event.
+	Create(context.Context, *AuditEvent) error
+}
+
+// AuditEvent records who performed a mutating api request,
+// when, and the result of the request. The target is the name
+// of the server or pool of the request, and the query is the
+// url query string, or the text of the grpc request message.
+type AuditEvent struct {
+	ID      int64  `db:"audit_id"      json:"id"`
+	User    string `db:"audit_user"    json:"user"`
+	Scope   string `db:"audit_scope"   json:"scope"`
+	Method  string `db:"audit_method"  json:"method"`
+	Path    string `db:"audit_path"    json:"path"`
+	Query   string `db:"audit_query"   json:"query,omitempty"`
+	Target  string `db:"audit_target"  json:"target,omitempty"`
+	Status  int    `db:"audit_status"  json:"status"`
+	Address string `db:"audit_address" json:"address"`
+	Created int64  `db:"audit_created" json:"created"`
+}
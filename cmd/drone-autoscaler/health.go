@@ -0,0 +1,66 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/autoscaler/server"
+
+	"github.com/drone/drone-go/drone"
+)
+
+// providerCheckTTL defines the duration the result of the
+// provider health check is cached, since validation calls the
+// provider api.
+const providerCheckTTL = 5 * time.Minute
+
+// droneCheckTTL defines the duration the result of the Drone
+// API health check is cached, so that frequent readiness probes
+// from each replica do not add load to the Drone server.
+const droneCheckTTL = time.Minute
+
+// helper function returns the health checks of the database,
+// the Drone API, the provider of each pool, and the planner.
+func setupHealth(conf config.Config, stores *stores, client drone.Client, pools []config.Config, bases []autoscaler.Provider, engine autoscaler.Engine) []*server.HealthCheck {
+	checks := append(setupReady(stores, client),
+		server.PlannerCheck(engine, 3*conf.Interval),
+	)
+	for i, provider := range bases {
+		if _, ok := provider.(autoscaler.Validator); !ok {
+			continue
+		}
+		name := "provider"
+		if pools[i].Pool.Name != "" {
+			name = "provider:" + pools[i].Pool.Name
+		}
+		provider := provider
+		checks = append(checks, &server.HealthCheck{
+			Name: name,
+			TTL:  providerCheckTTL,
+			Check: func(ctx context.Context) error {
+				return validate(ctx, provider)
+			},
+		})
+	}
+	return checks
+}
+
+// helper function returns the readiness checks of the database
+// and the Drone API, which are required to serve requests.
+func setupReady(stores *stores, client drone.Client) []*server.HealthCheck {
+	return []*server.HealthCheck{
+		{Name: "database", Check: stores.ping},
+		{Name: "drone", TTL: droneCheckTTL, Check: func(context.Context) error {
+			// the authenticated user is requested, which
+			// verifies the token is valid.
+			_, err := client.Self()
+			return err
+		}},
+	}
+}
@@ -6,7 +6,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"flag"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -19,8 +22,11 @@ import (
 	"github.com/drone/autoscaler/drivers/hetznercloud"
 	"github.com/drone/autoscaler/drivers/openstack"
 	"github.com/drone/autoscaler/drivers/packet"
+	"github.com/drone/autoscaler/drivers/plugin"
 	"github.com/drone/autoscaler/engine"
 	"github.com/drone/autoscaler/metrics"
+	"github.com/drone/autoscaler/ratelimit"
+	"github.com/drone/autoscaler/rpc"
 	"github.com/drone/autoscaler/server"
 	"github.com/drone/autoscaler/slack"
 	"github.com/drone/autoscaler/store"
@@ -28,12 +34,15 @@ import (
 	"github.com/drone/signal"
 
 	"github.com/go-chi/chi"
+	"github.com/gophercloud/gophercloud"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/hlog"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/oauth2"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/joho/godotenv/autoload"
@@ -47,64 +56,302 @@ var (
 )
 
 func main() {
+	validateOnly := flag.Bool("validate", false, "validate the provider configuration and exit")
+	rotateKeys := flag.Bool("rotate-keys", false, "re-encrypt the database secrets with the current key and exit")
+	flag.Parse()
+
 	conf := config.MustLoad()
 	setupLogging(conf)
 
-	provider, err := setupProvider(conf)
-	if err != nil {
+	// applies, reverts or lists the database migrations and
+	// exits, so that migrations can be performed explicitly
+	// during deployment.
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(conf, flag.Args()[1:]); err != nil {
+			log.Fatal().Err(err).
+				Msg("Cannot migrate the database")
+		}
+		return
+	}
+
+	// exports the servers to, or imports the servers from, a
+	// json snapshot and exits, so that the state can be moved
+	// between databases or restored from a backup.
+	switch flag.Arg(0) {
+	case "export", "import":
+		if err := runSnapshot(conf, flag.Arg(0), flag.Args()[1:]); err != nil {
+			log.Fatal().Err(err).
+				Msgf("Cannot %s the snapshot", flag.Arg(0))
+		}
+		return
+	}
+
+	// rejects misspelled or unknown hosting provider options,
+	// which would otherwise be ignored in favor of defaults.
+	if err := config.Check(os.Environ()); err != nil {
 		log.Fatal().Err(err).
-			Msg("Invalid or missing hosting provider")
+			Msg("Invalid hosting provider configuration")
+	}
+
+	// loads the configuration of each pool. If multiple pools
+	// are not configured, the global configuration is used.
+	pools := []config.Config{conf}
+	if len(conf.Pool.Names) != 0 {
+		pools = nil
+		for _, name := range conf.Pool.Names {
+			c, err := config.LoadPool(name)
+			if err != nil {
+				log.Fatal().Err(err).Str("pool", name).
+					Msg("Invalid pool configuration")
+			}
+			pools = append(pools, c)
+		}
+	}
+
+	// rejects an invalid docker daemon configuration or agent
+	// volumes, which would otherwise fail the installation of
+	// every instance.
+	for _, c := range pools {
+		if err := engine.ValidateVolumes(c.Agent.Volumes); err != nil {
+			log.Fatal().Err(err).Str("pool", c.Pool.Name).
+				Msg("Invalid agent volume configuration")
+		}
+		if _, err := engine.ParseAddressPools(c.Docker.AddressPools); err != nil {
+			log.Fatal().Err(err).Str("pool", c.Pool.Name).
+				Msg("Invalid docker address pool configuration")
+		}
+		if _, err := engine.ReadCACerts(c.Docker.CACertFile); err != nil {
+			log.Fatal().Err(err).Str("pool", c.Pool.Name).
+				Msg("Invalid certificate authority configuration")
+		}
+		if c.Installer.ManagedKeys && conf.Database.Secret == "" {
+			log.Fatal().Str("pool", c.Pool.Name).
+				Msg("Managed ssh keys require a database secret")
+		}
+		if c.Docker.DaemonConfig == "" {
+			continue
+		}
+		daemon := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(c.Docker.DaemonConfig), &daemon); err != nil {
+			log.Fatal().Err(err).Str("pool", c.Pool.Name).
+				Msg("Invalid docker daemon configuration")
+		}
+	}
+
+	// the raw providers are retained for validation, since the
+	// instrumented providers do not expose the optional
+	// validation interface.
+	bases := make([]autoscaler.Provider, len(pools))
+	for i, c := range pools {
+		provider, err := setupProvider(c)
+		if err != nil {
+			log.Fatal().Err(err).Str("pool", c.Pool.Name).
+				Msg("Invalid or missing hosting provider")
+		}
+		bases[i] = provider
 	}
 
-	// instruments the provider with prometheus metrics.
-	provider = metrics.ServerCreate(provider)
-	provider = metrics.ServerDelete(provider)
+	// verifies the provider credentials and resources before
+	// the first scale-up, failing fast on invalid configuration.
+	if conf.Provider.Validate || *validateOnly {
+		for i, provider := range bases {
+			err := validate(log.Logger.WithContext(context.Background()), provider)
+			if err != nil {
+				log.Fatal().Err(err).Str("pool", pools[i].Pool.Name).
+					Msg("Invalid hosting provider configuration")
+			}
+		}
+		if *validateOnly {
+			log.Info().Msg("Hosting provider configuration is valid")
+			return
+		}
+	}
 
-	db, err := store.Connect(conf.Database.Driver, conf.Database.Datasource)
+	stores, err := setupStores(conf)
 	if err != nil {
 		log.Fatal().Err(err).
 			Msg("Cannot establish database connection")
 	}
+	defer stores.close()
 
-	servers := store.NewServerStore(db)
+	// records the state transitions of the servers, so that
+	// the lifecycle of a server can be reconstructed.
+	events := stores.events
+	servers := store.Track(stores.servers, events)
+
+	// re-encrypts the server secrets and private keys with the
+	// current secret, after the secret is rotated or enabled.
+	if *rotateKeys {
+		if conf.Database.Secret == "" {
+			log.Fatal().
+				Msg("Key rotation requires a database secret")
+		}
+		err := store.Reencrypt(log.Logger.WithContext(context.Background()), stores.servers)
+		if err != nil {
+			log.Fatal().Err(err).
+				Msg("Cannot re-encrypt the database secrets")
+		}
+		log.Info().Msg("Database secrets re-encrypted")
+		return
+	}
 	// instruments the provider with slack notifications
 	// instance creation and termination events.
 	if conf.Slack.Webhook != "" {
 		servers = slack.New(conf, servers)
 	}
 	servers = metrics.ServerCount(servers)
-	defer db.Close()
 
 	client := setupClient(conf)
 
-	enginex := engine.New(
-		client,
-		conf,
-		servers,
-		provider,
-	)
+	// wakes the planner and collector of each engine when the
+	// servers change state, or the queue webhook is received.
+	// The database notifies every replica, if supported.
+	notifier := engine.NewBroadcaster()
+	notify := func(context.Context) error {
+		notifier.Notify()
+		return nil
+	}
+	if stores.notify != nil {
+		notify = stores.notify
+	}
+
+	var engines []autoscaler.Engine
+	providers := map[string]autoscaler.Provider{}
+	for i, c := range pools {
+		// limits the rate of provider api calls to prevent
+		// throttling during large scaling events.
+		provider := ratelimit.New(bases[i],
+			c.Provider.RateLimit,
+			c.Provider.RateBurst,
+		)
+
+		// instruments the provider with prometheus metrics.
+		provider = metrics.ServerCreatePool(provider, c.Pool.Name)
+		provider = metrics.ServerDeletePool(provider, c.Pool.Name)
+		providers[c.Pool.Name] = provider
+
+		engines = append(engines, engine.New(
+			client,
+			c,
+			servers,
+			provider,
+			engine.WithNotifier(notifier),
+			engine.WithScaleEvents(stores.scales),
+			engine.WithPlans(stores.plans),
+			engine.WithPoolStates(stores.states),
+		))
+	}
+
+	enginex := engines[0]
+	if len(engines) > 1 {
+		enginex = engine.NewGroup(engines...)
+	}
+
+	// runs the engine only while this replica holds the leader
+	// lease, if multiple replicas are deployed.
+	if conf.HA.Enabled {
+		id := conf.HA.ID
+		if id == "" {
+			id, _ = os.Hostname()
+		}
+		enginex = engine.NewLeader(enginex, stores.leases, id, conf.HA.LeaseTTL)
+	}
+
+	// authenticates identity tokens issued by the openid
+	// connect provider, if configured.
+	var oidc *server.OIDC
+	if conf.OIDC.Issuer != "" {
+		oidc, err = server.NewOIDC(context.Background(), conf)
+		if err != nil {
+			log.Fatal().Err(err).
+				Str("issuer", conf.OIDC.Issuer).
+				Msg("Cannot configure the openid connect provider")
+		}
+	}
 
 	r := chi.NewRouter()
+	// the forwarded headers are applied before the request
+	// is logged, so that the client address is logged.
+	if conf.HTTP.TrustProxy {
+		trusted, err := server.ParseNetworks(conf.HTTP.TrustedProxies)
+		if err != nil {
+			log.Fatal().Err(err).
+				Msg("Cannot parse the trusted proxies")
+		}
+		r.Use(server.Proxy(trusted))
+	}
 	r.Use(hlog.NewHandler(log.Logger))
 	r.Use(hlog.RemoteAddrHandler("ip"))
 	r.Use(hlog.URLHandler("path"))
 	r.Use(hlog.MethodHandler("method"))
 	r.Use(hlog.RequestIDHandler("request_id", "Request-Id"))
+	if len(conf.CORS.Origins) != 0 {
+		if err := server.ValidateCORS(conf); err != nil {
+			log.Fatal().Err(err).
+				Msg("Invalid cors configuration")
+		}
+		r.Use(server.CORS(conf))
+	}
+	r.Use(server.MaxBodySize(int64(conf.API.MaxBodySize)))
 
 	r.Route(conf.HTTP.Root, func(root chi.Router) {
 		root.Get("/metrics", server.HandleMetrics(conf.Prometheus.AuthToken))
 		root.Get("/version", server.HandleVersion(source, version, commit))
-		root.Get("/healthz", server.HandleHealthz())
+		root.Get("/openapi.json", server.HandleOpenAPI(conf.HTTP.Root, version))
+		root.Get("/healthz", server.HandleHealthz(
+			setupHealth(conf, stores, client, pools, bases, enginex)...,
+		))
+		root.Get("/livez", server.HandleLivez())
+		root.Get("/readyz", server.HandleHealthz(
+			setupReady(stores, client)...,
+		))
 		root.Get("/varz", server.HandleVarz(enginex))
+		if conf.Webhook.Secret != "" {
+			root.Post("/hooks/queue", server.HandleWebhook(conf.Webhook.Secret, notify))
+		}
 		root.Route("/api", func(api chi.Router) {
-			api.Use(server.CheckDrone(conf))
-
-			api.Post("/pause", server.HandleEnginePause(enginex))
-			api.Post("/resume", server.HandleEngineResume(enginex))
-			api.Get("/servers", server.HandleServerList(servers))
-			api.Post("/servers", server.HandleServerCreate(servers, conf))
-			api.Get("/servers/{name}", server.HandleServerFind(servers))
-			api.Delete("/servers/{name}", server.HandleServerDelete(servers))
+			api.Use(server.CheckDrone(conf, oidc))
+			api.Use(server.RateLimit(conf.API.RateLimit, conf.API.RateBurst))
+			api.Use(server.Audit(stores.audits))
+
+			api.Get("/queue", server.HandleQueue(enginex))
+			api.Get("/servers", server.HandleServerList(stores.readServers))
+			api.Get("/servers/{name}", server.HandleServerFind(servers, stores.readEvents, providers))
+			api.Get("/servers/{name}/logs", server.HandleServerLogs(servers))
+			api.Get("/servers/{name}/events", server.HandleServerEvents(stores.readEvents))
+			api.Get("/scale/events", server.HandleScaleEvents(stores.readScales))
+			api.Get("/plans", server.HandlePlans(stores.readPlans))
+			api.Post("/plan/preview", server.HandlePlanPreview(enginex))
+			api.Get("/events", server.HandleEvents(stores.readEvents, stores.readScales))
+			api.Get("/scale/summary", server.HandleScaleSummary(stores.readScales))
+
+			api.Group(func(op chi.Router) {
+				op.Use(server.RequireScope(server.ScopeOperator))
+				op.Post("/pause", server.HandleEnginePause(enginex))
+				op.Post("/resume", server.HandleEngineResume(enginex))
+				op.Post("/upgrade", server.HandleEngineUpgrade(enginex))
+				op.Post("/pools/{pool}/pause", server.HandlePoolPause(enginex))
+				op.Post("/pools/{pool}/resume", server.HandlePoolResume(enginex))
+				op.Post("/pools/{pool}/scale", server.HandleEngineScale(enginex))
+				op.Delete("/pools/{pool}/scale", server.HandleEngineScaleClear(enginex))
+				op.Post("/servers", server.HandleServerCreate(servers, conf))
+				op.Post("/servers/cordon", server.HandleServerBulkCordon(servers, enginex))
+				op.Patch("/servers/{name}/annotations", server.HandleServerAnnotate(servers))
+				op.Post("/servers/{name}/rotate", server.HandleServerRotate(enginex))
+				op.Post("/servers/{name}/repair", server.HandleServerRepair(enginex))
+				op.Post("/servers/{name}/replace", server.HandleServerReplace(enginex))
+				op.Post("/servers/{name}/cordon", server.HandleServerCordon(enginex))
+				op.Post("/servers/{name}/uncordon", server.HandleServerUncordon(enginex))
+			})
+
+			api.Group(func(admin chi.Router) {
+				admin.Use(server.RequireScope(server.ScopeAdmin))
+				admin.Delete("/servers", server.HandleServerBulkDelete(servers))
+				admin.Delete("/servers/{name}", server.HandleServerDelete(servers, enginex))
+				admin.Get("/audit", server.HandleAuditList(stores.audits))
+				admin.Get("/export", server.HandleExport(stores.servers, events))
+				admin.Post("/import", server.HandleImport(stores.servers, events))
+			})
 		})
 	})
 
@@ -116,9 +363,28 @@ func main() {
 		Handler: r,
 	}
 
+	//
+	// configures the grpc server, if enabled.
+	//
+
+	var rpcsrv *grpc.Server
+	if conf.GRPC.Port != "" {
+		rpcsrv, err = setupGRPC(conf, oidc, stores.audits)
+		if err != nil {
+			log.Fatal().Err(err).
+				Msg("Cannot configure the grpc server")
+		}
+		rpc.RegisterAutoscalerServer(rpcsrv,
+			rpc.New(enginex, client, servers, stores.readEvents, conf),
+		)
+	}
+
 	ctx := log.Logger.WithContext(context.Background())
 	ctx = signal.WithContextFunc(ctx, func() {
 		srv.Shutdown(ctx)
+		if rpcsrv != nil {
+			rpcsrv.GracefulStop()
+		}
 	})
 
 	var g errgroup.Group
@@ -137,6 +403,16 @@ func main() {
 		return srv.ListenAndServe()
 	})
 
+	if rpcsrv != nil {
+		g.Go(func() error {
+			l, err := net.Listen("tcp", conf.GRPC.Port)
+			if err != nil {
+				return err
+			}
+			return rpcsrv.Serve(l)
+		})
+	}
+
 	//
 	// starts the auto-scaler routine.
 	//
@@ -146,6 +422,28 @@ func main() {
 		return nil
 	})
 
+	if stores.listen != nil {
+		g.Go(func() error {
+			err := stores.listen(ctx, notifier.Notify)
+			if err != nil {
+				log.Error().Err(err).
+					Msg("Cannot listen for database notifications")
+			}
+			return nil
+		})
+	}
+
+	for i, c := range pools {
+		if c.Provider.ValidateInterval == 0 {
+			continue
+		}
+		provider, interval := bases[i], c.Provider.ValidateInterval
+		g.Go(func() error {
+			validateInterval(ctx, provider, interval)
+			return nil
+		})
+	}
+
 	if err := g.Wait(); err != nil {
 		log.Fatal().Err(err).Msg("Program terminated")
 	}
@@ -158,6 +456,28 @@ func setupServer(c config.Config) *http.Server {
 	}
 }
 
+// helper function configures the grpc server. The server
+// uses the tls certificate of the http server, if configured,
+// and requires a token with the scope of the method on every
+// request. The mutating requests are recorded in the audit log.
+func setupGRPC(c config.Config, oidc *server.OIDC, audits autoscaler.AuditStore) (*grpc.Server, error) {
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(rpc.ChainUnary(
+			rpc.UnaryAuth(c, oidc),
+			rpc.UnaryAudit(audits),
+		)),
+		grpc.StreamInterceptor(rpc.StreamAuth(c, oidc)),
+	}
+	if c.TLS.Cert != "" {
+		creds, err := credentials.NewServerTLSFromFile(c.TLS.Cert, c.TLS.Key)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	return grpc.NewServer(opts...), nil
+}
+
 // helper funciton configures the logging.
 func setupLogging(c config.Config) {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
@@ -192,43 +512,104 @@ func setupClient(c config.Config) drone.Client {
 // helper function configures the hosting provider.
 func setupProvider(c config.Config) (autoscaler.Provider, error) {
 	switch {
+	case c.Plugin.Path != "":
+		return plugin.New(
+			// the global userdata template is used unless the
+			// driver userdata template is configured.
+			plugin.WithUserData(c.UserData.Text),
+			plugin.WithUserDataFile(c.UserData.File),
+			plugin.WithPath(c.Plugin.Path),
+			plugin.WithArgs(c.Plugin.Args...),
+			plugin.WithEnviron(c.Plugin.Environ),
+			plugin.WithLabels(c.Provider.Labels),
+			plugin.WithUserData(c.Plugin.UserData),
+			plugin.WithUserDataFile(c.Plugin.UserDataFile),
+			plugin.WithUserDataHooks(c.Plugin.UserDataPre, c.Plugin.UserDataPost),
+		), nil
 	case c.Google.Project != "":
 		return google.New(
+			google.WithUserData(c.UserData.Text),
+			google.WithUserDataFile(c.UserData.File),
+			google.WithAccelerator(c.Google.Accelerator, c.Google.Accelerators),
+			google.WithConfidentialCompute(c.Google.Confidential),
 			google.WithDiskSize(c.Google.DiskSize),
 			google.WithDiskType(c.Google.DiskType),
+			google.WithDockerDisk(c.Google.DockerDiskSize, c.Google.DockerDiskType),
+			google.WithInstanceGroup(c.Google.Group),
+			google.WithIntegrityMonitoring(c.Google.Integrity),
+			google.WithLocalSSD(c.Google.LocalSSDs, c.Google.LocalSSDType),
 			google.WithMachineImage(c.Google.MachineImage),
+			google.WithImageFamily(c.Google.ImageFamily),
 			google.WithMachineType(c.Google.MachineType),
-			google.WithLabels(c.Google.Labels),
+			google.WithCustomMachineType(c.Google.CustomFamily, c.Google.CustomCPUs, c.Google.CustomMemory),
+			google.WithLabels(mergeLabels(c.Provider.Labels, c.Google.Labels)),
 			google.WithNetwork(c.Google.Network),
+			google.WithNodeGroup(c.Google.NodeGroup),
+			google.WithPreemptible(c.Google.Preemptible),
+			google.WithPrivateIP(c.Google.PrivateIP),
 			google.WithProject(c.Google.Project),
+			google.WithRegion(c.Google.Region),
+			google.WithRetries(c.Google.Retries),
+			google.WithSecureBoot(c.Google.SecureBoot),
+			google.WithSpot(c.Google.Spot),
+			google.WithStaticIP(c.Google.StaticIP),
 			google.WithTags(c.Google.Tags...),
+			google.WithTerminationAction(c.Google.Termination),
 			google.WithUserData(c.Google.UserData),
 			google.WithUserDataFile(c.Google.UserDataFile),
+			google.WithUserDataHooks(c.Google.UserDataPre, c.Google.UserDataPost),
+			google.WithVTPM(c.Google.VTPM),
 			google.WithZone(c.Google.Zone),
 		)
 	case c.DigitalOcean.Token != "":
 		return digitalocean.New(
+			digitalocean.WithUserData(c.UserData.Text),
+			digitalocean.WithUserDataFile(c.UserData.File),
 			digitalocean.WithSSHKey(c.DigitalOcean.SSHKey),
 			digitalocean.WithImage(c.DigitalOcean.Image),
 			digitalocean.WithRegion(c.DigitalOcean.Region),
 			digitalocean.WithSize(c.DigitalOcean.Size),
 			digitalocean.WithUserDataFile(c.DigitalOcean.UserDataFile),
+			digitalocean.WithUserDataHooks(c.DigitalOcean.UserDataPre, c.DigitalOcean.UserDataPost),
 			digitalocean.WithUserData(c.DigitalOcean.UserData),
 			digitalocean.WithToken(c.DigitalOcean.Token),
 			digitalocean.WithTags(c.DigitalOcean.Tags...),
+			digitalocean.WithLabels(c.Provider.Labels),
+			digitalocean.WithFirewalls(c.DigitalOcean.Firewalls...),
+			digitalocean.WithBackups(c.DigitalOcean.Backups),
+			digitalocean.WithIPv6(c.DigitalOcean.IPv6),
+			digitalocean.WithMonitoring(c.DigitalOcean.Monitoring),
+			digitalocean.WithPrivateIP(c.DigitalOcean.PrivateIP),
+			digitalocean.WithReservedIP(c.DigitalOcean.ReservedIP),
+			digitalocean.WithRetries(c.DigitalOcean.Retries),
+			digitalocean.WithVPC(c.DigitalOcean.VPC),
+			digitalocean.WithRegionVPCs(c.DigitalOcean.RegionVPCs),
 		), nil
 	case c.HetznerCloud.Token != "":
 		return hetznercloud.New(
+			hetznercloud.WithUserData(c.UserData.Text),
+			hetznercloud.WithUserDataFile(c.UserData.File),
 			hetznercloud.WithDatacenter(c.HetznerCloud.Datacenter),
 			hetznercloud.WithImage(c.HetznerCloud.Image),
 			hetznercloud.WithUserDataFile(c.HetznerCloud.UserDataFile),
+			hetznercloud.WithUserDataHooks(c.HetznerCloud.UserDataPre, c.HetznerCloud.UserDataPost),
 			hetznercloud.WithUserData(c.HetznerCloud.UserData),
 			hetznercloud.WithServerType(c.HetznerCloud.Type),
 			hetznercloud.WithSSHKey(c.HetznerCloud.SSHKey),
 			hetznercloud.WithToken(c.HetznerCloud.Token),
+			hetznercloud.WithFirewalls(c.HetznerCloud.Firewalls...),
+			hetznercloud.WithFloatingIP(c.HetznerCloud.FloatingIP),
+			hetznercloud.WithLabels(c.Provider.Labels),
+			hetznercloud.WithLocations(c.HetznerCloud.Locations...),
+			hetznercloud.WithNetworks(c.HetznerCloud.Networks...),
+			hetznercloud.WithPlacementGroup(c.HetznerCloud.PlacementGroup),
+			hetznercloud.WithPrivateIP(c.HetznerCloud.PrivateIP),
+			hetznercloud.WithRetries(c.HetznerCloud.Retries),
 		), nil
 	case c.Packet.APIKey != "":
 		return packet.New(
+			packet.WithUserData(c.UserData.Text),
+			packet.WithUserDataFile(c.UserData.File),
 			packet.WithAPIKey(c.Packet.APIKey),
 			packet.WithFacility(c.Packet.Facility),
 			packet.WithProject(c.Packet.ProjectID),
@@ -237,41 +618,113 @@ func setupProvider(c config.Config) (autoscaler.Provider, error) {
 			packet.WithSSHKey(c.Packet.SSHKey),
 			packet.WithUserData(c.Packet.UserData),
 			packet.WithUserDataFile(c.Packet.UserDataFile),
+			packet.WithUserDataHooks(c.Packet.UserDataPre, c.Packet.UserDataPost),
 			packet.WithHostname(c.Packet.Hostname),
 			packet.WithTags(c.Packet.Tags...),
+			packet.WithLabels(c.Provider.Labels),
 		), nil
-	case os.Getenv("AWS_ACCESS_KEY_ID") != "" || os.Getenv("AWS_IAM") != "":
+	case c.Amazon.AccessKeyID != "" || c.Amazon.IAM:
 		return amazon.New(
+			amazon.WithCredentials(c.Amazon.AccessKeyID, c.Amazon.SecretAccessKey, c.Amazon.SessionToken),
+			amazon.WithUserData(c.UserData.Text),
+			amazon.WithUserDataFile(c.UserData.File),
 			amazon.WithDeviceName(c.Amazon.DeviceName),
+			amazon.WithElasticIP(c.Amazon.ElasticIP),
+			amazon.WithDockerVolume(c.Amazon.DockerVolumeSize, c.Amazon.DockerVolumeType),
+			amazon.WithDockerVolumeDevice(c.Amazon.DockerVolumeDevice),
 			amazon.WithImage(c.Amazon.Image),
+			amazon.WithImageName(c.Amazon.ImageName),
+			amazon.WithImageOwners(c.Amazon.ImageOwners...),
+			amazon.WithImageTags(c.Amazon.ImageTags),
+			amazon.WithHost(c.Amazon.Host),
+			amazon.WithHostResourceGroup(c.Amazon.HostResourceGroup),
+			amazon.WithPlacementGroup(c.Amazon.PlacementGroup),
+			amazon.WithPricing(c.Amazon.Pricing),
 			amazon.WithRegion(c.Amazon.Region),
+			amazon.WithRegionImages(c.Amazon.RegionImages),
+			amazon.WithRegionSecurityGroups(c.Amazon.RegionGroups),
+			amazon.WithRegionSubnets(c.Amazon.RegionSubnets),
 			amazon.WithRetries(c.Amazon.Retries),
 			amazon.WithPrivateIP(c.Amazon.PrivateIP),
 			amazon.WithSSHKey(c.Amazon.SSHKey),
 			amazon.WithSecurityGroup(c.Amazon.SecurityGroup...),
 			amazon.WithSize(c.Amazon.Instance),
 			amazon.WithSubnet(c.Amazon.SubnetID),
-			amazon.WithTags(c.Amazon.Tags),
+			amazon.WithTags(mergeLabels(c.Provider.Labels, c.Amazon.Tags)),
+			amazon.WithTenancy(c.Amazon.Tenancy),
 			amazon.WithUserData(c.Amazon.UserData),
 			amazon.WithUserDataFile(c.Amazon.UserDataFile),
+			amazon.WithUserDataHooks(c.Amazon.UserDataPre, c.Amazon.UserDataPost),
 			amazon.WithVolumeSize(c.Amazon.VolumeSize),
 			amazon.WithVolumeType(c.Amazon.VolumeType),
+			amazon.WithVolumeIops(c.Amazon.VolumeIops),
+			amazon.WithVolumeThroughput(c.Amazon.VolumeThroughput),
+			amazon.WithVolumeEncryption(c.Amazon.VolumeEncrypted, c.Amazon.VolumeKMSKey),
 			amazon.WithIamProfileArn(c.Amazon.IamProfileArn),
 			amazon.WithMarketType(c.Amazon.MarketType),
+			amazon.WithSpotAllocationStrategy(c.Amazon.SpotAllocationStrategy),
+			amazon.WithSpotInterruptionBehavior(c.Amazon.SpotInterruptionBehavior),
+			amazon.WithSpotMaxPrice(c.Amazon.SpotMaxPrice),
 		), nil
-	case os.Getenv("OS_USERNAME") != "":
+	case c.OpenStack.Username != "" || c.OpenStack.UserID != "":
 		return openstack.New(
+			openstack.WithAuthOptions(openstackAuth(c)),
+			openstack.WithUserData(c.UserData.Text),
+			openstack.WithUserDataFile(c.UserData.File),
 			openstack.WithImage(c.OpenStack.Image),
 			openstack.WithRegion(c.OpenStack.Region),
 			openstack.WithFlavor(c.OpenStack.Flavor),
 			openstack.WithFloatingIpPool(c.OpenStack.Pool),
 			openstack.WithSSHKey(c.OpenStack.SSHKey),
 			openstack.WithSecurityGroup(c.OpenStack.SecurityGroup...),
-			openstack.WithMetadata(c.OpenStack.Metadata),
+			openstack.WithManagedSecurityGroup(c.OpenStack.ManageGroup, c.OpenStack.ManageCIDR),
+			openstack.WithManagedSSHKey(c.OpenStack.ManageSSHKey),
+			openstack.WithMetadata(mergeLabels(c.Provider.Labels, c.OpenStack.Metadata)),
 			openstack.WithUserData(c.OpenStack.UserData),
 			openstack.WithUserDataFile(c.OpenStack.UserDataFile),
+			openstack.WithUserDataHooks(c.OpenStack.UserDataPre, c.OpenStack.UserDataPost),
 		)
 	default:
 		return nil, errors.New("missing provider configuration")
 	}
 }
+
+// helper function returns the openstack identity api
+// credentials of the configuration. The project takes
+// precedence over the tenant of the same name.
+func openstackAuth(c config.Config) gophercloud.AuthOptions {
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: c.OpenStack.AuthURL,
+		Username:         c.OpenStack.Username,
+		UserID:           c.OpenStack.UserID,
+		Password:         c.OpenStack.Password,
+		TenantID:         c.OpenStack.TenantID,
+		TenantName:       c.OpenStack.TenantName,
+		DomainID:         c.OpenStack.DomainID,
+		DomainName:       c.OpenStack.DomainName,
+	}
+	if c.OpenStack.ProjectID != "" {
+		opts.TenantID = c.OpenStack.ProjectID
+	}
+	if c.OpenStack.ProjectName != "" {
+		opts.TenantName = c.OpenStack.ProjectName
+	}
+	return opts
+}
+
+// helper function merges the global instance labels with the
+// driver specific labels. Driver specific labels take
+// precedence.
+func mergeLabels(global, local map[string]string) map[string]string {
+	if len(global) == 0 {
+		return local
+	}
+	out := map[string]string{}
+	for k, v := range global {
+		out[k] = v
+	}
+	for k, v := range local {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/autoscaler/store"
+	"github.com/drone/autoscaler/store/migrate"
+
+	"github.com/rs/zerolog/log"
+)
+
+// helper function runs the migrate subcommand, which applies
+// (up), reverts the last (down), or lists (status) the
+// database migrations.
+func runMigrate(conf config.Config, args []string) error {
+	command := "up"
+	if len(args) != 0 {
+		command = args[0]
+	}
+
+	// the consul key value store is schemaless, and does
+	// not require migration.
+	if conf.Database.Driver == "consul" {
+		log.Info().Msg("No database migrations for consul")
+		return nil
+	}
+
+	opts, err := databaseOptions(conf)
+	if err != nil {
+		return err
+	}
+	db, err := store.Open(conf.Database.Driver, conf.Database.Datasource, opts...)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch command {
+	case "up":
+		if err := ddl.Migrate(db, conf.Database.Driver); err != nil {
+			return err
+		}
+		log.Info().Msg("Database migrations completed")
+		return nil
+	case "down":
+		name, err := ddl.Rollback(db, conf.Database.Driver)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			log.Info().Msg("No database migrations to revert")
+			return nil
+		}
+		log.Info().Str("migration", name).
+			Msg("Database migration reverted")
+		return nil
+	case "status":
+		migrations, err := ddl.Status(db, conf.Database.Driver)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "MIGRATION\tSTATUS")
+		for _, migration := range migrations {
+			status := "pending"
+			if migration.Completed {
+				status = "completed"
+			}
+			fmt.Fprintf(w, "%s\t%s\n", migration.Name, status)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("Unknown migrate command %q, want up, down or status", command)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/autoscaler/store"
+
+	"github.com/rs/zerolog/log"
+)
+
+// helper function runs the export subcommand, which writes a
+// json snapshot of the servers and server events to the file,
+// or to stdout, and the import subcommand, which creates the
+// servers and server events of the snapshot file.
+func runSnapshot(conf config.Config, command string, args []string) error {
+	stores, err := setupStores(conf)
+	if err != nil {
+		return err
+	}
+	defer stores.close()
+
+	ctx := context.Background()
+
+	switch command {
+	case "export":
+		snapshot, err := store.Export(ctx, stores.servers, stores.events)
+		if err != nil {
+			return err
+		}
+		var w io.Writer = os.Stdout
+		if len(args) != 0 {
+			f, err := os.OpenFile(args[0], os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(snapshot); err != nil {
+			return err
+		}
+		log.Info().Int("servers", len(snapshot.Servers)).
+			Msg("Snapshot exported")
+		return nil
+	default:
+		if len(args) == 0 {
+			return errors.New("Missing snapshot file")
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		snapshot := new(store.Snapshot)
+		if err := json.NewDecoder(f).Decode(snapshot); err != nil {
+			return err
+		}
+		imported, err := store.Import(ctx, stores.servers, stores.events, snapshot)
+		if err != nil {
+			return err
+		}
+		log.Info().Int("servers", imported).
+			Int("skipped", len(snapshot.Servers)-imported).
+			Msg("Snapshot imported")
+		return nil
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/autoscaler/metrics"
+	"github.com/drone/autoscaler/store"
+	"github.com/drone/autoscaler/store/consul"
+	"github.com/drone/autoscaler/store/migrate"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// stores groups the stores of the autoscaler.
+type stores struct {
+	servers autoscaler.ServerStore
+	events  autoscaler.ServerEventStore
+	leases  autoscaler.LeaseStore
+	states  autoscaler.PoolStateStore
+	scales  autoscaler.ScaleEventStore
+	plans   autoscaler.PlanStore
+	audits  autoscaler.AuditStore
+	close   func() error
+
+	// ping verifies the connection to the database.
+	ping func(context.Context) error
+
+	// the read stores serve the list and report queries of
+	// the api, from the read replica if configured, or from
+	// the primary database.
+	readServers autoscaler.ServerStore
+	readEvents  autoscaler.ServerEventStore
+	readScales  autoscaler.ScaleEventStore
+	readPlans   autoscaler.PlanStore
+
+	// notify and listen send and receive the notifications
+	// of the database, if supported.
+	notify func(context.Context) error
+	listen func(context.Context, func()) error
+}
+
+// helper function connects to the consul key value store, or
+// to the sql database, and returns the stores. The server
+// secrets and private keys are encrypted at rest, if a
+// database secret is configured. The previous secrets are used
+// to decrypt values encrypted before the secret was rotated.
+func setupStores(conf config.Config) (*stores, error) {
+	s := new(stores)
+	if conf.Database.Driver == "consul" {
+		client, err := setupConsul(conf)
+		if err != nil {
+			return nil, err
+		}
+		s.servers = consul.NewServerStore(client)
+		s.events = consul.NewEventStore(client)
+		s.leases = consul.NewLeaseStore(client)
+		s.states = consul.NewPoolStateStore(client)
+		s.scales = consul.NewScaleEventStore(client)
+		s.plans = consul.NewPlanStore(client)
+		s.audits = consul.NewAuditStore(client)
+		s.close = func() error { return nil }
+		s.ping = client.Ping
+		s.readServers = s.servers
+		s.readEvents = s.events
+		s.readScales = s.scales
+		s.readPlans = s.plans
+	} else {
+		db, err := setupDatabase(conf)
+		if err != nil {
+			return nil, err
+		}
+		setupPool(conf, db)
+		if conf.Database.Driver == "sqlite3" {
+			err := store.Pragma(db, store.Pragmas{
+				JournalMode: conf.Database.JournalMode,
+				BusyTimeout: conf.Database.BusyTimeout,
+				Synchronous: conf.Database.Synchronous,
+			})
+			if err != nil {
+				db.Close()
+				return nil, err
+			}
+		}
+		metrics.DatabaseStats(db)
+		s.servers = store.NewServerStore(db)
+		s.events = store.NewEventStore(db)
+		s.leases = store.NewLeaseStore(db)
+		s.states = store.NewPoolStateStore(db)
+		s.scales = store.NewScaleEventStore(db)
+		s.plans = store.NewPlanStore(db)
+		s.audits = store.NewAuditStore(db)
+		s.close = db.Close
+		s.ping = db.PingContext
+		// postgres notifies the replicas of server state
+		// changes, so that the replicas wake immediately. The
+		// listener connects with the database options, since
+		// it does not use the connection pool.
+		if conf.Database.Driver == "postgres" {
+			opts, err := databaseOptions(conf)
+			if err != nil {
+				db.Close()
+				return nil, err
+			}
+			s.events = store.NotifyEvents(s.events, db)
+			s.notify = func(ctx context.Context) error {
+				return store.Notify(ctx, db)
+			}
+			s.listen = func(ctx context.Context, notify func()) error {
+				return store.Listen(ctx, conf.Database.Datasource, notify, opts...)
+			}
+		}
+		s.readServers = s.servers
+		s.readEvents = s.events
+		s.readScales = s.scales
+		s.readPlans = s.plans
+		if conf.Database.ReplicaDatasource != "" {
+			opts, err := databaseOptions(conf)
+			if err != nil {
+				db.Close()
+				return nil, err
+			}
+			replica, err := store.Open(conf.Database.Driver, conf.Database.ReplicaDatasource, opts...)
+			if err != nil {
+				db.Close()
+				return nil, err
+			}
+			setupPool(conf, replica)
+			s.readServers = store.NewServerStore(replica)
+			s.readEvents = store.NewEventStore(replica)
+			s.readScales = store.NewScaleEventStore(replica)
+			s.readPlans = store.NewPlanStore(replica)
+			s.close = func() error {
+				replica.Close()
+				return db.Close()
+			}
+		}
+	}
+	if conf.Database.Secret != "" {
+		servers, err := encrypt(conf, s.servers)
+		if err != nil {
+			s.close()
+			return nil, err
+		}
+		readServers, err := encrypt(conf, s.readServers)
+		if err != nil {
+			s.close()
+			return nil, err
+		}
+		s.servers = servers
+		s.readServers = readServers
+	}
+	return s, nil
+}
+
+// helper function returns the server store that encrypts the
+// server secrets and private keys with the database secret.
+func encrypt(conf config.Config, servers autoscaler.ServerStore) (autoscaler.ServerStore, error) {
+	return store.Encrypt(servers,
+		conf.Database.Secret,
+		conf.Database.SecretPrevious...,
+	)
+}
+
+// helper function connects to the consul agent, and
+// verifies the connection with a ping.
+func setupConsul(conf config.Config) (*consul.Client, error) {
+	client, err := consul.Open(conf.Database.Datasource)
+	if err != nil {
+		return nil, err
+	}
+	return client, client.Ping(context.Background())
+}
+
+// helper function connects to the database. The database is
+// migrated unless migration at startup is disabled, in which
+// case the migrations must be completed with the migrate
+// subcommand.
+func setupDatabase(conf config.Config) (*sqlx.DB, error) {
+	opts, err := databaseOptions(conf)
+	if err != nil {
+		return nil, err
+	}
+	if !conf.Database.SkipMigrate {
+		return store.Connect(conf.Database.Driver, conf.Database.Datasource, opts...)
+	}
+	db, err := store.Open(conf.Database.Driver, conf.Database.Datasource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := ddl.Pending(db, conf.Database.Driver)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if pending {
+		db.Close()
+		return nil, errors.New("Database migrations are pending, run the migrate subcommand")
+	}
+	return db, nil
+}
+
+// helper function returns the database connection options,
+// which configure the database tls settings, and the auth
+// method of cloud databases.
+func databaseOptions(conf config.Config) ([]store.Option, error) {
+	opts := []store.Option{
+		store.WithTLS(store.TLS{
+			Mode: conf.Database.TLSMode,
+			CA:   conf.Database.TLSCA,
+			Cert: conf.Database.TLSCert,
+			Key:  conf.Database.TLSKey,
+		}),
+	}
+	if conf.Database.Auth != "" {
+		password, err := store.Password(
+			conf.Database.Auth,
+			conf.Database.AuthRegion,
+			conf.Database.AuthClientID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, store.WithPassword(password))
+	}
+	return opts, nil
+}
+
+// helper function configures the database connection pool
+// limits. Unset limits keep the driver defaults. The sqlite
+// database is limited to a single open connection, which is
+// not configurable, and is never closed, so that the sqlite
+// pragmas applied to the connection are retained.
+func setupPool(conf config.Config, db *sqlx.DB) {
+	if conf.Database.Driver == "sqlite3" {
+		return
+	}
+	if conf.Database.MaxOpenConns != 0 {
+		db.SetMaxOpenConns(conf.Database.MaxOpenConns)
+	}
+	if conf.Database.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(conf.Database.MaxIdleConns)
+	}
+	if conf.Database.ConnMaxLifetime != 0 {
+		db.SetConnMaxLifetime(conf.Database.ConnMaxLifetime)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// helper function validates the provider configuration, if
+// the provider supports validation.
+func validate(ctx context.Context, provider autoscaler.Provider) error {
+	validator, ok := provider.(autoscaler.Validator)
+	if !ok {
+		log.Ctx(ctx).Debug().
+			Msg("provider does not support validation")
+		return nil
+	}
+	return validator.Validate(ctx)
+}
+
+// helper function periodically validates the provider
+// configuration, logging an error if validation fails, so
+// that expired credentials or deleted resources are detected
+// before the next scale-up.
+func validateInterval(ctx context.Context, provider autoscaler.Provider, interval time.Duration) {
+	if _, ok := provider.(autoscaler.Validator); !ok {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := validate(ctx, provider); err != nil {
+				log.Ctx(ctx).Error().Err(err).
+					Msg("provider validation failed")
+			}
+		}
+	}
+}
@@ -6,6 +6,8 @@ package config
 
 import (
 	"time"
+
+	"github.com/dustin/go-humanize"
 )
 
 type (
@@ -28,9 +30,27 @@ type (
 		}
 
 		Pool struct {
-			Min    int           `default:"2"`
-			Max    int           `default:"4"`
-			MinAge time.Duration `default:"55m" split_words:"true"`
+			Name    string            `ignored:"true"`
+			Names   []string          `envconfig:"DRONE_POOLS"`
+			Min     int               `default:"2"`
+			Max     int               `default:"4"`
+			MinAge  time.Duration     `default:"55m" split_words:"true"`
+			Sizes   []string          `envconfig:"DRONE_POOL_INSTANCE_TYPES"`
+			Regions []string          `envconfig:"DRONE_POOL_REGIONS"`
+			Vars    map[string]string `envconfig:"DRONE_POOL_VARS"`
+		}
+
+		UserData struct {
+			Text string `envconfig:"DRONE_USERDATA"`
+			File string `envconfig:"DRONE_USERDATA_FILE"`
+		}
+
+		Provider struct {
+			Labels           map[string]string `envconfig:"DRONE_PROVIDER_LABELS"`
+			RateLimit        float64           `envconfig:"DRONE_PROVIDER_RATE_LIMIT"`
+			RateBurst        int               `envconfig:"DRONE_PROVIDER_RATE_BURST"`
+			Validate         bool              `envconfig:"DRONE_PROVIDER_VALIDATE"`
+			ValidateInterval time.Duration     `envconfig:"DRONE_PROVIDER_VALIDATE_INTERVAL"`
 		}
 
 		Server struct {
@@ -50,6 +70,24 @@ type (
 			Environ     []string
 			Volumes     []string
 			Labels      map[string]string `envconfig:"DRONE_AGENT_LABELS"`
+			GPU         bool              `envconfig:"DRONE_AGENT_GPU"`
+
+			RegistryUsername string `envconfig:"DRONE_AGENT_REGISTRY_USERNAME"`
+			RegistryPassword string `envconfig:"DRONE_AGENT_REGISTRY_PASSWORD"`
+			RegistryHelper   string `envconfig:"DRONE_AGENT_REGISTRY_CREDENTIAL_HELPER"`
+
+			ReadyTimeout time.Duration `envconfig:"DRONE_AGENT_READY_TIMEOUT"`
+			ReadyPeriod  time.Duration `envconfig:"DRONE_AGENT_READY_PERIOD"`
+
+			RegistrationCheck   bool          `envconfig:"DRONE_AGENT_REGISTRATION_CHECK" default:"true"`
+			RegistrationTimeout time.Duration `envconfig:"DRONE_AGENT_REGISTRATION_TIMEOUT"`
+			RegistrationPattern string        `envconfig:"DRONE_AGENT_REGISTRATION_PATTERN"`
+
+			PrepullImages []string `envconfig:"DRONE_AGENT_PREPULL_IMAGES"`
+
+			CPUs      float64 `envconfig:"DRONE_AGENT_CPUS"`
+			Memory    Bytes   `envconfig:"DRONE_AGENT_MEMORY_LIMIT"`
+			PidsLimit int64   `envconfig:"DRONE_AGENT_PIDS_LIMIT"`
 		}
 
 		Runner Runner
@@ -63,6 +101,76 @@ type (
 			Cache    string        `envconfig:"DRONE_GC_CACHE" default:"10gb"`
 		}
 
+		Bastion struct {
+			Host       string `envconfig:"DRONE_BASTION_HOST"`
+			User       string `envconfig:"DRONE_BASTION_USER"`
+			Key        string `envconfig:"DRONE_BASTION_KEY_FILE"`
+			KnownHosts string `envconfig:"DRONE_BASTION_KNOWN_HOSTS"`
+		}
+
+		Installer struct {
+			SSH    bool   `envconfig:"DRONE_INSTALLER_SSH"`
+			User   string `envconfig:"DRONE_INSTALLER_SSH_USER"`
+			Key    string `envconfig:"DRONE_INSTALLER_SSH_KEY_FILE"`
+			Script string `envconfig:"DRONE_INSTALLER_SSH_SCRIPT"`
+			Podman bool   `envconfig:"DRONE_INSTALLER_PODMAN"`
+
+			KnownHosts string `envconfig:"DRONE_INSTALLER_SSH_KNOWN_HOSTS"`
+
+			ManagedKeys    bool          `envconfig:"DRONE_INSTALLER_SSH_MANAGED_KEYS"`
+			KeyRotationAge time.Duration `envconfig:"DRONE_INSTALLER_SSH_KEY_ROTATION_AGE"`
+
+			Packages []string `envconfig:"DRONE_INSTALLER_PACKAGES"`
+
+			Exec    bool   `envconfig:"DRONE_INSTALLER_EXEC"`
+			ExecURL string `envconfig:"DRONE_INSTALLER_EXEC_URL"`
+
+			InstanceTimeout time.Duration `envconfig:"DRONE_INSTALLER_INSTANCE_TIMEOUT"`
+			DockerTimeout   time.Duration `envconfig:"DRONE_INSTALLER_DOCKER_TIMEOUT"`
+			ImageTimeout    time.Duration `envconfig:"DRONE_INSTALLER_IMAGE_TIMEOUT"`
+			AgentTimeout    time.Duration `envconfig:"DRONE_INSTALLER_AGENT_TIMEOUT"`
+			RetryInterval   time.Duration `envconfig:"DRONE_INSTALLER_RETRY_INTERVAL"`
+
+			Systemd      bool   `envconfig:"DRONE_INSTALLER_SYSTEMD"`
+			SystemdImage string `envconfig:"DRONE_INSTALLER_SYSTEMD_IMAGE"`
+		}
+
+		Docker struct {
+			RegistryMirrors    []string `envconfig:"DRONE_DOCKER_REGISTRY_MIRRORS"`
+			InsecureRegistries []string `envconfig:"DRONE_DOCKER_INSECURE_REGISTRIES"`
+			DaemonConfig       string   `envconfig:"DRONE_DOCKER_DAEMON_CONFIG"`
+
+			StorageDriver string   `envconfig:"DRONE_DOCKER_STORAGE_DRIVER"`
+			StorageOpts   []string `envconfig:"DRONE_DOCKER_STORAGE_OPTS"`
+			DataRoot      string   `envconfig:"DRONE_DOCKER_DATA_ROOT"`
+
+			MTU          int      `envconfig:"DRONE_DOCKER_MTU"`
+			Bip          string   `envconfig:"DRONE_DOCKER_BIP"`
+			AddressPools []string `envconfig:"DRONE_DOCKER_DEFAULT_ADDRESS_POOLS"`
+
+			CACertFile   string   `envconfig:"DRONE_DOCKER_CA_CERT_FILE"`
+			CARegistries []string `envconfig:"DRONE_DOCKER_CA_REGISTRIES"`
+
+			Version      string `envconfig:"DRONE_DOCKER_VERSION"`
+			CgroupDriver string `envconfig:"DRONE_DOCKER_CGROUP_DRIVER"`
+			CgroupnsMode string `envconfig:"DRONE_DOCKER_CGROUPNS_MODE"`
+		}
+
+		Proxy struct {
+			HTTP    string `envconfig:"DRONE_HTTP_PROXY"`
+			HTTPS   string `envconfig:"DRONE_HTTPS_PROXY"`
+			NoProxy string `envconfig:"DRONE_NO_PROXY"`
+		}
+
+		Upgrade struct {
+			DrainTimeout time.Duration `envconfig:"DRONE_UPGRADE_DRAIN_TIMEOUT"`
+			Interval     time.Duration `envconfig:"DRONE_UPGRADE_INTERVAL"`
+		}
+
+		Certs struct {
+			RotationAge time.Duration `envconfig:"DRONE_CERTS_ROTATION_AGE"`
+		}
+
 		Watchtower struct {
 			Enabled  bool          `envconfig:"DRONE_WATCHTOWER_ENABLED"`
 			Image    string        `envconfig:"DRONE_WATCHTOWER_IMAGE" default:"webhippie/watchtower"`
@@ -71,9 +179,23 @@ type (
 		}
 
 		HTTP struct {
-			Host string
-			Port string `default:":8080"`
-			Root string `default:"/"`
+			Host           string
+			Port           string   `default:":8080"`
+			Root           string   `default:"/"`
+			TrustProxy     bool     `envconfig:"DRONE_HTTP_TRUST_PROXY"`
+			TrustedProxies []string `envconfig:"DRONE_HTTP_TRUSTED_PROXIES"`
+		}
+
+		CORS struct {
+			Origins     []string      `envconfig:"DRONE_CORS_ALLOWED_ORIGINS"`
+			Methods     []string      `envconfig:"DRONE_CORS_ALLOWED_METHODS"`
+			Headers     []string      `envconfig:"DRONE_CORS_ALLOWED_HEADERS"`
+			Credentials bool          `envconfig:"DRONE_CORS_ALLOW_CREDENTIALS"`
+			MaxAge      time.Duration `envconfig:"DRONE_CORS_MAX_AGE"`
+		}
+
+		GRPC struct {
+			Port string `envconfig:"DRONE_GRPC_PORT"`
 		}
 
 		TLS struct {
@@ -86,64 +208,193 @@ type (
 			AuthToken string `split_words:"true"`
 		}
 
+		API struct {
+			Tokens      map[string]string `envconfig:"DRONE_API_TOKENS"`
+			RateLimit   float64           `envconfig:"DRONE_API_RATE_LIMIT"`
+			RateBurst   int               `envconfig:"DRONE_API_RATE_BURST"`
+			MaxBodySize Bytes             `envconfig:"DRONE_API_MAX_BODY_SIZE"`
+		}
+
+		OIDC struct {
+			Issuer      string            `envconfig:"DRONE_OIDC_ISSUER"`
+			ClientID    string            `envconfig:"DRONE_OIDC_CLIENT_ID"`
+			GroupsClaim string            `envconfig:"DRONE_OIDC_GROUPS_CLAIM"`
+			Groups      map[string]string `envconfig:"DRONE_OIDC_GROUPS"`
+		}
+
+		HA struct {
+			Enabled  bool          `envconfig:"DRONE_HA_ENABLED"`
+			ID       string        `envconfig:"DRONE_HA_ID"`
+			LeaseTTL time.Duration `envconfig:"DRONE_HA_LEASE_TTL"`
+		}
+
+		Webhook struct {
+			Secret string `envconfig:"DRONE_WEBHOOK_SECRET"`
+		}
+
+		Purge struct {
+			Interval       time.Duration `envconfig:"DRONE_PURGE_INTERVAL"`
+			StoppedTTL     time.Duration `envconfig:"DRONE_PURGE_STOPPED_TTL"`
+			ErrorTTL       time.Duration `envconfig:"DRONE_PURGE_ERROR_TTL"`
+			Retention      time.Duration `envconfig:"DRONE_PURGE_RETENTION"`
+			ScaleRetention time.Duration `envconfig:"DRONE_PURGE_SCALE_RETENTION"`
+		}
+
 		Database struct {
 			Driver     string `default:"sqlite3"`
 			Datasource string `default:"database.sqlite?cache=shared&mode=rwc&_busy_timeout=9999999"`
+
+			Secret         string   `envconfig:"DRONE_DATABASE_SECRET"`
+			SecretPrevious []string `envconfig:"DRONE_DATABASE_SECRET_PREVIOUS"`
+			SkipMigrate    bool     `envconfig:"DRONE_DATABASE_SKIP_MIGRATE"`
+
+			ReplicaDatasource string `envconfig:"DRONE_DATABASE_REPLICA_DATASOURCE"`
+
+			MaxOpenConns    int           `envconfig:"DRONE_DATABASE_MAX_OPEN_CONNECTIONS"`
+			MaxIdleConns    int           `envconfig:"DRONE_DATABASE_MAX_IDLE_CONNECTIONS"`
+			ConnMaxLifetime time.Duration `envconfig:"DRONE_DATABASE_CONNECTION_MAX_LIFETIME"`
+
+			TLSMode string `envconfig:"DRONE_DATABASE_TLS_MODE"`
+			TLSCA   string `envconfig:"DRONE_DATABASE_TLS_CA"`
+			TLSCert string `envconfig:"DRONE_DATABASE_TLS_CERT"`
+			TLSKey  string `envconfig:"DRONE_DATABASE_TLS_KEY"`
+
+			Auth         string `envconfig:"DRONE_DATABASE_AUTH"`
+			AuthRegion   string `envconfig:"DRONE_DATABASE_AUTH_REGION"`
+			AuthClientID string `envconfig:"DRONE_DATABASE_AUTH_CLIENT_ID"`
+
+			JournalMode string        `envconfig:"DRONE_DATABASE_SQLITE_JOURNAL_MODE"`
+			BusyTimeout time.Duration `envconfig:"DRONE_DATABASE_SQLITE_BUSY_TIMEOUT"`
+			Synchronous string        `envconfig:"DRONE_DATABASE_SQLITE_SYNCHRONOUS"`
 		}
 
 		Amazon struct {
-			DeviceName    string `envconfig:"DRONE_AMAZON_DEVICE_NAME"`
-			Image         string
-			Instance      string
-			PrivateIP     bool `split_words:"true"`
-			Region        string
-			Retries       int
-			SSHKey        string
-			SubnetID      string   `split_words:"true"`
-			SecurityGroup []string `split_words:"true"`
-			Tags          map[string]string
-			UserData      string `envconfig:"DRONE_AMAZON_USERDATA"`
-			UserDataFile  string `envconfig:"DRONE_AMAZON_USERDATA_FILE"`
-			VolumeSize    int64  `envconfig:"DRONE_AMAZON_VOLUME_SIZE"`
-			VolumeType    string `envconfig:"DRONE_AMAZON_VOLUME_TYPE"`
-			IamProfileArn string `envconfig:"DRONE_AMAZON_IAM_PROFILE_ARN"`
-			MarketType    string `envconfig:"DRONE_AMAZON_MARKET_TYPE"`
+			AccessKeyID        string `envconfig:"AWS_ACCESS_KEY_ID"`
+			SecretAccessKey    string `envconfig:"AWS_SECRET_ACCESS_KEY"`
+			SessionToken       string `envconfig:"AWS_SESSION_TOKEN"`
+			IAM                bool   `envconfig:"AWS_IAM"`
+			DeviceName         string `envconfig:"DRONE_AMAZON_DEVICE_NAME"`
+			ElasticIP          bool   `envconfig:"DRONE_AMAZON_ELASTIC_IP"`
+			Image              string
+			ImageName          string            `envconfig:"DRONE_AMAZON_IMAGE_NAME"`
+			ImageOwners        []string          `envconfig:"DRONE_AMAZON_IMAGE_OWNERS"`
+			ImageTags          map[string]string `envconfig:"DRONE_AMAZON_IMAGE_TAGS"`
+			Instance           string
+			Host               string `envconfig:"DRONE_AMAZON_HOST_ID"`
+			HostResourceGroup  string `envconfig:"DRONE_AMAZON_HOST_RESOURCE_GROUP_ARN"`
+			PlacementGroup     string `envconfig:"DRONE_AMAZON_PLACEMENT_GROUP"`
+			Pricing            bool   `envconfig:"DRONE_AMAZON_PRICING"`
+			PrivateIP          bool   `split_words:"true"`
+			Region             string
+			RegionImages       map[string]string `envconfig:"DRONE_AMAZON_REGION_IMAGES"`
+			RegionGroups       map[string]string `envconfig:"DRONE_AMAZON_REGION_SECURITY_GROUPS"`
+			RegionSubnets      map[string]string `envconfig:"DRONE_AMAZON_REGION_SUBNETS"`
+			Retries            int
+			SSHKey             string
+			SubnetID           string   `split_words:"true"`
+			SecurityGroup      []string `split_words:"true"`
+			Tags               map[string]string
+			Tenancy            string `envconfig:"DRONE_AMAZON_TENANCY"`
+			UserData           string `envconfig:"DRONE_AMAZON_USERDATA"`
+			UserDataFile       string `envconfig:"DRONE_AMAZON_USERDATA_FILE"`
+			UserDataPre        string `envconfig:"DRONE_AMAZON_USERDATA_PREPEND"`
+			UserDataPost       string `envconfig:"DRONE_AMAZON_USERDATA_APPEND"`
+			VolumeSize         int64  `envconfig:"DRONE_AMAZON_VOLUME_SIZE"`
+			VolumeType         string `envconfig:"DRONE_AMAZON_VOLUME_TYPE"`
+			VolumeIops         int64  `envconfig:"DRONE_AMAZON_VOLUME_IOPS"`
+			VolumeThroughput   int64  `envconfig:"DRONE_AMAZON_VOLUME_THROUGHPUT"`
+			VolumeEncrypted    bool   `envconfig:"DRONE_AMAZON_VOLUME_ENCRYPTED"`
+			VolumeKMSKey       string `envconfig:"DRONE_AMAZON_VOLUME_KMS_KEY_ID"`
+			DockerVolumeDevice string `envconfig:"DRONE_AMAZON_DOCKER_VOLUME_DEVICE"`
+			DockerVolumeSize   int64  `envconfig:"DRONE_AMAZON_DOCKER_VOLUME_SIZE"`
+			DockerVolumeType   string `envconfig:"DRONE_AMAZON_DOCKER_VOLUME_TYPE"`
+			IamProfileArn      string `envconfig:"DRONE_AMAZON_IAM_PROFILE_ARN"`
+			MarketType         string `envconfig:"DRONE_AMAZON_MARKET_TYPE"`
+
+			SpotAllocationStrategy   string `envconfig:"DRONE_AMAZON_SPOT_ALLOCATION_STRATEGY"`
+			SpotInterruptionBehavior string `envconfig:"DRONE_AMAZON_SPOT_INTERRUPTION_BEHAVIOR"`
+			SpotMaxPrice             string `envconfig:"DRONE_AMAZON_SPOT_MAX_PRICE"`
 		}
 
 		DigitalOcean struct {
 			Token        string
 			Image        string
 			Region       string
+			RegionVPCs   map[string]string `envconfig:"DRONE_DIGITALOCEAN_REGION_VPCS"`
 			SSHKey       string
 			Size         string
 			Tags         []string
+			Firewalls    []string
+			Backups      bool
+			IPv6         bool
+			Monitoring   bool
+			PrivateIP    bool   `split_words:"true"`
+			ReservedIP   bool   `split_words:"true"`
+			Retries      int    `default:"-1"`
+			VPC          string `envconfig:"DRONE_DIGITALOCEAN_VPC"`
 			UserData     string `envconfig:"DRONE_DIGITALOCEAN_USERDATA"`
 			UserDataFile string `envconfig:"DRONE_DIGITALOCEAN_USERDATA_FILE"`
+			UserDataPre  string `envconfig:"DRONE_DIGITALOCEAN_USERDATA_PREPEND"`
+			UserDataPost string `envconfig:"DRONE_DIGITALOCEAN_USERDATA_APPEND"`
 		}
 
 		Google struct {
-			MachineType  string            `envconfig:"DRONE_GOOGLE_MACHINE_TYPE"`
-			MachineImage string            `envconfig:"DRONE_GOOGLE_MACHINE_IMAGE"`
-			Network      string            `envconfig:"DRONE_GOOGLE_NETWORK"`
-			Labels       map[string]string `envconfig:"DRONE_GOOGLE_LABELS"`
-			Scopes       string            `envconfig:"DRONE_GOOGLE_SCOPES"`
-			DiskSize     int64             `envconfig:"DRONE_GOOGLE_DISK_SIZE"`
-			DiskType     string            `envconfig:"DRONE_GOOGLE_DISK_TYPE"`
-			Project      string            `envconfig:"DRONE_GOOGLE_PROJECT"`
-			Tags         []string          `envconfig:"DRONE_GOOGLE_TAGS"`
-			UserData     string            `envconfig:"DRONE_GOOGLE_USERDATA"`
-			UserDataFile string            `envconfig:"DRONE_GOOGLE_USERDATA_FILE"`
-			Zone         string            `envconfig:"DRONE_GOOGLE_ZONE"`
+			MachineType    string            `envconfig:"DRONE_GOOGLE_MACHINE_TYPE"`
+			MachineImage   string            `envconfig:"DRONE_GOOGLE_MACHINE_IMAGE"`
+			ImageFamily    string            `envconfig:"DRONE_GOOGLE_IMAGE_FAMILY"`
+			Accelerator    string            `envconfig:"DRONE_GOOGLE_ACCELERATOR_TYPE"`
+			Accelerators   int64             `envconfig:"DRONE_GOOGLE_ACCELERATOR_COUNT"`
+			CustomFamily   string            `envconfig:"DRONE_GOOGLE_CUSTOM_FAMILY"`
+			CustomCPUs     int64             `envconfig:"DRONE_GOOGLE_CUSTOM_CPUS"`
+			CustomMemory   int64             `envconfig:"DRONE_GOOGLE_CUSTOM_MEMORY"`
+			LocalSSDs      int               `envconfig:"DRONE_GOOGLE_LOCAL_SSD_COUNT"`
+			LocalSSDType   string            `envconfig:"DRONE_GOOGLE_LOCAL_SSD_INTERFACE"`
+			Network        string            `envconfig:"DRONE_GOOGLE_NETWORK"`
+			NodeGroup      string            `envconfig:"DRONE_GOOGLE_NODE_GROUP"`
+			Group          string            `envconfig:"DRONE_GOOGLE_INSTANCE_GROUP"`
+			Region         string            `envconfig:"DRONE_GOOGLE_REGION"`
+			Retries        int               `envconfig:"DRONE_GOOGLE_RETRIES" default:"-1"`
+			Preemptible    bool              `envconfig:"DRONE_GOOGLE_PREEMPTIBLE"`
+			PrivateIP      bool              `envconfig:"DRONE_GOOGLE_PRIVATE_IP"`
+			Spot           bool              `envconfig:"DRONE_GOOGLE_SPOT"`
+			StaticIP       bool              `envconfig:"DRONE_GOOGLE_STATIC_IP"`
+			Termination    string            `envconfig:"DRONE_GOOGLE_TERMINATION_ACTION"`
+			SecureBoot     bool              `envconfig:"DRONE_GOOGLE_SECURE_BOOT"`
+			VTPM           bool              `envconfig:"DRONE_GOOGLE_VTPM"`
+			Integrity      bool              `envconfig:"DRONE_GOOGLE_INTEGRITY_MONITORING"`
+			Confidential   bool              `envconfig:"DRONE_GOOGLE_CONFIDENTIAL_COMPUTE"`
+			Labels         map[string]string `envconfig:"DRONE_GOOGLE_LABELS"`
+			Scopes         string            `envconfig:"DRONE_GOOGLE_SCOPES"`
+			DiskSize       int64             `envconfig:"DRONE_GOOGLE_DISK_SIZE"`
+			DiskType       string            `envconfig:"DRONE_GOOGLE_DISK_TYPE"`
+			DockerDiskSize int64             `envconfig:"DRONE_GOOGLE_DOCKER_DISK_SIZE"`
+			DockerDiskType string            `envconfig:"DRONE_GOOGLE_DOCKER_DISK_TYPE"`
+			Project        string            `envconfig:"DRONE_GOOGLE_PROJECT"`
+			Tags           []string          `envconfig:"DRONE_GOOGLE_TAGS"`
+			UserData       string            `envconfig:"DRONE_GOOGLE_USERDATA"`
+			UserDataFile   string            `envconfig:"DRONE_GOOGLE_USERDATA_FILE"`
+			UserDataPre    string            `envconfig:"DRONE_GOOGLE_USERDATA_PREPEND"`
+			UserDataPost   string            `envconfig:"DRONE_GOOGLE_USERDATA_APPEND"`
+			Zone           string            `envconfig:"DRONE_GOOGLE_ZONE"`
 		}
 
 		HetznerCloud struct {
-			Datacenter   string
-			Image        string
-			SSHKey       int
-			Token        string
-			Type         string
-			UserData     string `envconfig:"DRONE_HETZNERCLOUD_USERDATA"`
-			UserDataFile string `envconfig:"DRONE_HETZNERCLOUD_USERDATA_FILE"`
+			Datacenter     string
+			Image          string
+			SSHKey         int
+			Token          string
+			Type           string
+			Firewalls      []int
+			Locations      []string
+			Networks       []int
+			PlacementGroup int    `split_words:"true"`
+			PrivateIP      bool   `split_words:"true"`
+			FloatingIP     bool   `split_words:"true"`
+			Retries        int    `default:"-1"`
+			UserData       string `envconfig:"DRONE_HETZNERCLOUD_USERDATA"`
+			UserDataFile   string `envconfig:"DRONE_HETZNERCLOUD_USERDATA_FILE"`
+			UserDataPre    string `envconfig:"DRONE_HETZNERCLOUD_USERDATA_PREPEND"`
+			UserDataPost   string `envconfig:"DRONE_HETZNERCLOUD_USERDATA_APPEND"`
 		}
 
 		Packet struct {
@@ -156,19 +407,46 @@ type (
 			SSHKey       string
 			UserData     string `envconfig:"DRONE_PACKET_USERDATA"`
 			UserDataFile string `envconfig:"DRONE_PACKET_USERDATA_FILE"`
+			UserDataPre  string `envconfig:"DRONE_PACKET_USERDATA_PREPEND"`
+			UserDataPost string `envconfig:"DRONE_PACKET_USERDATA_APPEND"`
 			Hostname     string
 		}
 
+		Plugin struct {
+			Path         string
+			Args         []string
+			Environ      map[string]string
+			UserData     string `envconfig:"DRONE_PLUGIN_USERDATA"`
+			UserDataFile string `envconfig:"DRONE_PLUGIN_USERDATA_FILE"`
+			UserDataPre  string `envconfig:"DRONE_PLUGIN_USERDATA_PREPEND"`
+			UserDataPost string `envconfig:"DRONE_PLUGIN_USERDATA_APPEND"`
+		}
+
 		OpenStack struct {
+			AuthURL       string `envconfig:"OS_AUTH_URL"`
+			Username      string `envconfig:"OS_USERNAME"`
+			UserID        string `envconfig:"OS_USERID"`
+			Password      string `envconfig:"OS_PASSWORD"`
+			ProjectID     string `envconfig:"OS_PROJECT_ID"`
+			ProjectName   string `envconfig:"OS_PROJECT_NAME"`
+			TenantID      string `envconfig:"OS_TENANT_ID"`
+			TenantName    string `envconfig:"OS_TENANT_NAME"`
+			DomainID      string `envconfig:"OS_DOMAIN_ID"`
+			DomainName    string `envconfig:"OS_DOMAIN_NAME"`
 			Region        string `envconfig:"OS_REGION_NAME"`
 			Image         string
 			Flavor        string
 			Pool          string   `envconfig:"DRONE_OPENSTACK_IP_POOL"`
 			SecurityGroup []string `split_words:"true"`
 			SSHKey        string
+			ManageGroup   bool   `envconfig:"DRONE_OPENSTACK_MANAGE_SECURITY_GROUP"`
+			ManageCIDR    string `envconfig:"DRONE_OPENSTACK_MANAGE_SECURITY_GROUP_CIDR"`
+			ManageSSHKey  bool   `envconfig:"DRONE_OPENSTACK_MANAGE_SSH_KEY"`
 			Metadata      map[string]string
 			UserData      string `envconfig:"DRONE_OPENSTACK_USERDATA"`
 			UserDataFile  string `envconfig:"DRONE_OPENSTACK_USERDATA_FILE"`
+			UserDataPre   string `envconfig:"DRONE_OPENSTACK_USERDATA_PREPEND"`
+			UserDataPost  string `envconfig:"DRONE_OPENSTACK_USERDATA_APPEND"`
 		}
 	}
 
@@ -177,4 +455,15 @@ type (
 		Devices    string
 		Privileged string
 	}
+
+	// Bytes is a size in bytes, decoded from a human readable
+	// size (e.g. 512MiB or 2GB).
+	Bytes int64
 )
+
+// Decode decodes a human readable size.
+func (b *Bytes) Decode(value string) error {
+	size, err := humanize.ParseBytes(value)
+	*b = Bytes(size)
+	return err
+}
@@ -4,15 +4,48 @@
 
 package config
 
-import "github.com/kelseyhightower/envconfig"
+import (
+	"os"
+	"strings"
+)
+
+// agentPrefix is the prefix of environment variables passed
+// through to the agent container.
+const agentPrefix = "DRONE_AGENT_ENV_"
 
 // Load loads the configuration from the environment.
 func Load() (Config, error) {
+	return loadEnviron(os.Environ())
+}
+
+// helper function loads the configuration from the list of
+// environment variables, in key=value format.
+func loadEnviron(environ []string) (Config, error) {
 	config := Config{}
-	err := envconfig.Process("DRONE", &config)
+	err := process("DRONE", &config, lookupEnviron(environ))
+	config.Agent.Environ = append(config.Agent.Environ, agentEnviron(environ)...)
 	return config, err
 }
 
+// helper function returns the environment variables passed
+// through to the agent container, with the prefix removed.
+// For example, DRONE_AGENT_ENV_DRONE_MEMORY_LIMIT=1g is passed
+// to the agent as DRONE_MEMORY_LIMIT=1g.
+func agentEnviron(environ []string) []string {
+	var envs []string
+	for _, env := range environ {
+		if !strings.HasPrefix(env, agentPrefix) {
+			continue
+		}
+		env = strings.TrimPrefix(env, agentPrefix)
+		if strings.HasPrefix(env, "=") {
+			continue
+		}
+		envs = append(envs, env)
+	}
+	return envs
+}
+
 // MustLoad loads the configuration from the environmnet
 // and panics if an error is encountered.
 func MustLoad() Config {
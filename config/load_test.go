@@ -49,6 +49,9 @@ func TestDefaults(t *testing.T) {
 	if got, want := conf.Agent.Image, "drone/agent:1"; got != want {
 		t.Errorf("Want default DRONE_AGENT_IMAGE of %s, got %s", want, got)
 	}
+	if got, want := conf.Agent.RegistrationCheck, true; got != want {
+		t.Errorf("Want default DRONE_AGENT_REGISTRATION_CHECK of %v, got %v", want, got)
+	}
 }
 
 func TestLoad(t *testing.T) {
@@ -195,7 +198,8 @@ var jsonConfig = []byte(`{
     "Image": "drone/agent:0.8",
     "Concurrency": 2,
     "KeepaliveTime": 360000000000,
-    "KeepaliveTimeout": 30000000000
+    "KeepaliveTimeout": 30000000000,
+    "RegistrationCheck": true
   },
   "HTTP": {
     "Host": "autoscaler.drone.company.com",
@@ -220,6 +224,8 @@ var jsonConfig = []byte(`{
     "Region": "ncy1",
     "SSHKey": "/path/to/ssh/key",
     "Size": "s-1vcpu-1gb",
+    "IPv6": true,
+    "Retries": -1,
     "Tags": [
       "drone",
       "agent",
@@ -258,6 +264,7 @@ var jsonConfig = []byte(`{
     "Scopes": "devstorage.read_only",
     "DiskSize": 10,
     "Project": "project-foo",
+    "Retries": -1,
     "Tags": [
       "drone",
       "agent",
@@ -272,6 +279,7 @@ var jsonConfig = []byte(`{
     "Datacenter": "nbg1-dc3",
     "SSHKey": 12345,
     "Type": "cx11",
+    "Retries": -1,
     "UserData": "#cloud-init",
     "UserDataFile": "/path/to/cloud/init.yml"
   },
@@ -318,3 +326,33 @@ var jsonConfig = []byte(`{
 		"Cache": "10gb"
 	}
 }`)
+
+func TestAgentEnviron(t *testing.T) {
+	got := agentEnviron([]string{
+		"DRONE_AGENT_ENVIRON=FOO=bar",
+		"DRONE_AGENT_ENV_DRONE_MEMORY_LIMIT=1g",
+		"DRONE_AGENT_ENV_DRONE_RUNNER_VOLUMES=/tmp/cache:/cache",
+		"DRONE_AGENT_ENV_=invalid",
+		"DRONE_RUNNER_VOLUMES=ignored",
+	})
+	want := []string{
+		"DRONE_MEMORY_LIMIT=1g",
+		"DRONE_RUNNER_VOLUMES=/tmp/cache:/cache",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want agent environment %v, got %v", want, got)
+	}
+}
+
+func TestBytesDecode(t *testing.T) {
+	var b Bytes
+	if err := b.Decode("512MiB"); err != nil {
+		t.Error(err)
+	}
+	if got, want := int64(b), int64(536870912); got != want {
+		t.Errorf("Want %d bytes, got %d", want, got)
+	}
+	if err := b.Decode("lots"); err == nil {
+		t.Errorf("Want error decoding an invalid size")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// LoadPool loads the configuration of the named pool from the
+// environment. Pool variables, prefixed with DRONE_POOL_<NAME>_,
+// override the global variable of the same name. Hosting
+// provider options are not inherited from the global
+// environment, so that each pool binds to its own provider.
+func LoadPool(name string) (Config, error) {
+	return loadPool(name, os.Environ())
+}
+
+// helper function loads the configuration of the named pool
+// from the list of environment variables.
+func loadPool(name string, environ []string) (Config, error) {
+	if err := checkPoolName(name); err != nil {
+		return Config{}, err
+	}
+	config, err := loadEnviron(poolEnviron(name, environ))
+	config.Pool.Name = name
+	return config, err
+}
+
+// helper function returns the environment of the named pool.
+// Variables with the pool prefix replace the global variable of
+// the same name, and global hosting provider options are removed.
+func poolEnviron(name string, environ []string) []string {
+	schema := Schema()
+	prefix := poolPrefix(name)
+
+	var global, local []string
+	for _, env := range environ {
+		key := strings.SplitN(env, "=", 2)[0]
+		switch {
+		case strings.HasPrefix(key, prefix):
+			local = append(local, "DRONE_"+strings.TrimPrefix(env, prefix))
+		case !isDriverOption(schema, key):
+			global = append(global, env)
+		}
+	}
+	// local variables are appended last so that they take
+	// precedence over global variables of the same name.
+	return append(global, local...)
+}
+
+// helper function returns true if the key is a hosting
+// provider option.
+func isDriverOption(schema map[string][]string, key string) bool {
+	for prefix, known := range schema {
+		if strings.HasPrefix(key, prefix+"_") || contains(known, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns the environment variable prefix of
+// the named pool.
+func poolPrefix(name string) string {
+	return "DRONE_POOL_" + strings.ToUpper(name) + "_"
+}
+
+// helper function returns an error if the environment
+// variable prefix of the named pool matches a global variable,
+// for example, a pool named min matches DRONE_POOL_MIN_AGE,
+// and the global variable would be read as a pool variable.
+func checkPoolName(name string) error {
+	prefix := poolPrefix(name)
+	for _, key := range options("DRONE", reflect.TypeOf(Config{})) {
+		if strings.HasPrefix(key, prefix) {
+			return fmt.Errorf("Invalid pool name %s, conflicts with %s", name, key)
+		}
+	}
+	return nil
+}
+
+// helper function returns the pool names listed in the
+// environment, or nil if multiple pools are not configured.
+func poolNames(environ []string) []string {
+	for _, env := range environ {
+		if !strings.HasPrefix(env, "DRONE_POOLS=") {
+			continue
+		}
+		var names []string
+		for _, name := range strings.Split(strings.TrimPrefix(env, "DRONE_POOLS="), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPoolEnviron(t *testing.T) {
+	environ := []string{
+		"DRONE_POOLS=amd64,arm64",
+		"DRONE_AGENT_IMAGE=drone/agent:1",
+		"DRONE_AMAZON_INSTANCE=t3.medium",
+		"DRONE_HETZNERCLOUD_TOKEN=secret",
+		"DRONE_POOL_ARM64_AGENT_ARCH=arm64",
+		"DRONE_POOL_ARM64_AMAZON_INSTANCE=t4g.medium",
+		"DRONE_POOL_AMD64_HETZNERCLOUD_TOKEN=secret",
+	}
+	got := poolEnviron("arm64", environ)
+	want := []string{
+		"DRONE_POOLS=amd64,arm64",
+		"DRONE_AGENT_IMAGE=drone/agent:1",
+		"DRONE_POOL_AMD64_HETZNERCLOUD_TOKEN=secret",
+		"DRONE_AGENT_ARCH=arm64",
+		"DRONE_AMAZON_INSTANCE=t4g.medium",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want pool environment %v, got %v", want, got)
+	}
+}
+
+func TestPoolNames(t *testing.T) {
+	environ := []string{
+		"DRONE_POOL_MIN=1",
+		"DRONE_POOLS=amd64, arm64",
+	}
+	got, want := poolNames(environ), []string{"amd64", "arm64"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want pool names %v, got %v", want, got)
+	}
+	if got := poolNames(nil); got != nil {
+		t.Errorf("Want no pool names, got %v", got)
+	}
+}
+
+func TestLoadPool(t *testing.T) {
+	environ := []string{
+		"DRONE_POOLS=arm64",
+		"DRONE_AGENT_IMAGE=drone/agent:1.6",
+		"DRONE_AMAZON_INSTANCE=t3.medium",
+		"DRONE_POOL_ARM64_AGENT_ARCH=arm64",
+		"DRONE_POOL_ARM64_POOL_MAX=8",
+		"DRONE_POOL_ARM64_HETZNERCLOUD_TOKEN=secret",
+	}
+
+	config, err := loadPool("arm64", environ)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := config.Pool.Name, "arm64"; got != want {
+		t.Errorf("Want pool name %q, got %q", want, got)
+	}
+	if got, want := config.Pool.Max, 8; got != want {
+		t.Errorf("Want pool max %d, got %d", want, got)
+	}
+	if got, want := config.Agent.Arch, "arm64"; got != want {
+		t.Errorf("Want agent arch %q, got %q", want, got)
+	}
+	if got, want := config.Agent.Image, "drone/agent:1.6"; got != want {
+		t.Errorf("Want inherited agent image %q, got %q", want, got)
+	}
+	if got, want := config.HetznerCloud.Token, "secret"; got != want {
+		t.Errorf("Want hetznercloud token %q, got %q", want, got)
+	}
+	if got := config.Amazon.Instance; got != "" {
+		t.Errorf("Want global amazon options ignored, got instance %q", got)
+	}
+}
+
+func TestLoadPool_AgentImage(t *testing.T) {
+	environ := []string{
+		"DRONE_POOLS=linux,macos",
+		"DRONE_AGENT_IMAGE=drone/drone-runner-docker:1",
+		"DRONE_POOL_MACOS_AGENT_IMAGE=drone/drone-runner-exec:1.0.0-beta.9",
+		"DRONE_POOL_MACOS_AGENT_OS=darwin",
+		"DRONE_POOL_MACOS_AGENT_VERSION=1.0.0-beta.9",
+	}
+
+	linux, err := loadPool("linux", environ)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	macos, err := loadPool("macos", environ)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := linux.Agent.Image, "drone/drone-runner-docker:1"; got != want {
+		t.Errorf("Want linux agent image %q, got %q", want, got)
+	}
+	if got, want := macos.Agent.Image, "drone/drone-runner-exec:1.0.0-beta.9"; got != want {
+		t.Errorf("Want macos agent image %q, got %q", want, got)
+	}
+	if got, want := macos.Agent.Version, "1.0.0-beta.9"; got != want {
+		t.Errorf("Want macos agent version %q, got %q", want, got)
+	}
+	if got := linux.Agent.Version; got != "" {
+		t.Errorf("Want linux agent version unset, got %q", got)
+	}
+}
+
+func TestLoadPool_Credentials(t *testing.T) {
+	environ := []string{
+		"DRONE_POOLS=arm64",
+		"AWS_ACCESS_KEY_ID=global",
+		"OS_USERNAME=global",
+		"DRONE_POOL_ARM64_AMAZON_AWS_ACCESS_KEY_ID=arm64",
+		"DRONE_POOL_ARM64_AMAZON_AWS_SECRET_ACCESS_KEY=secret",
+	}
+	config, err := loadPool("arm64", environ)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := config.Amazon.AccessKeyID, "arm64"; got != want {
+		t.Errorf("Want pool access key id %q, got %q", want, got)
+	}
+	if got, want := config.Amazon.SecretAccessKey, "secret"; got != want {
+		t.Errorf("Want pool secret access key %q, got %q", want, got)
+	}
+	if got := config.OpenStack.Username; got != "" {
+		t.Errorf("Want global openstack credentials ignored, got username %q", got)
+	}
+}
+
+func TestLoadPool_InvalidName(t *testing.T) {
+	if _, err := loadPool("min", nil); err == nil {
+		t.Errorf("Want error for a pool name matching a global option")
+	}
+	if _, err := loadPool("arm64", nil); err != nil {
+		t.Errorf("Want valid pool name accepted, got %s", err)
+	}
+}
+
+func TestCheck_Pool(t *testing.T) {
+	environ := []string{
+		"DRONE_POOLS=arm64",
+		"DRONE_POOL_ARM64_AMAZON_SUBNET_ID=subnet-0b32177f",
+		"DRONE_POOL_ARM64_AMAZON_SUBNET=subnet-0b32177f",
+	}
+	err := Check(environ)
+	if err == nil {
+		t.Errorf("Want unknown pool options rejected")
+		return
+	}
+	unknown := err.(*ErrUnknown)
+	if got, want := unknown.Options, []string{"DRONE_POOL_ARM64_AMAZON_SUBNET"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want unknown options %v, got %v", want, got)
+	}
+	if got, want := unknown.Suggest["DRONE_POOL_ARM64_AMAZON_SUBNET"], "DRONE_POOL_ARM64_AMAZON_SUBNET_ID"; got != want {
+		t.Errorf("Want suggestion %q, got %q", want, got)
+	}
+}
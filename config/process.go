@@ -0,0 +1,194 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// lookupFunc returns the value of the named variable, and
+// reports whether the variable is set.
+type lookupFunc func(key string) (string, bool)
+
+// helper function returns a lookup function for the list of
+// environment variables, in key=value format. Later variables
+// take precedence over earlier variables of the same name.
+func lookupEnviron(environ []string) lookupFunc {
+	vars := map[string]string{}
+	for _, env := range environ {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) == 2 {
+			vars[parts[0]] = parts[1]
+		}
+	}
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+// helper function populates the struct from the variables
+// returned by the lookup function. The variables are named and
+// decoded with the same rules as envconfig.Process, which reads
+// the process environment and cannot be given the variables of
+// a single pool.
+func process(prefix string, spec interface{}, lookup lookupFunc) error {
+	v := reflect.ValueOf(spec)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return envconfig.ErrInvalidSpecification
+	}
+	return processStruct(prefix, v.Elem(), lookup)
+}
+
+// helper function populates the struct fields from the
+// variables returned by the lookup function.
+func processStruct(prefix string, s reflect.Value, lookup lookupFunc) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		field, ftype := s.Field(i), t.Field(i)
+		if !field.CanSet() || ftype.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		alt := strings.ToUpper(ftype.Tag.Get("envconfig"))
+		key := ftype.Name
+		if ftype.Tag.Get("split_words") == "true" {
+			key = splitWords(ftype.Name)
+		}
+		if alt != "" {
+			key = alt
+		}
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		key = strings.ToUpper(key)
+
+		if field.Kind() == reflect.Struct && decoderFrom(field) == nil {
+			inner := key
+			if ftype.Anonymous {
+				inner = prefix
+			}
+			if err := processStruct(inner, field, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := lookup(key)
+		if !ok && alt != "" {
+			value, ok = lookup(alt)
+		}
+		def := ftype.Tag.Get("default")
+		if !ok && def != "" {
+			value = def
+		}
+		if !ok && def == "" {
+			if ftype.Tag.Get("required") == "true" {
+				return fmt.Errorf("required key %s missing value", key)
+			}
+			continue
+		}
+
+		if err := processField(value, field); err != nil {
+			return &envconfig.ParseError{
+				KeyName:   key,
+				FieldName: ftype.Name,
+				TypeName:  field.Type().String(),
+				Value:     value,
+				Err:       err,
+			}
+		}
+	}
+	return nil
+}
+
+// helper function decodes the value to the field.
+func processField(value string, field reflect.Value) error {
+	if decoder := decoderFrom(field); decoder != nil {
+		return decoder.Decode(value)
+	}
+
+	typ := field.Type()
+	switch typ.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var val int64
+		var err error
+		if typ == reflect.TypeOf(time.Duration(0)) {
+			var d time.Duration
+			d, err = time.ParseDuration(value)
+			val = int64(d)
+		} else {
+			val, err = strconv.ParseInt(value, 0, typ.Bits())
+		}
+		if err != nil {
+			return err
+		}
+		field.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(value, 0, typ.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(val)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(value, typ.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(val)
+	case reflect.Slice:
+		vals := strings.Split(value, ",")
+		sl := reflect.MakeSlice(typ, len(vals), len(vals))
+		for i, val := range vals {
+			if err := processField(val, sl.Index(i)); err != nil {
+				return err
+			}
+		}
+		field.Set(sl)
+	case reflect.Map:
+		mp := reflect.MakeMap(typ)
+		for _, pair := range strings.Split(value, ",") {
+			kv := strings.Split(pair, ":")
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid map item: %q", pair)
+			}
+			k := reflect.New(typ.Key()).Elem()
+			if err := processField(kv[0], k); err != nil {
+				return err
+			}
+			v := reflect.New(typ.Elem()).Elem()
+			if err := processField(kv[1], v); err != nil {
+				return err
+			}
+			mp.SetMapIndex(k, v)
+		}
+		field.Set(mp)
+	}
+	return nil
+}
+
+// helper function returns the decoder implemented by the
+// field, or nil if the field is not a decoder.
+func decoderFrom(field reflect.Value) envconfig.Decoder {
+	if !field.CanAddr() {
+		return nil
+	}
+	decoder, _ := field.Addr().Interface().(envconfig.Decoder)
+	return decoder
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProcess(t *testing.T) {
+	var spec struct {
+		Name     string
+		Region   string `envconfig:"DRONE_REGION"`
+		Retries  int    `default:"-1"`
+		Interval time.Duration
+		Size     Bytes
+		Tags     []string
+		Labels   map[string]string
+		Private  struct {
+			Subnet string `split_words:"true"`
+			SkipIP bool   `split_words:"true"`
+		}
+		Ignored string `ignored:"true"`
+	}
+	environ := []string{
+		"DRONE_NAME=agent",
+		"DRONE_REGION=us-east-1",
+		"DRONE_INTERVAL=5m",
+		"DRONE_SIZE=1kb",
+		"DRONE_TAGS=drone,agent",
+		"DRONE_LABELS=os:linux,arch:amd64",
+		"DRONE_PRIVATE_SKIP_IP=true",
+		"DRONE_IGNORED=ignored",
+	}
+	if err := process("DRONE", &spec, lookupEnviron(environ)); err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := spec.Name, "agent"; got != want {
+		t.Errorf("Want name %q, got %q", want, got)
+	}
+	if got, want := spec.Region, "us-east-1"; got != want {
+		t.Errorf("Want region %q from the alternate name, got %q", want, got)
+	}
+	if got, want := spec.Retries, -1; got != want {
+		t.Errorf("Want default retries %d, got %d", want, got)
+	}
+	if got, want := spec.Interval, 5*time.Minute; got != want {
+		t.Errorf("Want interval %v, got %v", want, got)
+	}
+	if got, want := spec.Size, Bytes(1000); got != want {
+		t.Errorf("Want decoded size %d, got %d", want, got)
+	}
+	if got, want := spec.Tags, []string{"drone", "agent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want tags %v, got %v", want, got)
+	}
+	if got, want := spec.Labels, map[string]string{"os": "linux", "arch": "amd64"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want labels %v, got %v", want, got)
+	}
+	if got, want := spec.Private.SkipIP, true; got != want {
+		t.Errorf("Want nested split words option %v, got %v", want, got)
+	}
+	if got := spec.Ignored; got != "" {
+		t.Errorf("Want ignored field unset, got %q", got)
+	}
+}
+
+func TestProcess_ParseError(t *testing.T) {
+	var spec struct {
+		Retries int
+	}
+	err := process("DRONE", &spec, lookupEnviron([]string{"DRONE_RETRIES=five"}))
+	if err == nil {
+		t.Errorf("Want parse error")
+	}
+}
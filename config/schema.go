@@ -0,0 +1,207 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// drivers lists the configuration sections of the hosting
+// providers. Environment variables with the section prefix
+// must match a known option.
+var drivers = []string{
+	"Amazon",
+	"DigitalOcean",
+	"Google",
+	"HetznerCloud",
+	"OpenStack",
+	"Packet",
+	"Plugin",
+}
+
+// ErrUnknown is returned when the environment includes
+// options that are not defined by the driver schema.
+type ErrUnknown struct {
+	Options []string
+	Suggest map[string]string
+}
+
+func (e *ErrUnknown) Error() string {
+	var parts []string
+	for _, option := range e.Options {
+		if s, ok := e.Suggest[option]; ok {
+			parts = append(parts, fmt.Sprintf("%s (did you mean %s?)", option, s))
+		} else {
+			parts = append(parts, option)
+		}
+	}
+	return "Unknown configuration options: " + strings.Join(parts, ", ")
+}
+
+// Schema returns the environment variables accepted by each
+// hosting provider, keyed by the driver environment prefix.
+// The variable names follow the same rules used to load the
+// configuration.
+func Schema() map[string][]string {
+	t := reflect.TypeOf(Config{})
+	schema := map[string][]string{}
+	for _, name := range drivers {
+		field, ok := t.FieldByName(name)
+		if !ok {
+			continue
+		}
+		prefix := "DRONE_" + strings.ToUpper(name)
+		schema[prefix] = options(prefix, field.Type)
+	}
+	return schema
+}
+
+// Check returns an error if the environment includes driver
+// options that are not defined by the driver schema, which
+// would otherwise be silently ignored.
+func Check(environ []string) error {
+	schema := Schema()
+	pools := poolNames(environ)
+	err := &ErrUnknown{Suggest: map[string]string{}}
+	for _, env := range environ {
+		key := strings.SplitN(env, "=", 2)[0]
+		// pool options are checked against the global option
+		// of the same name, and reported using the pool name.
+		name, pool := key, ""
+		for _, p := range pools {
+			if strings.HasPrefix(key, poolPrefix(p)) {
+				name = "DRONE_" + strings.TrimPrefix(key, poolPrefix(p))
+				pool = poolPrefix(p)
+			}
+		}
+		for prefix, known := range schema {
+			if !strings.HasPrefix(name, prefix+"_") || contains(known, name) {
+				continue
+			}
+			err.Options = append(err.Options, key)
+			if s := suggest(name, known); s != "" {
+				if pool != "" {
+					s = pool + strings.TrimPrefix(s, "DRONE_")
+				}
+				err.Suggest[key] = s
+			}
+		}
+	}
+	if len(err.Options) == 0 {
+		return nil
+	}
+	sort.Strings(err.Options)
+	return err
+}
+
+var gatherRegexp = regexp.MustCompile("([^A-Z]+|[A-Z][^A-Z]+|[A-Z]+)")
+
+// helper function returns the environment variable names of
+// the struct fields, using the names looked up by the loader.
+// Fields are named using the prefix and the upper case field
+// name, split into words if the split_words tag is set. The
+// envconfig tag replaces the field name, and the tag itself is
+// also looked up if the prefixed name is not set.
+func options(prefix string, t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		name := field.Name
+		if field.Tag.Get("split_words") == "true" {
+			name = splitWords(name)
+		}
+		alt := strings.ToUpper(field.Tag.Get("envconfig"))
+		if alt != "" {
+			name = alt
+		}
+		key := strings.ToUpper(prefix + "_" + name)
+
+		if field.Type.Kind() == reflect.Struct {
+			inner := key
+			if field.Anonymous {
+				inner = prefix
+			}
+			keys = append(keys, options(inner, field.Type)...)
+			continue
+		}
+
+		keys = append(keys, key)
+		if alt != "" {
+			keys = append(keys, alt)
+		}
+	}
+	return keys
+}
+
+// helper function splits the camel case name into words
+// separated by underscores. Consecutive upper case letters
+// are kept in a single word, which matches the names looked
+// up by envconfig.
+func splitWords(name string) string {
+	return strings.Join(gatherRegexp.FindAllString(name, -1), "_")
+}
+
+// helper function returns the known option closest to the
+// unknown option, or an empty string if no option is close.
+func suggest(key string, known []string) string {
+	best, min := "", 4
+	for _, option := range known {
+		if d := distance(key, option); d < min {
+			best, min = option, d
+		}
+	}
+	return best
+}
+
+// helper function returns the levenshtein edit distance
+// between the two strings.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// helper function returns the smallest value.
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// helper function returns true if the list includes the item.
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
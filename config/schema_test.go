@@ -0,0 +1,115 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+	tests := []struct {
+		prefix string
+		key    string
+	}{
+		{"DRONE_AMAZON", "DRONE_AMAZON_SUBNET_ID"},
+		{"DRONE_AMAZON", "DRONE_AMAZON_SSHKEY"},
+		{"DRONE_AMAZON", "DRONE_AMAZON_SPOT_MAX_PRICE"},
+		{"DRONE_GOOGLE", "DRONE_GOOGLE_MACHINE_TYPE"},
+		{"DRONE_HETZNERCLOUD", "DRONE_HETZNERCLOUD_PLACEMENT_GROUP"},
+		{"DRONE_OPENSTACK", "OS_REGION_NAME"},
+		{"DRONE_OPENSTACK", "DRONE_OPENSTACK_IP_POOL"},
+		{"DRONE_OPENSTACK", "DRONE_OPENSTACK_MANAGE_SSH_KEY"},
+		{"DRONE_PACKET", "DRONE_PACKET_PROJECT_ID"},
+	}
+	for _, test := range tests {
+		if !contains(schema[test.prefix], test.key) {
+			t.Errorf("Want %s in the %s schema", test.key, test.prefix)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	environ := []string{
+		"DRONE_AMAZON_SUBNET_ID=subnet-0b32177f",
+		"DRONE_AMAZON_REGION=us-east-2",
+		"DRONE_SERVER_HOST=drone.company.com",
+		"DRONE_RPC_SECRET=correct-horse-battery-staple",
+		"PATH=/usr/bin",
+	}
+	if err := Check(environ); err != nil {
+		t.Errorf("Want known options accepted, got %s", err)
+	}
+}
+
+func TestCheck_Unknown(t *testing.T) {
+	environ := []string{
+		"DRONE_AMAZON_SUBNET=subnet-0b32177f",
+		"DRONE_GOOGLE_SUBNETWORK=default",
+		"DRONE_AMAZON_REGION=us-east-2",
+	}
+	err := Check(environ)
+	if err == nil {
+		t.Errorf("Want unknown options rejected")
+		return
+	}
+	unknown, ok := err.(*ErrUnknown)
+	if !ok {
+		t.Errorf("Want ErrUnknown, got %T", err)
+		return
+	}
+	if got, want := len(unknown.Options), 2; got != want {
+		t.Errorf("Want %d unknown options, got %d", want, got)
+	}
+	if got, want := unknown.Suggest["DRONE_AMAZON_SUBNET"], "DRONE_AMAZON_SUBNET_ID"; got != want {
+		t.Errorf("Want suggestion %s, got %q", want, got)
+	}
+	if got, want := err.Error(), "Unknown configuration options: DRONE_AMAZON_SUBNET (did you mean DRONE_AMAZON_SUBNET_ID?), DRONE_GOOGLE_SUBNETWORK (did you mean DRONE_GOOGLE_NETWORK?)"; got != want {
+		t.Errorf("Want error %q, got %q", want, got)
+	}
+}
+
+func TestCheck_Alternate(t *testing.T) {
+	// fields with an alternate name are not loaded from the
+	// prefixed field name.
+	environ := []string{
+		"DRONE_AMAZON_HOST=h-0b32177f",
+		"DRONE_GOOGLE_NODEGROUP=default",
+		"DRONE_AMAZON_HOST_ID=h-0b32177f",
+		"DRONE_GOOGLE_NODE_GROUP=default",
+	}
+	err := Check(environ)
+	if err == nil {
+		t.Errorf("Want options with an alternate name rejected")
+		return
+	}
+	unknown, ok := err.(*ErrUnknown)
+	if !ok {
+		t.Errorf("Want ErrUnknown, got %T", err)
+		return
+	}
+	if got, want := unknown.Options, []string{"DRONE_AMAZON_HOST", "DRONE_GOOGLE_NODEGROUP"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want unknown options %v, got %v", want, got)
+	}
+	if got, want := unknown.Suggest["DRONE_AMAZON_HOST"], "DRONE_AMAZON_HOST_ID"; got != want {
+		t.Errorf("Want suggestion %s, got %q", want, got)
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := map[string]string{
+		"SubnetID":       "Subnet_ID",
+		"PrivateIP":      "Private_IP",
+		"PlacementGroup": "Placement_Group",
+		"MinAge":         "Min_Age",
+		"SSHKey":         "SSHK_ey",
+	}
+	for name, want := range tests {
+		if got := splitWords(name); got != want {
+			t.Errorf("Want %s split to %s, got %s", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/rs/zerolog/log"
+)
+
+// helper function allocates an elastic ip address, associates
+// it with the instance, and returns the public address.
+func (p *provider) associateAddress(ctx context.Context, client *ec2.EC2, instanceID, name string) (string, error) {
+	logger := log.Ctx(ctx).With().
+		Str("id", instanceID).
+		Str("name", name).
+		Logger()
+
+	address, err := client.AllocateAddressWithContext(ctx, &ec2.AllocateAddressInput{
+		Domain: aws.String(ec2.DomainTypeVpc),
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot allocate elastic ip")
+		return "", err
+	}
+
+	tags := createCopy(p.tags)
+	tags["Name"] = name
+	_, err = client.CreateTagsWithContext(ctx, &ec2.CreateTagsInput{
+		Resources: []*string{address.AllocationId},
+		Tags:      convertTags(tags),
+	})
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("cannot tag elastic ip")
+	}
+
+	_, err = client.AssociateAddressWithContext(ctx, &ec2.AssociateAddressInput{
+		AllocationId: address.AllocationId,
+		InstanceId:   aws.String(instanceID),
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot associate elastic ip")
+
+		// release the unassociated address to prevent
+		// leaking elastic ips.
+		client.ReleaseAddressWithContext(ctx, &ec2.ReleaseAddressInput{
+			AllocationId: address.AllocationId,
+		})
+		return "", err
+	}
+
+	logger.Debug().
+		Str("ip", aws.StringValue(address.PublicIp)).
+		Msg("elastic ip associated")
+
+	return aws.StringValue(address.PublicIp), nil
+}
+
+// helper function disassociates and releases the elastic ip
+// addresses associated with the instance.
+func (p *provider) releaseAddresses(ctx context.Context, client *ec2.EC2, instanceID string) error {
+	out, err := client.DescribeAddressesWithContext(ctx, &ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: aws.StringSlice([]string{instanceID}),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, address := range out.Addresses {
+		if address.AssociationId != nil {
+			_, err = client.DisassociateAddressWithContext(ctx, &ec2.DisassociateAddressInput{
+				AssociationId: address.AssociationId,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		_, err = client.ReleaseAddressWithContext(ctx, &ec2.ReleaseAddressInput{
+			AllocationId: address.AllocationId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
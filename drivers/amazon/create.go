@@ -22,13 +22,37 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		p.setup(ctx)
 	})
 
+	if p.dockerVolumeSize != 0 {
+		opts.Volume = p.dockerVolumeDevice
+	}
+
 	buf := new(bytes.Buffer)
 	err := p.userdata.Execute(buf, &opts)
 	if err != nil {
 		return nil, err
 	}
 
-	client := p.getClient()
+	region := p.region
+	if opts.Region != "" {
+		region = opts.Region
+	}
+
+	client := p.getRegionClient(region)
+
+	image, err := p.regionImage(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	subnet, groups, err := p.regionNetwork(region)
+	if err != nil {
+		return nil, err
+	}
+
+	size := p.size
+	if opts.Size != "" {
+		size = opts.Size
+	}
 
 	var iamProfile *ec2.IamInstanceProfileSpecification
 
@@ -42,7 +66,8 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 
 	if p.spotInstance == true {
 		marketOptions = &ec2.InstanceMarketOptionsRequest{
-			MarketType: aws.String("spot"),
+			MarketType:  aws.String("spot"),
+			SpotOptions: p.spotOptions(),
 		}
 	}
 
@@ -51,19 +76,19 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 
 	in := &ec2.RunInstancesInput{
 		KeyName:               aws.String(p.key),
-		ImageId:               aws.String(p.image),
-		InstanceType:          aws.String(p.size),
+		ImageId:               aws.String(image),
+		InstanceType:          aws.String(size),
 		MinCount:              aws.Int64(1),
 		MaxCount:              aws.Int64(1),
 		InstanceMarketOptions: marketOptions,
 		IamInstanceProfile:    iamProfile,
-		UserData:              aws.String(base64.StdEncoding.EncodeToString(buf.Bytes())),
+		UserData:              aws.String(base64.StdEncoding.EncodeToString(wrapUserdata(opts.OS, buf.Bytes()))),
 		NetworkInterfaces: []*ec2.InstanceNetworkInterfaceSpecification{
 			{
 				AssociatePublicIpAddress: aws.Bool(!p.privateIP),
 				DeviceIndex:              aws.Int64(0),
-				SubnetId:                 aws.String(p.subnet),
-				Groups:                   aws.StringSlice(p.groups),
+				SubnetId:                 aws.String(subnet),
+				Groups:                   aws.StringSlice(groups),
 			},
 		},
 		TagSpecifications: []*ec2.TagSpecification{
@@ -72,38 +97,42 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 				Tags:         convertTags(tags),
 			},
 		},
-		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
-			{
-				DeviceName: aws.String(p.deviceName),
-				Ebs: &ec2.EbsBlockDevice{
-					VolumeSize:          aws.Int64(p.volumeSize),
-					VolumeType:          aws.String(p.volumeType),
-					DeleteOnTermination: aws.Bool(true),
-				},
-			},
-		},
+		BlockDeviceMappings: p.blockDeviceMappings(),
+		Placement:           p.placement(),
 	}
 
 	logger := log.Ctx(ctx).With().
-		Str("region", p.region).
-		Str("image", p.image).
-		Str("size", p.size).
+		Str("region", region).
+		Str("image", image).
+		Str("size", size).
 		Str("name", opts.Name).
 		Logger()
 
 	logger.Debug().
 		Msg("instance create")
 
-	results, err := client.RunInstances(in)
+	var amazonInstance *ec2.Instance
+	if p.spotInstance && p.spotStrategy != "" {
+		// spot allocation strategies are only supported by the
+		// fleet api, which requires a launch template.
+		amazonInstance, err = p.createFleet(ctx, client, opts.Name, in)
+	} else {
+		var results *ec2.Reservation
+		results, err = client.RunInstances(in)
+		if err == nil {
+			amazonInstance = results.Instances[0]
+		}
+	}
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Msg("instance create failed")
+		if isCapacityError(err) {
+			return nil, autoscaler.ErrInsufficientCapacity
+		}
 		return nil, err
 	}
 
-	amazonInstance := results.Instances[0]
-
 	instance := &autoscaler.Instance{
 		Provider: autoscaler.ProviderAmazon,
 		ID:       *amazonInstance.InstanceId,
@@ -111,6 +140,7 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		Size:     *amazonInstance.InstanceType,
 		Region:   *amazonInstance.Placement.AvailabilityZone,
 		Image:    *amazonInstance.ImageId,
+		Price:    p.price(ctx, client, amazonInstance, opts.OS),
 	}
 
 	logger.Info().
@@ -165,6 +195,16 @@ poller:
 		}
 	}
 
+	// associate an elastic ip with the instance, so that
+	// the instance address can be allow-listed.
+	if p.elasticIP && !p.privateIP {
+		address, err := p.associateAddress(ctx, client, instance.ID, opts.Name)
+		if err != nil {
+			return instance, err
+		}
+		instance.Address = address
+	}
+
 	logger.Debug().
 		Str("name", instance.Name).
 		Str("ip", instance.Address).
@@ -172,3 +212,54 @@ poller:
 
 	return instance, nil
 }
+
+// helper function returns the block device mappings for the
+// root volume and the optional docker volume.
+func (p *provider) blockDeviceMappings() []*ec2.BlockDeviceMapping {
+	mappings := []*ec2.BlockDeviceMapping{
+		{
+			DeviceName: aws.String(p.deviceName),
+			Ebs: &ec2.EbsBlockDevice{
+				VolumeSize:          aws.Int64(p.volumeSize),
+				VolumeType:          aws.String(p.volumeType),
+				Iops:                int64OrNil(p.volumeIops),
+				Throughput:          int64OrNil(p.volumeThroughput),
+				Encrypted:           p.encrypted(),
+				KmsKeyId:            stringOrNil(p.volumeKMSKey),
+				DeleteOnTermination: aws.Bool(true),
+			},
+		},
+	}
+	if p.dockerVolumeSize != 0 {
+		mappings = append(mappings, &ec2.BlockDeviceMapping{
+			DeviceName: aws.String(p.dockerVolumeDevice),
+			Ebs: &ec2.EbsBlockDevice{
+				VolumeSize:          aws.Int64(p.dockerVolumeSize),
+				VolumeType:          aws.String(p.dockerVolumeType),
+				Encrypted:           p.encrypted(),
+				KmsKeyId:            stringOrNil(p.volumeKMSKey),
+				DeleteOnTermination: aws.Bool(true),
+			},
+		})
+	}
+	return mappings
+}
+
+// helper function returns true if volumes are encrypted, or
+// nil to use the account default encryption. Volumes are
+// always encrypted when a kms key is configured.
+func (p *provider) encrypted() *bool {
+	if p.volumeEncrypted || p.volumeKMSKey != "" {
+		return aws.Bool(true)
+	}
+	return nil
+}
+
+// helper function returns an int64 pointer, or nil if the
+// value is zero.
+func int64OrNil(v int64) *int64 {
+	if v == 0 {
+		return nil
+	}
+	return aws.Int64(v)
+}
@@ -26,12 +26,24 @@ func (p *provider) Destroy(ctx context.Context, instance *autoscaler.Instance) e
 	logger.Debug().
 		Msg("terminate instsance")
 
+	client := p.getRegionClient(p.instanceRegion(instance))
+
+	// the elastic ip must be released before the instance is
+	// terminated, while the association can still be found.
+	if p.elasticIP {
+		if err := p.releaseAddresses(ctx, client, instance.ID); err != nil {
+			logger.Warn().
+				Err(err).
+				Msg("cannot release elastic ip")
+		}
+	}
+
 	input := &ec2.TerminateInstancesInput{
 		InstanceIds: []*string{
 			aws.String(instance.ID),
 		},
 	}
-	_, err := p.getClient().TerminateInstances(input)
+	_, err := client.TerminateInstances(input)
 	if awsErr, ok := err.(awserr.Error); ok {
 		switch awsErr.Code() {
 		case ec2.UnsuccessfulInstanceCreditSpecificationErrorCodeInvalidInstanceIdMalformed:
@@ -0,0 +1,185 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/rs/zerolog/log"
+)
+
+// helper function returns the spot market options for
+// instances created with the run instances api.
+func (p *provider) spotOptions() *ec2.SpotMarketOptions {
+	if p.spotInterruption == "" && p.spotMaxPrice == "" {
+		return nil
+	}
+	opts := new(ec2.SpotMarketOptions)
+	if p.spotMaxPrice != "" {
+		opts.MaxPrice = aws.String(p.spotMaxPrice)
+	}
+	if p.spotInterruption != "" {
+		opts.InstanceInterruptionBehavior = aws.String(p.spotInterruption)
+		// stop and hibernate interruption behaviors are only
+		// supported for persistent spot requests.
+		if p.spotInterruption != ec2.InstanceInterruptionBehaviorTerminate {
+			opts.SpotInstanceType = aws.String(ec2.SpotInstanceTypePersistent)
+		}
+	}
+	return opts
+}
+
+// helper function creates a single spot instance using an
+// instant ec2 fleet so that the configured allocation strategy
+// is used to select the spot pool. The fleet api requires a
+// launch template, which is created from the run instances
+// input and removed once the fleet request completes.
+func (p *provider) createFleet(ctx context.Context, client *ec2.EC2, name string, in *ec2.RunInstancesInput) (*ec2.Instance, error) {
+	logger := log.Ctx(ctx)
+
+	template, err := client.CreateLaunchTemplateWithContext(ctx, &ec2.CreateLaunchTemplateInput{
+		LaunchTemplateName: aws.String(name),
+		LaunchTemplateData: launchTemplateData(in),
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot create launch template")
+		return nil, err
+	}
+
+	defer func() {
+		_, err := client.DeleteLaunchTemplate(&ec2.DeleteLaunchTemplateInput{
+			LaunchTemplateId: template.LaunchTemplate.LaunchTemplateId,
+		})
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("template", name).
+				Msg("cannot delete launch template")
+		}
+	}()
+
+	overrides := []*ec2.FleetLaunchTemplateOverridesRequest{
+		{
+			InstanceType: in.InstanceType,
+			SubnetId:     in.NetworkInterfaces[0].SubnetId,
+		},
+	}
+
+	out, err := client.CreateFleetWithContext(ctx, &ec2.CreateFleetInput{
+		Type: aws.String(ec2.FleetTypeInstant),
+		LaunchTemplateConfigs: []*ec2.FleetLaunchTemplateConfigRequest{
+			{
+				LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecificationRequest{
+					LaunchTemplateId: template.LaunchTemplate.LaunchTemplateId,
+					Version:          aws.String("$Latest"),
+				},
+				Overrides: overrides,
+			},
+		},
+		TargetCapacitySpecification: &ec2.TargetCapacitySpecificationRequest{
+			TotalTargetCapacity:       aws.Int64(1),
+			DefaultTargetCapacityType: aws.String(ec2.DefaultTargetCapacityTypeSpot),
+		},
+		SpotOptions: &ec2.SpotOptionsRequest{
+			AllocationStrategy:           aws.String(p.spotStrategy),
+			InstanceInterruptionBehavior: stringOrNil(p.spotInterruption),
+			MaxTotalPrice:                stringOrNil(p.spotMaxPrice),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []*string
+	for _, instance := range out.Instances {
+		ids = append(ids, instance.InstanceIds...)
+	}
+	if len(ids) == 0 {
+		if len(out.Errors) != 0 {
+			if aws.StringValue(out.Errors[0].ErrorCode) == errCodeInsufficientCapacity {
+				return nil, autoscaler.ErrInsufficientCapacity
+			}
+			return nil, errors.New(aws.StringValue(out.Errors[0].ErrorMessage))
+		}
+		return nil, errors.New("Fleet request did not launch an instance")
+	}
+
+	desc, err := client.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: ids[:1],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return desc.Reservations[0].Instances[0], nil
+}
+
+// helper function converts the run instances input to launch
+// template data.
+func launchTemplateData(in *ec2.RunInstancesInput) *ec2.RequestLaunchTemplateData {
+	data := &ec2.RequestLaunchTemplateData{
+		ImageId:      in.ImageId,
+		InstanceType: in.InstanceType,
+		KeyName:      in.KeyName,
+		UserData:     in.UserData,
+		Placement:    launchTemplatePlacement(in.Placement),
+	}
+	if in.IamInstanceProfile != nil {
+		data.IamInstanceProfile = &ec2.LaunchTemplateIamInstanceProfileSpecificationRequest{
+			Arn: in.IamInstanceProfile.Arn,
+		}
+	}
+	for _, nic := range in.NetworkInterfaces {
+		data.NetworkInterfaces = append(data.NetworkInterfaces,
+			&ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
+				AssociatePublicIpAddress: nic.AssociatePublicIpAddress,
+				DeviceIndex:              nic.DeviceIndex,
+				SubnetId:                 nic.SubnetId,
+				Groups:                   nic.Groups,
+			},
+		)
+	}
+	for _, spec := range in.TagSpecifications {
+		data.TagSpecifications = append(data.TagSpecifications,
+			&ec2.LaunchTemplateTagSpecificationRequest{
+				ResourceType: spec.ResourceType,
+				Tags:         spec.Tags,
+			},
+		)
+	}
+	for _, mapping := range in.BlockDeviceMappings {
+		device := &ec2.LaunchTemplateBlockDeviceMappingRequest{
+			DeviceName: mapping.DeviceName,
+		}
+		if mapping.Ebs != nil {
+			device.Ebs = &ec2.LaunchTemplateEbsBlockDeviceRequest{
+				VolumeSize:          mapping.Ebs.VolumeSize,
+				VolumeType:          mapping.Ebs.VolumeType,
+				Iops:                mapping.Ebs.Iops,
+				Throughput:          mapping.Ebs.Throughput,
+				Encrypted:           mapping.Ebs.Encrypted,
+				KmsKeyId:            mapping.Ebs.KmsKeyId,
+				DeleteOnTermination: mapping.Ebs.DeleteOnTermination,
+			}
+		}
+		data.BlockDeviceMappings = append(data.BlockDeviceMappings, device)
+	}
+	return data
+}
+
+// helper function returns a string pointer, or nil if the
+// string is empty.
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
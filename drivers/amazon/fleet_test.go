@@ -0,0 +1,76 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestSpotOptions(t *testing.T) {
+	p := New(
+		WithMarketType("spot"),
+		WithSpotInterruptionBehavior("stop"),
+		WithSpotMaxPrice("0.05"),
+	).(*provider)
+
+	opts := p.spotOptions()
+	if opts == nil {
+		t.Errorf("Want spot market options")
+		return
+	}
+	if got, want := aws.StringValue(opts.InstanceInterruptionBehavior), "stop"; got != want {
+		t.Errorf("Want interruption behavior %q, got %q", want, got)
+	}
+	if got, want := aws.StringValue(opts.SpotInstanceType), "persistent"; got != want {
+		t.Errorf("Want spot instance type %q, got %q", want, got)
+	}
+	if got, want := aws.StringValue(opts.MaxPrice), "0.05"; got != want {
+		t.Errorf("Want max price %q, got %q", want, got)
+	}
+}
+
+func TestSpotOptions_Default(t *testing.T) {
+	p := New(WithMarketType("spot")).(*provider)
+	if p.spotOptions() != nil {
+		t.Errorf("Want nil spot market options by default")
+	}
+}
+
+func TestLaunchTemplateData(t *testing.T) {
+	in := &ec2.RunInstancesInput{
+		ImageId:      aws.String("ami-66506c1c"),
+		InstanceType: aws.String("t3.large"),
+		KeyName:      aws.String("id_rsa"),
+		UserData:     aws.String("I2Nsb3VkLWNvbmZpZw=="),
+		NetworkInterfaces: []*ec2.InstanceNetworkInterfaceSpecification{
+			{
+				AssociatePublicIpAddress: aws.Bool(true),
+				DeviceIndex:              aws.Int64(0),
+				SubnetId:                 aws.String("subnet-0b32177f"),
+			},
+		},
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+			{
+				DeviceName: aws.String("/dev/sda1"),
+				Ebs: &ec2.EbsBlockDevice{
+					VolumeSize: aws.Int64(32),
+				},
+			},
+		},
+	}
+	data := launchTemplateData(in)
+	if got, want := aws.StringValue(data.ImageId), "ami-66506c1c"; got != want {
+		t.Errorf("Want image %q, got %q", want, got)
+	}
+	if got, want := aws.StringValue(data.NetworkInterfaces[0].SubnetId), "subnet-0b32177f"; got != want {
+		t.Errorf("Want subnet %q, got %q", want, got)
+	}
+	if got, want := aws.Int64Value(data.BlockDeviceMappings[0].Ebs.VolumeSize), int64(32); got != want {
+		t.Errorf("Want volume size %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/rs/zerolog/log"
+)
+
+// errImageNotFound is returned when no image matches the
+// configured image filters.
+var errImageNotFound = errors.New("No matching images")
+
+// helper function returns true if the image should be resolved
+// from filters at creation time.
+func (p *provider) hasImageFilters() bool {
+	return p.imageName != "" || len(p.imageTags) != 0
+}
+
+// helper function resolves the image identifier. If image filters
+// are configured, the most recently created image matching the
+// filters is returned. Otherwise the configured image is returned.
+func (p *provider) resolveImage(ctx context.Context, client *ec2.EC2) (string, error) {
+	if !p.hasImageFilters() {
+		return p.image, nil
+	}
+
+	logger := log.Ctx(ctx)
+
+	in := &ec2.DescribeImagesInput{
+		Filters: imageFilters(p.imageName, p.imageTags),
+	}
+	if len(p.imageOwners) != 0 {
+		in.Owners = aws.StringSlice(p.imageOwners)
+	}
+
+	out, err := client.DescribeImagesWithContext(ctx, in)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot describe images")
+		return "", err
+	}
+
+	image := latestImage(out.Images)
+	if image == nil {
+		logger.Error().
+			Str("image-name", p.imageName).
+			Strs("image-owners", p.imageOwners).
+			Msg("no matching images")
+		return "", errImageNotFound
+	}
+
+	logger.Debug().
+		Str("image", *image.ImageId).
+		Str("image-name", aws.StringValue(image.Name)).
+		Str("image-created", aws.StringValue(image.CreationDate)).
+		Msg("resolved image")
+
+	return *image.ImageId, nil
+}
+
+// helper function converts the image name pattern and tags
+// to a list of ec2 filters.
+func imageFilters(name string, tags map[string]string) []*ec2.Filter {
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("state"),
+			Values: aws.StringSlice([]string{"available"}),
+		},
+	}
+	if name != "" {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("name"),
+			Values: aws.StringSlice([]string{name}),
+		})
+	}
+	for k, v := range tags {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("tag:" + k),
+			Values: aws.StringSlice([]string{v}),
+		})
+	}
+	return filters
+}
+
+// helper function returns the most recently created image.
+func latestImage(images []*ec2.Image) *ec2.Image {
+	if len(images) == 0 {
+		return nil
+	}
+	sort.Sort(sort.Reverse(byCreationDate(images)))
+	return images[0]
+}
+
+// byCreationDate sorts the image list by creation date. The
+// creation date is an ISO 8601 timestamp and can therefore be
+// compared as a string.
+type byCreationDate []*ec2.Image
+
+func (a byCreationDate) Len() int      { return len(a) }
+func (a byCreationDate) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byCreationDate) Less(i, j int) bool {
+	return aws.StringValue(a[i].CreationDate) < aws.StringValue(a[j].CreationDate)
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestLatestImage(t *testing.T) {
+	images := []*ec2.Image{
+		{ImageId: aws.String("ami-1"), CreationDate: aws.String("2019-06-01T03:12:40.000Z")},
+		{ImageId: aws.String("ami-2"), CreationDate: aws.String("2019-06-03T03:12:40.000Z")},
+		{ImageId: aws.String("ami-3"), CreationDate: aws.String("2019-06-02T03:12:40.000Z")},
+	}
+	image := latestImage(images)
+	if image == nil {
+		t.Errorf("Want latest image, got nil")
+		return
+	}
+	if got, want := *image.ImageId, "ami-2"; got != want {
+		t.Errorf("Want latest image %q, got %q", want, got)
+	}
+}
+
+func TestLatestImage_Empty(t *testing.T) {
+	if image := latestImage(nil); image != nil {
+		t.Errorf("Want nil image when no images match")
+	}
+}
+
+func TestImageFilters(t *testing.T) {
+	filters := imageFilters("drone-agent-*", map[string]string{"release": "nightly"})
+	if got, want := len(filters), 3; got != want {
+		t.Errorf("Want %d filters, got %d", want, got)
+		return
+	}
+	index := map[string]string{}
+	for _, filter := range filters {
+		index[*filter.Name] = *filter.Values[0]
+	}
+	if got, want := index["state"], "available"; got != want {
+		t.Errorf("Want state filter %q, got %q", want, got)
+	}
+	if got, want := index["name"], "drone-agent-*"; got != want {
+		t.Errorf("Want name filter %q, got %q", want, got)
+	}
+	if got, want := index["tag:release"], "nightly"; got != want {
+		t.Errorf("Want tag filter %q, got %q", want, got)
+	}
+}
+
+func TestImageFilters_Defaults(t *testing.T) {
+	p := New(
+		WithRegion("us-west-2"),
+		WithImageName("drone-agent-*"),
+		WithImageOwners("self"),
+	).(*provider)
+	if got, want := p.image, ""; got != want {
+		t.Errorf("Want no default image when filters are set, got %q", got)
+	}
+	if got, want := p.imageOwners[0], "self"; got != want {
+		t.Errorf("Want image owner %q, got %q", want, got)
+	}
+}
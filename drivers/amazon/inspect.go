@@ -0,0 +1,59 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Inspect returns the current instance details. An error is
+// returned if the instance no longer exists, or if it was
+// stopped or terminated, which happens when spot instances
+// are interrupted.
+func (p *provider) Inspect(ctx context.Context, instance *autoscaler.Instance) (*autoscaler.Instance, error) {
+	client := p.getRegionClient(p.instanceRegion(instance))
+
+	desc, err := client.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{
+			aws.String(instance.ID),
+		},
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidInstanceID.NotFound" {
+		return nil, autoscaler.ErrInstanceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(desc.Reservations) == 0 || len(desc.Reservations[0].Instances) == 0 {
+		return nil, autoscaler.ErrInstanceNotFound
+	}
+	amazonInstance := desc.Reservations[0].Instances[0]
+
+	out := *instance
+	if amazonInstance.State != nil {
+		out.Status = aws.StringValue(amazonInstance.State.Name)
+	}
+	switch out.Status {
+	case ec2.InstanceStateNameShuttingDown,
+		ec2.InstanceStateNameTerminated,
+		ec2.InstanceStateNameStopping,
+		ec2.InstanceStateNameStopped:
+		return nil, autoscaler.ErrInstanceReclaimed
+	}
+
+	out.Pricing = "on-demand"
+	if amazonInstance.InstanceLifecycle != nil {
+		out.Pricing = *amazonInstance.InstanceLifecycle
+	}
+	out.PublicAddress = aws.StringValue(amazonInstance.PublicIpAddress)
+	out.PrivateAddress = aws.StringValue(amazonInstance.PrivateIpAddress)
+	return &out, nil
+}
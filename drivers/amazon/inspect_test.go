@@ -0,0 +1,77 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/h2non/gock"
+)
+
+func TestInspect(t *testing.T) {
+	defer gock.Off()
+
+	os.Setenv("AWS_ACCESS_KEY_ID", "your_access_key_id")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "your_secret_access_key")
+	defer func() {
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	}()
+
+	gock.New("https://ec2.us-east-1.amazonaws.com").
+		Post("/").
+		Reply(200).
+		BodyString(`<DescribeInstancesResponse><reservationSet><item><instancesSet><item><instanceId>i-1234567890abcdef0</instanceId><instanceState><name>running</name></instanceState><instanceLifecycle>spot</instanceLifecycle><privateIpAddress>10.0.0.2</privateIpAddress><ipAddress>54.1.2.3</ipAddress></item></instancesSet></item></reservationSet></DescribeInstancesResponse>`)
+
+	p := New(
+		WithRegion("us-east-1"),
+	).(*provider)
+	p.retries = 1
+
+	out, err := p.Inspect(context.TODO(), &autoscaler.Instance{ID: "i-1234567890abcdef0"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := out.Pricing, "spot"; got != want {
+		t.Errorf("Want pricing %s, got %s", want, got)
+	}
+	if got, want := out.PrivateAddress, "10.0.0.2"; got != want {
+		t.Errorf("Want private address %s, got %s", want, got)
+	}
+	if got, want := out.PublicAddress, "54.1.2.3"; got != want {
+		t.Errorf("Want public address %s, got %s", want, got)
+	}
+}
+
+func TestInspect_Terminated(t *testing.T) {
+	defer gock.Off()
+
+	os.Setenv("AWS_ACCESS_KEY_ID", "your_access_key_id")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "your_secret_access_key")
+	defer func() {
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	}()
+
+	gock.New("https://ec2.us-east-1.amazonaws.com").
+		Post("/").
+		Reply(200).
+		BodyString(`<DescribeInstancesResponse><reservationSet><item><instancesSet><item><instanceId>i-1234567890abcdef0</instanceId><instanceState><name>terminated</name></instanceState></item></instancesSet></item></reservationSet></DescribeInstancesResponse>`)
+
+	p := New(
+		WithRegion("us-east-1"),
+	).(*provider)
+	p.retries = 1
+
+	_, err := p.Inspect(context.TODO(), &autoscaler.Instance{ID: "i-1234567890abcdef0"})
+	if err != autoscaler.ErrInstanceReclaimed {
+		t.Errorf("Want instance reclaimed error, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ package amazon
 
 import (
 	"io/ioutil"
+	"strings"
 
 	"github.com/drone/autoscaler/drivers/internal/userdata"
 )
@@ -13,6 +14,17 @@ import (
 // Option configures a Digital Ocean provider option.
 type Option func(*provider)
 
+// WithCredentials returns an option to set the static api
+// credentials. The default credential chain is used if the
+// access key id is empty.
+func WithCredentials(id, secret, token string) Option {
+	return func(p *provider) {
+		p.accessKeyID = id
+		p.secretAccessKey = secret
+		p.sessionToken = token
+	}
+}
+
 // WithDeviceName returns an option to set the device name.
 func WithDeviceName(n string) Option {
 	return func(p *provider) {
@@ -20,6 +32,33 @@ func WithDeviceName(n string) Option {
 	}
 }
 
+// WithDockerVolume returns an option to attach an ebs volume
+// with the given size in gigabytes and volume type, which is
+// mounted as the docker data directory.
+func WithDockerVolume(size int64, volumeType string) Option {
+	return func(p *provider) {
+		p.dockerVolumeSize = size
+		p.dockerVolumeType = volumeType
+	}
+}
+
+// WithDockerVolumeDevice returns an option to set the device
+// name of the docker volume.
+func WithDockerVolumeDevice(n string) Option {
+	return func(p *provider) {
+		p.dockerVolumeDevice = n
+	}
+}
+
+// WithElasticIP returns an option to allocate and associate
+// an elastic ip address with each instance, which is released
+// when the instance is destroyed.
+func WithElasticIP(elasticIP bool) Option {
+	return func(p *provider) {
+		p.elasticIP = elasticIP
+	}
+}
+
 // WithImage returns an option to set the image.
 func WithImage(image string) Option {
 	return func(p *provider) {
@@ -27,6 +66,65 @@ func WithImage(image string) Option {
 	}
 }
 
+// WithImageName returns an option to set the image name
+// filter used to resolve the image at creation time. The
+// name may include wildcards.
+func WithImageName(name string) Option {
+	return func(p *provider) {
+		p.imageName = name
+	}
+}
+
+// WithImageOwners returns an option to set the image owners
+// used to resolve the image at creation time.
+func WithImageOwners(owners ...string) Option {
+	return func(p *provider) {
+		p.imageOwners = owners
+	}
+}
+
+// WithImageTags returns an option to set the image tag
+// filters used to resolve the image at creation time.
+func WithImageTags(tags map[string]string) Option {
+	return func(p *provider) {
+		p.imageTags = tags
+	}
+}
+
+// WithHost returns an option to launch instances on the
+// dedicated host.
+func WithHost(id string) Option {
+	return func(p *provider) {
+		p.hostID = id
+	}
+}
+
+// WithHostResourceGroup returns an option to launch instances
+// on a dedicated host in the host resource group.
+func WithHostResourceGroup(arn string) Option {
+	return func(p *provider) {
+		p.hostResourceGroup = arn
+	}
+}
+
+// WithPlacementGroup returns an option to launch instances in
+// the placement group.
+func WithPlacementGroup(name string) Option {
+	return func(p *provider) {
+		p.placementGroup = name
+	}
+}
+
+// WithPricing returns an option to estimate the hourly price
+// of created instances. On-demand prices are fetched from the
+// price list api, which requires the pricing:GetProducts
+// permission.
+func WithPricing(pricing bool) Option {
+	return func(p *provider) {
+		p.pricing = pricing
+	}
+}
+
 // WithPrivateIP returns an option to set the private IP address.
 func WithPrivateIP(private bool) Option {
 	return func(p *provider) {
@@ -48,6 +146,34 @@ func WithRegion(region string) Option {
 	}
 }
 
+// WithRegionImages returns an option to set the image of each
+// failover region, by region.
+func WithRegionImages(images map[string]string) Option {
+	return func(p *provider) {
+		p.regionImages = images
+	}
+}
+
+// WithRegionSecurityGroups returns an option to set the
+// security groups of each failover region, by region. The
+// security groups of a region are separated by spaces.
+func WithRegionSecurityGroups(groups map[string]string) Option {
+	return func(p *provider) {
+		p.regionGroups = map[string][]string{}
+		for region, v := range groups {
+			p.regionGroups[region] = strings.Fields(v)
+		}
+	}
+}
+
+// WithRegionSubnets returns an option to set the subnet of
+// each failover region, by region.
+func WithRegionSubnets(subnets map[string]string) Option {
+	return func(p *provider) {
+		p.regionSubnets = subnets
+	}
+}
+
 // WithSecurityGroup returns an option to set the instance size.
 func WithSecurityGroup(group ...string) Option {
 	return func(p *provider) {
@@ -83,6 +209,14 @@ func WithTags(tags map[string]string) Option {
 	}
 }
 
+// WithTenancy returns an option to set the instance tenancy.
+// Valid values are default, dedicated and host.
+func WithTenancy(tenancy string) Option {
+	return func(p *provider) {
+		p.tenancy = tenancy
+	}
+}
+
 // WithUserData returns an option to set the cloud-init
 // template from text.
 func WithUserData(text string) Option {
@@ -107,6 +241,44 @@ func WithUserDataFile(filepath string) Option {
 	}
 }
 
+// WithUserDataHooks returns an option to merge cloud-init
+// sections, such as write_files and runcmd, into the
+// cloud-init template. Entries of the before sections are
+// inserted before the existing entries, and entries of the
+// after sections are inserted after the existing entries.
+func WithUserDataHooks(before, after string) Option {
+	return func(p *provider) {
+		p.userdataPrepend = before
+		p.userdataAppend = after
+	}
+}
+
+// WithVolumeEncryption returns an option to encrypt the
+// instance volumes, optionally using the kms key.
+func WithVolumeEncryption(encrypted bool, key string) Option {
+	return func(p *provider) {
+		p.volumeEncrypted = encrypted
+		p.volumeKMSKey = key
+	}
+}
+
+// WithVolumeIops returns an option to set the provisioned
+// iops of the volume, supported by gp3, io1 and io2 volumes.
+func WithVolumeIops(iops int64) Option {
+	return func(p *provider) {
+		p.volumeIops = iops
+	}
+}
+
+// WithVolumeThroughput returns an option to set the
+// provisioned throughput of the volume in MiB/s, supported
+// by gp3 volumes.
+func WithVolumeThroughput(throughput int64) Option {
+	return func(p *provider) {
+		p.volumeThroughput = throughput
+	}
+}
+
 // WithVolumeSize returns an option to set the volume size
 // in gigabytes.
 func WithVolumeSize(s int64) Option {
@@ -135,3 +307,29 @@ func WithMarketType(t string) Option {
 		p.spotInstance = t == "spot"
 	}
 }
+
+// WithSpotAllocationStrategy returns an option to set the spot
+// allocation strategy (e.g. capacity-optimized, lowest-price,
+// price-capacity-optimized).
+func WithSpotAllocationStrategy(strategy string) Option {
+	return func(p *provider) {
+		p.spotStrategy = strategy
+	}
+}
+
+// WithSpotInterruptionBehavior returns an option to set the
+// spot instance interruption behavior (terminate, stop or
+// hibernate).
+func WithSpotInterruptionBehavior(behavior string) Option {
+	return func(p *provider) {
+		p.spotInterruption = behavior
+	}
+}
+
+// WithSpotMaxPrice returns an option to set the maximum hourly
+// price paid for spot instances.
+func WithSpotMaxPrice(price string) Option {
+	return func(p *provider) {
+		p.spotMaxPrice = price
+	}
+}
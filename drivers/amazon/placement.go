@@ -0,0 +1,45 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// helper function returns the instance placement, used to
+// launch instances on dedicated hardware or in a placement
+// group. A nil value is returned if no placement options are
+// configured.
+func (p *provider) placement() *ec2.Placement {
+	if p.tenancy == "" && p.hostID == "" && p.hostResourceGroup == "" && p.placementGroup == "" {
+		return nil
+	}
+	placement := &ec2.Placement{
+		Tenancy:              stringOrNil(p.tenancy),
+		HostId:               stringOrNil(p.hostID),
+		HostResourceGroupArn: stringOrNil(p.hostResourceGroup),
+		GroupName:            stringOrNil(p.placementGroup),
+	}
+	// instances launched on a specific host or host resource
+	// group must use host tenancy.
+	if placement.Tenancy == nil && (p.hostID != "" || p.hostResourceGroup != "") {
+		placement.Tenancy = stringOrNil(ec2.TenancyHost)
+	}
+	return placement
+}
+
+// helper function converts the instance placement to the
+// launch template placement.
+func launchTemplatePlacement(in *ec2.Placement) *ec2.LaunchTemplatePlacementRequest {
+	if in == nil {
+		return nil
+	}
+	return &ec2.LaunchTemplatePlacementRequest{
+		Tenancy:              in.Tenancy,
+		HostId:               in.HostId,
+		HostResourceGroupArn: in.HostResourceGroupArn,
+		GroupName:            in.GroupName,
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestPlacement(t *testing.T) {
+	p := New(
+		WithHostResourceGroup("arn:aws:resource-groups:us-east-1:123456789012:group/mac-hosts"),
+		WithPlacementGroup("build-agents"),
+	).(*provider)
+
+	placement := p.placement()
+	if placement == nil {
+		t.Errorf("Want instance placement")
+		return
+	}
+	if got, want := aws.StringValue(placement.Tenancy), "host"; got != want {
+		t.Errorf("Want tenancy %q, got %q", want, got)
+	}
+	if got, want := aws.StringValue(placement.GroupName), "build-agents"; got != want {
+		t.Errorf("Want placement group %q, got %q", want, got)
+	}
+	if placement.HostId != nil {
+		t.Errorf("Want nil host id")
+	}
+
+	data := launchTemplatePlacement(placement)
+	if got, want := aws.StringValue(data.HostResourceGroupArn), aws.StringValue(placement.HostResourceGroupArn); got != want {
+		t.Errorf("Want launch template host resource group %q, got %q", want, got)
+	}
+}
+
+func TestPlacement_Default(t *testing.T) {
+	p := New().(*provider)
+	if p.placement() != nil {
+		t.Errorf("Want nil placement by default")
+	}
+	if launchTemplatePlacement(nil) != nil {
+		t.Errorf("Want nil launch template placement")
+	}
+}
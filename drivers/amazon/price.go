@@ -0,0 +1,175 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/rs/zerolog/log"
+)
+
+// the price list api is only available in a subset of
+// regions, and returns prices for all regions.
+const pricingRegion = "us-east-1"
+
+// errPriceNotFound is returned when no on-demand price is
+// found in the price list.
+var errPriceNotFound = errors.New("No matching price")
+
+// helper function returns the estimated hourly price of the
+// instance in US dollars. Spot instances are priced using the
+// current spot price, and on-demand instances are priced using
+// the price list api. Pricing is best effort, and zero is
+// returned if the price cannot be determined.
+func (p *provider) price(ctx context.Context, client *ec2.EC2, instance *ec2.Instance, os string) float64 {
+	if !p.pricing {
+		return 0
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("size", aws.StringValue(instance.InstanceType)).
+		Str("zone", aws.StringValue(instance.Placement.AvailabilityZone)).
+		Logger()
+
+	var price float64
+	var err error
+	if aws.StringValue(instance.InstanceLifecycle) == ec2.InstanceLifecycleTypeSpot {
+		price, err = spotPrice(ctx, client, instance, os)
+	} else {
+		price, err = p.onDemandPrice(ctx, aws.StringValue(instance.InstanceType), aws.StringValue(client.Config.Region), os)
+	}
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("cannot determine instance price")
+		return 0
+	}
+
+	logger.Debug().
+		Float64("price", price).
+		Msg("instance price")
+	return price
+}
+
+// helper function returns the cached on-demand price of the
+// instance type, fetching the price from the price list api
+// if the price is not cached.
+func (p *provider) onDemandPrice(ctx context.Context, size, region, os string) (float64, error) {
+	key := size + "/" + region + "/" + os
+
+	p.pricesMu.Lock()
+	price, ok := p.prices[key]
+	p.pricesMu.Unlock()
+	if ok {
+		return price, nil
+	}
+
+	client := pricing.New(session.New(p.withCredentials(aws.NewConfig().WithRegion(pricingRegion))))
+	out, err := client.GetProductsWithContext(ctx, &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		MaxResults:  aws.Int64(1),
+		Filters: []*pricing.Filter{
+			priceFilter("instanceType", size),
+			priceFilter("regionCode", region),
+			priceFilter("operatingSystem", priceOS(os)),
+			priceFilter("tenancy", priceTenancy(p.tenancy)),
+			priceFilter("preInstalledSw", "NA"),
+			priceFilter("capacitystatus", "Used"),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(out.PriceList) == 0 {
+		return 0, errPriceNotFound
+	}
+	price, err = parsePriceList(out.PriceList[0])
+	if err != nil {
+		return 0, err
+	}
+
+	p.pricesMu.Lock()
+	p.prices[key] = price
+	p.pricesMu.Unlock()
+	return price, nil
+}
+
+// helper function returns the current spot price of the
+// instance type in the instance availability zone.
+func spotPrice(ctx context.Context, client *ec2.EC2, instance *ec2.Instance, os string) (float64, error) {
+	product := "Linux/UNIX"
+	if os == "windows" {
+		product = "Windows"
+	}
+	out, err := client.DescribeSpotPriceHistoryWithContext(ctx, &ec2.DescribeSpotPriceHistoryInput{
+		AvailabilityZone:    instance.Placement.AvailabilityZone,
+		InstanceTypes:       []*string{instance.InstanceType},
+		ProductDescriptions: aws.StringSlice([]string{product}),
+		StartTime:           aws.Time(time.Now()),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(out.SpotPriceHistory) == 0 {
+		return 0, errPriceNotFound
+	}
+	return strconv.ParseFloat(aws.StringValue(out.SpotPriceHistory[0].SpotPrice), 64)
+}
+
+// helper function parses the on-demand hourly price in US
+// dollars from the price list json document.
+func parsePriceList(doc aws.JSONValue) (float64, error) {
+	terms, _ := doc["terms"].(map[string]interface{})
+	onDemand, _ := terms["OnDemand"].(map[string]interface{})
+	for _, term := range onDemand {
+		term, _ := term.(map[string]interface{})
+		dimensions, _ := term["priceDimensions"].(map[string]interface{})
+		for _, dimension := range dimensions {
+			dimension, _ := dimension.(map[string]interface{})
+			unit, _ := dimension["pricePerUnit"].(map[string]interface{})
+			if usd, ok := unit["USD"].(string); ok {
+				return strconv.ParseFloat(usd, 64)
+			}
+		}
+	}
+	return 0, errPriceNotFound
+}
+
+// helper function returns a price list filter that matches
+// the attribute value.
+func priceFilter(field, value string) *pricing.Filter {
+	return &pricing.Filter{
+		Type:  aws.String(pricing.FilterTypeTermMatch),
+		Field: aws.String(field),
+		Value: aws.String(value),
+	}
+}
+
+// helper function returns the price list operating system.
+func priceOS(os string) string {
+	if os == "windows" {
+		return "Windows"
+	}
+	return "Linux"
+}
+
+// helper function returns the price list tenancy.
+func priceTenancy(tenancy string) string {
+	switch tenancy {
+	case ec2.TenancyDedicated:
+		return "Dedicated"
+	case ec2.TenancyHost:
+		return "Host"
+	default:
+		return "Shared"
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestParsePriceList(t *testing.T) {
+	doc := aws.JSONValue{}
+	err := json.Unmarshal([]byte(priceListDoc), &doc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	price, err := parsePriceList(doc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := price, 0.0464; got != want {
+		t.Errorf("Want price %v, got %v", want, got)
+	}
+}
+
+func TestParsePriceList_NotFound(t *testing.T) {
+	_, err := parsePriceList(aws.JSONValue{})
+	if err != errPriceNotFound {
+		t.Errorf("Want errPriceNotFound, got %v", err)
+	}
+}
+
+func TestPricing_Disabled(t *testing.T) {
+	p := New().(*provider)
+	if got := p.price(context.TODO(), nil, nil, "linux"); got != 0 {
+		t.Errorf("Want zero price when pricing is disabled, got %v", got)
+	}
+}
+
+var priceListDoc = `{
+  "product": {
+    "productFamily": "Compute Instance",
+    "attributes": {
+      "instanceType": "t2.medium",
+      "regionCode": "us-east-1",
+      "operatingSystem": "Linux"
+    },
+    "sku": "H6T3SYB5G6QCVMZM"
+  },
+  "terms": {
+    "OnDemand": {
+      "H6T3SYB5G6QCVMZM.JRTCKXETXF": {
+        "priceDimensions": {
+          "H6T3SYB5G6QCVMZM.JRTCKXETXF.6YS6EN2CT7": {
+            "unit": "Hrs",
+            "pricePerUnit": {
+              "USD": "0.0464000000"
+            }
+          }
+        },
+        "sku": "H6T3SYB5G6QCVMZM"
+      }
+    }
+  }
+}`
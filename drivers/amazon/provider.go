@@ -12,6 +12,7 @@ import (
 	"github.com/drone/autoscaler/drivers/internal/userdata"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
@@ -26,7 +27,11 @@ type provider struct {
 	key           string
 	region        string
 	image         string
+	imageName     string
+	imageOwners   []string
+	imageTags     map[string]string
 	privateIP     bool
+	elasticIP     bool
 	userdata      *template.Template
 	size          string
 	subnet        string
@@ -34,15 +39,64 @@ type provider struct {
 	tags          map[string]string
 	iamProfileArn string
 	spotInstance  bool
+	pricing       bool
+
+	pricesMu sync.Mutex
+	prices   map[string]float64
+
+	tenancy           string
+	hostID            string
+	hostResourceGroup string
+	placementGroup    string
+
+	spotStrategy     string
+	spotInterruption string
+	spotMaxPrice     string
+
+	regionSubnets map[string]string
+	regionGroups  map[string][]string
+	regionImages  map[string]string
+
+	dockerVolumeDevice string
+	dockerVolumeSize   int64
+	dockerVolumeType   string
+
+	volumeIops       int64
+	volumeThroughput int64
+	volumeEncrypted  bool
+	volumeKMSKey     string
+
+	userdataPrepend string
+	userdataAppend  string
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
 }
 
 func (p *provider) getClient() *ec2.EC2 {
+	return p.getRegionClient(p.region)
+}
+
+func (p *provider) getRegionClient(region string) *ec2.EC2 {
 	config := aws.NewConfig()
-	config = config.WithRegion(p.region)
+	config = config.WithRegion(region)
 	config = config.WithMaxRetries(p.retries)
+	config = p.withCredentials(config)
 	return ec2.New(session.New(config))
 }
 
+// helper function sets the static api credentials of the
+// provider, if configured.
+func (p *provider) withCredentials(config *aws.Config) *aws.Config {
+	if p.accessKeyID == "" {
+		return config
+	}
+	return config.WithCredentials(
+		credentials.NewStaticCredentials(p.accessKeyID, p.secretAccessKey, p.sessionToken),
+	)
+}
+
 // New returns a new Digital Ocean provider.
 func New(opts ...Option) autoscaler.Provider {
 	p := new(provider)
@@ -58,7 +112,7 @@ func New(opts ...Option) autoscaler.Provider {
 	if p.size == "" {
 		p.size = "t2.medium"
 	}
-	if p.image == "" {
+	if p.image == "" && !p.hasImageFilters() {
 		p.image = defaultImage(p.region)
 	}
 	if p.deviceName == "" {
@@ -70,8 +124,16 @@ func New(opts ...Option) autoscaler.Provider {
 	if p.volumeType == "" {
 		p.volumeType = "gp2"
 	}
+	if p.dockerVolumeDevice == "" {
+		p.dockerVolumeDevice = "/dev/sdf"
+	}
+	if p.dockerVolumeType == "" {
+		p.dockerVolumeType = "gp2"
+	}
 	if p.userdata == nil {
 		p.userdata = userdata.T
 	}
+	p.userdata = userdata.Hooks(p.userdata, p.userdataPrepend, p.userdataAppend)
+	p.prices = map[string]float64{}
 	return p
 }
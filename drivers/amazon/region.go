@@ -0,0 +1,49 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// helper function returns the subnet and security groups of
+// the region. Subnets and security groups are scoped to the
+// region, so the configured subnet and security groups cannot
+// be used in a failover region, and must be configured for
+// each failover region.
+func (p *provider) regionNetwork(region string) (string, []string, error) {
+	if region == p.region {
+		return p.subnet, p.groups, nil
+	}
+	subnet, ok := p.regionSubnets[region]
+	if !ok && p.subnet != "" {
+		return "", nil, fmt.Errorf("No subnet configured for region %s", region)
+	}
+	groups, ok := p.regionGroups[region]
+	if !ok && len(p.groups) != 0 {
+		return "", nil, fmt.Errorf("No security groups configured for region %s", region)
+	}
+	return subnet, groups, nil
+}
+
+// helper function returns the image of the region. The image
+// filters are resolved in the region. The configured image is
+// scoped to the region, and cannot be used in a failover
+// region, unless it is the default image.
+func (p *provider) regionImage(ctx context.Context, client *ec2.EC2, region string) (string, error) {
+	if region == p.region || p.hasImageFilters() {
+		return p.resolveImage(ctx, client)
+	}
+	if image, ok := p.regionImages[region]; ok {
+		return image, nil
+	}
+	if image := defaultImage(region); p.image == defaultImage(p.region) && image != "" {
+		return image, nil
+	}
+	return "", fmt.Errorf("No image configured for region %s", region)
+}
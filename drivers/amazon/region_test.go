@@ -0,0 +1,76 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRegionNetwork(t *testing.T) {
+	p := New(
+		WithRegion("us-east-1"),
+		WithSubnet("subnet-east"),
+		WithSecurityGroup("sg-east"),
+		WithRegionSubnets(map[string]string{"us-west-2": "subnet-west"}),
+		WithRegionSecurityGroups(map[string]string{"us-west-2": "sg-west1 sg-west2"}),
+	).(*provider)
+
+	subnet, groups, err := p.regionNetwork("us-east-1")
+	if err != nil {
+		t.Error(err)
+	}
+	if subnet != "subnet-east" || !reflect.DeepEqual(groups, []string{"sg-east"}) {
+		t.Errorf("Want the configured subnet and groups in the default region")
+	}
+
+	subnet, groups, err = p.regionNetwork("us-west-2")
+	if err != nil {
+		t.Error(err)
+	}
+	if subnet != "subnet-west" || !reflect.DeepEqual(groups, []string{"sg-west1", "sg-west2"}) {
+		t.Errorf("Want the region subnet and groups in the failover region, got %s %v", subnet, groups)
+	}
+
+	if _, _, err := p.regionNetwork("eu-west-1"); err == nil {
+		t.Errorf("Want error when the failover region subnet is not configured")
+	}
+}
+
+func TestRegionNetwork_Default(t *testing.T) {
+	p := New(WithRegion("us-east-1")).(*provider)
+	subnet, groups, err := p.regionNetwork("us-west-2")
+	if err != nil {
+		t.Error(err)
+	}
+	if subnet != "" || len(groups) != 0 {
+		t.Errorf("Want the default vpc used when no subnet is configured")
+	}
+}
+
+func TestRegionImage(t *testing.T) {
+	ctx := context.Background()
+
+	p := New(WithRegion("us-east-1")).(*provider)
+	if image, _ := p.regionImage(ctx, nil, "us-west-1"); image != defaultImage("us-west-1") {
+		t.Errorf("Want the default image of the failover region, got %s", image)
+	}
+
+	p = New(
+		WithRegion("us-east-1"),
+		WithImage("ami-east"),
+		WithRegionImages(map[string]string{"us-west-2": "ami-west"}),
+	).(*provider)
+	if image, _ := p.regionImage(ctx, nil, "us-east-1"); image != "ami-east" {
+		t.Errorf("Want the configured image in the default region, got %s", image)
+	}
+	if image, _ := p.regionImage(ctx, nil, "us-west-2"); image != "ami-west" {
+		t.Errorf("Want the region image in the failover region, got %s", image)
+	}
+	if _, err := p.regionImage(ctx, nil, "eu-west-1"); err == nil {
+		t.Errorf("Want error when the failover region image is not configured")
+	}
+}
@@ -5,10 +5,29 @@
 package amazon
 
 import (
+	"bytes"
+
+	"github.com/drone/autoscaler"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
+// errCodeInsufficientCapacity is the error code returned when
+// there is insufficient capacity for the instance type.
+const errCodeInsufficientCapacity = "InsufficientInstanceCapacity"
+
+// helper function returns true if the error indicates there
+// is insufficient capacity for the requested instance type.
+func isCapacityError(err error) bool {
+	if err == autoscaler.ErrInsufficientCapacity {
+		return true
+	}
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == errCodeInsufficientCapacity
+}
+
 // helper function converts an array of tags in string
 // format to an array of ec2 tags.
 func convertTags(in map[string]string) []*ec2.Tag {
@@ -22,6 +41,35 @@ func convertTags(in map[string]string) []*ec2.Tag {
 	return out
 }
 
+// helper function returns the region of the instance. The
+// instance region is stored as the availability zone, which
+// is the region name followed by the zone letter.
+func (p *provider) instanceRegion(instance *autoscaler.Instance) string {
+	zone := instance.Region
+	switch n := len(zone); {
+	case n == 0:
+		return p.region
+	case zone[n-1] >= 'a' && zone[n-1] <= 'z':
+		return zone[:n-1]
+	default:
+		return zone
+	}
+}
+
+// helper function returns the instance userdata. Windows
+// userdata is a powershell script, which must be enclosed in
+// powershell tags, and must persist across restarts.
+func wrapUserdata(os string, data []byte) []byte {
+	if os != "windows" || bytes.HasPrefix(data, []byte("<powershell>")) {
+		return data
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("<powershell>\n")
+	buf.Write(data)
+	buf.WriteString("\n</powershell>\n<persist>true</persist>\n")
+	return buf.Bytes()
+}
+
 // helper function creates a copy of map[string]string
 func createCopy(in map[string]string) map[string]string {
 	out := map[string]string{}
@@ -8,6 +8,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/drone/autoscaler"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/kr/pretty"
 )
 
@@ -30,3 +33,43 @@ func TestConvertTags(t *testing.T) {
 		pretty.Ldiff(t, a, b)
 	}
 }
+
+func TestIsCapacityError(t *testing.T) {
+	if !isCapacityError(awserr.New("InsufficientInstanceCapacity", "insufficient capacity", nil)) {
+		t.Errorf("Want insufficient capacity error detected")
+	}
+	if isCapacityError(awserr.New("InvalidParameterValue", "invalid parameter", nil)) {
+		t.Errorf("Want other errors ignored")
+	}
+}
+
+func TestInstanceRegion(t *testing.T) {
+	p := &provider{region: "us-east-1"}
+	tests := []struct {
+		zone, region string
+	}{
+		{"", "us-east-1"},
+		{"us-west-2b", "us-west-2"},
+		{"eu-central-1", "eu-central-1"},
+	}
+	for _, test := range tests {
+		got := p.instanceRegion(&autoscaler.Instance{Region: test.zone})
+		if got != test.region {
+			t.Errorf("Want region %q for zone %q, got %q", test.region, test.zone, got)
+		}
+	}
+}
+
+func TestWrapUserdata(t *testing.T) {
+	data := []byte("Install-WindowsFeature -Name Containers")
+	if got := wrapUserdata("linux", data); string(got) != string(data) {
+		t.Errorf("Want linux userdata unchanged")
+	}
+	want := "<powershell>\nInstall-WindowsFeature -Name Containers\n</powershell>\n<persist>true</persist>\n"
+	if got := wrapUserdata("windows", data); string(got) != want {
+		t.Errorf("Want powershell userdata %q, got %q", want, got)
+	}
+	if got := wrapUserdata("windows", []byte(want)); string(got) != want {
+		t.Errorf("Want wrapped userdata unchanged")
+	}
+}
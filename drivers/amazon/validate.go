@@ -0,0 +1,82 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// errCodeDryRun is returned by a dry run request that would
+// have succeeded.
+const errCodeDryRun = "DryRunOperation"
+
+// Validate verifies the credentials, image, subnet, security
+// groups and permissions by issuing a dry run instance create
+// request, without creating an instance.
+func (p *provider) Validate(ctx context.Context) error {
+	p.init.Do(func() {
+		p.setup(ctx)
+	})
+
+	if p.key == "" {
+		return errors.New("No ssh key configured, and no existing key pair found")
+	}
+
+	client := p.getClient()
+
+	image, err := p.resolveImage(ctx, client)
+	if err != nil {
+		return fmt.Errorf("Cannot resolve image: %s", err)
+	}
+
+	var iamProfile *ec2.IamInstanceProfileSpecification
+	if p.iamProfileArn != "" {
+		iamProfile = &ec2.IamInstanceProfileSpecification{
+			Arn: aws.String(p.iamProfileArn),
+		}
+	}
+
+	_, err = client.RunInstancesWithContext(ctx, &ec2.RunInstancesInput{
+		DryRun:             aws.Bool(true),
+		KeyName:            aws.String(p.key),
+		ImageId:            aws.String(image),
+		InstanceType:       aws.String(p.size),
+		MinCount:           aws.Int64(1),
+		MaxCount:           aws.Int64(1),
+		IamInstanceProfile: iamProfile,
+		NetworkInterfaces: []*ec2.InstanceNetworkInterfaceSpecification{
+			{
+				AssociatePublicIpAddress: aws.Bool(!p.privateIP),
+				DeviceIndex:              aws.Int64(0),
+				SubnetId:                 aws.String(p.subnet),
+				Groups:                   aws.StringSlice(p.groups),
+			},
+		},
+		BlockDeviceMappings: p.blockDeviceMappings(),
+		Placement:           p.placement(),
+	})
+	if isDryRun(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Dry run instance create failed: %s", err)
+	}
+	return nil
+}
+
+// helper function returns true if the error indicates the
+// dry run request would have succeeded.
+func isDryRun(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == errCodeDryRun
+	}
+	return false
+}
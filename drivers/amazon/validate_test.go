@@ -0,0 +1,27 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package amazon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestIsDryRun(t *testing.T) {
+	if !isDryRun(awserr.New("DryRunOperation", "Request would have succeeded", nil)) {
+		t.Errorf("Want dry run success detected")
+	}
+	if isDryRun(awserr.New("UnauthorizedOperation", "You are not authorized", nil)) {
+		t.Errorf("Want authorization errors reported")
+	}
+	if isDryRun(errors.New("error")) {
+		t.Errorf("Want other errors reported")
+	}
+	if isDryRun(nil) {
+		t.Errorf("Want nil error ignored")
+	}
+}
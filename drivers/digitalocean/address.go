@@ -0,0 +1,89 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package digitalocean
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler/drivers/internal/retry"
+
+	"github.com/digitalocean/godo"
+	"github.com/rs/zerolog/log"
+)
+
+// helper function creates a reserved ip address in the region
+// of the droplet, assigned to the droplet, and returns the
+// address. The reserved ips are managed with the floating ip
+// api, which is the previous name of the reserved ips.
+func (p *provider) assignReservedIP(ctx context.Context, client *godo.Client, droplet *godo.Droplet, region string) (string, error) {
+	logger := log.Ctx(ctx).With().
+		Int("id", droplet.ID).
+		Str("name", droplet.Name).
+		Logger()
+
+	req := &godo.FloatingIPCreateRequest{
+		Region:    region,
+		DropletID: droplet.ID,
+	}
+
+	var ip *godo.FloatingIP
+	err := retry.Do(ctx, p.retries, isThrottled, func() (err error) {
+		ip, _, err = client.FloatingIPs.Create(ctx, req)
+		return err
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot create reserved ip")
+		return "", err
+	}
+
+	logger.Debug().
+		Str("ip", ip.IP).
+		Msg("reserved ip assigned")
+
+	return ip.IP, nil
+}
+
+// helper function deletes the reserved ip addresses assigned
+// to the droplet.
+func (p *provider) deleteReservedIPs(ctx context.Context, client *godo.Client, id int) error {
+	var ips []godo.FloatingIP
+	opts := &godo.ListOptions{PerPage: 200}
+	for {
+		var page []godo.FloatingIP
+		var resp *godo.Response
+		err := retry.Do(ctx, p.retries, isTransient, func() (err error) {
+			page, resp, err = client.FloatingIPs.List(ctx, opts)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		ips = append(ips, page...)
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+		next, err := resp.Links.CurrentPage()
+		if err != nil {
+			return err
+		}
+		opts.Page = next + 1
+	}
+
+	for _, ip := range ips {
+		if ip.Droplet == nil || ip.Droplet.ID != id {
+			continue
+		}
+		err := retry.Do(ctx, p.retries, isTransient, func() error {
+			_, err := client.FloatingIPs.Delete(ctx, ip.IP)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -7,10 +7,13 @@ package digitalocean
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/labels"
+	"github.com/drone/autoscaler/drivers/internal/retry"
 
 	"github.com/digitalocean/godo"
 	"github.com/rs/zerolog/log"
@@ -27,13 +30,31 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		return nil, err
 	}
 
+	size := p.size
+	if opts.Size != "" {
+		size = opts.Size
+	}
+
+	region := p.region
+	if opts.Region != "" {
+		region = opts.Region
+	}
+
+	vpc, err := p.regionVPC(region)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &godo.DropletCreateRequest{
-		Name:     opts.Name,
-		Region:   p.region,
-		Size:     p.size,
-		Tags:     p.tags,
-		IPv6:     false,
-		UserData: buf.String(),
+		Name:       opts.Name,
+		Region:     region,
+		Size:       size,
+		Tags:       append(labels.Tags(p.labels), p.tags...),
+		VPCUUID:    vpc,
+		IPv6:       p.ipv6,
+		Monitoring: p.monitoring,
+		Backups:    p.backups,
+		UserData:   buf.String(),
 		SSHKeys: []godo.DropletCreateSSHKey{
 			{Fingerprint: p.key},
 		},
@@ -53,7 +74,11 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		Msg("instance create")
 
 	client := newClient(ctx, p.token)
-	droplet, _, err := client.Droplets.Create(ctx, req)
+	var droplet *godo.Droplet
+	err = retry.Do(ctx, p.retries, isThrottled, func() (err error) {
+		droplet, _, err = client.Droplets.Create(ctx, req)
+		return err
+	})
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -74,6 +99,21 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		Str("name", instance.Name).
 		Msg("instance created")
 
+	// attach the droplet to the configured cloud firewalls.
+	for _, firewall := range p.firewalls {
+		err = retry.Do(ctx, p.retries, isTransientOrNotFound, func() error {
+			_, err := client.Firewalls.AddDroplets(ctx, firewall, droplet.ID)
+			return err
+		})
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("firewall", firewall).
+				Msg("cannot assign firewall")
+			return instance, err
+		}
+	}
+
 	// poll the digitalocean endpoint for server updates
 	// and exit when a network address is allocated.
 	interval := time.Duration(0)
@@ -93,7 +133,10 @@ poller:
 				Str("name", instance.Name).
 				Msg("find instance network")
 
-			droplet, _, err = client.Droplets.Get(ctx, droplet.ID)
+			err = retry.Do(ctx, p.retries, isTransientOrNotFound, func() (err error) {
+				droplet, _, err = client.Droplets.Get(ctx, droplet.ID)
+				return err
+			})
 			if err != nil {
 				logger.Error().
 					Err(err).
@@ -101,11 +144,7 @@ poller:
 				return instance, err
 			}
 
-			for _, network := range droplet.Networks.V4 {
-				if network.Type == "public" {
-					instance.Address = network.IPAddress
-				}
-			}
+			instance.Address = p.address(droplet)
 
 			if instance.Address != "" {
 				break poller
@@ -113,6 +152,21 @@ poller:
 		}
 	}
 
+	// assign a reserved ip to the droplet, so that the
+	// droplet address can be allow-listed.
+	if p.reservedIP && !p.privateIP {
+		address, err := p.assignReservedIP(ctx, client, droplet, req.Region)
+		if err != nil {
+			return instance, err
+		}
+		instance.Address = address
+	}
+
+	// the droplet size includes the hourly price.
+	if droplet.Size != nil {
+		instance.Price = droplet.Size.PriceHourly
+	}
+
 	logger.Debug().
 		Str("name", instance.Name).
 		Str("ip", instance.Address).
@@ -120,3 +174,34 @@ poller:
 
 	return instance, nil
 }
+
+// helper function returns the droplet address used to reach
+// the docker daemon. The vpc address is returned for private
+// droplets.
+func (p *provider) address(droplet *godo.Droplet) string {
+	kind := "public"
+	if p.privateIP {
+		kind = "private"
+	}
+	for _, network := range droplet.Networks.V4 {
+		if network.Type == kind {
+			return network.IPAddress
+		}
+	}
+	return ""
+}
+
+// helper function returns the vpc of the region. The vpc is
+// scoped to the region, so the configured vpc cannot be used
+// in a failover region, and must be configured for each
+// failover region.
+func (p *provider) regionVPC(region string) (string, error) {
+	if region == p.region {
+		return p.vpc, nil
+	}
+	vpc, ok := p.regionVPCs[region]
+	if !ok && p.vpc != "" {
+		return "", fmt.Errorf("No vpc configured for region %s", region)
+	}
+	return vpc, nil
+}
@@ -44,6 +44,121 @@ func TestCreate(t *testing.T) {
 	}
 
 	t.Run("Attributes", testInstance(instance))
+
+	if got, want := instance.Price, 0.00744; got != want {
+		t.Errorf("Want droplet Price %v, got %v", want, got)
+	}
+}
+
+func TestCreate_Firewalls(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.digitalocean.com").
+		Post("/v2/droplets").
+		Reply(200).
+		BodyString(respDropletCreate)
+
+	gock.New("https://api.digitalocean.com").
+		Post("/v2/firewalls/bb4b2611-3d72-467b-8602-280330ecd65c/droplets").
+		JSON(map[string][]int{"droplet_ids": {3164494}}).
+		Reply(204)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/droplets/3164494").
+		Reply(200).
+		BodyString(respDropletDesc)
+
+	p := New(
+		WithSSHKey("58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7"),
+		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+		WithFirewalls("bb4b2611-3d72-467b-8602-280330ecd65c"),
+		WithVPC("5a4981aa-9653-4bd1-bef5-d6bff52042e4"),
+	).(*provider)
+	p.init.Do(func() {}) // prevent init function
+
+	_, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent1"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestCreate_ReservedIP(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.digitalocean.com").
+		Post("/v2/droplets").
+		Reply(200).
+		BodyString(respDropletCreate)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/droplets/3164494").
+		Reply(200).
+		BodyString(respDropletDesc)
+
+	gock.New("https://api.digitalocean.com").
+		Post("/v2/floating_ips").
+		JSON(map[string]interface{}{"region": "nyc1", "droplet_id": 3164494}).
+		Reply(202).
+		BodyString(`{"floating_ip": {"ip": "45.55.96.47", "droplet": null, "region": {"slug": "nyc3"}}}`)
+
+	p := New(
+		WithSSHKey("58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7"),
+		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+		WithReservedIP(true),
+	).(*provider)
+	p.init.Do(func() {}) // prevent init function
+
+	instance, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent1"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := instance.Address, "45.55.96.47"; got != want {
+		t.Errorf("Want reserved ip address %s, got %s", want, got)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestCreate_Throttled(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.digitalocean.com").
+		Post("/v2/droplets").
+		Reply(429)
+
+	gock.New("https://api.digitalocean.com").
+		Post("/v2/droplets").
+		Reply(200).
+		BodyString(respDropletCreate)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/droplets/3164494").
+		Reply(200).
+		BodyString(respDropletDesc)
+
+	p := New(
+		WithSSHKey("58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7"),
+		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+	).(*provider)
+	p.init.Do(func() {}) // prevent init function
+
+	instance, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent1"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+
+	t.Run("Attributes", testInstance(instance))
 }
 
 func TestCreate_CreateError(t *testing.T) {
@@ -264,7 +379,9 @@ const respDropletDesc = `
       
     ],
     "size": {
-      
+      "slug": "s-1vcpu-1gb",
+      "price_monthly": 5.0,
+      "price_hourly": 0.00744
     },
     "size_slug": "s-1vcpu-1gb",
     "networks": {
@@ -295,3 +412,41 @@ const respDropletDesc = `
   }
 }
 `
+
+func TestRegionVPC(t *testing.T) {
+	p := &provider{
+		region:     "nyc1",
+		vpc:        "vpc-nyc1",
+		regionVPCs: map[string]string{"sfo3": "vpc-sfo3"},
+	}
+	if got, _ := p.regionVPC("nyc1"); got != "vpc-nyc1" {
+		t.Errorf("Want the configured vpc in the default region, got %s", got)
+	}
+	if got, _ := p.regionVPC("sfo3"); got != "vpc-sfo3" {
+		t.Errorf("Want the region vpc in the failover region, got %s", got)
+	}
+	if _, err := p.regionVPC("ams3"); err == nil {
+		t.Errorf("Want error when the failover region vpc is not configured")
+	}
+}
+
+func TestAddress(t *testing.T) {
+	droplet := &godo.Droplet{
+		Networks: &godo.Networks{
+			V4: []godo.NetworkV4{
+				{IPAddress: "10.116.0.2", Type: "private"},
+				{IPAddress: "104.131.186.241", Type: "public"},
+			},
+		},
+	}
+
+	p := &provider{}
+	if got, want := p.address(droplet), "104.131.186.241"; got != want {
+		t.Errorf("Want public address %s, got %s", want, got)
+	}
+
+	p.privateIP = true
+	if got, want := p.address(droplet), "10.116.0.2"; got != want {
+		t.Errorf("Want private address %s, got %s", want, got)
+	}
+}
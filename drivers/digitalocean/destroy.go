@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
 
 	"github.com/rs/zerolog/log"
 )
@@ -27,8 +28,11 @@ func (p *provider) Destroy(ctx context.Context, instance *autoscaler.Instance) e
 		return err
 	}
 
-	_, res, err := client.Droplets.Get(ctx, id)
-	if err != nil && res.StatusCode == 404 {
+	err = retry.Do(ctx, p.retries, isTransient, func() error {
+		_, _, err := client.Droplets.Get(ctx, id)
+		return err
+	})
+	if statusCode(err) == 404 {
 		logger.Warn().
 			Err(err).
 			Msg("droplet does not exist")
@@ -40,10 +44,23 @@ func (p *provider) Destroy(ctx context.Context, instance *autoscaler.Instance) e
 		return err
 	}
 
+	// the reserved ip must be deleted before the droplet is
+	// deleted, while the assignment can still be found.
+	if p.reservedIP {
+		if err := p.deleteReservedIPs(ctx, client, id); err != nil {
+			logger.Warn().
+				Err(err).
+				Msg("cannot delete reserved ip")
+		}
+	}
+
 	logger.Debug().
 		Msg("deleting droplet")
 
-	_, err = client.Droplets.Delete(ctx, id)
+	err = retry.Do(ctx, p.retries, isTransient, func() error {
+		_, err := client.Droplets.Delete(ctx, id)
+		return err
+	})
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -48,6 +48,43 @@ func TestDestroy(t *testing.T) {
 	}
 }
 
+func TestDestroy_ReservedIP(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/droplets/3164494").
+		Reply(200).
+		BodyString(respDropletCreate)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/floating_ips").
+		Reply(200).
+		BodyString(`{"floating_ips": [{"ip": "45.55.96.47", "droplet": {"id": 3164494}}, {"ip": "45.55.96.48", "droplet": {"id": 3164495}}, {"ip": "45.55.96.49", "droplet": null}]}`)
+
+	gock.New("https://api.digitalocean.com").
+		Delete("/v2/floating_ips/45.55.96.47").
+		Reply(204)
+
+	gock.New("https://api.digitalocean.com").
+		Delete("/v2/droplets/3164494").
+		Reply(204)
+
+	p := New(
+		WithSSHKey("58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7"),
+		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+		WithReservedIP(true),
+	)
+
+	err := p.Destroy(context.TODO(), &autoscaler.Instance{ID: "3164494"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
 func TestDestroyDeleteError(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -13,6 +13,22 @@ import (
 // Option configures a Digital Ocean provider option.
 type Option func(*provider)
 
+// WithBackups returns an option to enable automated droplet
+// backups.
+func WithBackups(backups bool) Option {
+	return func(p *provider) {
+		p.backups = backups
+	}
+}
+
+// WithFirewalls returns an option to set the cloud firewalls
+// assigned to the instance.
+func WithFirewalls(firewalls ...string) Option {
+	return func(p *provider) {
+		p.firewalls = firewalls
+	}
+}
+
 // WithImage returns an option to set the image.
 func WithImage(image string) Option {
 	return func(p *provider) {
@@ -20,6 +36,47 @@ func WithImage(image string) Option {
 	}
 }
 
+// WithIPv6 returns an option to enable droplet ipv6
+// networking.
+func WithIPv6(ipv6 bool) Option {
+	return func(p *provider) {
+		p.ipv6 = ipv6
+	}
+}
+
+// WithLabels returns an option to set key value labels,
+// which are applied to the instance as key:value tags.
+func WithLabels(labels map[string]string) Option {
+	return func(p *provider) {
+		p.labels = labels
+	}
+}
+
+// WithMonitoring returns an option to install the monitoring
+// agent, which reports droplet metrics to the dashboard.
+func WithMonitoring(monitoring bool) Option {
+	return func(p *provider) {
+		p.monitoring = monitoring
+	}
+}
+
+// WithPrivateIP returns an option to reach the droplet using
+// its vpc network address instead of the public address.
+func WithPrivateIP(private bool) Option {
+	return func(p *provider) {
+		p.privateIP = private
+	}
+}
+
+// WithReservedIP returns an option to create a reserved ip
+// address assigned to each droplet, which is deleted when the
+// droplet is destroyed.
+func WithReservedIP(reserved bool) Option {
+	return func(p *provider) {
+		p.reservedIP = reserved
+	}
+}
+
 // WithRegion returns an option to set the target region.
 func WithRegion(region string) Option {
 	return func(p *provider) {
@@ -27,6 +84,15 @@ func WithRegion(region string) Option {
 	}
 }
 
+// WithRetries returns an option to set the number of times
+// transient api errors are retried. Zero disables retries and a
+// negative value uses the default.
+func WithRetries(retries int) Option {
+	return func(p *provider) {
+		p.retries = retries
+	}
+}
+
 // WithSize returns an option to set the instance size.
 func WithSize(size string) Option {
 	return func(p *provider) {
@@ -41,7 +107,7 @@ func WithSSHKey(key string) Option {
 	}
 }
 
-// WithTags returns an option to set the image.
+// WithTags returns an option to set the instance tags.
 func WithTags(tags ...string) Option {
 	return func(p *provider) {
 		p.tags = tags
@@ -78,3 +144,32 @@ func WithUserDataFile(filepath string) Option {
 		}
 	}
 }
+
+// WithUserDataHooks returns an option to merge cloud-init
+// sections, such as write_files and runcmd, into the
+// cloud-init template. Entries of the before sections are
+// inserted before the existing entries, and entries of the
+// after sections are inserted after the existing entries.
+func WithUserDataHooks(before, after string) Option {
+	return func(p *provider) {
+		p.userdataPrepend = before
+		p.userdataAppend = after
+	}
+}
+
+// WithRegionVPCs returns an option to set the VPC of each
+// failover region, by region.
+func WithRegionVPCs(vpcs map[string]string) Option {
+	return func(p *provider) {
+		p.regionVPCs = vpcs
+	}
+}
+
+// WithVPC returns an option to set the VPC in which the
+// instance is created. If empty, the instance is created in
+// the default VPC for the region.
+func WithVPC(vpc string) Option {
+	return func(p *provider) {
+		p.vpc = vpc
+	}
+}
@@ -8,12 +8,17 @@ import "testing"
 
 func TestOptions(t *testing.T) {
 	p := New(
+		WithBackups(true),
+		WithFirewalls("bb4b2611-3d72-467b-8602-280330ecd65c"),
+		WithIPv6(true),
+		WithMonitoring(true),
 		WithImage("ubuntu-18-04-x64"),
 		WithRegion("nyc3"),
 		WithSize("s-8vcpu-32gb"),
 		WithSSHKey("58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7"),
 		WithTags("drone", "agent"),
 		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+		WithVPC("5a4981aa-9653-4bd1-bef5-d6bff52042e4"),
 	).(*provider)
 
 	if got, want := p.image, "ubuntu-18-04-x64"; got != want {
@@ -34,4 +39,13 @@ func TestOptions(t *testing.T) {
 	if got, want := len(p.tags), 2; got != want {
 		t.Errorf("Want %d tags, got %d", want, got)
 	}
+	if got, want := len(p.firewalls), 1; got != want {
+		t.Errorf("Want %d firewalls, got %d", want, got)
+	}
+	if got, want := p.vpc, "5a4981aa-9653-4bd1-bef5-d6bff52042e4"; got != want {
+		t.Errorf("Want vpc %q, got %q", want, got)
+	}
+	if !p.backups || !p.ipv6 || !p.monitoring {
+		t.Errorf("Want backups, ipv6 and monitoring enabled")
+	}
 }
@@ -10,6 +10,8 @@ import (
 	"text/template"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
+	"github.com/drone/autoscaler/drivers/internal/userdata"
 
 	"github.com/digitalocean/godo"
 	"golang.org/x/oauth2"
@@ -26,11 +28,27 @@ type provider struct {
 	image    string
 	userdata *template.Template
 	tags     []string
+
+	backups    bool
+	ipv6       bool
+	monitoring bool
+
+	firewalls  []string
+	labels     map[string]string
+	privateIP  bool
+	reservedIP bool
+	retries    int
+	vpc        string
+
+	regionVPCs map[string]string
+
+	userdataPrepend string
+	userdataAppend  string
 }
 
 // New returns a new Digital Ocean provider.
 func New(opts ...Option) autoscaler.Provider {
-	p := new(provider)
+	p := &provider{retries: -1}
 	for _, opt := range opts {
 		opt(p)
 	}
@@ -46,6 +64,10 @@ func New(opts ...Option) autoscaler.Provider {
 	if p.userdata == nil {
 		p.userdata = userdataT
 	}
+	p.userdata = userdata.Hooks(p.userdata, p.userdataPrepend, p.userdataAppend)
+	if p.retries < 0 {
+		p.retries = retry.DefaultRetries
+	}
 	return p
 }
 
@@ -26,4 +26,18 @@ func TestDefaults(t *testing.T) {
 	if got, want := len(p.tags), 0; got != want {
 		t.Errorf("Want %d tags, got %d", want, got)
 	}
+	if got, want := p.retries, 5; got != want {
+		t.Errorf("Want %d retries, got %d", want, got)
+	}
+}
+
+func TestRetriesDisabled(t *testing.T) {
+	p := New(WithRetries(0)).(*provider)
+	if got, want := p.retries, 0; got != want {
+		t.Errorf("Want %d retries, got %d", want, got)
+	}
+	p = New(WithRetries(-1)).(*provider)
+	if got, want := p.retries, 5; got != want {
+		t.Errorf("Want %d retries, got %d", want, got)
+	}
 }
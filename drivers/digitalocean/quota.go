@@ -0,0 +1,43 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package digitalocean
+
+import (
+	"context"
+
+	"github.com/digitalocean/godo"
+	"github.com/rs/zerolog/log"
+)
+
+// Quota returns the number of droplets that can be created
+// before the account droplet limit is exceeded.
+func (p *provider) Quota(ctx context.Context) (int, error) {
+	logger := log.Ctx(ctx)
+	client := newClient(ctx, p.token)
+
+	account, _, err := client.Account.Get(ctx)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot get account details")
+		return 0, err
+	}
+
+	// the droplet list is paginated, however, we only
+	// need the total droplet count from the response.
+	_, res, err := client.Droplets.List(ctx, &godo.ListOptions{PerPage: 1})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot list droplets")
+		return 0, err
+	}
+
+	var count int
+	if res.Meta != nil {
+		count = res.Meta.Total
+	}
+	return account.DropletLimit - count, nil
+}
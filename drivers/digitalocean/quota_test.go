@@ -0,0 +1,45 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package digitalocean
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/h2non/gock"
+)
+
+func TestQuota(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/account").
+		Reply(200).
+		BodyString(`{"account":{"droplet_limit":25,"status":"active"}}`)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/droplets").
+		MatchParam("per_page", "1").
+		Reply(200).
+		BodyString(`{"droplets":[],"meta":{"total":22}}`)
+
+	p := New(
+		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+	)
+
+	got, err := p.(autoscaler.Quoter).Quota(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want := 3; got != want {
+		t.Errorf("Want quota %d, got %d", want, got)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
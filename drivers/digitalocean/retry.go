@@ -0,0 +1,42 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package digitalocean
+
+import (
+	"net/http"
+
+	"github.com/drone/autoscaler/drivers/internal/retry"
+
+	"github.com/digitalocean/godo"
+)
+
+// helper function returns the http status code of the
+// digitalocean error response.
+func statusCode(err error) int {
+	if e, ok := err.(*godo.ErrorResponse); ok && e.Response != nil {
+		return e.Response.StatusCode
+	}
+	return 0
+}
+
+// helper function returns true if the request was rate
+// limited and can be safely retried.
+func isThrottled(err error) bool {
+	return retry.IsThrottled(statusCode(err))
+}
+
+// helper function returns true if the idempotent request
+// failed with a transient error and can be retried.
+func isTransient(err error) bool {
+	return retry.IsTransient(statusCode(err))
+}
+
+// helper function returns true if the idempotent request
+// failed with a transient error, or if the droplet was not
+// found. A newly created droplet may not be immediately
+// visible due to eventual consistency.
+func isTransientOrNotFound(err error) bool {
+	return isTransient(err) || statusCode(err) == http.StatusNotFound
+}
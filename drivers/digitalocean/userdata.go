@@ -16,17 +16,12 @@ write_files:
   - path: /etc/default/docker
     content: |
       DOCKER_OPTS=""
+{{- with daemon . "{\"dns\": [\"8.8.8.8\", \"8.8.4.4\"]}" }}
   - path: /etc/docker/daemon.json
     content: |
-      {
-        "dns": [ "8.8.8.8", "8.8.4.4" ],
-        "hosts": [ "0.0.0.0:2376", "unix:///var/run/docker.sock" ],
-        "tls": true,
-        "tlsverify": true,
-        "tlscacert": "/etc/docker/ca.pem",
-        "tlscert": "/etc/docker/server-cert.pem",
-        "tlskey": "/etc/docker/server-key.pem"
-      }
+{{ indent 6 . }}
+{{- end }}
+{{- if .TLSCert }}
   - path: /etc/docker/ca.pem
     encoding: b64
     content: {{ .CACert | base64 }}
@@ -36,6 +31,7 @@ write_files:
   - path: /etc/docker/server-key.pem
     encoding: b64
     content: {{ .TLSKey | base64 }}
+{{- end }}
 
 runcmd:
   - [ systemctl, daemon-reload ]
@@ -0,0 +1,75 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Validate verifies the api token, ssh key, image, region,
+// size, vpc and cloud firewalls without creating a droplet.
+func (p *provider) Validate(ctx context.Context) error {
+	p.init.Do(func() {
+		p.setup(ctx)
+	})
+
+	client := newClient(ctx, p.token)
+
+	if _, _, err := client.Account.Get(ctx); err != nil {
+		return fmt.Errorf("Cannot authenticate: %s", err)
+	}
+	if p.key == "" {
+		return errors.New("No ssh key configured, and no existing key found")
+	}
+	if _, _, err := client.Keys.GetByFingerprint(ctx, p.key); err != nil {
+		return fmt.Errorf("Cannot find ssh key %s: %s", p.key, err)
+	}
+	if _, _, err := client.Images.GetBySlug(ctx, p.image); err != nil {
+		return fmt.Errorf("Cannot find image %s: %s", p.image, err)
+	}
+
+	regions, _, err := client.Regions.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Cannot list regions: %s", err)
+	}
+	var found bool
+	for _, region := range regions {
+		if region.Slug != p.region {
+			continue
+		}
+		found = true
+		if !contains(region.Sizes, p.size) {
+			return fmt.Errorf("Size %s is not available in region %s", p.size, p.region)
+		}
+	}
+	if !found {
+		return fmt.Errorf("Cannot find region %s", p.region)
+	}
+
+	if p.vpc != "" {
+		if _, _, err := client.VPCs.Get(ctx, p.vpc); err != nil {
+			return fmt.Errorf("Cannot find vpc %s: %s", p.vpc, err)
+		}
+	}
+	for _, firewall := range p.firewalls {
+		if _, _, err := client.Firewalls.Get(ctx, firewall); err != nil {
+			return fmt.Errorf("Cannot find firewall %s: %s", firewall, err)
+		}
+	}
+	return nil
+}
+
+// helper function returns true if the list contains the
+// string.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package digitalocean
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/h2non/gock"
+)
+
+func TestValidate(t *testing.T) {
+	defer gock.Off()
+
+	mockValidate("s-2vcpu-4gb")
+
+	p := New(
+		WithSSHKey("58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7"),
+		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+	)
+
+	err := p.(autoscaler.Validator).Validate(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestValidate_SizeUnavailable(t *testing.T) {
+	defer gock.Off()
+
+	mockValidate("s-1vcpu-1gb")
+
+	p := New(
+		WithSSHKey("58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7"),
+		WithToken("77e027c7447f468068a7d4fea41e7149a75a94088082c66fcf555de3977f69d3"),
+	)
+
+	err := p.(autoscaler.Validator).Validate(context.TODO())
+	if err == nil {
+		t.Errorf("Expect error when size is not available in region")
+	}
+}
+
+func mockValidate(size string) {
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/account").
+		Reply(200).
+		BodyString(`{"account":{"droplet_limit":25,"status":"active"}}`)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/account/keys/58:8e:30:66:fc:e2:ff:ad:4f:6f:02:4b:af:28:0d:c7").
+		Reply(200).
+		BodyString(`{"ssh_key":{"id":512189,"name":"drone"}}`)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/images/docker-18-04").
+		Reply(200).
+		BodyString(`{"image":{"id":7555620,"slug":"docker-18-04"}}`)
+
+	gock.New("https://api.digitalocean.com").
+		Get("/v2/regions").
+		Reply(200).
+		BodyString(`{"regions":[{"slug":"nyc1","sizes":["` + size + `"],"available":true}]}`)
+}
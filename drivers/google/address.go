@@ -0,0 +1,118 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// helper function reserves a static external ip address in
+// the region, named after the instance, and returns the
+// reserved address.
+func (p *provider) reserveAddress(ctx context.Context, name, region string) (string, error) {
+	logger := log.Ctx(ctx).With().
+		Str("name", name).
+		Str("region", region).
+		Logger()
+
+	op, err := p.service.Addresses.Insert(p.project, region, &compute.Address{
+		Name:        name,
+		AddressType: "EXTERNAL",
+		Labels:      p.labels,
+	}).Context(ctx).Do()
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot reserve static address")
+		return "", err
+	}
+
+	err = p.waitRegionOperation(ctx, region, op.Name)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("static address reservation failed")
+		return "", err
+	}
+
+	address, err := p.service.Addresses.Get(p.project, region, name).Context(ctx).Do()
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot get static address")
+		return "", err
+	}
+
+	logger.Debug().
+		Str("ip", address.Address).
+		Msg("static address reserved")
+
+	return address.Address, nil
+}
+
+// helper function releases the static external ip address
+// named after the instance. It is not an error if the
+// address does not exist.
+func (p *provider) releaseAddress(ctx context.Context, name, region string) error {
+	op, err := p.service.Addresses.Delete(p.project, region, name).Context(ctx).Do()
+	if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return p.waitRegionOperation(ctx, region, op.Name)
+}
+
+// helper function releases the static external ip address
+// reserved for an instance that could not be created.
+func (p *provider) cleanupAddress(ctx context.Context, name, zone string) {
+	if !p.staticIP {
+		return
+	}
+	if err := p.releaseAddress(ctx, name, zoneRegion(zone)); err != nil {
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("name", name).
+			Msg("cannot release static address")
+	}
+}
+
+// helper function returns the instance access configuration.
+// An external nat is not configured for private instances.
+func (p *provider) accessConfigs(natIP string) []*compute.AccessConfig {
+	if p.privateIP {
+		return nil
+	}
+	return []*compute.AccessConfig{
+		{
+			Name:  "External NAT",
+			Type:  "ONE_TO_ONE_NAT",
+			NatIP: natIP,
+		},
+	}
+}
+
+// helper function returns the instance address used to
+// reach the docker daemon. The internal network address is
+// returned for private instances.
+func (p *provider) address(instance *compute.Instance) string {
+	if len(instance.NetworkInterfaces) == 0 {
+		return ""
+	}
+	nic := instance.NetworkInterfaces[0]
+	if p.privateIP {
+		return nic.NetworkIP
+	}
+	if len(nic.AccessConfigs) == 0 {
+		return ""
+	}
+	return nic.AccessConfigs[0].NatIP
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestReleaseAddress_NotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://www.googleapis.com").
+		Delete("/compute/v1/projects/my-project/regions/us-central1/addresses/agent-807jvfwj").
+		Reply(404).
+		JSON(map[string]interface{}{"error": map[string]interface{}{"code": 404, "message": "not found"}})
+
+	v, err := New(
+		WithClient(http.DefaultClient),
+		WithProject("my-project"),
+		WithStaticIP(true),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+
+	err = p.releaseAddress(context.TODO(), "agent-807jvfwj", "us-central1")
+	if err != nil {
+		t.Errorf("Want missing address ignored, got %v", err)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestAddress(t *testing.T) {
+	instance := &compute.Instance{
+		NetworkInterfaces: []*compute.NetworkInterface{
+			{
+				NetworkIP: "10.128.0.2",
+				AccessConfigs: []*compute.AccessConfig{
+					{NatIP: "35.192.0.1"},
+				},
+			},
+		},
+	}
+
+	p := &provider{}
+	if got, want := p.address(instance), "35.192.0.1"; got != want {
+		t.Errorf("Want public address %s, got %s", want, got)
+	}
+	if p.accessConfigs("") == nil {
+		t.Errorf("Want external nat access config")
+	}
+
+	p.privateIP = true
+	if got, want := p.address(instance), "10.128.0.2"; got != want {
+		t.Errorf("Want private address %s, got %s", want, got)
+	}
+	if p.accessConfigs("") != nil {
+		t.Errorf("Want no access config for private instances")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
 	"github.com/rs/zerolog/log"
 
 	"google.golang.org/api/compute/v1"
@@ -22,6 +23,10 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		p.setup(ctx)
 	})
 
+	if p.dockerDiskSize != 0 && p.group == "" {
+		opts.Volume = "/dev/disk/by-id/google-" + dockerDiskName
+	}
+
 	buf := new(bytes.Buffer)
 	err := p.userdata.Execute(buf, &opts)
 	if err != nil {
@@ -30,25 +35,65 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 
 	name := strings.ToLower(opts.Name)
 
+	size := p.size
+	if opts.Size != "" {
+		size = opts.Size
+	}
+
+	zone := p.zone
+	if opts.Region != "" {
+		zone = opts.Region
+	}
+
 	logger := log.Ctx(ctx).With().
-		Str("zone", p.zone).
+		Str("zone", zone).
 		Str("image", p.image).
-		Str("size", p.size).
+		Str("size", size).
 		Str("name", opts.Name).
 		Logger()
 
+	if p.group != "" {
+		if opts.Size != "" {
+			logger.Error().
+				Err(errGroupSize).
+				Msg("cannot create instance")
+			return nil, errGroupSize
+		}
+		instance, err := p.createGroupInstance(ctx, name, buf.String())
+		if err != nil {
+			return nil, err
+		}
+		instance.Name = opts.Name
+		return instance, nil
+	}
+
+	image, err := p.resolveImage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// reserve a static external address, so that the
+	// instance address can be allow-listed.
+	var natIP string
+	if p.staticIP && !p.privateIP {
+		natIP, err = p.reserveAddress(ctx, name, zoneRegion(zone))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	logger.Debug().
 		Msg("instance insert")
 
 	in := &compute.Instance{
 		Name:           name,
-		Zone:           fmt.Sprintf("projects/%s/zones/%s", p.project, p.zone),
+		Zone:           fmt.Sprintf("projects/%s/zones/%s", p.project, zone),
 		MinCpuPlatform: "Automatic",
-		MachineType:    fmt.Sprintf("projects/%s/zones/%s/machineTypes/%s", p.project, p.zone, p.size),
+		MachineType:    fmt.Sprintf("projects/%s/zones/%s/machineTypes/%s", p.project, zone, size),
 		Metadata: &compute.Metadata{
 			Items: []*compute.MetadataItems{
 				{
-					Key:   "user-data",
+					Key:   metadataKey(opts.OS),
 					Value: googleapi.String(buf.String()),
 				},
 			},
@@ -56,39 +101,20 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		Tags: &compute.Tags{
 			Items: p.tags,
 		},
-		Disks: []*compute.AttachedDisk{
-			{
-				Type:       "PERSISTENT",
-				Boot:       true,
-				Mode:       "READ_WRITE",
-				AutoDelete: true,
-				DeviceName: name,
-				InitializeParams: &compute.AttachedDiskInitializeParams{
-					SourceImage: fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s", p.image),
-					DiskType:    fmt.Sprintf("projects/%s/zones/%s/diskTypes/%s", p.project, p.zone, p.diskType),
-					DiskSizeGb:  p.diskSize,
-				},
-			},
-		},
+		Disks:        p.disks(name, zone, image),
 		CanIpForward: false,
 		NetworkInterfaces: []*compute.NetworkInterface{
 			{
-				Network: p.network,
-				AccessConfigs: []*compute.AccessConfig{
-					{
-						Name: "External NAT",
-						Type: "ONE_TO_ONE_NAT",
-					},
-				},
+				Network:       p.network,
+				AccessConfigs: p.accessConfigs(natIP),
 			},
 		},
-		Labels: p.labels,
-		Scheduling: &compute.Scheduling{
-			Preemptible:       false,
-			OnHostMaintenance: "MIGRATE",
-			AutomaticRestart:  googleapi.Bool(true),
-		},
-		DeletionProtection: false,
+		Labels:                     p.labels,
+		Scheduling:                 p.scheduling(),
+		ShieldedInstanceConfig:     p.shieldedConfig(),
+		ConfidentialInstanceConfig: p.confidentialConfig(),
+		GuestAccelerators:          p.accelerators(zone),
+		DeletionProtection:         false,
 		ServiceAccounts: []*compute.ServiceAccount{
 			{
 				Scopes: p.scopes,
@@ -97,29 +123,39 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		},
 	}
 
-	op, err := p.service.Instances.Insert(p.project, p.zone, in).Context(ctx).Do()
+	var op *compute.Operation
+	err = retry.Do(ctx, p.retries, isThrottled, func() (err error) {
+		op, err = p.service.Instances.Insert(p.project, zone, in).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Msg("instance insert failed")
+		p.cleanupAddress(ctx, name, zone)
 		return nil, err
 	}
 
 	logger.Debug().
 		Msg("pending instance insert operation")
 
-	err = p.waitZoneOperation(ctx, op.Name)
+	err = p.waitZoneOperation(ctx, zone, op.Name)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Msg("instance insert operation failed")
+		p.cleanupAddress(ctx, name, zone)
 		return nil, err
 	}
 
 	logger.Debug().
 		Msg("instance insert operation complete")
 
-	resp, err := p.service.Instances.Get(p.project, p.zone, name).Do()
+	var resp *compute.Instance
+	err = retry.Do(ctx, p.retries, isTransientOrNotFound, func() (err error) {
+		resp, err = p.service.Instances.Get(p.project, zone, name).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -131,10 +167,10 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		Provider: autoscaler.ProviderGoogle,
 		ID:       name,
 		Name:     opts.Name,
-		Image:    p.image,
-		Region:   p.zone,
-		Size:     p.size,
-		Address:  resp.NetworkInterfaces[0].AccessConfigs[0].NatIP,
+		Image:    image,
+		Region:   zone,
+		Size:     size,
+		Address:  p.address(resp),
 	}
 
 	logger.Debug().
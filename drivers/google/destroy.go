@@ -8,12 +8,30 @@ import (
 	"context"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
+
+	compute "google.golang.org/api/compute/v1"
 )
 
 func (p *provider) Destroy(ctx context.Context, instance *autoscaler.Instance) error {
-	op, err := p.service.Instances.Delete(p.project, p.zone, instance.ID).Context(ctx).Do()
+	if p.group != "" {
+		return p.destroyGroupInstance(ctx, instance)
+	}
+	zone := p.instanceZone(instance)
+	var op *compute.Operation
+	err := retry.Do(ctx, p.retries, isTransient, func() (err error) {
+		op, err = p.service.Instances.Delete(p.project, zone, instance.ID).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		return err
 	}
-	return p.waitZoneOperation(ctx, op.Name)
+	err = p.waitZoneOperation(ctx, zone, op.Name)
+	if err != nil {
+		return err
+	}
+	if p.staticIP {
+		return p.releaseAddress(ctx, instance.ID, zoneRegion(zone))
+	}
+	return nil
 }
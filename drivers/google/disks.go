@@ -0,0 +1,84 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"fmt"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// dockerDiskName is the device name of the docker disk. The
+// disk is exposed to the instance under /dev/disk/by-id.
+const dockerDiskName = "docker"
+
+// helper function returns the attached disks for the named
+// instance in the zone, including the boot disk created from
+// the image, the optional docker disk and any local ssds.
+func (p *provider) disks(name, zone, image string) []*compute.AttachedDisk {
+	disks := []*compute.AttachedDisk{
+		{
+			Type:       "PERSISTENT",
+			Boot:       true,
+			Mode:       "READ_WRITE",
+			AutoDelete: true,
+			DeviceName: name,
+			InitializeParams: &compute.AttachedDiskInitializeParams{
+				SourceImage: fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s", image),
+				DiskType:    fmt.Sprintf("projects/%s/zones/%s/diskTypes/%s", p.project, zone, p.diskType),
+				DiskSizeGb:  p.diskSize,
+			},
+		},
+	}
+	if p.dockerDiskSize != 0 {
+		disks = append(disks, &compute.AttachedDisk{
+			Type:       "PERSISTENT",
+			Mode:       "READ_WRITE",
+			AutoDelete: true,
+			DeviceName: dockerDiskName,
+			InitializeParams: &compute.AttachedDiskInitializeParams{
+				DiskType:   fmt.Sprintf("projects/%s/zones/%s/diskTypes/%s", p.project, zone, p.dockerDiskType),
+				DiskSizeGb: p.dockerDiskSize,
+			},
+		})
+	}
+	for i := 0; i < p.localSSDs; i++ {
+		disks = append(disks, &compute.AttachedDisk{
+			Type:       "SCRATCH",
+			Mode:       "READ_WRITE",
+			AutoDelete: true,
+			Interface:  p.localSSDInterface,
+			DeviceName: fmt.Sprintf("local-ssd-%d", i),
+			InitializeParams: &compute.AttachedDiskInitializeParams{
+				DiskType: fmt.Sprintf("projects/%s/zones/%s/diskTypes/local-ssd", p.project, p.zone),
+			},
+		})
+	}
+	return disks
+}
+
+// helper function returns the custom machine type name for
+// the given machine family, number of vCPUs and memory, in
+// megabytes. The default family is N1.
+func customMachineType(family string, cpus, memory int64) string {
+	if family == "" || family == "n1" {
+		return fmt.Sprintf("custom-%d-%d", cpus, memory)
+	}
+	return fmt.Sprintf("%s-custom-%d-%d", family, cpus, memory)
+}
+
+// helper function returns the accelerators attached to
+// instances in the zone.
+func (p *provider) accelerators(zone string) []*compute.AcceleratorConfig {
+	if p.acceleratorCount == 0 {
+		return nil
+	}
+	return []*compute.AcceleratorConfig{
+		{
+			AcceleratorType:  fmt.Sprintf("projects/%s/zones/%s/acceleratorTypes/%s", p.project, zone, p.acceleratorType),
+			AcceleratorCount: p.acceleratorCount,
+		},
+	}
+}
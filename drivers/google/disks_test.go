@@ -0,0 +1,106 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDisks_LocalSSD(t *testing.T) {
+	v, _ := New(
+		WithClient(http.DefaultClient),
+		WithProject("my-project"),
+		WithZone("us-central1-a"),
+		WithLocalSSD(2, "NVME"),
+	)
+	p := v.(*provider)
+
+	disks := p.disks("agent-1", "us-central1-a", p.image)
+	if got, want := len(disks), 3; got != want {
+		t.Errorf("Want %d disks, got %d", want, got)
+		return
+	}
+	if !disks[0].Boot {
+		t.Errorf("Want first disk to be the boot disk")
+	}
+	for _, disk := range disks[1:] {
+		if got, want := disk.Type, "SCRATCH"; got != want {
+			t.Errorf("Want disk type %q, got %q", want, got)
+		}
+		if got, want := disk.Interface, "NVME"; got != want {
+			t.Errorf("Want disk interface %q, got %q", want, got)
+		}
+		if got, want := disk.InitializeParams.DiskType, "projects/my-project/zones/us-central1-a/diskTypes/local-ssd"; got != want {
+			t.Errorf("Want disk type %q, got %q", want, got)
+		}
+	}
+}
+
+func TestCustomMachineType(t *testing.T) {
+	tests := []struct {
+		family       string
+		cpus, memory int64
+		want         string
+	}{
+		{"", 4, 8192, "custom-4-8192"},
+		{"n1", 2, 4096, "custom-2-4096"},
+		{"n2", 8, 32768, "n2-custom-8-32768"},
+	}
+	for _, test := range tests {
+		if got := customMachineType(test.family, test.cpus, test.memory); got != test.want {
+			t.Errorf("Want machine type %q, got %q", test.want, got)
+		}
+	}
+}
+
+func TestDisks_DockerDisk(t *testing.T) {
+	v, _ := New(
+		WithClient(http.DefaultClient),
+		WithProject("my-project"),
+		WithZone("us-central1-a"),
+		WithDockerDisk(200, ""),
+	)
+	p := v.(*provider)
+
+	disks := p.disks("agent-1", "us-central1-a", p.image)
+	if got, want := len(disks), 2; got != want {
+		t.Errorf("Want %d disks, got %d", want, got)
+		return
+	}
+	if got, want := disks[1].DeviceName, "docker"; got != want {
+		t.Errorf("Want disk device name %q, got %q", want, got)
+	}
+	if got, want := disks[1].InitializeParams.DiskSizeGb, int64(200); got != want {
+		t.Errorf("Want disk size %d, got %d", want, got)
+	}
+	if got, want := disks[1].InitializeParams.DiskType, "projects/my-project/zones/us-central1-a/diskTypes/pd-standard"; got != want {
+		t.Errorf("Want disk type %q, got %q", want, got)
+	}
+}
+
+func TestAccelerators(t *testing.T) {
+	v, _ := New(
+		WithClient(http.DefaultClient),
+		WithProject("my-project"),
+		WithAccelerator("nvidia-tesla-t4", 2),
+	)
+	p := v.(*provider)
+
+	accelerators := p.accelerators("us-central1-a")
+	if got, want := len(accelerators), 1; got != want {
+		t.Errorf("Want %d accelerators, got %d", want, got)
+		return
+	}
+	if got, want := accelerators[0].AcceleratorType, "projects/my-project/zones/us-central1-a/acceleratorTypes/nvidia-tesla-t4"; got != want {
+		t.Errorf("Want accelerator type %q, got %q", want, got)
+	}
+	if got, want := accelerators[0].AcceleratorCount, int64(2); got != want {
+		t.Errorf("Want accelerator count %d, got %d", want, got)
+	}
+	if got, want := p.scheduling().OnHostMaintenance, "TERMINATE"; got != want {
+		t.Errorf("Want accelerator host maintenance %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/rs/zerolog/log"
+
+	"google.golang.org/api/compute/v1"
+)
+
+//
+// When an instance group is configured, instances are
+// created and deleted through the managed instance group
+// instead of the instances api. The instance group owns
+// the instance template and target size, and provides
+// regional spreading and autohealing. The instance group
+// is regional if a region is configured, else zonal.
+//
+
+// errGroupSize is returned when a machine type is configured
+// with an instance group. The machine type of the instances
+// is defined by the instance template of the group, and cannot
+// be set for each instance.
+var errGroupSize = errors.New("The machine type cannot be configured with an instance group, and must be set in the instance template")
+
+// helper function creates an instance in the managed
+// instance group, increasing the target size by one.
+func (p *provider) createGroupInstance(ctx context.Context, name, userdata string) (*autoscaler.Instance, error) {
+	logger := log.Ctx(ctx).With().
+		Str("group", p.group).
+		Str("name", name).
+		Logger()
+
+	config := &compute.PerInstanceConfig{
+		Name: name,
+		PreservedState: &compute.PreservedState{
+			Metadata: map[string]string{
+				"user-data": userdata,
+			},
+		},
+	}
+
+	logger.Debug().
+		Msg("instance group create instance")
+
+	var op *compute.Operation
+	var err error
+	if p.region != "" {
+		op, err = p.service.RegionInstanceGroupManagers.CreateInstances(p.project, p.region, p.group,
+			&compute.RegionInstanceGroupManagersCreateInstancesRequest{
+				Instances: []*compute.PerInstanceConfig{config},
+			},
+		).Context(ctx).Do()
+	} else {
+		op, err = p.service.InstanceGroupManagers.CreateInstances(p.project, p.zone, p.group,
+			&compute.InstanceGroupManagersCreateInstancesRequest{
+				Instances: []*compute.PerInstanceConfig{config},
+			},
+		).Context(ctx).Do()
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("instance group create instance failed")
+		return nil, err
+	}
+
+	err = p.waitGroupOperation(ctx, op.Name)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("instance group create instance operation failed")
+		return nil, err
+	}
+
+	// the instance group creates the instance asynchronously
+	// so we need to wait until the instance is running.
+	managed, err := p.waitGroupInstance(ctx, name)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("instance group instance not running")
+		return nil, err
+	}
+
+	zone := parseZone(managed.Instance)
+	resp, err := p.service.Instances.Get(p.project, zone, name).Context(ctx).Do()
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot get instance details")
+		return nil, err
+	}
+
+	instance := &autoscaler.Instance{
+		Provider: autoscaler.ProviderGoogle,
+		ID:       name,
+		Region:   zone,
+		Size:     path.Base(resp.MachineType),
+	}
+	if managed.Version != nil {
+		instance.Image = path.Base(managed.Version.InstanceTemplate)
+	}
+	instance.Address = p.address(resp)
+	return instance, nil
+}
+
+// helper function deletes the instance from the managed
+// instance group, decreasing the target size by one.
+func (p *provider) destroyGroupInstance(ctx context.Context, instance *autoscaler.Instance) error {
+	url := fmt.Sprintf("projects/%s/zones/%s/instances/%s", p.project, p.instanceZone(instance), instance.ID)
+
+	var op *compute.Operation
+	var err error
+	if p.region != "" {
+		op, err = p.service.RegionInstanceGroupManagers.DeleteInstances(p.project, p.region, p.group,
+			&compute.RegionInstanceGroupManagersDeleteInstancesRequest{
+				Instances: []string{url},
+			},
+		).Context(ctx).Do()
+	} else {
+		op, err = p.service.InstanceGroupManagers.DeleteInstances(p.project, p.zone, p.group,
+			&compute.InstanceGroupManagersDeleteInstancesRequest{
+				Instances: []string{url},
+			},
+		).Context(ctx).Do()
+	}
+	if err != nil {
+		return err
+	}
+	return p.waitGroupOperation(ctx, op.Name)
+}
+
+// helper function returns the managed instances in the
+// instance group.
+func (p *provider) listGroupInstances(ctx context.Context) ([]*compute.ManagedInstance, error) {
+	if p.region != "" {
+		resp, err := p.service.RegionInstanceGroupManagers.ListManagedInstances(p.project, p.region, p.group).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp.ManagedInstances, nil
+	}
+	resp, err := p.service.InstanceGroupManagers.ListManagedInstances(p.project, p.zone, p.group).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return resp.ManagedInstances, nil
+}
+
+// helper function waits until the named instance in the
+// instance group is running.
+func (p *provider) waitGroupInstance(ctx context.Context, name string) (*compute.ManagedInstance, error) {
+	for {
+		instances, err := p.listGroupInstances(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			if path.Base(instance.Instance) != name {
+				continue
+			}
+			if attempt := instance.LastAttempt; attempt != nil && attempt.Errors != nil && len(attempt.Errors.Errors) != 0 {
+				return nil, errors.New(attempt.Errors.Errors[0].Message)
+			}
+			if instance.CurrentAction == "NONE" && instance.InstanceStatus == "RUNNING" {
+				return instance, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// helper function waits for a zonal or regional operation,
+// depending on the instance group type.
+func (p *provider) waitGroupOperation(ctx context.Context, name string) error {
+	if p.region == "" {
+		return p.waitZoneOperation(ctx, p.zone, name)
+	}
+	return p.waitRegionOperation(ctx, p.region, name)
+}
+
+// helper function returns the zone of the instance. Instances
+// in a regional instance group are spread across zones, so
+// the zone is stored with the instance.
+func (p *provider) instanceZone(instance *autoscaler.Instance) string {
+	if instance.Region != "" {
+		return instance.Region
+	}
+	return p.zone
+}
+
+// helper function parses the zone from the instance url.
+func parseZone(url string) string {
+	return path.Base(path.Dir(path.Dir(url)))
+}
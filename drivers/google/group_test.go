@@ -0,0 +1,156 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/h2non/gock"
+)
+
+func TestCreate_InstanceGroup(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://www.googleapis.com").
+		Post("/compute/v1/projects/my-project/regions/us-central1/instanceGroupManagers/my-group/createInstances").
+		Reply(200).
+		BodyString(`{ "name": "operation-name" }`)
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/regions/us-central1/operations/operation-name").
+		Reply(200).
+		BodyString(`{ "status": "DONE" }`)
+
+	gock.New("https://www.googleapis.com").
+		Post("/compute/v1/projects/my-project/regions/us-central1/instanceGroupManagers/my-group/listManagedInstances").
+		Reply(200).
+		BodyString(`{ "managedInstances": [ {
+			"instance": "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-c/instances/agent-807jvfwj",
+			"version": { "instanceTemplate": "https://www.googleapis.com/compute/v1/projects/my-project/global/instanceTemplates/drone-agent" },
+			"instanceStatus": "RUNNING",
+			"currentAction": "NONE"
+		} ] }`)
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/zones/us-central1-c/instances/agent-807jvfwj").
+		Reply(200).
+		BodyString(`{ "machineType": "zones/us-central1-c/machineTypes/n1-standard-2", "networkInterfaces": [ { "accessConfigs": [ { "natIP": "1.2.3.4" } ] } ] }`)
+
+	v, err := New(
+		WithClient(http.DefaultClient),
+		WithInstanceGroup("my-group"),
+		WithProject("my-project"),
+		WithRegion("us-central1"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+	p.init.Do(func() {})
+
+	instance, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent-807jVFwj"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if want, got := instance.Address, "1.2.3.4"; got != want {
+		t.Errorf("Want instance IP %q, got %q", want, got)
+	}
+	if want, got := instance.ID, "agent-807jvfwj"; got != want {
+		t.Errorf("Want instance ID %q, got %q", want, got)
+	}
+	if want, got := instance.Name, "agent-807jVFwj"; got != want {
+		t.Errorf("Want instance Name %q, got %q", want, got)
+	}
+	if want, got := instance.Image, "drone-agent"; got != want {
+		t.Errorf("Want instance Image %q, got %q", want, got)
+	}
+	if want, got := instance.Region, "us-central1-c"; got != want {
+		t.Errorf("Want instance Region %q, got %q", want, got)
+	}
+	if want, got := instance.Size, "n1-standard-2"; got != want {
+		t.Errorf("Want instance Size %q, got %q", want, got)
+	}
+}
+
+func TestCreate_InstanceGroupSize(t *testing.T) {
+	v, err := New(
+		WithClient(http.DefaultClient),
+		WithInstanceGroup("my-group"),
+		WithProject("my-project"),
+		WithRegion("us-central1"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+	p.init.Do(func() {})
+
+	_, err = p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent-807jVFwj", Size: "n1-standard-4"})
+	if err != errGroupSize {
+		t.Errorf("Want instance group size error, got %v", err)
+	}
+}
+
+func TestNew_InstanceGroupSize(t *testing.T) {
+	_, err := New(
+		WithClient(http.DefaultClient),
+		WithInstanceGroup("my-group"),
+		WithMachineType("n1-standard-4"),
+		WithProject("my-project"),
+	)
+	if err != errGroupSize {
+		t.Errorf("Want instance group size error, got %v", err)
+	}
+}
+
+func TestDestroy_InstanceGroup(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://www.googleapis.com").
+		Post("/compute/v1/projects/my-project/zones/us-central1-a/instanceGroupManagers/my-group/deleteInstances").
+		JSON(map[string][]string{
+			"instances": {"projects/my-project/zones/us-central1-a/instances/my-instance"},
+		}).
+		Reply(200).
+		BodyString(`{ "name": "operation-name" }`)
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/zones/us-central1-a/operations/operation-name").
+		Reply(200).
+		BodyString(`{ "status": "DONE" }`)
+
+	p, err := New(
+		WithClient(http.DefaultClient),
+		WithInstanceGroup("my-group"),
+		WithProject("my-project"),
+		WithZone("us-central1-a"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockInstance := &autoscaler.Instance{
+		ID:     "my-instance",
+		Region: "us-central1-a",
+	}
+	if err := p.Destroy(context.TODO(), mockInstance); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseZone(t *testing.T) {
+	url := "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-c/instances/agent-1"
+	if got, want := parseZone(url), "us-central1-c"; got != want {
+		t.Errorf("Want zone %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"strings"
+
+	"github.com/drone/autoscaler/drivers/internal/retry"
+	"github.com/rs/zerolog/log"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// helper function resolves the image. If the image refers to
+// an image family, the latest image in the family is returned
+// in the format project/global/images/name, so that the image
+// used to create the instance is recorded.
+func (p *provider) resolveImage(ctx context.Context) (string, error) {
+	project, family, ok := parseImage(p.image)
+	if !ok {
+		return p.image, nil
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("image-project", project).
+		Str("image-family", family).
+		Logger()
+
+	var image *compute.Image
+	err := retry.Do(ctx, p.retries, isTransient, func() (err error) {
+		image, err = p.service.Images.GetFromFamily(project, family).Context(ctx).Do()
+		return err
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot resolve image family")
+		return "", err
+	}
+
+	logger.Debug().
+		Str("image", image.Name).
+		Msg("resolved image family")
+
+	return project + "/global/images/" + image.Name, nil
+}
+
+// helper function returns the image family path, in the format
+// project/global/images/family/name. The default project is
+// used if the family does not include a project.
+func familyImage(project, family string) string {
+	if parts := strings.SplitN(family, "/", 2); len(parts) == 2 {
+		project, family = parts[0], parts[1]
+	}
+	return project + "/global/images/family/" + family
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestResolveImage(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/ubuntu-os-cloud/global/images/family/ubuntu-2204-lts").
+		Reply(200).
+		BodyString(`{ "name": "ubuntu-2204-jammy-v20230114" }`)
+
+	v, err := New(
+		WithClient(http.DefaultClient),
+		WithProject("my-project"),
+		WithImageFamily("ubuntu-os-cloud/ubuntu-2204-lts"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+
+	image, err := p.resolveImage(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := image, "ubuntu-os-cloud/global/images/ubuntu-2204-jammy-v20230114"; got != want {
+		t.Errorf("Want image %q, got %q", want, got)
+	}
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestResolveImage_Fixed(t *testing.T) {
+	v, _ := New(WithClient(http.DefaultClient))
+	p := v.(*provider)
+
+	image, err := p.resolveImage(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := image, p.image; got != want {
+		t.Errorf("Want image %q, got %q", want, got)
+	}
+}
+
+func TestFamilyImage(t *testing.T) {
+	if got, want := familyImage("my-project", "ubuntu-os-cloud/ubuntu-2204-lts"), "ubuntu-os-cloud/global/images/family/ubuntu-2204-lts"; got != want {
+		t.Errorf("Want image family %q, got %q", want, got)
+	}
+	if got, want := familyImage("my-project", "build-agent"), "my-project/global/images/family/build-agent"; got != want {
+		t.Errorf("Want image family %q, got %q", want, got)
+	}
+}
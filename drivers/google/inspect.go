@@ -0,0 +1,61 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/drone/autoscaler"
+
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// Inspect returns the current instance details. An error is
+// returned if the instance no longer exists, or if it was
+// stopped or terminated by the provider, which happens when
+// preemptible and Spot instances are reclaimed.
+func (p *provider) Inspect(ctx context.Context, instance *autoscaler.Instance) (*autoscaler.Instance, error) {
+	resp, err := p.service.Instances.Get(p.project, p.instanceZone(instance), instance.ID).Context(ctx).Do()
+	if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == http.StatusNotFound {
+		return nil, autoscaler.ErrInstanceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case "STOPPING", "STOPPED", "SUSPENDING", "SUSPENDED", "TERMINATED":
+		return nil, autoscaler.ErrInstanceReclaimed
+	}
+
+	out := *instance
+	out.Address = p.address(resp)
+	out.Status = resp.Status
+	out.Pricing = pricing(resp.Scheduling)
+	if len(resp.NetworkInterfaces) != 0 {
+		nic := resp.NetworkInterfaces[0]
+		out.PrivateAddress = nic.NetworkIP
+		if len(nic.AccessConfigs) != 0 {
+			out.PublicAddress = nic.AccessConfigs[0].NatIP
+		}
+	}
+	return &out, nil
+}
+
+// helper function returns the pricing model of the instance.
+func pricing(scheduling *compute.Scheduling) string {
+	switch {
+	case scheduling == nil:
+		return "standard"
+	case scheduling.ProvisioningModel == "SPOT":
+		return "spot"
+	case scheduling.Preemptible:
+		return "preemptible"
+	default:
+		return "standard"
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/h2non/gock"
+)
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		status string
+		code   int
+		err    error
+	}{
+		{status: "RUNNING", code: 200, err: nil},
+		{status: "TERMINATED", code: 200, err: autoscaler.ErrInstanceReclaimed},
+		{status: "STOPPING", code: 200, err: autoscaler.ErrInstanceReclaimed},
+		{code: 404, err: autoscaler.ErrInstanceNotFound},
+	}
+
+	for _, test := range tests {
+		gock.New("https://www.googleapis.com").
+			Get("/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance").
+			Reply(test.code).
+			JSON(map[string]string{"status": test.status})
+
+		p, err := New(
+			WithClient(http.DefaultClient),
+			WithZone("us-central1-a"),
+			WithProject("my-project"),
+		)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		mockInstance := &autoscaler.Instance{ID: "my-instance"}
+		_, err = p.(autoscaler.Inspector).Inspect(context.TODO(), mockInstance)
+		if err != test.err {
+			t.Errorf("Want error %v for status %q, got %v", test.err, test.status, err)
+		}
+		gock.Off()
+	}
+}
+
+func TestInspect_Details(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance").
+		Reply(200).
+		BodyString(`{"status":"RUNNING","scheduling":{"provisioningModel":"SPOT"},"networkInterfaces":[{"networkIP":"10.0.0.2","accessConfigs":[{"natIP":"35.1.2.3"}]}]}`)
+
+	p, err := New(
+		WithClient(http.DefaultClient),
+		WithZone("us-central1-a"),
+		WithProject("my-project"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	out, err := p.(autoscaler.Inspector).Inspect(context.TODO(), &autoscaler.Instance{ID: "my-instance"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := out.Pricing, "spot"; got != want {
+		t.Errorf("Want pricing %s, got %s", want, got)
+	}
+	if got, want := out.Status, "RUNNING"; got != want {
+		t.Errorf("Want status %s, got %s", want, got)
+	}
+	if got, want := out.PrivateAddress, "10.0.0.2"; got != want {
+		t.Errorf("Want private address %s, got %s", want, got)
+	}
+	if got, want := out.PublicAddress, "35.1.2.3"; got != want {
+		t.Errorf("Want public address %s, got %s", want, got)
+	}
+}
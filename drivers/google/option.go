@@ -16,6 +16,18 @@ import (
 // Option configures a Digital Ocean provider option.
 type Option func(*provider)
 
+// WithAccelerator returns an option to attach the given
+// number of accelerators (e.g. nvidia-tesla-t4) to the
+// instance.
+func WithAccelerator(acceleratorType string, count int64) Option {
+	return func(p *provider) {
+		if acceleratorType != "" && count != 0 {
+			p.acceleratorType = acceleratorType
+			p.acceleratorCount = count
+		}
+	}
+}
+
 // WithClient returns an option to set the default http
 // Client used with the Google Compute provider.
 func WithClient(client *http.Client) Option {
@@ -28,6 +40,25 @@ func WithClient(client *http.Client) Option {
 	}
 }
 
+// WithConfidentialCompute returns an option to create
+// confidential instances.
+func WithConfidentialCompute(confidential bool) Option {
+	return func(p *provider) {
+		p.confidential = confidential
+	}
+}
+
+// WithCustomMachineType returns an option to set a custom
+// instance type with the given machine family, number of
+// vCPUs and memory in megabytes (e.g. custom-4-8192).
+func WithCustomMachineType(family string, cpus, memory int64) Option {
+	return func(p *provider) {
+		if cpus != 0 && memory != 0 {
+			p.size = customMachineType(family, cpus, memory)
+		}
+	}
+}
+
 // WithDiskSize returns an option to set the instance disk
 // size in gigabytes.
 func WithDiskSize(diskSize int64) Option {
@@ -43,6 +74,35 @@ func WithDiskType(diskType string) Option {
 	}
 }
 
+// WithDockerDisk returns an option to attach a persistent disk
+// with the given size in gigabytes and disk type, which is
+// mounted as the docker data directory. The disk type defaults
+// to the boot disk type.
+func WithDockerDisk(size int64, diskType string) Option {
+	return func(p *provider) {
+		p.dockerDiskSize = size
+		p.dockerDiskType = diskType
+	}
+}
+
+// WithInstanceGroup returns an option to create instances
+// in the named managed instance group, instead of creating
+// standalone instances. The machine type is defined by the
+// instance template of the group, and cannot be configured.
+func WithInstanceGroup(group string) Option {
+	return func(p *provider) {
+		p.group = group
+	}
+}
+
+// WithIntegrityMonitoring returns an option to enable shielded
+// vm integrity monitoring.
+func WithIntegrityMonitoring(enabled bool) Option {
+	return func(p *provider) {
+		p.integrityMonitoring = enabled
+	}
+}
+
 // WithLabels returns an option to set the metadata labels.
 func WithLabels(labels map[string]string) Option {
 	return func(p *provider) {
@@ -50,6 +110,27 @@ func WithLabels(labels map[string]string) Option {
 	}
 }
 
+// WithLocalSSD returns an option to attach local ssds to the
+// instance using the given interface, either SCSI or NVME. Local
+// ssds are ephemeral and are deleted with the instance.
+func WithLocalSSD(count int, iface string) Option {
+	return func(p *provider) {
+		p.localSSDs = count
+		p.localSSDInterface = iface
+	}
+}
+
+// WithImageFamily returns an option to create instances
+// from the latest image in the image family, in the format
+// project/family. The instance project is used if the project
+// is omitted. The image family takes precedence over the
+// machine image.
+func WithImageFamily(family string) Option {
+	return func(p *provider) {
+		p.imageFamily = family
+	}
+}
+
 // WithMachineImage returns an option to set the image.
 func WithMachineImage(image string) Option {
 	return func(p *provider) {
@@ -71,6 +152,32 @@ func WithNetwork(network string) Option {
 	}
 }
 
+// WithNodeGroup returns an option to create instances on
+// sole-tenant nodes in the node group.
+func WithNodeGroup(group string) Option {
+	return func(p *provider) {
+		p.nodeGroup = group
+	}
+}
+
+// WithPreemptible returns an option to create preemptible
+// instances. Preemptible instances are terminated by the
+// provider after 24 hours, or earlier when capacity is needed.
+func WithPreemptible(preemptible bool) Option {
+	return func(p *provider) {
+		p.preemptible = preemptible
+	}
+}
+
+// WithPrivateIP returns an option to create instances
+// without an external ip address. The instance is reached
+// using its internal network address.
+func WithPrivateIP(private bool) Option {
+	return func(p *provider) {
+		p.privateIP = private
+	}
+}
+
 // WithProject returns an option to set the project.
 func WithProject(project string) Option {
 	return func(p *provider) {
@@ -78,6 +185,49 @@ func WithProject(project string) Option {
 	}
 }
 
+// WithRegion returns an option to set the region of a
+// regional managed instance group.
+func WithRegion(region string) Option {
+	return func(p *provider) {
+		p.region = region
+	}
+}
+
+// WithRetries returns an option to set the number of times
+// transient api errors are retried. Zero disables retries and a
+// negative value uses the default.
+func WithRetries(retries int) Option {
+	return func(p *provider) {
+		p.retries = retries
+	}
+}
+
+// WithSecureBoot returns an option to enable shielded vm
+// secure boot.
+func WithSecureBoot(enabled bool) Option {
+	return func(p *provider) {
+		p.secureBoot = enabled
+	}
+}
+
+// WithSpot returns an option to create instances using the
+// Spot provisioning model.
+func WithSpot(spot bool) Option {
+	return func(p *provider) {
+		p.spot = spot
+	}
+}
+
+// WithStaticIP returns an option to reserve a static
+// external ip address for each instance, which is released
+// when the instance is destroyed. It is not supported with
+// managed instance groups.
+func WithStaticIP(staticIP bool) Option {
+	return func(p *provider) {
+		p.staticIP = staticIP
+	}
+}
+
 // WithTags returns an option to set the resource tags.
 func WithTags(tags ...string) Option {
 	return func(p *provider) {
@@ -85,6 +235,15 @@ func WithTags(tags ...string) Option {
 	}
 }
 
+// WithTerminationAction returns an option to set the action
+// taken when a Spot instance is reclaimed, either DELETE or
+// STOP. The default action is DELETE.
+func WithTerminationAction(action string) Option {
+	return func(p *provider) {
+		p.termination = action
+	}
+}
+
 // WithUserData returns an option to set the cloud-init
 // template from text.
 func WithUserData(text string) Option {
@@ -109,6 +268,26 @@ func WithUserDataFile(filepath string) Option {
 	}
 }
 
+// WithUserDataHooks returns an option to merge cloud-init
+// sections, such as write_files and runcmd, into the
+// cloud-init template. Entries of the before sections are
+// inserted before the existing entries, and entries of the
+// after sections are inserted after the existing entries.
+func WithUserDataHooks(before, after string) Option {
+	return func(p *provider) {
+		p.userdataPrepend = before
+		p.userdataAppend = after
+	}
+}
+
+// WithVTPM returns an option to enable the shielded vm
+// virtual trusted platform module.
+func WithVTPM(enabled bool) Option {
+	return func(p *provider) {
+		p.vtpm = enabled
+	}
+}
+
 // WithZone returns an option to set the target zone.
 func WithZone(zone string) Option {
 	return func(p *provider) {
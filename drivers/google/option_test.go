@@ -16,9 +16,13 @@ func TestOptions(t *testing.T) {
 		WithDiskType("local-ssd"),
 		WithMachineImage("ubuntu-1604-lts"),
 		WithMachineType("c3.large"),
+		WithLocalSSD(1, ""),
 		WithNetwork("global/defaults/foo"),
+		WithPreemptible(true),
 		WithProject("my-project"),
+		WithSpot(true),
 		WithTags("drone", "agent"),
+		WithTerminationAction("STOP"),
 		WithZone("us-central1-f"),
 	)
 	if err != nil {
@@ -36,9 +40,21 @@ func TestOptions(t *testing.T) {
 	if got, want := p.image, "ubuntu-1604-lts"; got != want {
 		t.Errorf("Want image %q, got %q", want, got)
 	}
+	if got, want := p.localSSDInterface, "SCSI"; got != want {
+		t.Errorf("Want default local ssd interface %q, got %q", want, got)
+	}
 	if got, want := p.network, "global/defaults/foo"; got != want {
 		t.Errorf("Want network %q, got %q", want, got)
 	}
+	if got, want := p.preemptible, true; got != want {
+		t.Errorf("Want preemptible %v, got %v", want, got)
+	}
+	if got, want := p.spot, true; got != want {
+		t.Errorf("Want spot %v, got %v", want, got)
+	}
+	if got, want := p.termination, "STOP"; got != want {
+		t.Errorf("Want termination action %q, got %q", want, got)
+	}
 	if got, want := p.project, "my-project"; got != want {
 		t.Errorf("Want project %q, got %q", want, got)
 	}
@@ -52,3 +68,80 @@ func TestOptions(t *testing.T) {
 		t.Errorf("Want zone %q, got %q", want, got)
 	}
 }
+
+func TestScheduling(t *testing.T) {
+	v, _ := New(WithClient(http.DefaultClient))
+	p := v.(*provider)
+	if s := p.scheduling(); s.Preemptible || s.OnHostMaintenance != "MIGRATE" || !*s.AutomaticRestart {
+		t.Errorf("Want default scheduling to migrate and restart, got %+v", s)
+	}
+
+	v, _ = New(WithClient(http.DefaultClient), WithPreemptible(true))
+	p = v.(*provider)
+	if s := p.scheduling(); !s.Preemptible || s.OnHostMaintenance != "TERMINATE" || *s.AutomaticRestart {
+		t.Errorf("Want preemptible scheduling, got %+v", s)
+	}
+
+	v, _ = New(WithClient(http.DefaultClient), WithSpot(true))
+	p = v.(*provider)
+	s := p.scheduling()
+	if got, want := s.ProvisioningModel, "SPOT"; got != want {
+		t.Errorf("Want provisioning model %q, got %q", want, got)
+	}
+	if got, want := s.InstanceTerminationAction, "DELETE"; got != want {
+		t.Errorf("Want default termination action %q, got %q", want, got)
+	}
+}
+
+func TestScheduling_NodeGroup(t *testing.T) {
+	v, _ := New(WithClient(http.DefaultClient))
+	p := v.(*provider)
+	if s := p.scheduling(); s.NodeAffinities != nil {
+		t.Errorf("Want no node affinities by default, got %+v", s.NodeAffinities)
+	}
+
+	v, _ = New(WithClient(http.DefaultClient), WithNodeGroup("build-nodes"))
+	p = v.(*provider)
+	s := p.scheduling()
+	if len(s.NodeAffinities) != 1 {
+		t.Errorf("Want node group affinity")
+		return
+	}
+	affinity := s.NodeAffinities[0]
+	if got, want := affinity.Key, "compute.googleapis.com/node-group-name"; got != want {
+		t.Errorf("Want affinity key %q, got %q", want, got)
+	}
+	if got, want := affinity.Values[0], "build-nodes"; got != want {
+		t.Errorf("Want affinity value %q, got %q", want, got)
+	}
+}
+
+func TestShieldedConfig(t *testing.T) {
+	v, _ := New(WithClient(http.DefaultClient))
+	p := v.(*provider)
+	if p.shieldedConfig() != nil {
+		t.Errorf("Want nil shielded config by default")
+	}
+	if p.confidentialConfig() != nil {
+		t.Errorf("Want nil confidential config by default")
+	}
+
+	v, _ = New(
+		WithClient(http.DefaultClient),
+		WithSecureBoot(true),
+		WithVTPM(true),
+		WithIntegrityMonitoring(true),
+		WithConfidentialCompute(true),
+	)
+	p = v.(*provider)
+	shielded := p.shieldedConfig()
+	if shielded == nil || !shielded.EnableSecureBoot || !shielded.EnableVtpm || !shielded.EnableIntegrityMonitoring {
+		t.Errorf("Want shielded config enabled, got %+v", shielded)
+	}
+	if c := p.confidentialConfig(); c == nil || !c.EnableConfidentialCompute {
+		t.Errorf("Want confidential compute enabled")
+	}
+	if got, want := p.scheduling().OnHostMaintenance, "TERMINATE"; got != want {
+		t.Errorf("Want confidential host maintenance %q, got %q", want, got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
 	"github.com/drone/autoscaler/drivers/internal/userdata"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -35,24 +36,49 @@ var (
 type provider struct {
 	init sync.Once
 
-	diskSize int64
-	diskType string
-	image    string
-	labels   map[string]string
-	network  string
-	project  string
-	scopes   []string
-	size     string
-	tags     []string
-	zone     string
-	userdata *template.Template
+	confidential        bool
+	integrityMonitoring bool
+	secureBoot          bool
+	vtpm                bool
+
+	acceleratorType   string
+	acceleratorCount  int64
+	dockerDiskSize    int64
+	dockerDiskType    string
+	localSSDs         int
+	localSSDInterface string
+
+	diskSize    int64
+	diskType    string
+	group       string
+	image       string
+	imageFamily string
+	labels      map[string]string
+	network     string
+	nodeGroup   string
+	preemptible bool
+	privateIP   bool
+	project     string
+	region      string
+	retries     int
+	scopes      []string
+	size        string
+	spot        bool
+	staticIP    bool
+	tags        []string
+	termination string
+	zone        string
+	userdata    *template.Template
 
 	service *compute.Service
+
+	userdataPrepend string
+	userdataAppend  string
 }
 
 // New returns a new Digital Ocean provider.
 func New(opts ...Option) (autoscaler.Provider, error) {
-	p := new(provider)
+	p := &provider{retries: -1}
 	for _, opt := range opts {
 		opt(p)
 	}
@@ -65,9 +91,15 @@ func New(opts ...Option) (autoscaler.Provider, error) {
 	if p.zone == "" {
 		p.zone = "us-central1-a"
 	}
+	if p.group != "" && p.size != "" {
+		return nil, errGroupSize
+	}
 	if p.size == "" {
 		p.size = "n1-standard-1"
 	}
+	if p.imageFamily != "" {
+		p.image = familyImage(p.project, p.imageFamily)
+	}
 	if p.image == "" {
 		p.image = "ubuntu-os-cloud/global/images/ubuntu-1604-xenial-v20170721"
 	}
@@ -77,12 +109,25 @@ func New(opts ...Option) (autoscaler.Provider, error) {
 	if p.userdata == nil {
 		p.userdata = userdata.T
 	}
+	p.userdata = userdata.Hooks(p.userdata, p.userdataPrepend, p.userdataAppend)
 	if len(p.tags) == 0 {
 		p.tags = defaultTags
 	}
 	if len(p.scopes) == 0 {
 		p.scopes = defaultScopes
 	}
+	if p.dockerDiskType == "" {
+		p.dockerDiskType = p.diskType
+	}
+	if p.localSSDs != 0 && p.localSSDInterface == "" {
+		p.localSSDInterface = "SCSI"
+	}
+	if p.retries < 0 {
+		p.retries = retry.DefaultRetries
+	}
+	if p.spot && p.termination == "" {
+		p.termination = "DELETE"
+	}
 	if p.service == nil {
 		client, err := google.DefaultClient(oauth2.NoContext, p.scopes...)
 		if err != nil {
@@ -96,14 +141,38 @@ func New(opts ...Option) (autoscaler.Provider, error) {
 	return p, nil
 }
 
-func (p *provider) waitZoneOperation(ctx context.Context, name string) error {
+func (p *provider) waitZoneOperation(ctx context.Context, zone, name string) error {
 	for {
-		op, err := p.service.ZoneOperations.Get(p.project, p.zone, name).Context(ctx).Do()
+		var op *compute.Operation
+		err := retry.Do(ctx, p.retries, isTransient, func() (err error) {
+			op, err = p.service.ZoneOperations.Get(p.project, zone, name).Context(ctx).Do()
+			return err
+		})
 		if err != nil {
 			return err
 		}
 		if op.Error != nil {
-			return errors.New(op.Error.Errors[0].Message)
+			return operationError(op)
+		}
+		if op.Status == "DONE" {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (p *provider) waitRegionOperation(ctx context.Context, region, name string) error {
+	for {
+		var op *compute.Operation
+		err := retry.Do(ctx, p.retries, isTransient, func() (err error) {
+			op, err = p.service.RegionOperations.Get(p.project, region, name).Context(ctx).Do()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if op.Error != nil {
+			return operationError(op)
 		}
 		if op.Status == "DONE" {
 			return nil
@@ -127,3 +196,25 @@ func (p *provider) waitGlogalOperation(ctx context.Context, name string) error {
 		time.Sleep(time.Second)
 	}
 }
+
+// helper function returns the operation error. Zone resource
+// exhaustion errors are converted to ErrInsufficientCapacity.
+func operationError(op *compute.Operation) error {
+	for _, err := range op.Error.Errors {
+		switch err.Code {
+		case "ZONE_RESOURCE_POOL_EXHAUSTED", "ZONE_RESOURCE_POOL_EXHAUSTED_WITH_DETAILS":
+			return autoscaler.ErrInsufficientCapacity
+		}
+	}
+	return errors.New(op.Error.Errors[0].Message)
+}
+
+// helper function returns the metadata key used to pass the
+// userdata to the instance. Windows instances run the userdata
+// as a powershell startup script.
+func metadataKey(os string) string {
+	if os == "windows" {
+		return "windows-startup-script-ps1"
+	}
+	return "user-data"
+}
@@ -50,3 +50,12 @@ func TestDefaults(t *testing.T) {
 		t.Errorf("Want region %q, got %q", want, got)
 	}
 }
+
+func TestMetadataKey(t *testing.T) {
+	if got, want := metadataKey("linux"), "user-data"; got != want {
+		t.Errorf("Want metadata key %q, got %q", want, got)
+	}
+	if got, want := metadataKey("windows"), "windows-startup-script-ps1"; got != want {
+		t.Errorf("Want metadata key %q, got %q", want, got)
+	}
+}
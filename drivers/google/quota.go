@@ -0,0 +1,80 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	compute "google.golang.org/api/compute/v1"
+)
+
+// Quota returns the number of instances that can be created
+// before the regional vCPU or instance quota is exceeded.
+func (p *provider) Quota(ctx context.Context) (int, error) {
+	logger := log.Ctx(ctx)
+
+	machine, err := p.service.MachineTypes.Get(p.project, p.zone, p.size).Context(ctx).Do()
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("size", p.size).
+			Msg("cannot get machine type")
+		return 0, err
+	}
+
+	region := p.region
+	if region == "" {
+		region = zoneRegion(p.zone)
+	}
+	resp, err := p.service.Regions.Get(p.project, region).Context(ctx).Do()
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("region", region).
+			Msg("cannot get region quotas")
+		return 0, err
+	}
+
+	// preemptible and spot instances consume the preemptible
+	// cpu quota if granted, else the standard cpu quota.
+	cpus := findQuota(resp.Quotas, "CPUS")
+	if p.preemptible || p.spot {
+		if quota := findQuota(resp.Quotas, "PREEMPTIBLE_CPUS"); quota != nil && quota.Limit > 0 {
+			cpus = quota
+		}
+	}
+
+	remaining := math.MaxInt32
+	if cpus != nil && machine.GuestCpus > 0 {
+		remaining = int((cpus.Limit - cpus.Usage) / float64(machine.GuestCpus))
+	}
+	if quota := findQuota(resp.Quotas, "INSTANCES"); quota != nil {
+		if n := int(quota.Limit - quota.Usage); n < remaining {
+			remaining = n
+		}
+	}
+	return remaining, nil
+}
+
+// helper function returns the named quota metric.
+func findQuota(quotas []*compute.Quota, metric string) *compute.Quota {
+	for _, quota := range quotas {
+		if quota.Metric == metric {
+			return quota
+		}
+	}
+	return nil
+}
+
+// helper function returns the region of the zone.
+func zoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i != -1 {
+		return zone[:i]
+	}
+	return zone
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/h2non/gock"
+)
+
+func TestQuota(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/zones/us-central1-a/machineTypes/n1-standard-4").
+		Reply(200).
+		JSON(map[string]interface{}{"guestCpus": 4})
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/regions/us-central1").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"quotas": []map[string]interface{}{
+				{"metric": "CPUS", "limit": 24, "usage": 10},
+				{"metric": "INSTANCES", "limit": 100, "usage": 3},
+			},
+		})
+
+	p, err := New(
+		WithClient(http.DefaultClient),
+		WithZone("us-central1-a"),
+		WithProject("my-project"),
+		WithMachineType("n1-standard-4"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	got, err := p.(autoscaler.Quoter).Quota(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want := 3; got != want {
+		t.Errorf("Want quota %d, got %d", want, got)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestZoneRegion(t *testing.T) {
+	if got, want := zoneRegion("us-central1-a"), "us-central1"; got != want {
+		t.Errorf("Want region %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"net/http"
+
+	"github.com/drone/autoscaler/drivers/internal/retry"
+
+	"google.golang.org/api/googleapi"
+)
+
+// helper function returns true if the request was rate
+// limited and can be safely retried. Google returns either
+// a 429 or a 403 with a rate limit reason.
+func isThrottled(err error) bool {
+	gerr, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+	if retry.IsThrottled(gerr.Code) {
+		return true
+	}
+	if gerr.Code == http.StatusForbidden {
+		for _, item := range gerr.Errors {
+			switch item.Reason {
+			case "rateLimitExceeded", "userRateLimitExceeded":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// helper function returns true if the idempotent request
+// failed with a transient error and can be retried.
+func isTransient(err error) bool {
+	if gerr, ok := err.(*googleapi.Error); ok && retry.IsTransient(gerr.Code) {
+		return true
+	}
+	return isThrottled(err)
+}
+
+// helper function returns true if the idempotent request
+// failed with a transient error, or if the resource was not
+// found. A newly created instance may not be immediately
+// visible due to eventual consistency.
+func isTransientOrNotFound(err error) bool {
+	if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == http.StatusNotFound {
+		return true
+	}
+	return isTransient(err)
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsThrottled(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: errors.New("error"), want: false},
+		{err: &googleapi.Error{Code: 429}, want: true},
+		{err: &googleapi.Error{Code: 403}, want: false},
+		{err: &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, want: true},
+		{err: &googleapi.Error{Code: 503}, want: false},
+	}
+	for _, test := range tests {
+		if got := isThrottled(test.err); got != test.want {
+			t.Errorf("Want throttled %v for error %v", test.want, test.err)
+		}
+	}
+}
+
+func TestIsTransientOrNotFound(t *testing.T) {
+	if !isTransientOrNotFound(&googleapi.Error{Code: 404}) {
+		t.Errorf("Want not found error retried")
+	}
+	if !isTransientOrNotFound(&googleapi.Error{Code: 503}) {
+		t.Errorf("Want service unavailable error retried")
+	}
+	if isTransient(&googleapi.Error{Code: 404}) {
+		t.Errorf("Want not found error not transient")
+	}
+}
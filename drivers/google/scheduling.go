@@ -0,0 +1,67 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// helper function returns the instance scheduling options.
+// Preemptible and Spot instances cannot be live migrated or
+// automatically restarted. Confidential instances and
+// instances with accelerators cannot be live migrated.
+func (p *provider) scheduling() *compute.Scheduling {
+	scheduling := p.provisioning()
+	scheduling.NodeAffinities = p.nodeAffinities()
+	return scheduling
+}
+
+// helper function returns the instance provisioning and host
+// maintenance options.
+func (p *provider) provisioning() *compute.Scheduling {
+	switch {
+	case p.spot:
+		return &compute.Scheduling{
+			ProvisioningModel:         "SPOT",
+			InstanceTerminationAction: p.termination,
+			OnHostMaintenance:         "TERMINATE",
+			AutomaticRestart:          googleapi.Bool(false),
+		}
+	case p.preemptible:
+		return &compute.Scheduling{
+			Preemptible:       true,
+			OnHostMaintenance: "TERMINATE",
+			AutomaticRestart:  googleapi.Bool(false),
+		}
+	case p.confidential, p.acceleratorCount != 0:
+		return &compute.Scheduling{
+			Preemptible:       false,
+			OnHostMaintenance: "TERMINATE",
+			AutomaticRestart:  googleapi.Bool(true),
+		}
+	default:
+		return &compute.Scheduling{
+			Preemptible:       false,
+			OnHostMaintenance: "MIGRATE",
+			AutomaticRestart:  googleapi.Bool(true),
+		}
+	}
+}
+
+// helper function returns the node affinities used to place
+// instances on sole-tenant nodes in the node group.
+func (p *provider) nodeAffinities() []*compute.SchedulingNodeAffinity {
+	if p.nodeGroup == "" {
+		return nil
+	}
+	return []*compute.SchedulingNodeAffinity{
+		{
+			Key:      "compute.googleapis.com/node-group-name",
+			Operator: "IN",
+			Values:   []string{p.nodeGroup},
+		},
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"google.golang.org/api/compute/v1"
+)
+
+// helper function returns the shielded instance configuration,
+// or nil if no shielded vm options are enabled.
+func (p *provider) shieldedConfig() *compute.ShieldedInstanceConfig {
+	if !p.secureBoot && !p.vtpm && !p.integrityMonitoring {
+		return nil
+	}
+	return &compute.ShieldedInstanceConfig{
+		EnableSecureBoot:          p.secureBoot,
+		EnableVtpm:                p.vtpm,
+		EnableIntegrityMonitoring: p.integrityMonitoring,
+	}
+}
+
+// helper function returns the confidential instance
+// configuration, or nil if confidential compute is disabled.
+func (p *provider) confidentialConfig() *compute.ConfidentialInstanceConfig {
+	if !p.confidential {
+		return nil
+	}
+	return &compute.ConfidentialInstanceConfig{
+		EnableConfidentialCompute: true,
+	}
+}
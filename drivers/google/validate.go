@@ -0,0 +1,72 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Validate verifies the credentials, zone, machine type,
+// image, network and instance group without creating an
+// instance.
+func (p *provider) Validate(ctx context.Context) error {
+	if _, err := p.service.Zones.Get(p.project, p.zone).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("Cannot find zone %s in project %s: %s", p.zone, p.project, err)
+	}
+	if _, err := p.service.MachineTypes.Get(p.project, p.zone, p.size).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("Cannot find machine type %s in zone %s: %s", p.size, p.zone, err)
+	}
+
+	project, name, family := parseImage(p.image)
+	if family {
+		_, err := p.service.Images.GetFromFamily(project, name).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("Cannot find image family %s: %s", p.image, err)
+		}
+	} else {
+		_, err := p.service.Images.Get(project, name).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("Cannot find image %s: %s", p.image, err)
+		}
+	}
+
+	network := path.Base(p.network)
+	if _, err := p.service.Networks.Get(p.project, network).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("Cannot find network %s: %s", p.network, err)
+	}
+
+	if p.group != "" {
+		var err error
+		if p.region != "" {
+			_, err = p.service.RegionInstanceGroupManagers.Get(p.project, p.region, p.group).Context(ctx).Do()
+		} else {
+			_, err = p.service.InstanceGroupManagers.Get(p.project, p.zone, p.group).Context(ctx).Do()
+		}
+		if err != nil {
+			return fmt.Errorf("Cannot find instance group %s: %s", p.group, err)
+		}
+	}
+	return nil
+}
+
+// helper function parses the image in the format
+// project/global/images/name or project/global/images/family/name
+// and returns the project and name, and whether the name is an
+// image family.
+func parseImage(image string) (project, name string, family bool) {
+	parts := strings.SplitN(image, "/global/images/", 2)
+	if len(parts) != 2 {
+		return "", image, false
+	}
+	project = strings.TrimPrefix(parts[0], "projects/")
+	name = parts[1]
+	if strings.HasPrefix(name, "family/") {
+		return project, strings.TrimPrefix(name, "family/"), true
+	}
+	return project, name, false
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package google
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/h2non/gock"
+)
+
+func TestValidate(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/zones/us-central1-a").
+		Reply(200).
+		JSON(map[string]string{"name": "us-central1-a"})
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/my-project/zones/us-central1-a/machineTypes/n1-standard-1").
+		Reply(200).
+		JSON(map[string]string{"name": "n1-standard-1"})
+
+	gock.New("https://www.googleapis.com").
+		Get("/compute/v1/projects/ubuntu-os-cloud/global/images/ubuntu-1604-xenial-v20170721").
+		Reply(404).
+		JSON(map[string]interface{}{"error": map[string]interface{}{"code": 404, "message": "not found"}})
+
+	p, err := New(
+		WithClient(http.DefaultClient),
+		WithZone("us-central1-a"),
+		WithProject("my-project"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = p.(autoscaler.Validator).Validate(context.TODO())
+	if err == nil {
+		t.Errorf("Expect error when image does not exist")
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		image   string
+		project string
+		name    string
+		family  bool
+	}{
+		{
+			image:   "ubuntu-os-cloud/global/images/ubuntu-1604-xenial-v20170721",
+			project: "ubuntu-os-cloud",
+			name:    "ubuntu-1604-xenial-v20170721",
+		},
+		{
+			image:   "projects/cos-cloud/global/images/family/cos-stable",
+			project: "cos-cloud",
+			name:    "cos-stable",
+			family:  true,
+		},
+	}
+	for _, test := range tests {
+		project, name, family := parseImage(test.image)
+		if project != test.project || name != test.name || family != test.family {
+			t.Errorf("Want image %q parsed as %s, %s, %v, got %s, %s, %v",
+				test.image, test.project, test.name, test.family, project, name, family)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package hetznercloud
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler/drivers/internal/retry"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"github.com/rs/zerolog/log"
+)
+
+// helper function creates a floating ip address assigned to
+// the server. The floating ip is named after the server, so
+// that it can be found when the server is destroyed.
+func (p *provider) assignFloatingIP(ctx context.Context, server *hcloud.Server) error {
+	logger := log.Ctx(ctx).With().
+		Int("id", server.ID).
+		Str("name", server.Name).
+		Logger()
+
+	var resp hcloud.FloatingIPCreateResult
+	err := retry.Do(ctx, p.retries, isThrottled, func() (err error) {
+		resp, _, err = p.client.FloatingIP.Create(ctx, hcloud.FloatingIPCreateOpts{
+			Type:   hcloud.FloatingIPTypeIPv4,
+			Name:   hcloud.String(server.Name),
+			Server: server,
+			Labels: p.labels,
+		})
+		return err
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot create floating ip")
+		return err
+	}
+
+	logger.Debug().
+		Str("ip", resp.FloatingIP.IP.String()).
+		Msg("floating ip assigned")
+
+	return nil
+}
+
+// helper function deletes the floating ip address named after
+// the server, if one exists.
+func (p *provider) deleteFloatingIP(ctx context.Context, name string) error {
+	var ip *hcloud.FloatingIP
+	err := retry.Do(ctx, p.retries, isTransient, func() (err error) {
+		ip, _, err = p.client.FloatingIP.GetByName(ctx, name)
+		return err
+	})
+	if err != nil || ip == nil {
+		return err
+	}
+	return retry.Do(ctx, p.retries, isTransient, func() error {
+		_, err := p.client.FloatingIP.Delete(ctx, ip)
+		return err
+	})
+}
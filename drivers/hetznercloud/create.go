@@ -7,14 +7,20 @@ package hetznercloud
 import (
 	"bytes"
 	"context"
+	"errors"
 	"strconv"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
 
 	"github.com/hetznercloud/hcloud-go/hcloud"
 	"github.com/rs/zerolog/log"
 )
 
+// errNoPrivateNet is returned when a private server has no
+// private network address.
+var errNoPrivateNet = errors.New("Server has no private network address")
+
 func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpts) (*autoscaler.Instance, error) {
 	p.init.Do(func() {
 		p.setup(ctx)
@@ -26,11 +32,17 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		return nil, err
 	}
 
+	serverType := p.serverType
+	if opts.Size != "" {
+		serverType = opts.Size
+	}
+
 	req := hcloud.ServerCreateOpts{
 		Name:     opts.Name,
 		UserData: buf.String(),
+		Labels:   p.labels,
 		ServerType: &hcloud.ServerType{
-			Name: p.serverType,
+			Name: serverType,
 		},
 		Image: &hcloud.Image{
 			Name: p.image,
@@ -42,6 +54,47 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		},
 	}
 
+	for _, id := range p.networks {
+		req.Networks = append(req.Networks, &hcloud.Network{ID: id})
+	}
+	for _, id := range p.firewalls {
+		req.Firewalls = append(req.Firewalls, &hcloud.ServerCreateFirewall{
+			Firewall: hcloud.Firewall{ID: id},
+		})
+	}
+	if p.privateIP {
+		req.PublicNet = &hcloud.ServerCreatePublicNet{
+			EnableIPv4: false,
+			EnableIPv6: false,
+		}
+	}
+	if p.placementGroup != 0 {
+		req.PlacementGroup = &hcloud.PlacementGroup{ID: p.placementGroup}
+	}
+
+	locations := p.locations
+	if opts.Region != "" {
+		locations = []string{opts.Region}
+	}
+
+	// if multiple locations are configured the server is
+	// created in the first location with available capacity.
+	if len(locations) != 0 {
+		var err error
+		for _, location := range locations {
+			req.Location = &hcloud.Location{Name: location}
+			var instance *autoscaler.Instance
+			instance, err = p.create(ctx, req, location)
+			if err == nil {
+				return instance, nil
+			}
+			if !isCapacityError(err) {
+				return nil, err
+			}
+		}
+		return nil, autoscaler.ErrInsufficientCapacity
+	}
+
 	datacenter := "unknown"
 
 	if p.datacenter != "" {
@@ -52,8 +105,23 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		datacenter = p.datacenter
 	}
 
+	instance, err := p.create(ctx, req, datacenter)
+	if isCapacityError(err) {
+		return nil, autoscaler.ErrInsufficientCapacity
+	}
+	return instance, err
+}
+
+// helper function returns true if the error indicates there
+// is insufficient capacity for the requested server type.
+func isCapacityError(err error) bool {
+	return hcloud.IsError(err, hcloud.ErrorCodeResourceUnavailable) ||
+		hcloud.IsError(err, hcloud.ErrorCodePlacementError)
+}
+
+func (p *provider) create(ctx context.Context, req hcloud.ServerCreateOpts, region string) (*autoscaler.Instance, error) {
 	logger := log.Ctx(ctx).With().
-		Str("datacenter", datacenter).
+		Str("datacenter", region).
 		Str("image", req.Image.Name).
 		Str("serverType", req.ServerType.Name).
 		Str("name", req.Name).
@@ -62,7 +130,11 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 	logger.Debug().
 		Msg("instance create")
 
-	resp, _, err := p.client.Server.Create(ctx, req)
+	var resp hcloud.ServerCreateResult
+	err := retry.Do(ctx, p.retries, isThrottled, func() (err error) {
+		resp, _, err = p.client.Server.Create(ctx, req)
+		return err
+	})
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -74,13 +146,52 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		Str("name", req.Name).
 		Msg("instance created")
 
-	return &autoscaler.Instance{
+	instance := &autoscaler.Instance{
 		Provider: autoscaler.ProviderHetznerCloud,
 		ID:       strconv.Itoa(resp.Server.ID),
 		Name:     resp.Server.Name,
 		Address:  resp.Server.PublicNet.IPv4.IP.String(),
 		Size:     req.ServerType.Name,
-		Region:   datacenter,
+		Region:   region,
 		Image:    req.Image.Name,
-	}, nil
+		Price:    serverPrice(resp.Server),
+	}
+
+	// assign a floating ip to the server, so that the server
+	// address can be allow-listed.
+	if p.floatingIP && !p.privateIP {
+		if err := p.assignFloatingIP(ctx, resp.Server); err != nil {
+			return instance, err
+		}
+	}
+
+	// private servers are reached using the address in the
+	// first attached private network.
+	if p.privateIP {
+		if len(resp.Server.PrivateNet) == 0 {
+			logger.Error().
+				Msg("cannot find private network address")
+			return instance, errNoPrivateNet
+		}
+		instance.Address = resp.Server.PrivateNet[0].IP.String()
+	}
+
+	return instance, nil
+}
+
+// helper function returns the net hourly price of the server
+// in the server location. The server type includes the price
+// in each location.
+func serverPrice(server *hcloud.Server) float64 {
+	if server.ServerType == nil || server.Datacenter == nil || server.Datacenter.Location == nil {
+		return 0
+	}
+	for _, pricing := range server.ServerType.Pricings {
+		if pricing.Location == nil || pricing.Location.Name != server.Datacenter.Location.Name {
+			continue
+		}
+		price, _ := strconv.ParseFloat(pricing.Hourly.Net, 64)
+		return price
+	}
+	return 0
 }
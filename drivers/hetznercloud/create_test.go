@@ -34,6 +34,95 @@ func TestCreate(t *testing.T) {
 	t.Run("Attributes", testInstance(instance))
 }
 
+func TestCreate_Locations(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.hetzner.cloud").
+		Post("/v1/servers").
+		Reply(412).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"error": {"code": "resource_unavailable", "message": "unavailable"}}`)
+
+	gock.New("https://api.hetzner.cloud").
+		Post("/v1/servers").
+		Reply(200).
+		BodyString(respInstanceCreate)
+
+	p := New(
+		WithToken("LRK9DAWQ1ZAEFSrCNEEzLCUwhYX1U3g7wMg4dTlkkDC96fyDuyJ39nVbVjCKSDfj"),
+		WithLocations("fsn1", "nbg1"),
+		WithNetworks(1),
+		WithFirewalls(2),
+		WithPlacementGroup(3),
+	).(*provider)
+	p.init.Do(func() {}) // pre-initialize
+
+	instance, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent1"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := instance.Region, "nbg1"; got != want {
+		t.Errorf("Want instance Region %v, got %v", want, got)
+	}
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestCreate_FloatingIP(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.hetzner.cloud").
+		Post("/v1/servers").
+		Reply(200).
+		BodyString(respInstanceCreate)
+
+	gock.New("https://api.hetzner.cloud").
+		Post("/v1/floating_ips").
+		JSON(map[string]interface{}{"type": "ipv4", "name": "test", "server": 544037}).
+		Reply(201).
+		BodyString(`{"floating_ip": {"id": 4711, "name": "test", "ip": "131.232.99.1", "type": "ipv4", "server": 544037}}`)
+
+	p := New(
+		WithToken("LRK9DAWQ1ZAEFSrCNEEzLCUwhYX1U3g7wMg4dTlkkDC96fyDuyJ39nVbVjCKSDfj"),
+		WithFloatingIP(true),
+	).(*provider)
+	p.init.Do(func() {}) // pre-initialize
+
+	_, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
+func TestCreate_PrivateNoNetwork(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.hetzner.cloud").
+		Post("/v1/servers").
+		Reply(200).
+		BodyString(respInstanceCreate)
+
+	p := New(
+		WithToken("LRK9DAWQ1ZAEFSrCNEEzLCUwhYX1U3g7wMg4dTlkkDC96fyDuyJ39nVbVjCKSDfj"),
+		WithNetworks(1),
+		WithPrivateIP(true),
+	).(*provider)
+	p.init.Do(func() {}) // pre-initialize
+
+	instance, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent1"})
+	if err != errNoPrivateNet {
+		t.Errorf("Want errNoPrivateNet, got %v", err)
+	}
+	if instance == nil {
+		t.Errorf("Want instance returned for cleanup")
+	}
+}
+
 func TestCreate_CreateError(t *testing.T) {
 	defer gock.Off()
 
@@ -72,6 +161,9 @@ func testInstance(instance *autoscaler.Instance) func(t *testing.T) {
 		if got, want := instance.Provider, autoscaler.ProviderHetznerCloud; got != want {
 			t.Errorf("Want instance Provider %v, got %v", want, got)
 		}
+		if got, want := instance.Price, 0.004; got != want {
+			t.Errorf("Want instance Price %v, got %v", want, got)
+		}
 	}
 }
 
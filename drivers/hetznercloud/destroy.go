@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
 
 	"github.com/hetznercloud/hcloud-go/hcloud"
 	"github.com/rs/zerolog/log"
@@ -27,10 +28,23 @@ func (p *provider) Destroy(ctx context.Context, instance *autoscaler.Instance) e
 		return err
 	}
 
+	// the floating ip is named after the server, and is
+	// deleted before the server is deleted.
+	if p.floatingIP {
+		if err := p.deleteFloatingIP(ctx, instance.Name); err != nil {
+			logger.Warn().
+				Err(err).
+				Msg("cannot delete floating ip")
+		}
+	}
+
 	logger.Debug().
 		Msg("deleting instance")
 
-	_, err = p.client.Server.Delete(ctx, &hcloud.Server{ID: id})
+	err = retry.Do(ctx, p.retries, isTransient, func() error {
+		_, err := p.client.Server.Delete(ctx, &hcloud.Server{ID: id})
+		return err
+	})
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -35,6 +35,36 @@ func TestDestroy(t *testing.T) {
 	}
 }
 
+func TestDestroy_FloatingIP(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.hetzner.cloud").
+		Get("/v1/floating_ips").
+		MatchParam("name", "agent1").
+		Reply(200).
+		BodyString(`{"floating_ips": [{"id": 4711, "name": "agent1", "ip": "131.232.99.1", "type": "ipv4", "server": 3164494}]}`)
+
+	gock.New("https://api.hetzner.cloud").
+		Delete("/v1/floating_ips/4711").
+		Reply(204)
+
+	gock.New("https://api.hetzner.cloud").
+		Delete("/v1/servers/3164494").
+		Reply(200)
+
+	p := New(
+		WithToken("LRK9DAWQ1ZAEFSrCNEEzLCUwhYX1U3g7wMg4dTlkkDC96fyDuyJ39nVbVjCKSDfj"),
+		WithFloatingIP(true),
+	)
+	err := p.Destroy(context.TODO(), &autoscaler.Instance{ID: "3164494", Name: "agent1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
+
 func TestDestroyDeleteError(t *testing.T) {
 	defer gock.Off()
 
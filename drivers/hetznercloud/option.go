@@ -28,6 +28,25 @@ func WithDatacenter(datacenter string) Option {
 	}
 }
 
+// WithFirewalls returns an option to set the firewalls
+// applied to the server.
+func WithFirewalls(firewalls ...int) Option {
+	return func(p *provider) {
+		p.firewalls = firewalls
+	}
+}
+
+// WithFloatingIP returns an option to create a floating ip
+// address assigned to each server, which is deleted when the
+// server is destroyed. The floating ip must be configured on
+// the server network interface, for example in the user data,
+// before it is used for outbound traffic.
+func WithFloatingIP(floating bool) Option {
+	return func(p *provider) {
+		p.floatingIP = floating
+	}
+}
+
 // WithImage returns an option to set the image.
 func WithImage(image string) Option {
 	return func(p *provider) {
@@ -35,6 +54,56 @@ func WithImage(image string) Option {
 	}
 }
 
+// WithLabels returns an option to set the server labels.
+func WithLabels(labels map[string]string) Option {
+	return func(p *provider) {
+		p.labels = labels
+	}
+}
+
+// WithLocations returns an option to set the locations. The
+// server is created in the first location with available
+// capacity. Locations take precedence over the datacenter.
+func WithLocations(locations ...string) Option {
+	return func(p *provider) {
+		p.locations = locations
+	}
+}
+
+// WithNetworks returns an option to attach the server to
+// the private networks.
+func WithNetworks(networks ...int) Option {
+	return func(p *provider) {
+		p.networks = networks
+	}
+}
+
+// WithPlacementGroup returns an option to set the placement
+// group, used to spread servers across physical hosts.
+func WithPlacementGroup(group int) Option {
+	return func(p *provider) {
+		p.placementGroup = group
+	}
+}
+
+// WithPrivateIP returns an option to create servers without
+// a public network. The server is reached using its address in
+// the first private network, and networks must be configured.
+func WithPrivateIP(private bool) Option {
+	return func(p *provider) {
+		p.privateIP = private
+	}
+}
+
+// WithRetries returns an option to set the number of times
+// transient api errors are retried. Zero disables retries and a
+// negative value uses the default.
+func WithRetries(retries int) Option {
+	return func(p *provider) {
+		p.retries = retries
+	}
+}
+
 // WithServerType returns an option to set the server type.
 func WithServerType(serverType string) Option {
 	return func(p *provider) {
@@ -83,3 +152,15 @@ func WithUserDataFile(filepath string) Option {
 		}
 	}
 }
+
+// WithUserDataHooks returns an option to merge cloud-init
+// sections, such as write_files and runcmd, into the
+// cloud-init template. Entries of the before sections are
+// inserted before the existing entries, and entries of the
+// after sections are inserted after the existing entries.
+func WithUserDataHooks(before, after string) Option {
+	return func(p *provider) {
+		p.userdataPrepend = before
+		p.userdataAppend = after
+	}
+}
@@ -12,6 +12,11 @@ func TestOptions(t *testing.T) {
 		WithDatacenter("fsn1-dc8"),
 		WithServerType("cx20"),
 		WithSSHKey(23234),
+		WithFirewalls(1, 2),
+		WithLocations("fsn1", "nbg1"),
+		WithNetworks(3),
+		WithPlacementGroup(4),
+		WithLabels(map[string]string{"team": "platform"}),
 	).(*provider)
 
 	if got, want := p.image, "ubuntu-17.04"; got != want {
@@ -26,4 +31,19 @@ func TestOptions(t *testing.T) {
 	if got, want := p.key, 23234; got != want {
 		t.Errorf("Want key %d, got %d", want, got)
 	}
+	if got, want := len(p.firewalls), 2; got != want {
+		t.Errorf("Want %d firewalls, got %d", want, got)
+	}
+	if got, want := p.labels["team"], "platform"; got != want {
+		t.Errorf("Want label %q, got %q", want, got)
+	}
+	if got, want := len(p.locations), 2; got != want {
+		t.Errorf("Want %d locations, got %d", want, got)
+	}
+	if got, want := len(p.networks), 1; got != want {
+		t.Errorf("Want %d networks, got %d", want, got)
+	}
+	if got, want := p.placementGroup, 4; got != want {
+		t.Errorf("Want placement group %d, got %d", want, got)
+	}
 }
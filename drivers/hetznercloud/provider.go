@@ -9,6 +9,7 @@ import (
 	"text/template"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/retry"
 	"github.com/drone/autoscaler/drivers/internal/userdata"
 
 	"github.com/hetznercloud/hcloud-go/hcloud"
@@ -25,12 +26,24 @@ type provider struct {
 	userdata   *template.Template
 	key        int
 
+	firewalls      []int
+	labels         map[string]string
+	locations      []string
+	networks       []int
+	placementGroup int
+	privateIP      bool
+	floatingIP     bool
+	retries        int
+
 	client *hcloud.Client
+
+	userdataPrepend string
+	userdataAppend  string
 }
 
 // New returns a new Digital Ocean provider.
 func New(opts ...Option) autoscaler.Provider {
-	p := new(provider)
+	p := &provider{retries: -1}
 	for _, opt := range opts {
 		opt(p)
 	}
@@ -43,5 +56,9 @@ func New(opts ...Option) autoscaler.Provider {
 	if p.userdata == nil {
 		p.userdata = userdata.T
 	}
+	p.userdata = userdata.Hooks(p.userdata, p.userdataPrepend, p.userdataAppend)
+	if p.retries < 0 {
+		p.retries = retry.DefaultRetries
+	}
 	return p
 }
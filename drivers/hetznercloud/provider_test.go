@@ -18,3 +18,14 @@ func TestDefaults(t *testing.T) {
 		t.Errorf("Want server type %q, got %q", want, got)
 	}
 }
+
+func TestRetriesDisabled(t *testing.T) {
+	p := New(WithRetries(0)).(*provider)
+	if got, want := p.retries, 0; got != want {
+		t.Errorf("Want %d retries, got %d", want, got)
+	}
+	p = New(WithRetries(-1)).(*provider)
+	if got, want := p.retries, 5; got != want {
+		t.Errorf("Want %d retries, got %d", want, got)
+	}
+}
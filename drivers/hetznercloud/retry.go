@@ -0,0 +1,30 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package hetznercloud
+
+import (
+	"github.com/hetznercloud/hcloud-go/hcloud"
+)
+
+// errorCodeTimeout is returned when the api request timed
+// out. The client library does not define this error code.
+const errorCodeTimeout hcloud.ErrorCode = "timeout"
+
+// helper function returns true if the request was rate
+// limited and can be safely retried.
+func isThrottled(err error) bool {
+	return hcloud.IsError(err, hcloud.ErrorCodeRateLimitExceeded)
+}
+
+// helper function returns true if the idempotent request
+// failed with a transient error and can be retried. This
+// includes servers locked by a running action.
+func isTransient(err error) bool {
+	return isThrottled(err) ||
+		hcloud.IsError(err, hcloud.ErrorCodeConflict) ||
+		hcloud.IsError(err, hcloud.ErrorCodeLocked) ||
+		hcloud.IsError(err, hcloud.ErrorCodeMaintenance) ||
+		hcloud.IsError(err, errorCodeTimeout)
+}
@@ -0,0 +1,27 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package hetznercloud
+
+import (
+	"testing"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+)
+
+func TestIsTransient(t *testing.T) {
+	throttled := hcloud.Error{Code: hcloud.ErrorCodeRateLimitExceeded}
+	locked := hcloud.Error{Code: hcloud.ErrorCodeLocked}
+	invalid := hcloud.Error{Code: hcloud.ErrorCodeInvalidInput}
+
+	if !isThrottled(throttled) || !isTransient(throttled) {
+		t.Errorf("Want rate limit error retried")
+	}
+	if isThrottled(locked) || !isTransient(locked) {
+		t.Errorf("Want locked error retried for idempotent requests only")
+	}
+	if isTransient(invalid) {
+		t.Errorf("Want invalid input error not retried")
+	}
+}
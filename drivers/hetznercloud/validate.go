@@ -0,0 +1,76 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package hetznercloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Validate verifies the api token, server type, image, ssh
+// key, locations, networks, firewalls and placement group
+// without creating a server.
+func (p *provider) Validate(ctx context.Context) error {
+	p.init.Do(func() {
+		p.setup(ctx)
+	})
+
+	serverType, _, err := p.client.ServerType.GetByName(ctx, p.serverType)
+	if err != nil {
+		return fmt.Errorf("Cannot authenticate or find server type %s: %s", p.serverType, err)
+	}
+	if serverType == nil {
+		return fmt.Errorf("Cannot find server type %s", p.serverType)
+	}
+
+	image, _, err := p.client.Image.GetByName(ctx, p.image)
+	if err != nil || image == nil {
+		return fmt.Errorf("Cannot find image %s: %v", p.image, err)
+	}
+
+	if p.key == 0 {
+		return errors.New("No ssh key configured, and no existing key found")
+	}
+	key, _, err := p.client.SSHKey.GetByID(ctx, p.key)
+	if err != nil || key == nil {
+		return fmt.Errorf("Cannot find ssh key %d: %v", p.key, err)
+	}
+
+	if p.datacenter != "" {
+		datacenter, _, err := p.client.Datacenter.GetByName(ctx, p.datacenter)
+		if err != nil || datacenter == nil {
+			return fmt.Errorf("Cannot find datacenter %s: %v", p.datacenter, err)
+		}
+	}
+	for _, name := range p.locations {
+		location, _, err := p.client.Location.GetByName(ctx, name)
+		if err != nil || location == nil {
+			return fmt.Errorf("Cannot find location %s: %v", name, err)
+		}
+	}
+	if p.privateIP && len(p.networks) == 0 {
+		return errors.New("Private servers require a private network")
+	}
+	for _, id := range p.networks {
+		network, _, err := p.client.Network.GetByID(ctx, id)
+		if err != nil || network == nil {
+			return fmt.Errorf("Cannot find network %d: %v", id, err)
+		}
+	}
+	for _, id := range p.firewalls {
+		firewall, _, err := p.client.Firewall.GetByID(ctx, id)
+		if err != nil || firewall == nil {
+			return fmt.Errorf("Cannot find firewall %d: %v", id, err)
+		}
+	}
+	if p.placementGroup != 0 {
+		group, _, err := p.client.PlacementGroup.GetByID(ctx, p.placementGroup)
+		if err != nil || group == nil {
+			return fmt.Errorf("Cannot find placement group %d: %v", p.placementGroup, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package hetznercloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestValidate_ServerTypeNotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.hetzner.cloud").
+		Get("/v1/server_types").
+		MatchParam("name", "cx11").
+		Reply(200).
+		BodyString(`{"server_types": []}`)
+
+	p := New(
+		WithToken("LRK9DAWQ1ZAEFSrCNEEzLCUwhYX1U3g7wMg4dTlkkDC96fyDuyJ39nVbVjCKSDfj"),
+	).(*provider)
+	p.init.Do(func() {}) // pre-initialize
+
+	err := p.Validate(context.TODO())
+	if err == nil {
+		t.Errorf("Expect error when server type does not exist")
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("Expected http requests not detected")
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+// Package labels provides helpers to convert the instance
+// labels to the tags of providers that only support string
+// tags.
+package labels
+
+import "sort"
+
+// Tags converts the key value labels to tags in key:value
+// format, sorted for a stable order.
+func Tags(labels map[string]string) []string {
+	var tags []string
+	for k, v := range labels {
+		tags = append(tags, k+":"+v)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,21 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package labels
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	got := Tags(map[string]string{
+		"team":        "platform",
+		"cost-center": "ci",
+	})
+	want := []string{"cost-center:ci", "team:platform"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want tags %v, got %v", want, got)
+	}
+}
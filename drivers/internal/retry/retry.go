@@ -0,0 +1,81 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+// Package retry provides a retry helper with exponential
+// backoff and jitter, used by the drivers to retry transient
+// provider errors.
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// DefaultRetries is the default number of retries.
+const DefaultRetries = 5
+
+var (
+	// base is the initial backoff interval.
+	base = time.Second
+
+	// limit is the maximum backoff interval.
+	limit = time.Second * 30
+)
+
+// Do calls fn until it succeeds, returns an error that is not
+// retryable, the number of retries is exhausted, or the
+// context is cancelled. The last error is returned.
+func Do(ctx context.Context, retries int, retryable func(error) bool, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff returns the backoff interval for the attempt,
+// using exponential backoff with full jitter.
+func Backoff(attempt int) time.Duration {
+	max := limit
+	if attempt < 16 {
+		if d := base << uint(attempt); d < limit {
+			max = d
+		}
+	}
+	return time.Duration(rand.Int63n(int64(max)) + 1)
+}
+
+// IsThrottled returns true if the http status code
+// indicates the request was rate limited. A throttled
+// request was not processed and is always safe to retry.
+func IsThrottled(code int) bool {
+	return code == http.StatusTooManyRequests
+}
+
+// IsTransient returns true if the http status code
+// indicates a transient error. A transient error may have
+// been processed, and should only be retried if the request
+// is idempotent. Internal server errors are not considered
+// transient.
+func IsTransient(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
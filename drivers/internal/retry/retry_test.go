@@ -0,0 +1,112 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+func init() {
+	base = time.Millisecond
+	limit = time.Millisecond * 5
+}
+
+func TestDo(t *testing.T) {
+	var calls int
+	err := Do(context.Background(), 3, isTransient, func() error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := calls, 3; got != want {
+		t.Errorf("Want %d calls, got %d", want, got)
+	}
+}
+
+func TestDo_Exhausted(t *testing.T) {
+	var calls int
+	err := Do(context.Background(), 2, isTransient, func() error {
+		calls++
+		return errTransient
+	})
+	if err != errTransient {
+		t.Errorf("Want transient error, got %v", err)
+	}
+	if got, want := calls, 3; got != want {
+		t.Errorf("Want %d calls, got %d", want, got)
+	}
+}
+
+func TestDo_NotRetryable(t *testing.T) {
+	var calls int
+	errFatal := errors.New("fatal")
+	err := Do(context.Background(), 3, isTransient, func() error {
+		calls++
+		return errFatal
+	})
+	if err != errFatal {
+		t.Errorf("Want fatal error, got %v", err)
+	}
+	if got, want := calls, 1; got != want {
+		t.Errorf("Want %d calls, got %d", want, got)
+	}
+}
+
+func TestDo_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int
+	err := Do(ctx, 3, isTransient, func() error {
+		calls++
+		return errTransient
+	})
+	if err != errTransient {
+		t.Errorf("Want transient error, got %v", err)
+	}
+	if got, want := calls, 1; got != want {
+		t.Errorf("Want %d calls, got %d", want, got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 100; attempt++ {
+		if got := Backoff(attempt); got <= 0 || got > limit {
+			t.Errorf("Want backoff between 0 and %s, got %s", limit, got)
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	for code, want := range map[int]bool{
+		200: false,
+		404: false,
+		429: true,
+		500: false,
+		502: true,
+		503: true,
+	} {
+		if got := IsTransient(code); got != want {
+			t.Errorf("Want transient %v for status %d", want, code)
+		}
+	}
+	if !IsThrottled(429) || IsThrottled(503) {
+		t.Errorf("Want only status 429 throttled")
+	}
+}
+
+func isTransient(err error) bool {
+	return err == errTransient
+}
@@ -0,0 +1,107 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package userdata
+
+import (
+	"encoding/json"
+
+	"github.com/drone/autoscaler"
+)
+
+// helper function returns the docker daemon configuration of
+// the instance, in json format, or an empty string if the
+// daemon does not require configuration. The optional base
+// documents are json objects merged into the configuration,
+// which is used by providers that require additional daemon
+// settings. The user-defined configuration is merged after
+// the base documents, and the tls settings are merged last,
+// since the autoscaler cannot connect to the daemon without
+// them.
+func daemon(opts *autoscaler.InstanceCreateOpts, base ...string) (string, error) {
+	config := map[string]interface{}{}
+	if opts.Daemon.Config != "" {
+		base = append(base, opts.Daemon.Config)
+	}
+	for _, doc := range base {
+		if err := json.Unmarshal([]byte(doc), &config); err != nil {
+			return "", err
+		}
+	}
+
+	if len(opts.TLSCert) != 0 {
+		if opts.OS == "windows" {
+			config["hosts"] = []string{"tcp://0.0.0.0:2376", "npipe://"}
+			config["tlsverify"] = true
+			config["tlscacert"] = `C:\ProgramData\docker\certs.d\ca.pem`
+			config["tlscert"] = `C:\ProgramData\docker\certs.d\server-cert.pem`
+			config["tlskey"] = `C:\ProgramData\docker\certs.d\server-key.pem`
+		} else {
+			config["hosts"] = []string{"0.0.0.0:2376", "unix:///var/run/docker.sock"}
+			config["tls"] = true
+			config["tlsverify"] = true
+			config["tlscacert"] = "/etc/docker/ca.pem"
+			config["tlscert"] = "/etc/docker/server-cert.pem"
+			config["tlskey"] = "/etc/docker/server-key.pem"
+		}
+	}
+	if len(opts.Daemon.RegistryMirrors) != 0 {
+		config["registry-mirrors"] = opts.Daemon.RegistryMirrors
+	}
+	if len(opts.Daemon.InsecureRegistries) != 0 {
+		config["insecure-registries"] = opts.Daemon.InsecureRegistries
+	}
+	if proxies := daemonProxies(opts.Daemon); len(proxies) != 0 {
+		config["proxies"] = proxies
+	}
+	if opts.Daemon.StorageDriver != "" {
+		config["storage-driver"] = opts.Daemon.StorageDriver
+	}
+	if len(opts.Daemon.StorageOpts) != 0 {
+		config["storage-opts"] = opts.Daemon.StorageOpts
+	}
+	if opts.Daemon.DataRoot != "" {
+		config["data-root"] = opts.Daemon.DataRoot
+	}
+	if opts.Daemon.MTU != 0 {
+		config["mtu"] = opts.Daemon.MTU
+	}
+	if opts.Daemon.Bip != "" {
+		config["bip"] = opts.Daemon.Bip
+	}
+	if len(opts.Daemon.AddressPools) != 0 {
+		config["default-address-pools"] = opts.Daemon.AddressPools
+	}
+	if opts.Daemon.CgroupDriver != "" {
+		// the cgroup driver is appended to the exec options of
+		// the user-defined configuration, if any.
+		execOpts, _ := config["exec-opts"].([]interface{})
+		config["exec-opts"] = append(execOpts, "native.cgroupdriver="+opts.Daemon.CgroupDriver)
+	}
+	if opts.Daemon.CgroupnsMode != "" {
+		config["default-cgroupns-mode"] = opts.Daemon.CgroupnsMode
+	}
+
+	if len(config) == 0 {
+		return "", nil
+	}
+	out, err := json.MarshalIndent(config, "", "  ")
+	return string(out), err
+}
+
+// helper function returns the proxy configuration of the
+// docker daemon.
+func daemonProxies(opts autoscaler.DaemonOpts) map[string]string {
+	proxies := map[string]string{}
+	if opts.HTTPProxy != "" {
+		proxies["http-proxy"] = opts.HTTPProxy
+	}
+	if opts.HTTPSProxy != "" {
+		proxies["https-proxy"] = opts.HTTPSProxy
+	}
+	if opts.NoProxy != "" {
+		proxies["no-proxy"] = opts.NoProxy
+	}
+	return proxies
+}
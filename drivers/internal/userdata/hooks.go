@@ -0,0 +1,196 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package userdata
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// Hooks returns the userdata template extended with the
+// cloud-init sections of the before and after templates,
+// such as extra write_files and runcmd entries. Entries of
+// sections defined by the userdata are inserted before or
+// after the existing entries, and other sections are added
+// to the userdata. Userdata that is not a cloud-config
+// document, such as the windows powershell script, is not
+// modified.
+func Hooks(t *template.Template, before, after string) *template.Template {
+	if before == "" && after == "" {
+		return t
+	}
+	preT, postT := Parse(before), Parse(after)
+	hooks := func(data interface{}) (string, error) {
+		var base, pre, post bytes.Buffer
+		if err := t.Execute(&base, data); err != nil {
+			return "", err
+		}
+		if err := preT.Execute(&pre, data); err != nil {
+			return "", err
+		}
+		if err := postT.Execute(&post, data); err != nil {
+			return "", err
+		}
+		return merge(base.String(), pre.String(), post.String()), nil
+	}
+	return template.Must(
+		template.New("_").Funcs(template.FuncMap{"hooks": hooks}).Parse(`{{ hooks . }}`),
+	)
+}
+
+// section is a top-level cloud-config key and the lines
+// that define its value, including the key line.
+type section struct {
+	key   string
+	lines []string
+}
+
+// helper function returns true if the section value is
+// defined in the block below the key line.
+func (s *section) block() bool {
+	return strings.HasSuffix(strings.TrimSpace(s.lines[0]), ":")
+}
+
+// helper function returns the index after the last line of
+// the section value, excluding trailing blank lines.
+func (s *section) end() int {
+	i := len(s.lines)
+	for i > 1 && strings.TrimSpace(s.lines[i-1]) == "" {
+		i--
+	}
+	return i
+}
+
+// helper function returns the section value lines, indented
+// to match the section value lines of the base section.
+func (s *section) body(base *section) []string {
+	body := s.lines[1:s.end()]
+	diff := indent(base.lines[1:]) - indent(body)
+	var out []string
+	for _, line := range body {
+		switch {
+		case strings.TrimSpace(line) == "":
+			out = append(out, line)
+		case diff > 0:
+			out = append(out, strings.Repeat(" ", diff)+line)
+		default:
+			out = append(out, line[minIndent(line, -diff):])
+		}
+	}
+	return out
+}
+
+// helper function merges the sections of the before and
+// after hooks into the cloud-config document.
+func merge(base, before, after string) string {
+	if !strings.HasPrefix(base, "#cloud-config") {
+		return base
+	}
+	head, sections := split(base)
+	_, pre := split(before)
+	_, post := split(after)
+	sections = mergeSections(sections, pre, true)
+	sections = mergeSections(sections, post, false)
+
+	lines := head
+	for _, s := range sections {
+		lines = append(lines, s.lines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// helper function merges the hook sections into the
+// sections, inserting the hook entries before or after the
+// existing entries.
+func mergeSections(sections, hooks []*section, prepend bool) []*section {
+	for _, hook := range hooks {
+		s := find(sections, hook.key)
+		switch {
+		case s == nil:
+			sections = append(sections, hook)
+		case !s.block() || !hook.block():
+			// scalar values cannot be merged, and are
+			// therefore replaced.
+			*s = *hook
+		case prepend:
+			s.lines = concat(s.lines[:1], hook.body(s), s.lines[1:])
+		default:
+			end := s.end()
+			s.lines = concat(s.lines[:end], hook.body(s), s.lines[end:])
+		}
+	}
+	return sections
+}
+
+// helper function splits the cloud-config document into the
+// header lines and the top-level sections.
+func split(doc string) ([]string, []*section) {
+	var head []string
+	var sections []*section
+	for _, line := range strings.Split(doc, "\n") {
+		if key, ok := topLevelKey(line); ok {
+			sections = append(sections, &section{key: key, lines: []string{line}})
+		} else if len(sections) == 0 {
+			head = append(head, line)
+		} else {
+			s := sections[len(sections)-1]
+			s.lines = append(s.lines, line)
+		}
+	}
+	return head, sections
+}
+
+// helper function returns the top-level key of the line.
+func topLevelKey(line string) (string, bool) {
+	if line == "" || strings.ContainsAny(line[:1], " \t#-") {
+		return "", false
+	}
+	i := strings.Index(line, ":")
+	if i <= 0 {
+		return "", false
+	}
+	return line[:i], true
+}
+
+// helper function returns the named section.
+func find(sections []*section, key string) *section {
+	for _, s := range sections {
+		if s.key == key {
+			return s
+		}
+	}
+	return nil
+}
+
+// helper function returns the indentation of the first
+// non-blank line.
+func indent(lines []string) int {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return len(line) - len(strings.TrimLeft(line, " "))
+		}
+	}
+	return 0
+}
+
+// helper function returns the number of leading spaces of
+// the line, up to n.
+func minIndent(line string, n int) int {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return i
+}
+
+// helper function concatenates the lines into a new slice.
+func concat(lines ...[]string) []string {
+	var out []string
+	for _, l := range lines {
+		out = append(out, l...)
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package userdata
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/drone/autoscaler"
+)
+
+func TestHooks(t *testing.T) {
+	before := `#cloud-config
+runcmd:
+  - [ update-ca-certificates ]
+`
+	after := `#cloud-config
+write_files:
+- path: /etc/ssl/certs/{{ .Name }}.pem
+  content: |
+    -----BEGIN CERTIFICATE-----
+runcmd:
+- [ systemctl, start, node-exporter ]
+ntp:
+  enabled: true
+`
+	buf := new(bytes.Buffer)
+	err := Hooks(T, before, after).Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name: "agent-123456",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	out := buf.String()
+
+	want := `runcmd:
+  - [ update-ca-certificates ]
+  - [ systemctl, daemon-reload ]
+  - [ systemctl, restart, docker ]
+  - [ systemctl, start, node-exporter ]
+`
+	if !strings.Contains(out, want) {
+		t.Errorf("Want runcmd entries merged, got\n%s", out)
+	}
+	if !strings.Contains(out, "\n  - path: /etc/ssl/certs/agent-123456.pem\n    content: |\n      -----BEGIN CERTIFICATE-----\n") {
+		t.Errorf("Want write_files entry appended, got\n%s", out)
+	}
+	if strings.Count(out, "write_files:") != 1 || strings.Count(out, "runcmd:") != 1 {
+		t.Errorf("Want sections merged, got\n%s", out)
+	}
+	if !strings.Contains(out, "ntp:\n  enabled: true\n") {
+		t.Errorf("Want section added, got\n%s", out)
+	}
+}
+
+func TestHooks_Windows(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Hooks(T, "", "#cloud-config\nruncmd:\n  - [ reboot ]\n").Execute(buf, &autoscaler.InstanceCreateOpts{
+		OS: "windows",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Contains(buf.String(), "reboot") {
+		t.Errorf("Want windows userdata unchanged")
+	}
+}
+
+func TestHooks_Empty(t *testing.T) {
+	if Hooks(T, "", "") != T {
+		t.Errorf("Want userdata template unchanged without hooks")
+	}
+}
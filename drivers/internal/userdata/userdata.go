@@ -6,6 +6,7 @@ package userdata
 
 import (
 	"encoding/base64"
+	"strings"
 	"text/template"
 )
 
@@ -13,6 +14,17 @@ var funcs = map[string]interface{}{
 	"base64": func(src []byte) string {
 		return base64.StdEncoding.EncodeToString(src)
 	},
+	"indent": func(n int, s string) string {
+		pad := strings.Repeat(" ", n)
+		return pad + strings.Replace(strings.TrimSuffix(s, "\n"), "\n", "\n"+pad, -1)
+	},
+	"mount": func() string {
+		return Mount
+	},
+	"nvidia": func() string {
+		return Nvidia
+	},
+	"daemon": daemon,
 }
 
 // Parse parses the userdata template.
@@ -28,21 +40,51 @@ func Parse(text string) *template.Template {
 	)
 }
 
-// T is the default userdata template.
-var T = Parse(`#cloud-config
+// T is the default userdata template. The windows template
+// is used to provision windows instances, and the linux
+// template is used to provision all other instances.
+var T = func() *template.Template {
+	t := template.Must(
+		template.New("_").Funcs(funcs).Parse(
+			`{{ if eq .OS "windows" }}{{ template "windows" . }}{{ else }}{{ template "linux" . }}{{ end }}`,
+		),
+	)
+	template.Must(t.AddParseTree("linux", Linux.Tree))
+	template.Must(t.AddParseTree("windows", Windows.Tree))
+	return t
+}()
+
+// Linux is the default linux userdata template.
+var Linux = Parse(`#cloud-config
 
 apt_reboot_if_required: false
 package_update: false
 package_upgrade: false
+{{- with .SSHKey }}
+
+ssh_authorized_keys:
+  - {{ . }}
+{{- end }}
 
 apt:
+{{- with .Daemon.HTTPProxy }}
+  http_proxy: {{ . }}
+{{- end }}
+{{- with .Daemon.HTTPSProxy }}
+  https_proxy: {{ . }}
+{{- end }}
   sources:
     docker.list:
       source: deb [arch=amd64] https://download.docker.com/linux/ubuntu $RELEASE stable
       keyid: 0EBFCD88
 
 packages:
+{{- with .Daemon.Version }}
+  - [docker-ce, "5:{{ . }}*"]
+  - [docker-ce-cli, "5:{{ . }}*"]
+{{- else }}
   - docker-ce
+{{- end }}
 
 write_files:
   - path: /etc/systemd/system/docker.service.d/override.conf
@@ -53,16 +95,12 @@ write_files:
   - path: /etc/default/docker
     content: |
       DOCKER_OPTS=""
+{{- with daemon . }}
   - path: /etc/docker/daemon.json
     content: |
-      {
-        "hosts": [ "0.0.0.0:2376", "unix:///var/run/docker.sock" ],
-        "tls": true,
-        "tlsverify": true,
-        "tlscacert": "/etc/docker/ca.pem",
-        "tlscert": "/etc/docker/server-cert.pem",
-        "tlskey": "/etc/docker/server-key.pem"
-      }
+{{ indent 6 . }}
+{{- end }}
+{{- if .TLSCert }}
   - path: /etc/docker/ca.pem
     encoding: b64
     content: {{ .CACert | base64 }}
@@ -72,8 +110,115 @@ write_files:
   - path: /etc/docker/server-key.pem
     encoding: b64
     content: {{ .TLSKey | base64 }}
+{{- end }}
+{{- if .Volume }}
+  - path: /usr/local/bin/mount-docker-volume
+    permissions: '0755'
+    content: |
+{{ mount | indent 6 }}
+{{- end }}
+{{- if .GPU }}
+  - path: /usr/local/bin/install-nvidia
+    permissions: '0755'
+    content: |
+{{ nvidia | indent 6 }}
+{{- end }}
 
 runcmd:
+{{- if .Volume }}
+  - [ systemctl, stop, docker ]
+  - [ /usr/local/bin/mount-docker-volume, {{ .Volume }} ]
+{{- end }}
+{{- if .GPU }}
+  - [ /usr/local/bin/install-nvidia ]
+{{- end }}
   - [ systemctl, daemon-reload ]
   - [ systemctl, restart, docker ]
 `)
+
+// Windows is the default windows userdata template. It is a
+// powershell script that installs the containers feature and
+// docker, and configures docker to listen on port 2376 with
+// tls enabled. The script is idempotent, since the instance
+// must restart once the containers feature is installed, and
+// is therefore expected to run on every boot.
+var Windows = Parse(`$ErrorActionPreference = "Stop"
+
+if (-not (Get-WindowsFeature -Name Containers).Installed) {
+  Install-WindowsFeature -Name Containers
+  Restart-Computer -Force
+  exit
+}
+
+if (-not (Get-Service docker -ErrorAction SilentlyContinue)) {
+  Invoke-WebRequest -UseBasicParsing -OutFile "$env:TEMP\docker.zip" ` + "`" + `
+    -Uri "https://download.docker.com/win/static/stable/x86_64/docker-{{ or .Daemon.Version "24.0.7" }}.zip"
+  Expand-Archive "$env:TEMP\docker.zip" -DestinationPath $env:ProgramFiles -Force
+  Remove-Item "$env:TEMP\docker.zip"
+  [Environment]::SetEnvironmentVariable("Path", "$env:Path;$env:ProgramFiles\docker", "Machine")
+  & "$env:ProgramFiles\docker\dockerd.exe" --register-service
+}
+
+$certs = "$env:ProgramData\docker\certs.d"
+New-Item -ItemType Directory -Force -Path $certs, "$env:ProgramData\docker\config" | Out-Null
+[IO.File]::WriteAllBytes("$certs\ca.pem", [Convert]::FromBase64String("{{ .CACert | base64 }}"))
+[IO.File]::WriteAllBytes("$certs\server-cert.pem", [Convert]::FromBase64String("{{ .TLSCert | base64 }}"))
+[IO.File]::WriteAllBytes("$certs\server-key.pem", [Convert]::FromBase64String("{{ .TLSKey | base64 }}"))
+
+{{- with daemon . }}
+Set-Content -Path "$env:ProgramData\docker\config\daemon.json" -Value @"
+{{ . }}
+"@
+{{- end }}
+
+if (-not (Get-NetFirewallRule -Name docker-tls -ErrorAction SilentlyContinue)) {
+  New-NetFirewallRule -Name docker-tls -DisplayName "Docker TLS" -Direction Inbound -Protocol TCP -LocalPort 2376 -Action Allow | Out-Null
+}
+
+Restart-Service docker
+`)
+
+// Mount is a shell script that formats the block volume, if
+// not already formatted, and mounts the volume as the docker
+// data directory. The device path is passed as the first
+// argument. Some instance types expose volumes under a
+// different name (e.g. nvme devices), in which case the
+// first unused disk is selected.
+var Mount = `#!/bin/sh
+set -e
+DEV="$1"
+if [ ! -b "$DEV" ]; then
+  DEV=$(echo "$DEV" | sed 's#^/dev/sd#/dev/xvd#')
+fi
+if [ ! -b "$DEV" ]; then
+  for disk in $(lsblk -dpno NAME,TYPE | awk '$2 == "disk" { print $1 }'); do
+    if [ -z "$(lsblk -no MOUNTPOINT,FSTYPE "$disk" | tr -d '[:space:]')" ]; then
+      DEV="$disk"
+      break
+    fi
+  done
+fi
+blkid "$DEV" || mkfs.ext4 -q -F "$DEV"
+mkdir -p /var/lib/docker
+grep -q " /var/lib/docker " /etc/fstab || echo "$DEV /var/lib/docker ext4 defaults,nofail 0 2" >> /etc/fstab
+mount /var/lib/docker
+`
+
+// Nvidia is a shell script that installs the nvidia gpu
+// drivers and the nvidia container toolkit, and configures
+// the nvidia container runtime as the docker default runtime.
+var Nvidia = `#!/bin/sh
+set -e
+export DEBIAN_FRONTEND=noninteractive
+apt-get update
+apt-get install -y ubuntu-drivers-common curl gnupg
+ubuntu-drivers install --gpgpu
+curl -fsSL https://nvidia.github.io/libnvidia-container/gpgkey | gpg --dearmor -o /usr/share/keyrings/nvidia-container-toolkit-keyring.gpg
+curl -fsSL https://nvidia.github.io/libnvidia-container/stable/deb/nvidia-container-toolkit.list \
+  | sed 's#deb https://#deb [signed-by=/usr/share/keyrings/nvidia-container-toolkit-keyring.gpg] https://#g' \
+  > /etc/apt/sources.list.d/nvidia-container-toolkit.list
+apt-get update
+apt-get install -y nvidia-container-toolkit
+nvidia-ctk runtime configure --runtime=docker --set-as-default
+modprobe nvidia || true
+`
@@ -6,6 +6,7 @@ package userdata
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/drone/autoscaler"
@@ -25,6 +26,201 @@ func TestUserdata(t *testing.T) {
 	}
 }
 
+func TestUserdata_NoTLS(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name: "agent-123456",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Contains(buf.String(), "/etc/docker/daemon.json") {
+		t.Errorf("Want docker tls configuration skipped without certificates")
+	}
+}
+
+func TestUserdata_RegistryMirrors(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name: "agent-123456",
+		Daemon: autoscaler.DaemonOpts{
+			RegistryMirrors:    []string{"https://mirror.company.com"},
+			InsecureRegistries: []string{"registry.company.local:5000"},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, want := range []string{
+		`      "registry-mirrors": [`,
+		`        "https://mirror.company.com"`,
+		`      "insecure-registries": [`,
+		`        "registry.company.local:5000"`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Want %s in the docker daemon configuration, got %s", want, buf.String())
+		}
+	}
+}
+
+func TestDaemon_Config(t *testing.T) {
+	got, err := daemon(&autoscaler.InstanceCreateOpts{
+		TLSCert: []byte(dummyCert),
+		Daemon: autoscaler.DaemonOpts{
+			Config: `{"log-driver": "json-file", "log-opts": {"max-size": "10m"}, "tls": false}`,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, want := range []string{
+		`"log-driver": "json-file"`,
+		`"max-size": "10m"`,
+		`"tls": true`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Want %s in the docker daemon configuration, got %s", want, got)
+		}
+	}
+
+	_, err = daemon(&autoscaler.InstanceCreateOpts{
+		Daemon: autoscaler.DaemonOpts{Config: "log-driver: json-file"},
+	})
+	if err == nil {
+		t.Errorf("Want error for an invalid docker daemon configuration")
+	}
+}
+
+func TestDaemon_Proxy(t *testing.T) {
+	got, err := daemon(&autoscaler.InstanceCreateOpts{
+		Daemon: autoscaler.DaemonOpts{
+			HTTPProxy: "http://proxy.company.com:3128",
+			NoProxy:   "localhost",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := `{
+  "proxies": {
+    "http-proxy": "http://proxy.company.com:3128",
+    "no-proxy": "localhost"
+  }
+}`
+	if got != want {
+		t.Errorf("Want docker daemon proxies %s, got %s", want, got)
+	}
+}
+
+func TestDaemon(t *testing.T) {
+	got, err := daemon(&autoscaler.InstanceCreateOpts{
+		TLSCert: []byte(dummyCert),
+		Daemon: autoscaler.DaemonOpts{
+			RegistryMirrors: []string{"https://mirror.company.com"},
+		},
+	}, `{"dns": ["8.8.8.8"]}`)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := `{
+  "dns": [
+    "8.8.8.8"
+  ],
+  "hosts": [
+    "0.0.0.0:2376",
+    "unix:///var/run/docker.sock"
+  ],
+  "registry-mirrors": [
+    "https://mirror.company.com"
+  ],
+  "tls": true,
+  "tlscacert": "/etc/docker/ca.pem",
+  "tlscert": "/etc/docker/server-cert.pem",
+  "tlskey": "/etc/docker/server-key.pem",
+  "tlsverify": true
+}`
+	if got != want {
+		t.Errorf("Want docker daemon configuration %s, got %s", want, got)
+	}
+}
+
+func TestUserdata_Custom(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Parse(`#cloud-config
+runcmd:
+  - [ echo, {{ .Name }}, {{ .Pool }}, {{ .Server }}, {{ .Vars.mirror }} ]
+`).Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name:   "agent-123456",
+		Pool:   "arm64",
+		Server: "https://drone.company.com",
+		Vars:   map[string]string{"mirror": "https://mirror.company.com"},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := "[ echo, agent-123456, arm64, https://drone.company.com, https://mirror.company.com ]"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Want template variables rendered, got %s", buf.String())
+	}
+}
+
+func TestUserdata_Volume(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name:   "agent-123456",
+		Volume: "/dev/sdf",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(buf.String(), "[ /usr/local/bin/mount-docker-volume, /dev/sdf ]") {
+		t.Errorf("Want docker volume mounted")
+	}
+}
+
+func TestUserdata_GPU(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name: "agent-123456",
+		GPU:  true,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(buf.String(), "[ /usr/local/bin/install-nvidia ]") {
+		t.Errorf("Want nvidia drivers installed")
+	}
+}
+
+func TestUserdata_Windows(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name:    "agent-123456",
+		OS:      "windows",
+		CACert:  []byte(dummyCA),
+		TLSCert: []byte(dummyCert),
+		TLSKey:  []byte(dummykey),
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Contains(buf.String(), "#cloud-config") {
+		t.Errorf("Want powershell script, got cloud-config")
+	}
+	if !strings.Contains(buf.String(), "Install-WindowsFeature") {
+		t.Errorf("Want containers feature installed")
+	}
+}
+
 var dummyCA = `-----BEGIN CERTIFICATE-----
 MIIGOTCCBCGgAwIBAgIJAOE/vJd8EB24MA0GCSqGSIb3DQEBBQUAMIGyMQswCQYD
 VQQGEwJGUjEPMA0GA1UECAwGQWxzYWNlMRMwEQYDVQQHDApTdHJhc2JvdXJnMRgw
@@ -48,3 +244,124 @@ bGFuIFNhbXBsZSBDZXJ0aWZpY2F0ZSBBdXRob3JpdHkxIjAgBgkqhkiG9w0BCQEW
 E2NvbnRhY3RAZnJlZWxhbi5vcmcwHhcNMTIwNDI3MTAzMTE4WhcNMjIwNDI1MTAz
 DiH5uEqBXExjrj0FslxcVKdVj5glVcSmkLwZKbEU1OKwleT/iXFhvooWhQ==
 -----END CERTIFICATE-----`
+
+func TestDaemon_Storage(t *testing.T) {
+	got, err := daemon(&autoscaler.InstanceCreateOpts{
+		Daemon: autoscaler.DaemonOpts{
+			StorageDriver: "overlay2",
+			StorageOpts:   []string{"overlay2.size=20G"},
+			DataRoot:      "/mnt/docker",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, want := range []string{
+		`"storage-driver": "overlay2"`,
+		`"overlay2.size=20G"`,
+		`"data-root": "/mnt/docker"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Want %s in the docker daemon configuration, got %s", want, got)
+		}
+	}
+}
+
+func TestDaemon_Network(t *testing.T) {
+	got, err := daemon(&autoscaler.InstanceCreateOpts{
+		Daemon: autoscaler.DaemonOpts{
+			MTU: 1400,
+			Bip: "10.200.0.1/24",
+			AddressPools: []autoscaler.AddressPool{
+				{Base: "10.10.0.0/16", Size: 24},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, want := range []string{
+		`"mtu": 1400`,
+		`"bip": "10.200.0.1/24"`,
+		`"base": "10.10.0.0/16"`,
+		`"size": 24`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Want %s in the docker daemon configuration, got %s", want, got)
+		}
+	}
+}
+
+func TestUserdata_Version(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name:   "agent-123456",
+		Daemon: autoscaler.DaemonOpts{Version: "24.0.7"},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, want := range []string{
+		`  - [docker-ce, "5:24.0.7*"]`,
+		`  - [docker-ce-cli, "5:24.0.7*"]`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Want %s in the userdata, got %s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	err = T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name:   "agent-123456",
+		OS:     "windows",
+		Daemon: autoscaler.DaemonOpts{Version: "25.0.3"},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want := "docker-25.0.3.zip"; !strings.Contains(buf.String(), want) {
+		t.Errorf("Want %s in the userdata, got %s", want, buf.String())
+	}
+}
+
+func TestDaemon_Cgroup(t *testing.T) {
+	got, err := daemon(&autoscaler.InstanceCreateOpts{
+		Daemon: autoscaler.DaemonOpts{
+			Config:       `{"exec-opts": ["isolation=process"]}`,
+			CgroupDriver: "systemd",
+			CgroupnsMode: "private",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, want := range []string{
+		`"isolation=process"`,
+		`"native.cgroupdriver=systemd"`,
+		`"default-cgroupns-mode": "private"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Want %s in the docker daemon configuration, got %s", want, got)
+		}
+	}
+}
+
+func TestUserdata_SSHKey(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := T.Execute(buf, &autoscaler.InstanceCreateOpts{
+		Name:   "agent-123456",
+		SSHKey: "ecdsa-sha2-nistp256 AAAAE2VjZHNh",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(buf.String(), "ssh_authorized_keys:\n  - ecdsa-sha2-nistp256 AAAAE2VjZHNh\n") {
+		t.Errorf("Want ssh key authorized")
+	}
+}
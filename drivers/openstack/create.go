@@ -17,9 +17,9 @@ import (
 
 // Create creates an OpenStack instance
 func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpts) (*autoscaler.Instance, error) {
-	p.init.Do(func() {
-		_ = p.setup(ctx)
-	})
+	if err := p.ensureSetup(ctx); err != nil {
+		return nil, err
+	}
 
 	buf := new(bytes.Buffer)
 	err := p.userdata.Execute(buf, &opts)
@@ -34,10 +34,15 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		return nil, err
 	}
 
+	flavor := p.flavor
+	if opts.Size != "" {
+		flavor = opts.Size
+	}
+
 	serverCreateOpts := servers.CreateOpts{
 		Name:           opts.Name,
 		ImageName:      p.image,
-		FlavorName:     p.flavor,
+		FlavorName:     flavor,
 		UserData:       buf.Bytes(),
 		ServiceClient:  p.computeClient,
 		Metadata:       p.metadata,
@@ -55,7 +60,7 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 	logger := log.Ctx(ctx).With().
 		Str("region", p.region).
 		Str("image", p.image).
-		Str("sizes", p.flavor).
+		Str("sizes", flavor).
 		Str("name", opts.Name).
 		Logger()
 
@@ -77,7 +82,7 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		Region:   p.region,
 		Address:  ip.IP,
 		Image:    p.image,
-		Size:     p.flavor,
+		Size:     flavor,
 	}
 
 	logger.Debug().
@@ -92,7 +92,7 @@ func TestCreate(t *testing.T) {
 		return
 	}
 	p := v.(*provider)
-	p.init.Do(func() {}) // prevent init function
+	p.ready = true // prevent init function
 
 	instance, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent-RjISb5v1"})
 	if err != nil {
@@ -201,7 +201,7 @@ func TestCreateFail(t *testing.T) {
 		return
 	}
 	p := v.(*provider)
-	p.init.Do(func() {}) // prevent init function
+	p.ready = true // prevent init function
 
 	_, err = p.Create(context.TODO(), autoscaler.InstanceCreateOpts{Name: "agent-RjISb5v1"})
 	if err == nil {
@@ -66,7 +66,7 @@ func TestDestroy(t *testing.T) {
 		return
 	}
 	p := v.(*provider)
-	p.init.Do(func() {}) //
+	p.ready = true //
 
 	err = p.Destroy(mockContext, mockInstance)
 	if err != nil {
@@ -0,0 +1,176 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package openstack
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/secgroups"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/ssh"
+)
+
+// managedName is the name of the keypair and security group
+// managed by the autoscaler.
+const managedName = "drone-autoscaler"
+
+// managedPorts are the ports opened by the managed security
+// group, for the docker tls endpoint and ssh.
+var managedPorts = []int{2376, 22}
+
+// helper function finds or creates the managed security group,
+// which allows ingress to the docker tls port and ssh, and adds
+// the group to the instance security groups. Missing rules are
+// created for an existing group, since a previous attempt may
+// have created the group but failed to create the rules.
+func (p *provider) setupSecurityGroup(ctx context.Context) error {
+	logger := log.Ctx(ctx).With().
+		Str("group", managedName).
+		Logger()
+
+	pages, err := secgroups.List(p.computeClient).AllPages()
+	if err != nil {
+		return err
+	}
+	groups, err := secgroups.ExtractSecurityGroups(pages)
+	if err != nil {
+		return err
+	}
+	var group *secgroups.SecurityGroup
+	for i := range groups {
+		if groups[i].Name == managedName {
+			group = &groups[i]
+			break
+		}
+	}
+
+	if group != nil {
+		logger.Debug().
+			Str("id", group.ID).
+			Msg("using managed security group")
+	} else {
+		group, err = secgroups.Create(p.computeClient, secgroups.CreateOpts{
+			Name:        managedName,
+			Description: "Managed by the drone autoscaler",
+		}).Extract()
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Msg("cannot create managed security group")
+			return err
+		}
+		logger.Info().
+			Str("id", group.ID).
+			Msg("created managed security group")
+	}
+
+	for _, port := range managedPorts {
+		if hasRule(group.Rules, port, p.groupCIDR) {
+			continue
+		}
+		_, err := secgroups.CreateRule(p.computeClient, secgroups.CreateRuleOpts{
+			ParentGroupID: group.ID,
+			FromPort:      port,
+			ToPort:        port,
+			IPProtocol:    "tcp",
+			CIDR:          p.groupCIDR,
+		}).Extract()
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Int("port", port).
+				Msg("cannot create managed security group rule")
+			return err
+		}
+	}
+
+	if !contains(p.groups, group.Name) {
+		p.groups = append(p.groups, group.Name)
+	}
+	return nil
+}
+
+// helper function returns true if the rules include a tcp
+// ingress rule for the port and cidr.
+func hasRule(rules []secgroups.Rule, port int, cidr string) bool {
+	for _, rule := range rules {
+		if rule.FromPort == port &&
+			rule.ToPort == port &&
+			strings.EqualFold(rule.IPProtocol, "tcp") &&
+			rule.IPRange.CIDR == cidr {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the list contains the item.
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function finds or creates the managed keypair. The
+// keypair is created from a generated rsa key. The private key
+// is discarded, since the docker daemon is accessed over tls.
+func (p *provider) setupKeyPair(ctx context.Context) error {
+	logger := log.Ctx(ctx).With().
+		Str("name", managedName).
+		Logger()
+
+	key, err := keypairs.Get(p.computeClient, managedName).Extract()
+	if err == nil {
+		logger.Debug().
+			Str("fingerprint", key.Fingerprint).
+			Msg("using managed ssh key")
+		p.key = key.Name
+		return nil
+	}
+
+	publicKey, err := generatePublicKey()
+	if err != nil {
+		return err
+	}
+
+	key, err = keypairs.Create(p.computeClient, keypairs.CreateOpts{
+		Name:      managedName,
+		PublicKey: publicKey,
+	}).Extract()
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot create managed ssh key")
+		return err
+	}
+
+	logger.Info().
+		Str("fingerprint", key.Fingerprint).
+		Msg("created managed ssh key")
+
+	p.key = key.Name
+	return nil
+}
+
+// helper function generates an rsa key and returns the public
+// key in authorized keys format.
+func generatePublicKey() (string, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+	publicKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return string(ssh.MarshalAuthorizedKey(publicKey)), nil
+}
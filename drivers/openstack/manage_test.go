@@ -0,0 +1,234 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package openstack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/h2non/gock"
+)
+
+func helperClient() *gophercloud.ServiceClient {
+	return &gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       "http://ops.my.cloud/compute/v2.1/",
+	}
+}
+
+func TestSetupSecurityGroup_Create(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://ops.my.cloud").
+		Get("/compute/v2.1/os-security-groups").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"security_groups": [{"id": "1", "name": "default"}]}`)
+
+	gock.New("http://ops.my.cloud").
+		Post("/compute/v2.1/os-security-groups").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"security_group": {"id": "2", "name": "drone-autoscaler"}}`)
+
+	gock.New("http://ops.my.cloud").
+		Post("/compute/v2.1/os-security-group-rules").
+		Times(2).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"security_group_rule": {"id": "3", "parent_group_id": "2"}}`)
+
+	v, err := New(
+		WithComputeClient(helperClient()),
+		WithSecurityGroup("default"),
+		WithManagedSecurityGroup(true, "10.0.0.0/8"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+
+	err = p.setupSecurityGroup(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := strings.Join(p.groups, ","), "default,drone-autoscaler"; got != want {
+		t.Errorf("Want security groups %q, got %q", want, got)
+	}
+	if !gock.IsDone() {
+		t.Error("Not all expected http requests completed")
+	}
+}
+
+func TestSetupSecurityGroup_Existing(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://ops.my.cloud").
+		Get("/compute/v2.1/os-security-groups").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"security_groups": [{"id": "2", "name": "drone-autoscaler", "rules": [{"id": "3", "parent_group_id": "2", "from_port": 2376, "to_port": 2376, "ip_protocol": "tcp", "ip_range": {"cidr": "0.0.0.0/0"}}, {"id": "4", "parent_group_id": "2", "from_port": 22, "to_port": 22, "ip_protocol": "tcp", "ip_range": {"cidr": "0.0.0.0/0"}}]}]}`)
+
+	v, err := New(
+		WithComputeClient(helperClient()),
+		WithManagedSecurityGroup(true, ""),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+
+	err = p.setupSecurityGroup(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := strings.Join(p.groups, ","), "drone-autoscaler"; got != want {
+		t.Errorf("Want security groups %q, got %q", want, got)
+	}
+	if got, want := p.groupCIDR, "0.0.0.0/0"; got != want {
+		t.Errorf("Want default cidr %q, got %q", want, got)
+	}
+}
+
+func TestSetupSecurityGroup_MissingRules(t *testing.T) {
+	defer gock.Off()
+
+	// the group was created by a previous attempt, which
+	// failed after creating the ssh rule.
+	gock.New("http://ops.my.cloud").
+		Get("/compute/v2.1/os-security-groups").
+		Times(2).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"security_groups": [{"id": "2", "name": "drone-autoscaler", "rules": [{"id": "3", "parent_group_id": "2", "from_port": 22, "to_port": 22, "ip_protocol": "tcp", "ip_range": {"cidr": "0.0.0.0/0"}}]}]}`)
+
+	gock.New("http://ops.my.cloud").
+		Post("/compute/v2.1/os-security-group-rules").
+		MatchType("json").
+		JSON(map[string]interface{}{"security_group_rule": map[string]interface{}{
+			"parent_group_id": "2",
+			"from_port":       2376,
+			"to_port":         2376,
+			"ip_protocol":     "tcp",
+			"cidr":            "0.0.0.0/0",
+		}}).
+		Times(2).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"security_group_rule": {"id": "4", "parent_group_id": "2"}}`)
+
+	v, err := New(
+		WithComputeClient(helperClient()),
+		WithManagedSecurityGroup(true, ""),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+
+	for i := 0; i < 2; i++ {
+		if err := p.setupSecurityGroup(context.TODO()); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	if got, want := strings.Join(p.groups, ","), "drone-autoscaler"; got != want {
+		t.Errorf("Want security groups %q, got %q", want, got)
+	}
+	if !gock.IsDone() {
+		t.Error("Not all expected http requests completed")
+	}
+}
+
+func TestSetupKeyPair_Create(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://ops.my.cloud").
+		Get("/compute/v2.1/os-keypairs/drone-autoscaler").
+		Reply(404)
+
+	gock.New("http://ops.my.cloud").
+		Post("/compute/v2.1/os-keypairs").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"keypair": {"name": "drone-autoscaler", "fingerprint": "1e:2c:9b:56:79:4b:45:77:f9:ca:7a:98:2c:b0:d5:3c"}}`)
+
+	v, err := New(
+		WithComputeClient(helperClient()),
+		WithManagedSSHKey(true),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+
+	err = p.setupKeyPair(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := p.key, "drone-autoscaler"; got != want {
+		t.Errorf("Want ssh key %q, got %q", want, got)
+	}
+	if !gock.IsDone() {
+		t.Error("Not all expected http requests completed")
+	}
+}
+
+func TestEnsureSetup_Retry(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://ops.my.cloud").
+		Get("/compute/v2.1/os-keypairs/drone-autoscaler").
+		Reply(404)
+
+	gock.New("http://ops.my.cloud").
+		Post("/compute/v2.1/os-keypairs").
+		Reply(500)
+
+	v, err := New(
+		WithComputeClient(helperClient()),
+		WithManagedSSHKey(true),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p := v.(*provider)
+
+	err = p.ensureSetup(context.TODO())
+	if err == nil {
+		t.Errorf("Expect error when the keypair cannot be created")
+	}
+	if p.ready {
+		t.Errorf("Expect setup to be retried after an error")
+	}
+
+	gock.New("http://ops.my.cloud").
+		Get("/compute/v2.1/os-keypairs/drone-autoscaler").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"keypair": {"name": "drone-autoscaler", "fingerprint": "1e:2c:9b:56:79:4b:45:77:f9:ca:7a:98:2c:b0:d5:3c"}}`)
+
+	err = p.ensureSetup(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !p.ready {
+		t.Errorf("Expect setup to complete")
+	}
+	if got, want := p.key, "drone-autoscaler"; got != want {
+		t.Errorf("Want ssh key %q, got %q", want, got)
+	}
+}
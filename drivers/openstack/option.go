@@ -39,6 +39,35 @@ func WithSecurityGroup(group ...string) Option {
 		p.groups = group
 	}
 }
+
+// WithManagedSecurityGroup returns an option to find or
+// create a security group that allows ingress to the docker
+// tls port and ssh from the cidr. The group is added to the
+// instance security groups.
+func WithManagedSecurityGroup(manage bool, cidr string) Option {
+	return func(p *provider) {
+		p.manageGroup = manage
+		p.groupCIDR = cidr
+	}
+}
+
+// WithManagedSSHKey returns an option to find or create a
+// keypair when no ssh key is configured.
+func WithManagedSSHKey(manage bool) Option {
+	return func(p *provider) {
+		p.manageKey = manage
+	}
+}
+
+// WithAuthOptions returns an option to set the identity api
+// credentials. The credentials are read from the OS_
+// environment variables if the identity endpoint is empty.
+func WithAuthOptions(opts gophercloud.AuthOptions) Option {
+	return func(p *provider) {
+		p.authOpts = opts
+	}
+}
+
 // WithComputeClient returns an option to set the
 // GopherCloud ServiceClient.
 func WithComputeClient(computeClient *gophercloud.ServiceClient) Option {
@@ -97,3 +126,15 @@ func WithUserDataFile(filepath string) Option {
 		}
 	}
 }
+
+// WithUserDataHooks returns an option to merge cloud-init
+// sections, such as write_files and runcmd, into the
+// cloud-init template. Entries of the before sections are
+// inserted before the existing entries, and entries of the
+// after sections are inserted after the existing entries.
+func WithUserDataHooks(before, after string) Option {
+	return func(p *provider) {
+		p.userdataPrepend = before
+		p.userdataAppend = after
+	}
+}
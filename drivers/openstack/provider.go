@@ -16,7 +16,8 @@ import (
 
 // provider implements an OpenStack provider
 type provider struct {
-	init sync.Once
+	mu    sync.Mutex
+	ready bool
 
 	key      string
 	region   string
@@ -28,7 +29,15 @@ type provider struct {
 	groups   []string
 	metadata map[string]string
 
+	manageKey   bool
+	manageGroup bool
+	groupCIDR   string
+
+	authOpts      gophercloud.AuthOptions
 	computeClient *gophercloud.ServiceClient
+
+	userdataPrepend string
+	userdataAppend  string
 }
 
 // New returns a new OpenStack provider.
@@ -41,11 +50,19 @@ func New(opts ...Option) (autoscaler.Provider, error) {
 	if p.userdata == nil {
 		p.userdata = userdata.T
 	}
+	p.userdata = userdata.Hooks(p.userdata, p.userdataPrepend, p.userdataAppend)
+	if p.groupCIDR == "" {
+		p.groupCIDR = "0.0.0.0/0"
+	}
 
 	if p.computeClient == nil {
-		authOpts, err := openstack.AuthOptionsFromEnv()
-		if err != nil {
-			return nil, err
+		authOpts := p.authOpts
+		if authOpts.IdentityEndpoint == "" {
+			var err error
+			authOpts, err = openstack.AuthOptionsFromEnv()
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		authClient, err := openstack.AuthenticatedClient(authOpts)
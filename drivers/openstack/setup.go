@@ -13,11 +13,42 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// helper function runs the one-time setup, retrying on the
+// next call until setup succeeds.
+func (p *provider) ensureSetup(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ready {
+		return nil
+	}
+	if err := p.setup(ctx); err != nil {
+		return err
+	}
+	p.ready = true
+	return nil
+}
+
 func (p *provider) setup(ctx context.Context) error {
 	var g errgroup.Group
-	if p.key == "" {
+	if p.key == "" && p.manageKey {
+		g.Go(func() error {
+			return p.setupKeyPair(ctx)
+		})
+	} else if p.key == "" {
+		// the ssh key is optional, and the instance is created
+		// without a keypair if no default key is found.
+		g.Go(func() error {
+			if err := p.findKeyPair(ctx); err != nil {
+				log.Ctx(ctx).Warn().
+					Err(err).
+					Msg("cannot find default ssh key")
+			}
+			return nil
+		})
+	}
+	if p.manageGroup {
 		g.Go(func() error {
-			return p.findKeyPair(ctx)
+			return p.setupSecurityGroup(ctx)
 		})
 	}
 
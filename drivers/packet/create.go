@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/labels"
 	"github.com/packethost/packngo"
 	"github.com/rs/zerolog/log"
 )
@@ -25,23 +26,34 @@ func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpt
 		return nil, err
 	}
 
+	plan := p.plan
+	if opts.Size != "" {
+		plan = opts.Size
+	}
+
+	facility := p.facility
+	if opts.Region != "" {
+		facility = opts.Region
+	}
+
 	logger := log.Ctx(ctx).With().
 		Str("project", p.project).
-		Str("facility", p.facility).
+		Str("facility", facility).
 		Str("billing", p.billing).
-		Str("plan", p.plan).
+		Str("plan", plan).
 		Str("os", p.os).
 		Str("hostname", p.hostname).
 		Logger()
 
 	cr := &packngo.DeviceCreateRequest{
 		HostName:     p.hostname,
-		Facility:     p.facility,
-		Plan:         p.plan,
+		Facility:     facility,
+		Plan:         plan,
 		OS:           p.os,
 		ProjectID:    p.project,
 		BillingCycle: p.billing,
 		UserData:     buf.String(),
+		Tags:         append(labels.Tags(p.labels), p.tags...),
 	}
 
 	logger.Debug().
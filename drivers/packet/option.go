@@ -64,7 +64,15 @@ func WithHostname(hostname string) Option {
 	}
 }
 
-// WithTags returns an option to set the image.
+// WithLabels returns an option to set key value labels,
+// which are applied to the device as key:value tags.
+func WithLabels(labels map[string]string) Option {
+	return func(p *provider) {
+		p.labels = labels
+	}
+}
+
+// WithTags returns an option to set the device tags.
 func WithTags(tags ...string) Option {
 	return func(p *provider) {
 		p.tags = tags
@@ -94,3 +102,15 @@ func WithUserDataFile(filepath string) Option {
 		}
 	}
 }
+
+// WithUserDataHooks returns an option to merge cloud-init
+// sections, such as write_files and runcmd, into the
+// cloud-init template. Entries of the before sections are
+// inserted before the existing entries, and entries of the
+// after sections are inserted after the existing entries.
+func WithUserDataHooks(before, after string) Option {
+	return func(p *provider) {
+		p.userdataPrepend = before
+		p.userdataAppend = after
+	}
+}
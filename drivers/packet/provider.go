@@ -27,10 +27,14 @@ type provider struct {
 	project  string
 	sshkey   string
 	hostname string
+	labels   map[string]string
 	tags     []string
 	userdata *template.Template
 
 	client *packngo.Client
+
+	userdataPrepend string
+	userdataAppend  string
 }
 
 // New returns a new Packet.net provider.
@@ -54,6 +58,7 @@ func New(opts ...Option) autoscaler.Provider {
 	if p.userdata == nil {
 		p.userdata = userdata.T
 	}
+	p.userdata = userdata.Hooks(p.userdata, p.userdataPrepend, p.userdataAppend)
 	if p.client == nil {
 		p.client = packngo.NewClient(
 			consumerToken, p.apikey, nil)
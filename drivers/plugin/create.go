@@ -0,0 +1,67 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errNoInstance is returned when the plugin create response
+// does not include the instance.
+var errNoInstance = errors.New("Plugin did not return an instance")
+
+func (p *provider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpts) (*autoscaler.Instance, error) {
+	buf := new(bytes.Buffer)
+	err := p.userdata.Execute(buf, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("plugin", p.path).
+		Str("name", opts.Name).
+		Logger()
+
+	logger.Debug().
+		Msg("plugin creating instance")
+
+	res, err := p.exec(ctx, MethodCreate, &Request{
+		Create: &CreateRequest{
+			Name:     opts.Name,
+			CACert:   string(opts.CACert),
+			CAKey:    string(opts.CAKey),
+			TLSCert:  string(opts.TLSCert),
+			TLSKey:   string(opts.TLSKey),
+			OS:       opts.OS,
+			Size:     opts.Size,
+			Region:   opts.Region,
+			GPU:      opts.GPU,
+			Userdata: buf.String(),
+			Labels:   p.labels,
+		},
+	})
+	// if the plugin returns an instance with the error the
+	// instance was created and must be cleaned up.
+	if res != nil && res.Instance != nil {
+		instance := convertInstance(res.Instance)
+		if instance.Name == "" {
+			instance.Name = opts.Name
+		}
+		return instance, err
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("plugin failed to create instance")
+		return nil, err
+	}
+	return nil, errNoInstance
+}
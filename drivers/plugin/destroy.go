@@ -0,0 +1,43 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (p *provider) Destroy(ctx context.Context, instance *autoscaler.Instance) error {
+	logger := log.Ctx(ctx).With().
+		Str("plugin", p.path).
+		Str("id", instance.ID).
+		Str("name", instance.Name).
+		Logger()
+
+	logger.Debug().
+		Msg("plugin deleting instance")
+
+	_, err := p.exec(ctx, MethodDestroy, &Request{
+		Instance: toInstance(instance),
+	})
+	if err == autoscaler.ErrInstanceNotFound {
+		logger.Warn().
+			Msg("instance does not exist")
+		return err
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("plugin failed to delete instance")
+		return err
+	}
+
+	logger.Debug().
+		Msg("plugin deleted instance")
+	return nil
+}
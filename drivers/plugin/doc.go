@@ -0,0 +1,37 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+/*
+Package plugin contains an autoscaler driver that delegates
+server management to an external plugin executable, so that
+third parties can ship out-of-tree drivers without forking
+the autoscaler.
+
+The plugin is executed once per call, with the method name
+as the first argument:
+
+	drone-autoscaler-plugin create
+	drone-autoscaler-plugin destroy
+	drone-autoscaler-plugin inspect
+	drone-autoscaler-plugin validate
+
+The Request is written to the plugin stdin as json, and the
+plugin must write a single Response to stdout as json. The
+plugin stderr is captured and logged for debugging purposes.
+
+A plugin reports well-known failures using the response
+error code, for example "not_found" if the instance does
+not exist, or "insufficient_capacity" if the instance cannot
+be created. Plugins that do not support a method should
+return the "not_implemented" error code.
+
+Plugins written in Go can use the Serve function to expose
+an autoscaler.Provider implementation.
+
+Configure driver with:
+DRONE_PLUGIN_PATH=/usr/local/bin/drone-autoscaler-plugin
+DRONE_PLUGIN_ARGS=--config,/etc/plugin.yml
+DRONE_PLUGIN_ENVIRON=PLUGIN_TOKEN:secret
+*/
+package plugin
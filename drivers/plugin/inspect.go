@@ -0,0 +1,30 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler"
+)
+
+// Inspect returns the current instance details. If the plugin
+// does not implement the inspect method, the instance is
+// returned unchanged.
+func (p *provider) Inspect(ctx context.Context, instance *autoscaler.Instance) (*autoscaler.Instance, error) {
+	res, err := p.exec(ctx, MethodInspect, &Request{
+		Instance: toInstance(instance),
+	})
+	if err == errNotImplemented {
+		return instance, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if res.Instance == nil {
+		return instance, nil
+	}
+	return convertInstance(res.Instance), nil
+}
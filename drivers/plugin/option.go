@@ -0,0 +1,84 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"io/ioutil"
+
+	"github.com/drone/autoscaler/drivers/internal/userdata"
+)
+
+// Option configures a plugin provider option.
+type Option func(*provider)
+
+// WithLabels returns an option to set the key value labels
+// passed to the plugin with the create request.
+func WithLabels(labels map[string]string) Option {
+	return func(p *provider) {
+		p.labels = labels
+	}
+}
+
+// WithPath returns an option to set the plugin executable
+// path.
+func WithPath(path string) Option {
+	return func(p *provider) {
+		p.path = path
+	}
+}
+
+// WithArgs returns an option to set additional arguments
+// passed to the plugin executable.
+func WithArgs(args ...string) Option {
+	return func(p *provider) {
+		p.args = args
+	}
+}
+
+// WithEnviron returns an option to set the plugin environment
+// variables, in key=value format.
+func WithEnviron(environ map[string]string) Option {
+	return func(p *provider) {
+		for k, v := range environ {
+			p.environ = append(p.environ, k+"="+v)
+		}
+	}
+}
+
+// WithUserData returns an option to set the cloud-init
+// template from text.
+func WithUserData(text string) Option {
+	return func(p *provider) {
+		if text != "" {
+			p.userdata = userdata.Parse(text)
+		}
+	}
+}
+
+// WithUserDataFile returns an option to set the cloud-init
+// template from file.
+func WithUserDataFile(filepath string) Option {
+	return func(p *provider) {
+		if filepath != "" {
+			b, err := ioutil.ReadFile(filepath)
+			if err != nil {
+				panic(err)
+			}
+			p.userdata = userdata.Parse(string(b))
+		}
+	}
+}
+
+// WithUserDataHooks returns an option to merge cloud-init
+// sections, such as write_files and runcmd, into the
+// cloud-init template. Entries of the before sections are
+// inserted before the existing entries, and entries of the
+// after sections are inserted after the existing entries.
+func WithUserDataHooks(before, after string) Option {
+	return func(p *provider) {
+		p.userdataPrepend = before
+		p.userdataAppend = after
+	}
+}
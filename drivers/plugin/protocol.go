@@ -0,0 +1,148 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"github.com/drone/autoscaler"
+)
+
+// Plugin method enumeration.
+const (
+	MethodCreate   = "create"
+	MethodDestroy  = "destroy"
+	MethodInspect  = "inspect"
+	MethodValidate = "validate"
+)
+
+// Plugin error code enumeration.
+const (
+	CodeNotFound             = "not_found"
+	CodeReclaimed            = "reclaimed"
+	CodeInsufficientCapacity = "insufficient_capacity"
+	CodeNotImplemented       = "not_implemented"
+)
+
+type (
+	// Request is written to the plugin stdin.
+	Request struct {
+		// Create is the instance create request. It is
+		// set for the create method only.
+		Create *CreateRequest `json:"create,omitempty"`
+
+		// Instance is the instance to destroy or inspect.
+		Instance *Instance `json:"instance,omitempty"`
+	}
+
+	// CreateRequest defines the instance create options.
+	// Certificates are pem encoded.
+	CreateRequest struct {
+		Name     string            `json:"name"`
+		CACert   string            `json:"ca_cert"`
+		CAKey    string            `json:"ca_key"`
+		TLSCert  string            `json:"tls_cert"`
+		TLSKey   string            `json:"tls_key"`
+		OS       string            `json:"os,omitempty"`
+		Size     string            `json:"size,omitempty"`
+		Region   string            `json:"region,omitempty"`
+		GPU      bool              `json:"gpu,omitempty"`
+		Userdata string            `json:"userdata,omitempty"`
+		Labels   map[string]string `json:"labels,omitempty"`
+	}
+
+	// Instance represents a server instance.
+	Instance struct {
+		Provider string  `json:"provider"`
+		ID       string  `json:"id"`
+		Name     string  `json:"name"`
+		Address  string  `json:"address"`
+		Region   string  `json:"region"`
+		Image    string  `json:"image"`
+		Size     string  `json:"size"`
+		Price    float64 `json:"price,omitempty"`
+	}
+
+	// Response is written to the plugin stdout.
+	Response struct {
+		Instance *Instance `json:"instance,omitempty"`
+		Error    *Error    `json:"error,omitempty"`
+	}
+
+	// Error represents a plugin error.
+	Error struct {
+		Code    string `json:"code,omitempty"`
+		Message string `json:"message"`
+	}
+)
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// helper function converts the plugin error to the
+// equivalent autoscaler error, if one exists.
+func convertError(err *Error) error {
+	switch err.Code {
+	case CodeNotFound:
+		return autoscaler.ErrInstanceNotFound
+	case CodeReclaimed:
+		return autoscaler.ErrInstanceReclaimed
+	case CodeInsufficientCapacity:
+		return autoscaler.ErrInsufficientCapacity
+	default:
+		return err
+	}
+}
+
+// helper function converts the autoscaler error to the
+// equivalent plugin error.
+func toError(err error) *Error {
+	switch err {
+	case autoscaler.ErrInstanceNotFound:
+		return &Error{Code: CodeNotFound, Message: err.Error()}
+	case autoscaler.ErrInstanceReclaimed:
+		return &Error{Code: CodeReclaimed, Message: err.Error()}
+	case autoscaler.ErrInsufficientCapacity:
+		return &Error{Code: CodeInsufficientCapacity, Message: err.Error()}
+	default:
+		return &Error{Message: err.Error()}
+	}
+}
+
+// helper function converts the autoscaler instance to the
+// plugin instance.
+func toInstance(in *autoscaler.Instance) *Instance {
+	if in == nil {
+		return nil
+	}
+	return &Instance{
+		Provider: string(in.Provider),
+		ID:       in.ID,
+		Name:     in.Name,
+		Address:  in.Address,
+		Region:   in.Region,
+		Image:    in.Image,
+		Size:     in.Size,
+		Price:    in.Price,
+	}
+}
+
+// helper function converts the plugin instance to the
+// autoscaler instance.
+func convertInstance(in *Instance) *autoscaler.Instance {
+	if in == nil {
+		return nil
+	}
+	return &autoscaler.Instance{
+		Provider: autoscaler.ProviderType(in.Provider),
+		ID:       in.ID,
+		Name:     in.Name,
+		Address:  in.Address,
+		Region:   in.Region,
+		Image:    in.Image,
+		Size:     in.Size,
+		Price:    in.Price,
+	}
+}
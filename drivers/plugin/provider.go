@@ -0,0 +1,100 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"text/template"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/drivers/internal/userdata"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errNotImplemented is returned when the plugin does not
+// implement the requested method.
+var errNotImplemented = errors.New("Not implemented")
+
+// provider implements a plugin provider.
+type provider struct {
+	path     string
+	args     []string
+	environ  []string
+	labels   map[string]string
+	userdata *template.Template
+
+	userdataPrepend string
+	userdataAppend  string
+}
+
+// New returns a new plugin provider.
+func New(opts ...Option) autoscaler.Provider {
+	p := new(provider)
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.userdata == nil {
+		p.userdata = userdata.T
+	}
+	p.userdata = userdata.Hooks(p.userdata, p.userdataPrepend, p.userdataAppend)
+	return p
+}
+
+// helper function executes the plugin method, writing the
+// request to stdin and reading the response from stdout.
+func (p *provider) exec(ctx context.Context, method string, req *Request) (*Response, error) {
+	logger := log.Ctx(ctx).With().
+		Str("plugin", p.path).
+		Str("method", method).
+		Logger()
+
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	cmd := exec.CommandContext(ctx, p.path, append([]string{method}, p.args...)...)
+	cmd.Env = append(os.Environ(), p.environ...)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		logger.Error().
+			Err(err).
+			Str("stderr", stderr.String()).
+			Msg("plugin execution failed")
+		return nil, err
+	}
+
+	if stderr.Len() != 0 {
+		logger.Debug().
+			Str("stderr", stderr.String()).
+			Msg("plugin execution complete")
+	}
+
+	res := new(Response)
+	if err := json.Unmarshal(stdout.Bytes(), res); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("cannot decode plugin response")
+		return nil, err
+	}
+	if res.Error != nil {
+		if res.Error.Code == CodeNotImplemented {
+			return res, errNotImplemented
+		}
+		return res, convertError(res.Error)
+	}
+	return res, nil
+}
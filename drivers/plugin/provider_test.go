@@ -0,0 +1,127 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/drone/autoscaler"
+)
+
+// TestMain runs the test binary as a plugin when the
+// TEST_PLUGIN environment variable is set. This allows the
+// unit tests to execute a real plugin process.
+func TestMain(m *testing.M) {
+	if os.Getenv("TEST_PLUGIN") == "true" {
+		Serve(new(fakeProvider))
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeProvider implements a fake provider served by the
+// test plugin process.
+type fakeProvider struct{}
+
+func (*fakeProvider) Create(ctx context.Context, opts autoscaler.InstanceCreateOpts) (*autoscaler.Instance, error) {
+	if opts.Size == "large" {
+		return nil, autoscaler.ErrInsufficientCapacity
+	}
+	return &autoscaler.Instance{
+		Provider: "fake",
+		ID:       "i-123456",
+		Name:     opts.Name,
+		Address:  "10.0.0.1",
+		Size:     opts.Size,
+	}, nil
+}
+
+func (*fakeProvider) Destroy(ctx context.Context, instance *autoscaler.Instance) error {
+	if instance.ID != "i-123456" {
+		return autoscaler.ErrInstanceNotFound
+	}
+	return nil
+}
+
+func newTestProvider() autoscaler.Provider {
+	return New(
+		WithPath(os.Args[0]),
+		WithEnviron(map[string]string{"TEST_PLUGIN": "true"}),
+	)
+}
+
+func TestCreate(t *testing.T) {
+	p := newTestProvider()
+
+	instance, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{
+		Name: "agent-807jVFwj",
+		Size: "small",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := instance.ID, "i-123456"; got != want {
+		t.Errorf("Want instance ID %q, got %q", want, got)
+	}
+	if got, want := instance.Name, "agent-807jVFwj"; got != want {
+		t.Errorf("Want instance Name %q, got %q", want, got)
+	}
+	if got, want := instance.Address, "10.0.0.1"; got != want {
+		t.Errorf("Want instance Address %q, got %q", want, got)
+	}
+	if got, want := instance.Provider, autoscaler.ProviderType("fake"); got != want {
+		t.Errorf("Want instance Provider %q, got %q", want, got)
+	}
+}
+
+func TestCreate_InsufficientCapacity(t *testing.T) {
+	p := newTestProvider()
+
+	_, err := p.Create(context.TODO(), autoscaler.InstanceCreateOpts{
+		Name: "agent-807jVFwj",
+		Size: "large",
+	})
+	if err != autoscaler.ErrInsufficientCapacity {
+		t.Errorf("Want insufficient capacity error, got %v", err)
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	p := newTestProvider()
+
+	err := p.Destroy(context.TODO(), &autoscaler.Instance{ID: "i-123456"})
+	if err != nil {
+		t.Error(err)
+	}
+	err = p.Destroy(context.TODO(), &autoscaler.Instance{ID: "i-654321"})
+	if err != autoscaler.ErrInstanceNotFound {
+		t.Errorf("Want not found error, got %v", err)
+	}
+}
+
+func TestInspect_NotImplemented(t *testing.T) {
+	p := newTestProvider()
+
+	instance := &autoscaler.Instance{ID: "i-123456"}
+	got, err := p.(autoscaler.Inspector).Inspect(context.TODO(), instance)
+	if err != nil {
+		t.Error(err)
+	}
+	if got != instance {
+		t.Errorf("Want instance returned unchanged")
+	}
+}
+
+func TestValidate_NotImplemented(t *testing.T) {
+	p := newTestProvider()
+
+	err := p.(autoscaler.Validator).Validate(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+}
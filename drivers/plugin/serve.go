@@ -0,0 +1,85 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/drone/autoscaler"
+)
+
+// Serve serves the provider as a plugin, reading the request
+// from stdin and writing the response to stdout. It is
+// intended to be called from the plugin main function.
+func Serve(provider autoscaler.Provider) {
+	var method string
+	if len(os.Args) > 1 {
+		method = os.Args[1]
+	}
+	if err := serve(context.Background(), provider, method, os.Stdin, os.Stdout); err != nil {
+		os.Stderr.WriteString(err.Error())
+		os.Exit(1)
+	}
+}
+
+func serve(ctx context.Context, provider autoscaler.Provider, method string, r io.Reader, w io.Writer) error {
+	req := new(Request)
+	if err := json.NewDecoder(r).Decode(req); err != nil {
+		return err
+	}
+
+	var instance *autoscaler.Instance
+	var err error
+
+	switch method {
+	case MethodCreate:
+		if req.Create == nil {
+			return errors.New("Missing create request")
+		}
+		instance, err = provider.Create(ctx, autoscaler.InstanceCreateOpts{
+			Name:    req.Create.Name,
+			CACert:  []byte(req.Create.CACert),
+			CAKey:   []byte(req.Create.CAKey),
+			TLSCert: []byte(req.Create.TLSCert),
+			TLSKey:  []byte(req.Create.TLSKey),
+			OS:      req.Create.OS,
+			Size:    req.Create.Size,
+			Region:  req.Create.Region,
+			GPU:     req.Create.GPU,
+		})
+	case MethodDestroy:
+		err = provider.Destroy(ctx, convertInstance(req.Instance))
+	case MethodInspect:
+		inspector, ok := provider.(autoscaler.Inspector)
+		if !ok {
+			return json.NewEncoder(w).Encode(&Response{
+				Error: &Error{Code: CodeNotImplemented, Message: "Not implemented"},
+			})
+		}
+		instance, err = inspector.Inspect(ctx, convertInstance(req.Instance))
+	case MethodValidate:
+		validator, ok := provider.(autoscaler.Validator)
+		if !ok {
+			return json.NewEncoder(w).Encode(&Response{
+				Error: &Error{Code: CodeNotImplemented, Message: "Not implemented"},
+			})
+		}
+		err = validator.Validate(ctx)
+	default:
+		return json.NewEncoder(w).Encode(&Response{
+			Error: &Error{Code: CodeNotImplemented, Message: "Not implemented"},
+		})
+	}
+
+	res := &Response{Instance: toInstance(instance)}
+	if err != nil {
+		res.Error = toError(err)
+	}
+	return json.NewEncoder(w).Encode(res)
+}
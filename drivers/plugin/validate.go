@@ -0,0 +1,24 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+)
+
+// Validate verifies the plugin configuration. If the plugin
+// does not implement the validate method, only the plugin
+// executable is verified.
+func (p *provider) Validate(ctx context.Context) error {
+	if p.path == "" {
+		return errors.New("No plugin executable configured")
+	}
+	_, err := p.exec(ctx, MethodValidate, new(Request))
+	if err == errNotImplemented {
+		return nil
+	}
+	return err
+}
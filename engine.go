@@ -4,7 +4,10 @@
 
 package autoscaler
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // An Engine is responsible for running the scaling
 // alogirthm to provision and shutdown instances according
@@ -14,9 +17,47 @@ type Engine interface {
 	// to cancel a running engine.
 	Start(context.Context)
 	// Pause pauses the Engine.
-	Pause()
+	Pause() error
 	// Paused returns true if th Engine is paused.
 	Paused() bool
+	// LastPlan returns the time the planner last completed
+	// a planning cycle, or the zero time if the planner has
+	// not completed a planning cycle.
+	LastPlan() time.Time
 	// Resume resumes the Engine if paused.
-	Resume()
+	Resume() error
+	// PausePool pauses the named pool.
+	PausePool(string) error
+	// ResumePool resumes the named pool if paused.
+	ResumePool(string) error
+	// Upgrade starts a rolling upgrade of the agent image
+	// across the running servers.
+	Upgrade(context.Context, string) error
+	// Rotate starts the rotation of the docker tls
+	// certificate of the named server.
+	Rotate(context.Context, string) error
+	// Repair starts a reinstall of the named server.
+	Repair(context.Context, string) error
+	// Replace provisions a replacement for the named server,
+	// and destroys the named server once the replacement is
+	// running, and returns the replacement.
+	Replace(context.Context, string) (*Server, error)
+	// Destroy immediately destroys the named server,
+	// without draining the running builds.
+	Destroy(context.Context, string) error
+	// Cordon excludes the named server from new builds
+	// without terminating the server.
+	Cordon(context.Context, string) error
+	// Uncordon returns the named server to service.
+	Uncordon(context.Context, string) error
+	// Scale sets the target server count of the named
+	// pool. A negative count clears the target.
+	Scale(context.Context, string, int) error
+	// Queue returns the view of the build queue of the
+	// planner of each pool.
+	Queue(context.Context) ([]*QueueSnapshot, error)
+	// Preview returns the plan of each pool, executed
+	// against the current build volume and server capacity,
+	// without allocating or terminating servers.
+	Preview(context.Context) ([]*Plan, error)
 }
@@ -20,6 +20,41 @@ type allocator struct {
 
 	servers  autoscaler.ServerStore
 	provider autoscaler.Provider
+
+	// sizes is an ordered list of instance sizes. If the
+	// provider has insufficient capacity for a size, the
+	// next size in the list is used.
+	sizes []string
+
+	// regions is an ordered list of regions. If the provider
+	// fails to create an instance in a region, the next
+	// region in the list is used.
+	regions *failover
+
+	// gpu instructs the provider to install the gpu drivers.
+	gpu bool
+
+	// os is the agent operating system.
+	os string
+
+	// pool, server, secret and vars are passed to the userdata
+	// template of the provider.
+	pool   string
+	server string
+	secret string
+	vars   map[string]string
+
+	// daemon configures the docker daemon of the server.
+	daemon autoscaler.DaemonOpts
+
+	// sshKeys instructs the allocator to generate an ssh
+	// keypair for each server.
+	sshKeys bool
+
+	// ssh instructs the allocator to skip the docker tls
+	// certificates, since the docker daemon is accessed over
+	// ssh and is not exposed on the network.
+	ssh bool
 }
 
 func (a *allocator) Allocate(ctx context.Context) error {
@@ -62,28 +97,48 @@ func (a *allocator) allocate(ctx context.Context, server *autoscaler.Server) err
 		}
 	}()
 
-	ca, err := certs.GenerateCA()
-	if err != nil {
-		return err
-	}
-
-	cert, err := certs.GenerateCert(server.Name, ca)
-	if err != nil {
-		return err
-	}
-
 	ctx, cancel := context.WithTimeout(ctx, time.Hour)
 	defer cancel()
 
 	opts := autoscaler.InstanceCreateOpts{
-		Name:    server.Name,
-		CAKey:   ca.Key,
-		CACert:  ca.Cert,
-		TLSKey:  cert.Key,
-		TLSCert: cert.Cert,
+		Name:   server.Name,
+		GPU:    a.gpu,
+		OS:     a.os,
+		Pool:   a.pool,
+		Server: a.server,
+		Secret: a.secret,
+		Vars:   a.vars,
+		Daemon: a.daemon,
+	}
+
+	if !a.ssh {
+		ca, err := certs.GenerateCA()
+		if err != nil {
+			return err
+		}
+
+		cert, err := certs.GenerateCert(server.Name, ca)
+		if err != nil {
+			return err
+		}
+
+		opts.CAKey = ca.Key
+		opts.CACert = ca.Cert
+		opts.TLSKey = cert.Key
+		opts.TLSCert = cert.Cert
+	}
+
+	var sshKey []byte
+	if a.sshKeys {
+		private, public, err := generateSSHKey()
+		if err != nil {
+			return err
+		}
+		sshKey = private
+		opts.SSHKey = public
 	}
 
-	instance, err := a.provider.Create(ctx, opts)
+	instance, err := a.create(ctx, opts)
 	if err != nil {
 		log.Ctx(ctx).Error().
 			Err(err).
@@ -106,11 +161,74 @@ func (a *allocator) allocate(ctx context.Context, server *autoscaler.Server) err
 		server.Provider = instance.Provider
 		server.Region = instance.Region
 		server.Size = instance.Size
+		server.Price = instance.Price
 		server.CACert = opts.CACert
 		server.CAKey = opts.CAKey
 		server.TLSCert = opts.TLSCert
 		server.TLSKey = opts.TLSKey
+		server.SSHKey = sshKey
 		server.Started = time.Now().Unix()
 	}
 	return a.servers.Update(ctx, server)
 }
+
+// helper function creates the instance, falling back to the
+// next instance size when the provider has insufficient
+// capacity, and to the next region when the provider fails
+// to create the instance.
+func (a *allocator) create(ctx context.Context, opts autoscaler.InstanceCreateOpts) (*autoscaler.Instance, error) {
+	regions := []string{""}
+	if a.regions != nil {
+		regions = a.regions.candidates()
+	}
+	logger := log.Ctx(ctx)
+
+	var instance *autoscaler.Instance
+	var err error
+	for _, region := range regions {
+		opts.Region = region
+		instance, err = a.createSize(ctx, opts)
+		if err == nil {
+			a.regions.success(region)
+			return instance, nil
+		}
+		// if the provider returns an instance with the error
+		// the instance was created and must be cleaned up,
+		// so we cannot fail over to the next region.
+		if instance != nil {
+			return instance, err
+		}
+		a.regions.failure(region)
+		if len(regions) > 1 {
+			logger.Warn().
+				Err(err).
+				Str("server", opts.Name).
+				Str("region", region).
+				Msg("cannot create server, trying next region")
+		}
+	}
+	return instance, err
+}
+
+// helper function creates the instance, falling back to the
+// next instance size when the provider has insufficient
+// capacity.
+func (a *allocator) createSize(ctx context.Context, opts autoscaler.InstanceCreateOpts) (*autoscaler.Instance, error) {
+	if len(a.sizes) == 0 {
+		return a.provider.Create(ctx, opts)
+	}
+	var instance *autoscaler.Instance
+	var err error
+	for _, size := range a.sizes {
+		opts.Size = size
+		instance, err = a.provider.Create(ctx, opts)
+		if err != autoscaler.ErrInsufficientCapacity {
+			break
+		}
+		log.Ctx(ctx).Warn().
+			Str("server", opts.Name).
+			Str("size", size).
+			Msg("insufficient capacity, trying next instance size")
+	}
+	return instance, err
+}
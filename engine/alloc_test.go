@@ -20,7 +20,7 @@ func TestAllocate(t *testing.T) {
 	defer controller.Finish()
 
 	mockctx := context.Background()
-	mockInstance := &autoscaler.Instance{}
+	mockInstance := &autoscaler.Instance{Price: 0.0464}
 	mockServers := []*autoscaler.Server{
 		{State: autoscaler.StatePending},
 	}
@@ -43,6 +43,59 @@ func TestAllocate(t *testing.T) {
 	if got, want := mockServers[0].State, autoscaler.StateCreated; got != want {
 		t.Errorf("Want server state Created, got %v", got)
 	}
+	if got, want := mockServers[0].Price, 0.0464; got != want {
+		t.Errorf("Want server price %v, got %v", want, got)
+	}
+}
+
+func TestAllocate_TemplateVars(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServers := []*autoscaler.Server{
+		{Name: "agent-1", State: autoscaler.StatePending},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StatePending).Return(mockServers, nil)
+	store.EXPECT().Update(mockctx, mockServers[0]).Return(nil)
+	store.EXPECT().Update(gomock.Any(), mockServers[0]).Return(nil)
+
+	var opts autoscaler.InstanceCreateOpts
+	provider := mocks.NewMockProvider(controller)
+	provider.EXPECT().Create(gomock.Any(), gomock.Any()).Do(
+		func(_ context.Context, in autoscaler.InstanceCreateOpts) {
+			opts = in
+		},
+	).Return(&autoscaler.Instance{}, nil)
+
+	a := allocator{
+		servers:  store,
+		provider: provider,
+		pool:     "arm64",
+		server:   "https://drone.company.com",
+		secret:   "correct-horse-battery-staple",
+		vars:     map[string]string{"mirror": "https://mirror.company.com"},
+	}
+	a.Allocate(mockctx)
+	a.wg.Wait()
+
+	if got, want := opts.Pool, "arm64"; got != want {
+		t.Errorf("Want pool %q, got %q", want, got)
+	}
+	if got, want := opts.Server, "https://drone.company.com"; got != want {
+		t.Errorf("Want server %q, got %q", want, got)
+	}
+	if got, want := opts.Secret, "correct-horse-battery-staple"; got != want {
+		t.Errorf("Want secret %q, got %q", want, got)
+	}
+	if got, want := opts.Vars["mirror"], "https://mirror.company.com"; got != want {
+		t.Errorf("Want template variable %q, got %q", want, got)
+	}
+	if len(opts.TLSCert) == 0 {
+		t.Errorf("Want tls certificate generated")
+	}
 }
 
 func TestAllocate_ServerCreateError(t *testing.T) {
@@ -110,3 +163,81 @@ func TestAllocate_ServerUpdateError(t *testing.T) {
 		t.Errorf("Want server state Staging, got %v", got)
 	}
 }
+
+func TestAllocate_SizeFallback(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockInstance := &autoscaler.Instance{Size: "c5.xlarge"}
+	mockServers := []*autoscaler.Server{
+		{State: autoscaler.StatePending},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StatePending).Return(mockServers, nil)
+	store.EXPECT().Update(mockctx, mockServers[0]).Return(nil)
+	store.EXPECT().Update(gomock.Any(), mockServers[0]).Return(nil)
+
+	provider := mocks.NewMockProvider(controller)
+	gomock.InOrder(
+		provider.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, autoscaler.ErrInsufficientCapacity),
+		provider.EXPECT().Create(gomock.Any(), gomock.Any()).Return(mockInstance, nil),
+	)
+
+	a := allocator{
+		servers:  store,
+		provider: provider,
+		sizes:    []string{"m5.xlarge", "c5.xlarge", "m4.xlarge"},
+	}
+	a.Allocate(mockctx)
+	a.wg.Wait()
+
+	if got, want := mockServers[0].State, autoscaler.StateCreated; got != want {
+		t.Errorf("Want server state Created, got %v", got)
+	}
+	if got, want := mockServers[0].Size, "c5.xlarge"; got != want {
+		t.Errorf("Want server size %q, got %q", want, got)
+	}
+}
+
+func TestAllocate_RegionFailover(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockerr := errors.New("quota exceeded")
+	mockInstance := &autoscaler.Instance{Region: "us-west-2a"}
+	mockServers := []*autoscaler.Server{
+		{State: autoscaler.StatePending},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StatePending).Return(mockServers, nil)
+	store.EXPECT().Update(mockctx, mockServers[0]).Return(nil)
+	store.EXPECT().Update(gomock.Any(), mockServers[0]).Return(nil)
+
+	provider := mocks.NewMockProvider(controller)
+	gomock.InOrder(
+		provider.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, mockerr),
+		provider.EXPECT().Create(gomock.Any(), gomock.Any()).Return(mockInstance, nil),
+	)
+
+	a := allocator{
+		servers:  store,
+		provider: provider,
+		regions:  newFailover([]string{"us-east-1", "us-west-2"}),
+	}
+	a.Allocate(mockctx)
+	a.wg.Wait()
+
+	if got, want := mockServers[0].State, autoscaler.StateCreated; got != want {
+		t.Errorf("Want server state Created, got %v", got)
+	}
+	if got, want := mockServers[0].Region, "us-west-2a"; got != want {
+		t.Errorf("Want server region %q, got %q", want, got)
+	}
+	if got, want := a.regions.failures["us-east-1"], 1; got != want {
+		t.Errorf("Want %d region failures, got %d", want, got)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// bastion dials server addresses through an ssh jump host,
+// used to reach servers that do not have a public address.
+type bastion struct {
+	sync.Mutex
+
+	addr       string
+	user       string
+	keyfile    string
+	knownHosts string
+	hostKey    string
+
+	client *ssh.Client
+}
+
+// newBastion returns a new bastion for the ssh jump host.
+// The port defaults to 22 when not included in the address.
+func newBastion(addr, user, keyfile, knownHosts string) *bastion {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+	return &bastion{
+		addr:       addr,
+		user:       user,
+		keyfile:    keyfile,
+		knownHosts: knownHosts,
+	}
+}
+
+// DialContext connects to the address through the jump host.
+// The ssh connection is shared, and is re-established once if
+// the dial fails because the connection was closed.
+func (b *bastion) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := b.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.Dial(network, addr)
+	if err == nil {
+		return conn, nil
+	}
+	b.reset(client)
+	client, err = b.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Dial(network, addr)
+}
+
+// helper function returns the shared ssh client, connecting to
+// the jump host if no connection exists.
+func (b *bastion) connect(ctx context.Context) (*ssh.Client, error) {
+	b.Lock()
+	defer b.Unlock()
+	if b.client != nil {
+		return b.client, nil
+	}
+
+	config, err := b.config()
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", b.addr)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, b.addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	b.client = ssh.NewClient(c, chans, reqs)
+	return b.client, nil
+}
+
+// helper function closes and discards the ssh client, if it is
+// still the shared client.
+func (b *bastion) reset(client *ssh.Client) {
+	b.Lock()
+	defer b.Unlock()
+	if b.client == client {
+		b.client.Close()
+		b.client = nil
+	}
+}
+
+// helper function returns the ssh client configuration. The
+// host key presented on the first connection is pinned if no
+// known hosts file is set. The caller must hold the lock.
+func (b *bastion) config() (*ssh.ClientConfig, error) {
+	callback, err := hostKeyCallback(b.knownHosts, b.hostKey, func(key string) {
+		b.hostKey = key
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sshConfig(b.user, b.keyfile, callback)
+}
+
+// helper function returns the ssh host key callback. The host
+// key is verified against the known hosts file, if set, or else
+// against the pinned host key, in authorized keys format. If
+// neither is set, the host key presented by the server is
+// accepted and passed to the pin function, so that later
+// connections can be verified.
+func hostKeyCallback(knownHosts, pinned string, pin func(string)) (ssh.HostKeyCallback, error) {
+	if knownHosts != "" {
+		return knownhosts.New(knownHosts)
+	}
+	if pinned != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(pinned))
+		if err != nil {
+			return nil, err
+		}
+		return ssh.FixedHostKey(key), nil
+	}
+	return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+		pin(strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))))
+		return nil
+	}, nil
+}
+
+// helper function returns the ssh client configuration for the
+// user and private key file.
+func sshConfig(user, keyfile string, callback ssh.HostKeyCallback) (*ssh.ClientConfig, error) {
+	key, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		return nil, err
+	}
+	return sshKeyConfig(user, key, callback)
+}
+
+// helper function returns the ssh client configuration for
+// the user and pem encoded private key.
+func sshKeyConfig(user string, key []byte, callback ssh.HostKeyCallback) (*ssh.ClientConfig, error) {
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: callback,
+	}, nil
+}
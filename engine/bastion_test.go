@@ -0,0 +1,58 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewBastion(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"bastion.company.com", "bastion.company.com:22"},
+		{"bastion.company.com:2222", "bastion.company.com:2222"},
+		{"10.0.0.1", "10.0.0.1:22"},
+	}
+	for _, test := range tests {
+		b := newBastion(test.addr, "root", "/root/.ssh/id_rsa", "")
+		if got := b.addr; got != test.want {
+			t.Errorf("Want bastion address %s, got %s", test.want, got)
+		}
+	}
+}
+
+func TestHostKeyCallback_Pin(t *testing.T) {
+	key1, key2 := testHostSigner(t).PublicKey(), testHostSigner(t).PublicKey()
+
+	var pinned string
+	callback, err := hostKeyCallback("", "", func(key string) {
+		pinned = key
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := callback("10.0.0.1:22", nil, key1); err != nil {
+		t.Errorf("Want host key accepted on first connect, got %s", err)
+	}
+	if !strings.HasPrefix(pinned, "ssh-ed25519 ") {
+		t.Errorf("Want host key pinned on first connect")
+	}
+
+	callback, err = hostKeyCallback("", pinned, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := callback("10.0.0.1:22", nil, key1); err != nil {
+		t.Errorf("Want pinned host key accepted, got %s", err)
+	}
+	if err := callback("10.0.0.1:22", nil, key2); err == nil {
+		t.Errorf("Want error when the host key does not match the pinned key")
+	}
+}
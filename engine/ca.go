@@ -0,0 +1,78 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	docker "docker.io/go-docker"
+)
+
+// caScript installs the certificate authorities in the host
+// trust store, and in the registry certificate directory of
+// each registry. It runs in the host namespaces, entered with
+// nsenter.
+const caScript = `set -e
+if [ -d /usr/local/share/ca-certificates ]; then
+	printf '%s\n' "$DRONE_CA_CERTS" > /usr/local/share/ca-certificates/drone-autoscaler.crt
+	update-ca-certificates
+elif [ -d /etc/pki/ca-trust/source/anchors ]; then
+	printf '%s\n' "$DRONE_CA_CERTS" > /etc/pki/ca-trust/source/anchors/drone-autoscaler.crt
+	update-ca-trust extract
+fi
+for registry in $DRONE_CA_REGISTRIES; do
+	mkdir -p "$DRONE_CA_CERTS_DIR/$registry"
+	printf '%s\n' "$DRONE_CA_CERTS" > "$DRONE_CA_CERTS_DIR/$registry/ca.crt"
+done
+`
+
+// ReadCACerts reads the pem encoded certificate authorities
+// installed on the servers. An error is returned if the file
+// does not contain a valid certificate.
+func ReadCACerts(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rest, count := data, 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("Invalid certificate authority in %s: %s", path, err)
+		}
+		count++
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("Cannot find a certificate authority in %s", path)
+	}
+	return data, nil
+}
+
+// helper function installs the certificate authorities on
+// the host. The certificate authorities are installed by a
+// privileged container that enters the host namespaces.
+func (i *installer) setupCACerts(ctx context.Context, client docker.APIClient) error {
+	dir := "/etc/docker/certs.d"
+	if i.podman {
+		dir = "/etc/containers/certs.d"
+	}
+	return i.runHost(ctx, client, "drone-ca-certs", caScript, []string{
+		"DRONE_CA_CERTS=" + strings.TrimSpace(string(i.caCerts)),
+		"DRONE_CA_CERTS_DIR=" + dir,
+		"DRONE_CA_REGISTRIES=" + strings.Join(i.caRegistries, " "),
+	})
+}
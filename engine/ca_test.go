@@ -0,0 +1,106 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler/engine/certs"
+	"github.com/drone/autoscaler/mocks"
+
+	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/network"
+	"github.com/golang/mock/gomock"
+)
+
+func TestReadCACerts(t *testing.T) {
+	ca, err := certs.GenerateCA()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	f, err := ioutil.TempFile("", "ca")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.Remove(f.Name())
+	f.Write(ca.Cert)
+	f.Close()
+
+	data, err := ReadCACerts(f.Name())
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(data, ca.Cert) {
+		t.Errorf("Want certificate authority file contents")
+	}
+}
+
+func TestReadCACerts_Invalid(t *testing.T) {
+	f, err := ioutil.TempFile("", "ca")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("not a certificate")
+	f.Close()
+
+	if _, err := ReadCACerts(f.Name()); err == nil {
+		t.Errorf("Want error reading a file without certificates")
+	}
+	if _, err := ReadCACerts("/path/to/missing/ca.pem"); err == nil {
+		t.Errorf("Want error reading a missing file")
+	}
+	if data, err := ReadCACerts(""); data != nil || err != nil {
+		t.Errorf("Want no certificate authorities without a file")
+	}
+}
+
+func TestSetupCACerts(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+
+	wait := make(chan container.ContainerWaitOKBody, 1)
+	wait <- container.ContainerWaitOKBody{StatusCode: 0}
+	errc := make(chan error)
+
+	var config *container.Config
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ImagePull(mockctx, "alpine:3", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil)
+	client.EXPECT().ContainerCreate(mockctx, gomock.Any(), gomock.Any(), gomock.Any(), "drone-ca-certs").Do(
+		func(_ context.Context, c *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ string) {
+			config = c
+		},
+	).Return(container.ContainerCreateCreatedBody{ID: "3f2a"}, nil)
+	client.EXPECT().ContainerStart(mockctx, "3f2a", gomock.Any()).Return(nil)
+	client.EXPECT().ContainerWait(mockctx, "3f2a", container.WaitConditionNotRunning).Return((<-chan container.ContainerWaitOKBody)(wait), (<-chan error)(errc))
+	client.EXPECT().ContainerRemove(gomock.Any(), "3f2a", gomock.Any()).Return(nil)
+
+	i := installer{
+		caCerts:      []byte("-----BEGIN CERTIFICATE-----\n"),
+		caRegistries: []string{"registry.company.local", "registry.company.local:5000"},
+	}
+	if err := i.setupCACerts(mockctx, client); err != nil {
+		t.Error(err)
+		return
+	}
+	want := []string{
+		"DRONE_CA_CERTS=-----BEGIN CERTIFICATE-----",
+		"DRONE_CA_CERTS_DIR=/etc/docker/certs.d",
+		"DRONE_CA_REGISTRIES=registry.company.local registry.company.local:5000",
+	}
+	if config == nil || !reflect.DeepEqual(config.Env, want) {
+		t.Errorf("Want certificate authority environment %v, got %v", want, config)
+	}
+}
@@ -44,14 +44,17 @@ func (c *collector) Collect(ctx context.Context) error {
 
 		c.wg.Add(1)
 		go func(server *autoscaler.Server) {
-			c.collect(ctx, server)
+			c.collect(ctx, server, false)
 			c.wg.Done()
 		}(server)
 	}
 	return nil
 }
 
-func (c *collector) collect(ctx context.Context, server *autoscaler.Server) error {
+// helper function destroys the server. The agent is stopped
+// before the server is destroyed, unless force is true, which
+// destroys servers that are hung without waiting.
+func (c *collector) collect(ctx context.Context, server *autoscaler.Server, force bool) error {
 	logger := log.Ctx(ctx)
 	logger.Debug().
 		Str("server", server.Name).
@@ -79,20 +82,22 @@ func (c *collector) collect(ctx context.Context, server *autoscaler.Server) erro
 		Size:     server.Size,
 	}
 
-	client, err := c.client(server)
-	if err != nil {
-		return err
-	}
+	if !force {
+		client, err := c.client(server)
+		if err != nil {
+			return err
+		}
 
-	timeout := time.Hour * 60
-	err = client.ContainerStop(ctx, "agent", &timeout)
-	if err != nil {
-		logger.Warn().Err(err).
-			Str("server", server.Name).
-			Msg("cannot stop the agent")
+		timeout := time.Hour * 60
+		err = client.ContainerStop(ctx, "agent", &timeout)
+		if err != nil {
+			logger.Warn().Err(err).
+				Str("server", server.Name).
+				Msg("cannot stop the agent")
+		}
 	}
 
-	err = c.provider.Destroy(ctx, in)
+	err := c.provider.Destroy(ctx, in)
 	if err != nil {
 		logger.Error().
 			Str("server", server.Name).
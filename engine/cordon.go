@@ -0,0 +1,131 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// errCordonState is returned when cordoning a server that
+	// is not running.
+	errCordonState = errors.New("Server is not running")
+
+	// errUncordonState is returned when uncordoning a server
+	// that is not cordoned.
+	errUncordonState = errors.New("Server is not cordoned")
+)
+
+// Cordon cordons the named server. The agent container is
+// paused, so that the server does not accept new builds, and
+// the server is moved to the cordoned state, which excludes
+// the server from scale down and pinging until uncordoned.
+func (e *engine) Cordon(ctx context.Context, name string) error {
+	server, err := e.installer.servers.Find(ctx, name)
+	if err != nil || server.Pool != e.pool {
+		return autoscaler.ErrServerNotFound
+	}
+	if server.State != autoscaler.StateRunning {
+		return errCordonState
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("server", name).
+		Logger()
+
+	err = pauseAgent(logger.WithContext(ctx), e.installer, server)
+	if err != nil {
+		return err
+	}
+
+	server.State = autoscaler.StateCordoned
+	err = e.installer.servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server state")
+		return err
+	}
+
+	logger.Info().
+		Msg("server cordoned")
+	return nil
+}
+
+// Uncordon uncordons the named server. The agent container
+// is unpaused, and the server is returned to the running
+// state.
+func (e *engine) Uncordon(ctx context.Context, name string) error {
+	server, err := e.installer.servers.Find(ctx, name)
+	if err != nil || server.Pool != e.pool {
+		return autoscaler.ErrServerNotFound
+	}
+	if server.State != autoscaler.StateCordoned {
+		return errUncordonState
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("server", name).
+		Logger()
+
+	err = unpauseAgent(logger.WithContext(ctx), e.installer, server)
+	if err != nil {
+		return err
+	}
+
+	server.State = autoscaler.StateRunning
+	err = e.installer.servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server state")
+		return err
+	}
+
+	logger.Info().
+		Msg("server uncordoned")
+	return nil
+}
+
+// helper function pauses the agent container of the server,
+// so that the server does not accept new builds.
+func pauseAgent(ctx context.Context, installer *installer, server *autoscaler.Server) error {
+	logger := log.Ctx(ctx)
+	client, err := installer.client(server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot create docker client")
+		return err
+	}
+	err = client.ContainerPause(ctx, "agent")
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot pause agent container")
+		return err
+	}
+	return nil
+}
+
+// helper function unpauses the agent container of the server,
+// so that the server accepts new builds.
+func unpauseAgent(ctx context.Context, installer *installer, server *autoscaler.Server) error {
+	logger := log.Ctx(ctx)
+	client, err := installer.client(server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot create docker client")
+		return err
+	}
+	err = client.ContainerUnpause(ctx, "agent")
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot unpause agent container")
+		return err
+	}
+	return nil
+}
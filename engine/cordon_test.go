@@ -0,0 +1,104 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	docker "docker.io/go-docker"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCordon(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerPause(mockctx, "agent").Return(nil)
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Return(nil)
+
+	e := engine{installer: &installer{
+		servers: store,
+		client: func(*autoscaler.Server) (docker.APIClient, error) {
+			return client, nil
+		},
+	}}
+	if err := e.Cordon(mockctx, "agent-1"); err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateCordoned; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+func TestCordon_State(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateStaging}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+	e := engine{installer: &installer{servers: store}}
+	if err := e.Cordon(mockctx, "agent-1"); err != errCordonState {
+		t.Errorf("Want cordon state error, got %v", err)
+	}
+}
+
+func TestUncordon(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateCordoned}
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerUnpause(mockctx, "agent").Return(nil)
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Return(nil)
+
+	e := engine{installer: &installer{
+		servers: store,
+		client: func(*autoscaler.Server) (docker.APIClient, error) {
+			return client, nil
+		},
+	}}
+	if err := e.Uncordon(mockctx, "agent-1"); err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+func TestUncordon_State(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+	e := engine{installer: &installer{servers: store}}
+	if err := e.Uncordon(mockctx, "agent-1"); err != errUncordonState {
+		t.Errorf("Want uncordon state error, got %v", err)
+	}
+}
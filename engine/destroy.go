@@ -0,0 +1,62 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errDestroyState is returned when force destroying a server
+// that is not provisioned, is being installed, or is already
+// stopped.
+var errDestroyState = errors.New("Server cannot be destroyed in its current state")
+
+// Destroy immediately destroys the named server. The running
+// builds are not drained, the agent is not stopped, and the
+// minimum server age is ignored, so that servers that are hung
+// are destroyed without waiting. The server is destroyed in
+// the background.
+func (e *engine) Destroy(ctx context.Context, name string) error {
+	servers := e.collector.servers
+	server, err := servers.Find(ctx, name)
+	if err != nil || server.Pool != e.pool {
+		return autoscaler.ErrServerNotFound
+	}
+	switch server.State {
+	case autoscaler.StatePending, autoscaler.StateCreating, autoscaler.StateStaging, autoscaler.StateStopped:
+		return errDestroyState
+	}
+	if server.ID == "" {
+		return errDestroyState
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("server", name).
+		Str("state", string(server.State)).
+		Logger()
+
+	server.State = autoscaler.StateStopping
+	err = servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server state")
+		return err
+	}
+
+	logger.Warn().
+		Msg("force destroying server")
+
+	e.collector.wg.Add(1)
+	go func() {
+		e.collector.collect(logger.WithContext(ctx), server, true)
+		e.collector.wg.Done()
+	}()
+	return nil
+}
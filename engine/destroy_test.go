@@ -0,0 +1,91 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	docker "docker.io/go-docker"
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestDestroy_State(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	for _, state := range []autoscaler.ServerState{
+		autoscaler.StateStaging,
+		autoscaler.StateStopped,
+	} {
+		mockServer := &autoscaler.Server{ID: "i-1", Name: "agent-1", State: state}
+
+		store := mocks.NewMockServerStore(controller)
+		store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+		e := engine{collector: &collector{servers: store}}
+		if err := e.Destroy(mockctx, "agent-1"); err != errDestroyState {
+			t.Errorf("Want destroy state error for %s server, got %v", state, err)
+		}
+	}
+}
+
+func TestDestroy_Pool(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{ID: "i-1", Name: "agent-1", State: autoscaler.StateRunning, Pool: "arm64"}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+	e := engine{collector: &collector{servers: store}}
+	if err := e.Destroy(mockctx, "agent-1"); err != autoscaler.ErrServerNotFound {
+		t.Errorf("Want server assigned to another pool not found, got %v", err)
+	}
+}
+
+// this test verifies that the agent is not stopped, since
+// the agent of a hung server may never stop.
+func TestDestroy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{ID: "i-1", Name: "agent-1", State: autoscaler.StateRunning}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Return(nil)
+	store.EXPECT().Update(gomock.Any(), mockServer).Return(nil)
+
+	provider := mocks.NewMockProvider(controller)
+	provider.EXPECT().Destroy(gomock.Any(), gomock.Any()).Return(nil)
+
+	e := engine{
+		collector: &collector{
+			servers:  store,
+			provider: provider,
+			client: func(*autoscaler.Server) (docker.APIClient, error) {
+				t.Errorf("Want agent not stopped")
+				return nil, nil
+			},
+		},
+	}
+	err := e.Destroy(mockctx, "agent-1")
+	e.collector.wg.Wait()
+
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateStopped; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
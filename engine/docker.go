@@ -5,9 +5,11 @@
 package engine
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"net/http"
 
 	docker "docker.io/go-docker"
@@ -20,9 +22,30 @@ import (
 // mock unit testing.
 type clientFunc func(*autoscaler.Server) (docker.APIClient, error)
 
+// dialFunc defines a function used to dial the Docker daemon.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
 // newDockerClient returns a new Docker client configured for the
 // Server host and certificate chain.
 func newDockerClient(server *autoscaler.Server) (docker.APIClient, error) {
+	return newDockerClientDial(server, nil)
+}
+
+// newDockerClientFunc returns a clientFunc that dials the
+// Docker daemon using the dial function.
+func newDockerClientFunc(dial dialFunc) clientFunc {
+	if dial == nil {
+		return newDockerClient
+	}
+	return func(server *autoscaler.Server) (docker.APIClient, error) {
+		return newDockerClientDial(server, dial)
+	}
+}
+
+// newDockerClientDial returns a new Docker client configured
+// for the Server host and certificate chain, that dials the
+// Docker daemon using the dial function, if not nil.
+func newDockerClientDial(server *autoscaler.Server, dial dialFunc) (docker.APIClient, error) {
 	tlsCert, err := tls.X509KeyPair(server.TLSCert, server.TLSKey)
 	if err != nil {
 		return nil, err
@@ -36,6 +59,7 @@ func newDockerClient(server *autoscaler.Server) (docker.APIClient, error) {
 	client := &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: tlsConfig,
+			DialContext:     dial,
 		},
 	}
 	host := fmt.Sprintf("https://%s:2376", server.Address)
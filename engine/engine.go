@@ -16,10 +16,22 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// defines the interval at which terminated instances are
-// purged from the database.
+// defines the default interval at which terminated instances
+// are purged from the database.
 const purge = time.Hour * 24
 
+// defines the default period that stopped servers are retained
+// before they are soft-deleted.
+const defaultStoppedTTL = time.Hour * 24
+
+// defines the default period that soft-deleted servers are
+// retained in the database before they are pruned.
+const defaultRetention = time.Hour * 24 * 7
+
+// defines the default period that scale events are retained
+// in the database before they are pruned.
+const defaultScaleRetention = time.Hour * 24 * 90
+
 type engine struct {
 	mu sync.Mutex
 
@@ -29,9 +41,44 @@ type engine struct {
 	pinger    *pinger
 	planner   *planner
 	reaper    *reaper
+	reclaimer *reclaimer
+	upgrader  *upgrader
+	rotator   *rotator
+	keys      *keyRotator
+	updater   *updater
+	usage     *usageTracker
 
-	interval time.Duration
-	paused   bool
+	interval   time.Duration
+	update     time.Duration
+	purgeEvery time.Duration
+	stoppedTTL time.Duration
+	errorTTL   time.Duration
+	retention  time.Duration
+	scaleTTL   time.Duration
+	paused     bool
+	planned    time.Time
+	upgrading  bool
+	notifier   Notifier
+	states     autoscaler.PoolStateStore
+	pool       string
+	exec       bool
+}
+
+// defaultPool is the name used to address the unnamed pool.
+const defaultPool = "default"
+
+// helper function returns the function used to dial the
+// docker daemon, or nil if servers are dialed directly.
+func newDialer(config config.Config) dialFunc {
+	if config.Bastion.Host == "" {
+		return nil
+	}
+	return newBastion(
+		config.Bastion.Host,
+		config.Bastion.User,
+		config.Bastion.Key,
+		config.Bastion.KnownHosts,
+	).DialContext
 }
 
 // New returns a new autoscale Engine.
@@ -40,18 +87,95 @@ func New(
 	config config.Config,
 	servers autoscaler.ServerStore,
 	provider autoscaler.Provider,
+	opts ...Option,
 ) autoscaler.Engine {
-	return &engine{
-		paused:   false,
-		interval: config.Interval,
+	// the engine of a named pool manages only the servers
+	// assigned to the pool.
+	if config.Pool.Name != "" {
+		servers = &poolStore{ServerStore: servers, pool: config.Pool.Name}
+	}
+	dial := newDialer(config)
+
+	// the address pools and certificate authorities are
+	// validated on startup.
+	pools, _ := ParseAddressPools(config.Docker.AddressPools)
+	caCerts, _ := ReadCACerts(config.Docker.CACertFile)
+	dockerClient := newDockerClientFunc(dial)
+
+	// servers provisioned over ssh are accessed through the
+	// docker daemon unix socket, forwarded over ssh. The podman
+	// api does not support tls, so podman hosts are always
+	// provisioned over ssh. The exec runner is installed over
+	// ssh, and docker is not installed.
+	var provision func(context.Context, *autoscaler.Server) error
+	var exec func(context.Context, *autoscaler.Server, []string) error
+	overSSH := config.Installer.SSH || config.Installer.Podman || config.Installer.Exec
+	if overSSH {
+		script := config.Installer.Script
+		if script == "" && !config.Installer.Podman {
+			script = versionScript(config.Docker.Version)
+		}
+		remote := newSSHInstaller(
+			config.Installer.User,
+			config.Installer.Key,
+			config.Installer.KnownHosts,
+			script,
+			config.Installer.Podman,
+			dial,
+		)
+		dockerClient = remote.Client
+		provision = remote.Provision
+		if config.Installer.Exec {
+			provision = nil
+			exec = newExecInstaller(remote, config.Installer.ExecURL, config.Agent.Arch).Install
+		}
+	}
+	e := &engine{
+		paused:     false,
+		interval:   config.Interval,
+		update:     config.Upgrade.Interval,
+		purgeEvery: config.Purge.Interval,
+		stoppedTTL: config.Purge.StoppedTTL,
+		errorTTL:   config.Purge.ErrorTTL,
+		retention:  config.Purge.Retention,
+		scaleTTL:   config.Purge.ScaleRetention,
+		pool:       config.Pool.Name,
+		exec:       config.Installer.Exec,
 		allocator: &allocator{
 			servers:  servers,
 			provider: provider,
+			sizes:    config.Pool.Sizes,
+			regions:  newFailover(config.Pool.Regions),
+			gpu:      config.Agent.GPU,
+			os:       config.Agent.OS,
+			ssh:      overSSH,
+			sshKeys:  config.Installer.ManagedKeys,
+			pool:     config.Pool.Name,
+			server:   config.Server.Proto + "://" + config.Server.Host,
+			secret:   config.Agent.Token,
+			vars:     config.Pool.Vars,
+			daemon: autoscaler.DaemonOpts{
+				RegistryMirrors:    config.Docker.RegistryMirrors,
+				InsecureRegistries: config.Docker.InsecureRegistries,
+				Config:             config.Docker.DaemonConfig,
+				HTTPProxy:          config.Proxy.HTTP,
+				HTTPSProxy:         config.Proxy.HTTPS,
+				NoProxy:            config.Proxy.NoProxy,
+				StorageDriver:      config.Docker.StorageDriver,
+				StorageOpts:        config.Docker.StorageOpts,
+				DataRoot:           config.Docker.DataRoot,
+				MTU:                config.Docker.MTU,
+				Bip:                config.Docker.Bip,
+				AddressPools:       pools,
+				Version:            config.Docker.Version,
+				CgroupDriver:       config.Docker.CgroupDriver,
+				CgroupnsMode:       config.Docker.CgroupnsMode,
+			},
 		},
 		collector: &collector{
 			servers:  servers,
 			provider: provider,
-			client:   newDockerClient,
+			client:   dockerClient,
 		},
 		installer: &installer{
 			servers:            servers,
@@ -60,9 +184,14 @@ func New(
 			envs:               config.Agent.Environ,
 			volumes:            config.Agent.Volumes,
 			labels:             config.Agent.Labels,
+			gpu:                config.Agent.GPU,
+			os:                 config.Agent.OS,
+			arch:               config.Agent.Arch,
+			kernel:             config.Agent.Kernel,
+			variant:            config.Agent.Version,
 			proto:              config.Server.Proto,
 			host:               config.Server.Host,
-			client:             newDockerClient,
+			client:             dockerClient,
 			runner:             config.Runner,
 			gcEnabled:          config.GC.Enabled,
 			gcDebug:            config.GC.Debug,
@@ -74,36 +203,104 @@ func New(
 			watchtowerImage:    config.Watchtower.Image,
 			watchtowerTimeout:  config.Watchtower.Timeout,
 			watchtowerInterval: config.Watchtower.Interval,
+			provision:          provision,
+			exec:               exec,
+			systemd:            config.Installer.Systemd,
+			systemdImage:       config.Installer.SystemdImage,
+			podman:             config.Installer.Podman,
+			packages:           config.Installer.Packages,
+			caCerts:            caCerts,
+			caRegistries:       config.Docker.CARegistries,
+			registryUser:       config.Agent.RegistryUsername,
+			registryPass:       config.Agent.RegistryPassword,
+			registryHelper:     config.Agent.RegistryHelper,
+			httpProxy:          config.Proxy.HTTP,
+			httpsProxy:         config.Proxy.HTTPS,
+			noProxy:            config.Proxy.NoProxy,
+			readyTimeout:       config.Agent.ReadyTimeout,
+			readyPeriod:        config.Agent.ReadyPeriod,
+			registerCheck:      config.Agent.RegistrationCheck,
+			registerTimeout:    config.Agent.RegistrationTimeout,
+			registerPattern:    config.Agent.RegistrationPattern,
+			prepull:            config.Agent.PrepullImages,
+			cpus:               config.Agent.CPUs,
+			memory:             int64(config.Agent.Memory),
+			pidsLimit:          config.Agent.PidsLimit,
+			instanceTimeout:    config.Installer.InstanceTimeout,
+			dockerTimeout:      config.Installer.DockerTimeout,
+			imageTimeout:       config.Installer.ImageTimeout,
+			agentTimeout:       config.Installer.AgentTimeout,
+			retryInterval:      config.Installer.RetryInterval,
 		},
 		pinger: &pinger{
 			servers: servers,
-			client:  newDockerClient,
+			client:  dockerClient,
 		},
 		planner: &planner{
-			client:  client,
-			servers: servers,
-			os:      config.Agent.OS,
-			arch:    config.Agent.Arch,
-			version: config.Agent.Version,
-			kernel:  config.Agent.Kernel,
-			ttu:     config.Pool.MinAge,
-			min:     config.Pool.Min,
-			max:     config.Pool.Max,
-			cap:     config.Agent.Concurrency,
-			labels:  config.Agent.Labels,
+			client:   client,
+			servers:  servers,
+			provider: provider,
+			os:       config.Agent.OS,
+			arch:     config.Agent.Arch,
+			version:  config.Agent.Version,
+			kernel:   config.Agent.Kernel,
+			ttu:      config.Pool.MinAge,
+			min:      config.Pool.Min,
+			max:      config.Pool.Max,
+			cap:      config.Agent.Concurrency,
+			labels:   config.Agent.Labels,
+			exec:     config.Installer.Exec,
+			pool:     config.Pool.Name,
 		},
 		reaper: &reaper{
 			servers:  servers,
 			provider: provider,
 		},
+		reclaimer: &reclaimer{
+			servers:  servers,
+			provider: provider,
+		},
+	}
+	e.upgrader = &upgrader{
+		servers:   servers,
+		installer: e.installer,
+		planner:   e.planner,
+		timeout:   config.Upgrade.DrainTimeout,
+	}
+	e.rotator = &rotator{
+		servers:   servers,
+		installer: e.installer,
+		planner:   e.planner,
+		age:       config.Certs.RotationAge,
+		timeout:   config.Upgrade.DrainTimeout,
+	}
+	e.keys = &keyRotator{
+		servers:   servers,
+		installer: e.installer,
+		user:      config.Installer.User,
+		age:       config.Installer.KeyRotationAge,
 	}
+	e.updater = &updater{
+		servers:   servers,
+		installer: e.installer,
+	}
+	e.usage = &usageTracker{
+		client:  client,
+		servers: servers,
+		planner: e.planner,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // Pause paueses the scaler.
-func (e *engine) Pause() {
+func (e *engine) Pause() error {
 	e.mu.Lock()
 	e.paused = true
 	e.mu.Unlock()
+	return e.saveState(context.Background())
 }
 
 // Paused returns true if scaling is paused.
@@ -113,16 +310,174 @@ func (e *engine) Paused() bool {
 	return e.paused
 }
 
+// LastPlan returns the time the planner last completed a
+// planning cycle.
+func (e *engine) LastPlan() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.planned
+}
+
 // Resume resumes the scaler.
-func (e *engine) Resume() {
+func (e *engine) Resume() error {
 	e.mu.Lock()
 	e.paused = false
 	e.mu.Unlock()
+	return e.saveState(context.Background())
+}
+
+// PausePool pauses the scaler, if the scaler manages the
+// named pool. The unnamed pool is addressed as default.
+func (e *engine) PausePool(pool string) error {
+	if !e.named(pool) {
+		return autoscaler.ErrPoolNotFound
+	}
+	return e.Pause()
+}
+
+// ResumePool resumes the scaler, if the scaler manages the
+// named pool. The unnamed pool is addressed as default.
+func (e *engine) ResumePool(pool string) error {
+	if !e.named(pool) {
+		return autoscaler.ErrPoolNotFound
+	}
+	return e.Resume()
+}
+
+// helper function returns true if the scaler manages the
+// named pool.
+func (e *engine) named(pool string) bool {
+	return pool == e.pool || (e.pool == "" && pool == defaultPool)
+}
+
+// Upgrade starts a rolling upgrade of the agent image. The
+// upgrade runs in the background until complete, or until the
+// context is canceled.
+func (e *engine) Upgrade(ctx context.Context, image string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.exec {
+		return errUpgradeExec
+	}
+	if e.upgrading {
+		return errUpgradeInProgress
+	}
+	e.upgrading = true
+	go func() {
+		e.upgrader.Upgrade(ctx, image)
+		e.mu.Lock()
+		e.upgrading = false
+		e.mu.Unlock()
+	}()
+	return nil
+}
+
+// Rotate starts the rotation of the docker tls certificate of
+// the named server. The server is cordoned before returning,
+// and the certificate is rotated in the background once the
+// running builds complete.
+func (e *engine) Rotate(ctx context.Context, name string) error {
+	server, err := e.rotator.servers.Find(ctx, name)
+	if err != nil || server.Pool != e.pool {
+		return autoscaler.ErrServerNotFound
+	}
+	err = e.rotator.cordon(ctx, server)
+	if err != nil {
+		return err
+	}
+	go e.rotator.rotate(ctx, server)
+	return nil
+}
+
+// Repair starts the repair of the named server. The install
+// sequence is run again in the background, and the docker tls
+// certificate is refreshed once the agent is running.
+func (e *engine) Repair(ctx context.Context, name string) error {
+	server, err := e.installer.servers.Find(ctx, name)
+	if err != nil || server.Pool != e.pool {
+		return autoscaler.ErrServerNotFound
+	}
+	switch server.State {
+	case autoscaler.StateRunning, autoscaler.StateError:
+	default:
+		return errRepairState
+	}
+	// the phase is reset so that the install sequence is
+	// repeated from the start, instead of resumed.
+	server.State = autoscaler.StateStaging
+	server.Phase = ""
+	server.Error = ""
+	err = e.installer.servers.Update(ctx, server)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Str("server", name).
+			Msg("cannot update server state")
+		return err
+	}
+	go e.repair(ctx, server)
+	return nil
+}
+
+// Scale sets the target server count of the pool. The planner
+// allocates servers until the target is reached, and does not
+// scale below the target, until the target is cleared with a
+// negative count. The unnamed pool is addressed as default.
+func (e *engine) Scale(ctx context.Context, pool string, n int) error {
+	if !e.named(pool) {
+		return autoscaler.ErrPoolNotFound
+	}
+	e.planner.setTarget(n)
+	if n < 0 {
+		log.Ctx(ctx).Info().
+			Str("pool", pool).
+			Msg("target server count cleared")
+	} else {
+		log.Ctx(ctx).Info().
+			Str("pool", pool).
+			Int("count", n).
+			Msg("target server count set")
+	}
+	return e.saveState(ctx)
+}
+
+// Queue returns the view of the build queue of the planner.
+// The unnamed pool is named default.
+func (e *engine) Queue(ctx context.Context) ([]*autoscaler.QueueSnapshot, error) {
+	snapshot, err := e.planner.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Pool = e.pool
+	if snapshot.Pool == "" {
+		snapshot.Pool = defaultPool
+	}
+	return []*autoscaler.QueueSnapshot{snapshot}, nil
+}
+
+// Preview returns the plan of the planner, without allocating
+// or terminating servers.
+func (e *engine) Preview(ctx context.Context) ([]*autoscaler.Plan, error) {
+	plan, err := e.planner.Preview(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []*autoscaler.Plan{plan}, nil
 }
 
 func (e *engine) Start(ctx context.Context) {
+	if e.pool != "" {
+		logger := log.Ctx(ctx).With().
+			Str("pool", e.pool).
+			Logger()
+		ctx = logger.WithContext(ctx)
+	}
+
+	// the state is restored when the engine starts, which is
+	// when the replica is elected leader.
+	e.restoreState(ctx)
+
 	var wg sync.WaitGroup
-	wg.Add(5)
+	wg.Add(11)
 	go func() {
 		e.allocate(ctx)
 		wg.Done()
@@ -151,6 +506,22 @@ func (e *engine) Start(ctx context.Context) {
 		e.ping(ctx)
 		wg.Done()
 	}()
+	go func() {
+		e.reclaim(ctx)
+		wg.Done()
+	}()
+	go func() {
+		e.rotate(ctx)
+		wg.Done()
+	}()
+	go func() {
+		e.autoUpgrade(ctx)
+		wg.Done()
+	}()
+	go func() {
+		e.track(ctx)
+		wg.Done()
+	}()
 	wg.Wait()
 }
 
@@ -192,35 +563,46 @@ func (e *engine) install(ctx context.Context) {
 	}
 }
 
-// runs the collection process.
+// runs the collection process. The collector is woken when
+// notified, so that stopped servers are destroyed promptly.
 func (e *engine) collect(ctx context.Context) {
 	const interval = time.Second * 10
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-time.After(interval):
-			e.collector.Collect(ctx)
-		}
-	}
+	schedule(ctx, interval, subscribe(e.notifier), func() {
+		e.collector.Collect(ctx)
+	})
 }
 
-// runs the planning process.
+// runs the planning process. The planner is woken when
+// notified, so that capacity is planned promptly when the
+// build queue changes.
 func (e *engine) plan(ctx context.Context) {
+	schedule(ctx, e.interval, subscribe(e.notifier), func() {
+		if !e.Paused() && e.planner.Plan(ctx) == nil {
+			e.mu.Lock()
+			e.planned = time.Now()
+			e.mu.Unlock()
+		}
+	})
+}
+
+// runs the usage tracking process.
+func (e *engine) track(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(e.interval):
-			if !e.Paused() {
-				e.planner.Plan(ctx)
-			}
+		case <-time.After(usageInterval):
+			e.usage.Track(ctx)
 		}
 	}
 }
 
-// runs the ping process.
+// runs the ping process. The exec runner is installed
+// without docker, and servers are therefore not pinged.
 func (e *engine) ping(ctx context.Context) {
+	if e.exec {
+		return
+	}
 	const interval = time.Minute * 10
 	for {
 		select {
@@ -232,10 +614,14 @@ func (e *engine) ping(ctx context.Context) {
 	}
 }
 
-// runs the purge process.
+// runs the purge process. Stopped servers, and errored
+// servers if the error ttl is set, are soft-deleted, and are
+// permanently deleted once the retention period has elapsed.
 func (e *engine) purge(ctx context.Context) {
-	const interval = time.Hour * 24
-	const retain = time.Hour * 24 * -1
+	interval := durationOr(e.purgeEvery, purge)
+	stoppedTTL := durationOr(e.stoppedTTL, defaultStoppedTTL)
+	retention := durationOr(e.retention, defaultRetention)
+	scaleTTL := durationOr(e.scaleTTL, defaultScaleRetention)
 
 	logger := log.Ctx(ctx)
 	for {
@@ -243,10 +629,36 @@ func (e *engine) purge(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-time.After(interval):
+			if e.errorTTL != 0 {
+				logger.Debug().
+					Str("ttl", e.errorTTL.String()).
+					Msg("clear errored servers from database")
+				e.reaper.Expire(ctx, time.Now().Add(-e.errorTTL).Unix())
+			}
+
 			logger.Debug().
-				Str("ttl", retain.String()).
+				Str("ttl", stoppedTTL.String()).
 				Msg("clear stopped servers from database")
-			e.planner.servers.Purge(ctx, time.Now().Add(retain).Unix())
+			e.planner.servers.Purge(ctx, time.Now().Add(-stoppedTTL).Unix())
+
+			logger.Debug().
+				Str("retention", retention.String()).
+				Msg("prune deleted servers from database")
+			e.planner.servers.Prune(ctx, time.Now().Add(-retention).Unix())
+
+			if e.planner.scales != nil {
+				logger.Debug().
+					Str("retention", scaleTTL.String()).
+					Msg("prune scale events from database")
+				e.planner.scales.Prune(ctx, time.Now().Add(-scaleTTL).Unix())
+			}
+
+			if e.planner.plans != nil {
+				logger.Debug().
+					Str("retention", scaleTTL.String()).
+					Msg("prune plans from database")
+				e.planner.plans.Prune(ctx, time.Now().Add(-scaleTTL).Unix())
+			}
 		}
 	}
 }
@@ -265,3 +677,63 @@ func (e *engine) reap(ctx context.Context) {
 		}
 	}
 }
+
+// runs the reclaim process.
+func (e *engine) reclaim(ctx context.Context) {
+	const interval = time.Minute
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+			e.reclaimer.Reclaim(ctx)
+		}
+	}
+}
+
+// runs the certificate rotation process.
+func (e *engine) rotate(ctx context.Context) {
+	const interval = time.Hour
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+			e.rotator.Rotate(ctx)
+			e.keys.Rotate(ctx)
+		}
+	}
+}
+
+// runs the automatic upgrade process. The servers are upgraded
+// when a new digest of the agent image tag is published.
+func (e *engine) autoUpgrade(ctx context.Context) {
+	if e.update == 0 || e.exec {
+		return
+	}
+	logger := log.Ctx(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.update):
+			digest, changed, err := e.updater.Check(ctx)
+			if err != nil || !changed {
+				continue
+			}
+			image := e.installer.agentImage()
+			err = e.Upgrade(ctx, image)
+			if err != nil {
+				logger.Warn().Err(err).
+					Str("image", image).
+					Msg("cannot start the automatic upgrade")
+				continue
+			}
+			logger.Info().
+				Str("image", image).
+				Str("digest", digest).
+				Msg("agent image updated, upgrade started")
+			e.updater.Accept(image, digest)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultExecURL is the download url of the exec runner
+// release archive, formatted with the server architecture.
+const defaultExecURL = "https://github.com/drone-runners/drone-runner-exec/releases/latest/download/drone_runner_exec_linux_%s.tar.gz"
+
+// stageTypeExec is the stage type of pipelines executed by
+// the exec runner.
+const stageTypeExec = "exec"
+
+// errUpgradeExec is returned when an upgrade is requested and
+// the exec runner is installed, since the exec runner is not
+// installed from an image.
+var errUpgradeExec = errors.New("Upgrade is not supported by the exec runner")
+
+// execUnit is the systemd unit of the exec runner.
+const execUnit = `[Unit]
+Description=Drone Exec Runner
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Restart=always
+RestartSec=5
+ExecStart=/usr/local/bin/drone-runner-exec daemon /etc/drone-runner-exec/config
+
+[Install]
+WantedBy=multi-user.target`
+
+// execScript downloads the exec runner, if not already
+// installed, and installs the exec runner systemd unit and
+// configuration file. The script is formatted with the
+// download url, configuration and unit, quoted for the shell.
+const execScript = `set -e
+if [ ! -x /usr/local/bin/drone-runner-exec ]; then
+  curl -fsSL %[1]s | tar -xz -C /usr/local/bin
+fi
+mkdir -p /etc/drone-runner-exec
+printf '%%s\n' %[2]s > /etc/drone-runner-exec/config
+chmod 0600 /etc/drone-runner-exec/config
+printf '%%s\n' %[3]s > /etc/systemd/system/drone-runner-exec.service
+systemctl daemon-reload
+systemctl enable drone-runner-exec.service
+systemctl restart drone-runner-exec.service`
+
+// execInstaller installs the exec runner directly on the host
+// over ssh, for pools that run builds without docker.
+type execInstaller struct {
+	ssh *sshInstaller
+	url string
+}
+
+// newExecInstaller returns a new exec runner installer. The
+// url defaults to the latest exec runner release for the
+// server architecture.
+func newExecInstaller(ssh *sshInstaller, url, arch string) *execInstaller {
+	if url == "" {
+		url = fmt.Sprintf(defaultExecURL, arch)
+	}
+	return &execInstaller{ssh: ssh, url: url}
+}
+
+// Install installs and starts the exec runner, configured
+// with the environment variables.
+func (e *execInstaller) Install(ctx context.Context, server *autoscaler.Server, envs []string) error {
+	return e.ssh.run(ctx, server, e.ssh.sudo(e.script(envs)))
+}
+
+// helper function returns the install script.
+func (e *execInstaller) script(envs []string) string {
+	return fmt.Sprintf(execScript,
+		shellQuote(e.url),
+		shellQuote(strings.Join(envs, "\n")),
+		shellQuote(execUnit),
+	)
+}
+
+// helper function installs the exec runner on the server. The
+// install is retried until successful, or until the context
+// is canceled.
+func (i *installer) installExec(ctx context.Context, instance *autoscaler.Server) error {
+	logger := log.Ctx(ctx).With().
+		Str("ip", instance.Address).
+		Str("name", instance.Name).
+		Logger()
+
+	interval := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debug().
+				Msg("connection timeout")
+
+			return i.errorUpdate(ctx, instance, ctx.Err())
+		case <-time.After(interval):
+			interval = time.Minute
+
+			logger.Debug().
+				Msg("install the exec runner")
+
+			err := i.exec(ctx, instance, i.agentEnvs(instance))
+			if err != nil {
+				logger.Debug().
+					Str("error", err.Error()).
+					Msgf("cannot install the exec runner, retry in %v", interval)
+				installLogf(ctx, "cannot install the exec runner: %s, retry in %v", err, interval)
+				continue
+			}
+
+			logger.Debug().
+				Msg("exec runner started")
+
+			instance.State = autoscaler.StateRunning
+			return i.servers.Update(ctx, instance)
+		}
+	}
+}
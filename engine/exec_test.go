@@ -0,0 +1,133 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/drone-go/drone"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestExecInstaller(t *testing.T) {
+	e := newExecInstaller(newSSHInstaller("ubuntu", "", "", "", false, nil), "", "arm64")
+	if got, want := e.url, "https://github.com/drone-runners/drone-runner-exec/releases/latest/download/drone_runner_exec_linux_arm64.tar.gz"; got != want {
+		t.Errorf("Want default download url %s, got %s", want, got)
+	}
+
+	script := e.script([]string{"DRONE_RPC_HOST=drone.company.com", "DRONE_RUNNER_NAME=agent-1"})
+	for _, want := range []string{
+		"curl -fsSL '" + e.url + "'",
+		"printf '%s\\n' 'DRONE_RPC_HOST=drone.company.com\nDRONE_RUNNER_NAME=agent-1' > /etc/drone-runner-exec/config",
+		"ExecStart=/usr/local/bin/drone-runner-exec daemon /etc/drone-runner-exec/config",
+		"systemctl restart drone-runner-exec.service",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Want %s in the install script, got\n%s", want, script)
+		}
+	}
+	if got := e.ssh.sudo(script); !strings.HasPrefix(got, "sudo sh -c '") {
+		t.Errorf("Want install script run with sudo, got %s", got)
+	}
+}
+
+func TestInstallExec(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", Capacity: 2, State: autoscaler.StateStaging}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Update(gomock.Any(), mockServer).Return(nil)
+
+	var envs []string
+	i := installer{
+		servers: store,
+		host:    "drone.company.com",
+		proto:   "https",
+		exec: func(_ context.Context, _ *autoscaler.Server, e []string) error {
+			envs = e
+			return nil
+		},
+	}
+	if err := i.install(mockctx, mockServer); err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+	for _, want := range []string{
+		"DRONE_RPC_SERVER=https://drone.company.com",
+		"DRONE_RUNNER_CAPACITY=2",
+		"DRONE_RUNNER_NAME=agent-1",
+	} {
+		if !contains(envs, want) {
+			t.Errorf("Want %s in the exec runner environment, got %v", want, envs)
+		}
+	}
+}
+
+func TestInstallExec_Timeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx, cancel := context.WithCancel(context.Background())
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateStaging}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Update(gomock.Any(), mockServer).Return(nil)
+
+	i := installer{
+		servers: store,
+		exec: func(context.Context, *autoscaler.Server, []string) error {
+			cancel()
+			return errors.New("connection refused")
+		},
+	}
+	if err := i.install(mockctx, mockServer); err == nil {
+		t.Errorf("Want error when the exec runner cannot be installed")
+	}
+	if got, want := mockServer.State, autoscaler.StateError; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+func TestPlannerMatchType(t *testing.T) {
+	tests := []struct {
+		exec  bool
+		typ   string
+		match bool
+	}{
+		{false, "", true},
+		{false, "docker", true},
+		{false, "exec", false},
+		{true, "exec", true},
+		{true, "docker", false},
+		{true, "", false},
+	}
+	for _, test := range tests {
+		p := &planner{os: "linux", arch: "amd64", exec: test.exec}
+		stage := &drone.Stage{OS: "linux", Arch: "amd64", Type: test.typ}
+		if got, want := p.match(stage), test.match; got != want {
+			t.Errorf("Want match %v for stage type %q with exec %v", want, test.typ, test.exec)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
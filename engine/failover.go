@@ -0,0 +1,91 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// failoverThreshold is the number of consecutive
+	// failures after which a region is considered unhealthy.
+	failoverThreshold = 3
+
+	// failoverCooldown is the duration after which an
+	// unhealthy region is considered healthy again.
+	failoverCooldown = time.Minute * 15
+)
+
+// failover tracks provisioning failures for an ordered list
+// of regions, so that regions that persistently fail to
+// provision servers are tried last.
+type failover struct {
+	sync.Mutex
+
+	regions  []string
+	failures map[string]int
+	failed   map[string]time.Time
+}
+
+// newFailover returns a new failover for the regions, or
+// nil if no regions are configured.
+func newFailover(regions []string) *failover {
+	if len(regions) == 0 {
+		return nil
+	}
+	return &failover{
+		regions:  regions,
+		failures: map[string]int{},
+		failed:   map[string]time.Time{},
+	}
+}
+
+// candidates returns the regions in the order they should be
+// tried. Healthy regions are returned in the configured order,
+// followed by unhealthy regions.
+func (f *failover) candidates() []string {
+	f.Lock()
+	defer f.Unlock()
+
+	var healthy, unhealthy []string
+	for _, region := range f.regions {
+		if f.unhealthy(region) {
+			unhealthy = append(unhealthy, region)
+		} else {
+			healthy = append(healthy, region)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// success resets the failure count for the region.
+func (f *failover) success(region string) {
+	if f == nil {
+		return
+	}
+	f.Lock()
+	delete(f.failures, region)
+	delete(f.failed, region)
+	f.Unlock()
+}
+
+// failure increments the failure count for the region.
+func (f *failover) failure(region string) {
+	if f == nil {
+		return
+	}
+	f.Lock()
+	f.failures[region]++
+	f.failed[region] = time.Now()
+	f.Unlock()
+}
+
+func (f *failover) unhealthy(region string) bool {
+	if f.failures[region] < failoverThreshold {
+		return false
+	}
+	return time.Since(f.failed[region]) < failoverCooldown
+}
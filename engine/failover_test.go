@@ -0,0 +1,40 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFailover(t *testing.T) {
+	f := newFailover([]string{"us-east-1", "us-west-2"})
+
+	if got, want := f.candidates(), []string{"us-east-1", "us-west-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want regions %v, got %v", want, got)
+	}
+
+	for i := 0; i < failoverThreshold; i++ {
+		f.failure("us-east-1")
+	}
+	if got, want := f.candidates(), []string{"us-west-2", "us-east-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want unhealthy region tried last, got %v", got)
+	}
+
+	f.success("us-east-1")
+	if got, want := f.candidates(), []string{"us-east-1", "us-west-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want region healthy after success, got %v", got)
+	}
+}
+
+func TestFailover_Nil(t *testing.T) {
+	f := newFailover(nil)
+	if f != nil {
+		t.Errorf("Want nil failover when no regions configured")
+	}
+	// nil failovers must be safe to use.
+	f.success("us-east-1")
+	f.failure("us-east-1")
+}
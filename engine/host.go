@@ -0,0 +1,74 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api/types"
+	"docker.io/go-docker/api/types/container"
+)
+
+// defaultSystemdImage is the image used to run scripts on the
+// host, such as installing the agent systemd unit. The image
+// must provide nsenter and sh.
+const defaultSystemdImage = "alpine:3"
+
+// helper function runs the script on the host. The script is
+// run by a privileged container that enters the host
+// namespaces, and the container is removed once the script
+// exits.
+func (i *installer) runHost(ctx context.Context, client docker.APIClient, name, script string, envs []string) error {
+	helper := i.systemdImage
+	if helper == "" {
+		helper = defaultSystemdImage
+	}
+
+	rc, err := client.ImagePull(ctx, helper, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, rc)
+	rc.Close()
+
+	res, err := client.ContainerCreate(ctx,
+		&container.Config{
+			Image: helper,
+			Cmd: []string{
+				"nsenter", "-t", "1", "-m", "-u", "-i", "-n", "-p", "--",
+				"sh", "-c", script,
+			},
+			Env: envs,
+		},
+		&container.HostConfig{
+			PidMode:    "host",
+			Privileged: true,
+		}, nil, name)
+	if err != nil {
+		return err
+	}
+	defer client.ContainerRemove(context.Background(), res.ID, types.ContainerRemoveOptions{Force: true})
+
+	err = client.ContainerStart(ctx, res.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return err
+	}
+
+	wait, errc := client.ContainerWait(ctx, res.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errc:
+		return err
+	case result := <-wait:
+		captureLogs(ctx, client, name, res.ID)
+		if result.StatusCode != 0 {
+			return fmt.Errorf("%s exited with code %d", name, result.StatusCode)
+		}
+		return nil
+	}
+}
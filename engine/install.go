@@ -26,6 +26,7 @@ import (
 
 type installer struct {
 	wg sync.WaitGroup
+	mu sync.Mutex
 
 	image            string
 	secret           string
@@ -37,6 +38,12 @@ type installer struct {
 	keepaliveTimeout time.Duration
 	runner           config.Runner
 	labels           map[string]string
+	gpu              bool
+
+	os      string
+	arch    string
+	kernel  string
+	variant string
 
 	gcEnabled  bool
 	gcDebug    bool
@@ -50,8 +57,59 @@ type installer struct {
 	watchtowerInterval int
 	watchtowerTimeout  time.Duration
 
+	systemd      bool
+	systemdImage string
+	podman       bool
+	packages     []string
+
+	caCerts      []byte
+	caRegistries []string
+
+	registryUser   string
+	registryPass   string
+	registryHelper string
+
+	httpProxy  string
+	httpsProxy string
+	noProxy    string
+
+	readyTimeout  time.Duration
+	readyPeriod   time.Duration
+	readyInterval time.Duration
+
+	registerCheck   bool
+	registerTimeout time.Duration
+	registerPattern string
+
+	// timeouts of the install phases, and the interval at
+	// which a failed phase is retried.
+	instanceTimeout time.Duration
+	dockerTimeout   time.Duration
+	imageTimeout    time.Duration
+	agentTimeout    time.Duration
+	retryInterval   time.Duration
+
+	// prepull is a list of images pulled once the agent is
+	// started, so that the first builds on the server do not
+	// wait for the images to download.
+	prepull []string
+
+	// resource limits of the agent container.
+	cpus      float64
+	memory    int64
+	pidsLimit int64
+
 	servers autoscaler.ServerStore
 	client  clientFunc
+
+	// provision is an optional function used to install the
+	// docker daemon before connecting, for servers that are
+	// provisioned over ssh.
+	provision func(context.Context, *autoscaler.Server) error
+
+	// exec is an optional function used to install the exec
+	// runner on the host, instead of the agent container.
+	exec func(context.Context, *autoscaler.Server, []string) error
 }
 
 func (i *installer) Install(ctx context.Context) error {
@@ -84,6 +142,23 @@ func (i *installer) Install(ctx context.Context) error {
 }
 
 func (i *installer) install(ctx context.Context, instance *autoscaler.Server) error {
+	// the install output is captured to the server logs. The
+	// logs of a previous attempt are kept if the install is
+	// resumed. The server list excludes the logs, so the logs
+	// of the previous attempt are loaded from the store.
+	if instance.Phase == "" {
+		instance.Logs = nil
+	} else if instance.Logs == nil {
+		if server, err := i.servers.Find(ctx, instance.Name); err == nil {
+			instance.Logs = server.Logs
+		}
+	}
+	ctx = withInstallLog(ctx, instance)
+
+	if i.exec != nil {
+		return i.installExec(ctx, instance)
+	}
+
 	logger := log.Ctx(ctx).With().
 		Str("ip", instance.Address).
 		Str("name", instance.Name).
@@ -96,58 +171,274 @@ func (i *installer) install(ctx context.Context, instance *autoscaler.Server) er
 		return i.errorUpdate(ctx, instance, err)
 	}
 
+	image := i.agentImage()
+	err = i.runPhases(logger.WithContext(ctx), instance, []phase{
+		{
+			name:     phaseInstance,
+			timeout:  durationOr(i.instanceTimeout, defaultInstanceTimeout),
+			interval: durationOr(i.retryInterval, time.Minute),
+			run: func(ctx context.Context) error {
+				return i.setupInstance(ctx, instance)
+			},
+		},
+		{
+			name:     phaseDocker,
+			timeout:  durationOr(i.dockerTimeout, defaultDockerTimeout),
+			interval: durationOr(i.retryInterval, time.Minute),
+			run: func(ctx context.Context) error {
+				return i.checkDocker(ctx, client)
+			},
+		},
+		{
+			name:     phaseImage,
+			timeout:  durationOr(i.imageTimeout, defaultImageTimeout),
+			interval: durationOr(i.retryInterval, time.Second*30),
+			run: func(ctx context.Context) error {
+				return i.setupImage(ctx, client, image)
+			},
+		},
+		{
+			name:     phaseAgent,
+			timeout:  durationOr(i.agentTimeout, defaultAgentTimeout),
+			interval: durationOr(i.retryInterval, time.Second*30),
+			run: func(ctx context.Context) error {
+				return i.setupHost(ctx, client, instance, image)
+			},
+		},
+	})
+	if err != nil {
+		return i.errorUpdate(ctx, instance, err)
+	}
+
 	logger.Debug().
-		Str("name", instance.Name).
-		Msg("check docker connectivity")
-
-	interval := time.Duration(0)
-poller:
-	for {
-		select {
-		case <-ctx.Done():
-			logger.Debug().
-				Str("name", instance.Name).
-				Msg("connection timeout")
-
-			return i.errorUpdate(ctx, instance, ctx.Err())
-		case <-time.After(interval):
-			interval = time.Minute
-
-			logger.Debug().
-				Str("name", instance.Name).
-				Msg("connecting to docker")
-
-			_, err := client.ContainerList(ctx, types.ContainerListOptions{})
-			if err != nil {
-				logger.Debug().
-					Str("error", err.Error()).
-					Str("name", instance.Name).
-					Msgf("cannot connect, retry in %v", interval)
-				continue
-			}
-			break poller
+		Str("image", image).
+		Msg("verify agent container")
+
+	err = i.waitAgent(ctx, client)
+	if err != nil {
+		logger.Error().Err(err).
+			Str("image", image).
+			Msg("agent container is not ready")
+		return i.errorUpdate(ctx, instance, err)
+	}
+
+	// the server capacity is not counted until the agent
+	// connects to the Drone server, so that an agent that
+	// cannot connect is not reported as running.
+	err = i.waitRegistered(ctx, client, image)
+	if err != nil {
+		logger.Error().Err(err).
+			Str("image", image).
+			Msg("agent is not connected to the drone server")
+		return i.errorUpdate(ctx, instance, err)
+	}
+
+	installLogf(ctx, "agent connected, server running")
+
+	instance.State = autoscaler.StateRunning
+	instance.Phase = ""
+	return i.servers.Update(ctx, instance)
+}
+
+// helper function provisions the server over ssh, for servers
+// that are provisioned over ssh.
+func (i *installer) setupInstance(ctx context.Context, instance *autoscaler.Server) error {
+	if i.provision == nil {
+		return nil
+	}
+	log.Ctx(ctx).Debug().
+		Msg("provisioning server")
+	return i.provision(ctx, instance)
+}
+
+// helper function returns an error if the docker daemon is
+// not reachable, or if the container runtime is not ready.
+func (i *installer) checkDocker(ctx context.Context, client docker.APIClient) error {
+	log.Ctx(ctx).Debug().
+		Msg("connecting to docker")
+
+	_, err := client.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return err
+	}
+
+	// the nvidia container toolkit is installed after
+	// the docker daemon, so gpu servers must wait until
+	// the nvidia runtime is registered with the daemon.
+	return i.checkRuntime(ctx, client)
+}
+
+// helper function prepares the host, and pulls the agent
+// image.
+func (i *installer) setupImage(ctx context.Context, client docker.APIClient, image string) error {
+	logger := log.Ctx(ctx)
+
+	if len(i.caCerts) != 0 && i.os != "windows" {
+		logger.Debug().
+			Msg("install certificate authorities")
+
+		err := i.setupCACerts(ctx, client)
+		if err != nil {
+			logger.Error().Err(err).
+				Msg("cannot install certificate authorities")
+			return err
 		}
 	}
 
+	if len(i.packages) != 0 && i.os != "windows" {
+		logger.Debug().
+			Strs("packages", i.packages).
+			Msg("install packages")
+
+		err := i.setupPackages(ctx, client)
+		if err != nil {
+			logger.Error().Err(err).
+				Strs("packages", i.packages).
+				Msg("cannot install packages")
+			return err
+		}
+	}
+
+	return i.pullAgent(ctx, client, image)
+}
+
+// helper function starts the agent, and the containers
+// installed alongside the agent.
+func (i *installer) setupHost(ctx context.Context, client docker.APIClient, instance *autoscaler.Server, image string) error {
+	logger := log.Ctx(ctx)
+
+	// the containers of a previous attempt are removed, so
+	// that the phase can be repeated.
+	i.removeContainers(ctx, client)
+
+	err := i.runAgent(ctx, client, instance, image)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug().
+		Str("image", image).
+		Msg("agent container started")
+
+	if i.os == "windows" && (i.gcEnabled || i.watchtowerEnabled) {
+		logger.Warn().
+			Msg("garbage collector and watchtower not supported on windows")
+	}
+
+	if i.gcEnabled && i.os != "windows" {
+		logger.Debug().
+			Str("image", image).
+			Msg("setup the garbage collector")
+		err = i.setupGarbageCollectoer(ctx, client)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("image", image).
+				Msg("cannot setup the garbage collector")
+		}
+	}
+
+	if i.watchtowerEnabled && i.os != "windows" {
+		logger.Debug().
+			Str("image", image).
+			Msg("setup watchtower")
+		err = i.setupWatchtower(ctx, client)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("image", image).
+				Msg("cannot setup watchtwoer")
+		}
+	}
+
+	if len(i.prepull) != 0 {
+		logger.Debug().
+			Strs("images", i.prepull).
+			Msg("pre-pull docker images")
+		i.pullImages(ctx, client)
+	}
+	return nil
+}
+
+// helper function pulls the agent image, and creates and
+// starts the agent container, or installs the agent systemd
+// unit.
+func (i *installer) startAgent(ctx context.Context, client docker.APIClient, instance *autoscaler.Server, image string) error {
+	err := i.pullAgent(ctx, client, image)
+	if err != nil {
+		return err
+	}
+	return i.runAgent(ctx, client, instance, image)
+}
+
+// helper function pulls the agent image.
+func (i *installer) pullAgent(ctx context.Context, client docker.APIClient, image string) error {
+	logger := log.Ctx(ctx)
+
 	logger.Debug().
-		Str("image", i.image).
+		Str("image", image).
 		Msg("pull docker image")
 
-	rc, err := client.ImagePull(ctx, i.image, types.ImagePullOptions{})
+	auth, err := i.registryAuth(image)
+	if err != nil {
+		logger.Error().Err(err).
+			Str("image", image).
+			Msg("cannot get registry credentials")
+		return err
+	}
+
+	err = i.pull(ctx, client, image, auth)
 	if err != nil {
 		logger.Error().Err(err).
-			Str("image", i.image).
+			Str("image", image).
 			Msg("cannot pull docker image")
-		return i.errorUpdate(ctx, instance, err)
 	}
-	io.Copy(ioutil.Discard, rc)
-	rc.Close()
+	return err
+}
 
-	logger.Debug().
-		Str("image", i.image).
-		Msg("create agent container")
+// helper function creates and starts the agent container, or
+// installs the agent systemd unit.
+func (i *installer) runAgent(ctx context.Context, client docker.APIClient, instance *autoscaler.Server, image string) error {
+	logger := log.Ctx(ctx).With().
+		Str("ip", instance.Address).
+		Str("name", instance.Name).
+		Logger()
+
+	envs := i.agentEnvs(instance)
+	volumes := append(i.volumes, i.dockerSocket())
+
+	if i.systemd && i.os != "windows" {
+		logger.Debug().
+			Str("image", image).
+			Msg("install the agent systemd unit")
+
+		err := i.setupSystemd(ctx, client, instance, image, envs, volumes)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("image", image).
+				Msg("cannot install the agent systemd unit")
+			return err
+		}
+	} else {
+		logger.Debug().
+			Str("image", image).
+			Msg("create agent container")
+
+		err := i.setupAgent(ctx, client, instance, image, envs, volumes)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("image", image).
+				Msg("cannot start the agent container")
+			return err
+		}
+	}
+	return nil
+}
 
-	envs := append(i.envs,
+// helper function returns the environment variables of the
+// agent.
+func (i *installer) agentEnvs(instance *autoscaler.Server) []string {
+	envs := []string{
 		fmt.Sprintf("DRONE_RPC_HOST=%s", i.host),
 		fmt.Sprintf("DRONE_RPC_PROTO=%s", i.proto),
 		fmt.Sprintf("DRONE_RPC_SERVER=%s://%s", i.proto, i.host),
@@ -157,7 +448,10 @@ poller:
 		fmt.Sprintf("DRONE_RUNNER_VOLUMES=%s", i.runner.Volumes),
 		fmt.Sprintf("DRONE_RUNNER_DEVICES=%s", i.runner.Devices),
 		fmt.Sprintf("DRONE_RUNNER_PRIVILEGED_IMAGES=%s", i.runner.Privileged),
-	)
+	}
+
+	envs = append(envs, i.platform()...)
+	envs = append(envs, i.proxy()...)
 
 	if len(i.labels) > 0 {
 		var stringLabels []string
@@ -171,89 +465,57 @@ poller:
 		)
 	}
 
-	volumes := append(i.volumes,
-		"/var/run/docker.sock:/var/run/docker.sock",
-	)
+	if i.gpu {
+		envs = append(envs,
+			"NVIDIA_VISIBLE_DEVICES=all",
+			"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+		)
+	}
+
+	// user-defined variables are appended last so that they
+	// take precedence over the default agent configuration.
+	return append(envs, i.envs...)
+}
+
+// helper function creates and starts the agent container.
+func (i *installer) setupAgent(ctx context.Context, client docker.APIClient, instance *autoscaler.Server, image string, envs, volumes []string) error {
+	labels := agentLabels(instance)
+	labels["com.centurylinklabs.watchtower.enable"] = "true"
+	labels["com.centurylinklabs.watchtower.stop-signal"] = "SIGHUP"
 
 	res, err := client.ContainerCreate(ctx,
 		&container.Config{
-			Image:        i.image,
+			Image:        image,
 			AttachStdout: true,
 			AttachStderr: true,
 			Env:          envs,
 			Volumes:      toVol(volumes),
-			Labels: map[string]string{
-				"com.centurylinklabs.watchtower.enable":      "true",
-				"com.centurylinklabs.watchtower.stop-signal": "SIGHUP",
-				"io.drone.agent.name":                        instance.Name,
-				"io.drone.agent.zone":                        instance.Region,
-				"io.drone.agent.size":                        instance.Size,
-				"io.drone.agent.instance":                    instance.ID,
-				"io.drone.agent.capacity":                    fmt.Sprint(instance.Capacity),
-			},
+			Labels:       labels,
 		},
 		&container.HostConfig{
-			Binds: volumes,
+			Binds:     volumes,
+			Runtime:   i.runtime(),
+			Resources: i.resources(),
 			RestartPolicy: container.RestartPolicy{
 				Name: "always",
 			},
 		}, nil, "agent")
-
 	if err != nil {
-		logger.Error().Err(err).
-			Str("image", i.image).
-			Msg("cannot create agent container")
-		return i.errorUpdate(ctx, instance, err)
+		return err
 	}
+	return client.ContainerStart(ctx, res.ID, types.ContainerStartOptions{})
+}
 
-	logger.Debug().
-		Str("image", i.image).
-		Msg("start the agent container")
-
-	err = client.ContainerStart(ctx, res.ID, types.ContainerStartOptions{})
+func (i *installer) setupWatchtower(ctx context.Context, client docker.APIClient) error {
+	auth, err := i.imageAuth(i.watchtowerImage)
 	if err != nil {
-		logger.Debug().
-			Str("image", i.image).
-			Msg("cannot start the agent container")
-		return i.errorUpdate(ctx, instance, err)
-	}
-
-	logger.Debug().
-		Str("image", i.image).
-		Msg("agent container started")
-
-	if i.gcEnabled {
-		logger.Debug().
-			Str("image", i.image).
-			Msg("setup the garbage collector")
-		err = i.setupGarbageCollectoer(ctx, client)
-		if err != nil {
-			logger.Warn().
-				Err(err).
-				Str("image", i.image).
-				Msg("cannot setup the garbage collector")
-		}
+		return err
 	}
-
-	if i.watchtowerEnabled {
-		logger.Debug().
-			Str("image", i.image).
-			Msg("setup watchtower")
-		err = i.setupWatchtower(ctx, client)
-		if err != nil {
-			logger.Warn().
-				Err(err).
-				Str("image", i.image).
-				Msg("cannot setup watchtwoer")
-		}
+	err = i.pull(ctx, client, i.watchtowerImage, auth)
+	if err != nil {
+		return err
 	}
-
-	instance.State = autoscaler.StateRunning
-	return i.servers.Update(ctx, instance)
-}
-
-func (i *installer) setupWatchtower(ctx context.Context, client docker.APIClient) error {
-	vols := []string{"/var/run/docker.sock:/var/run/docker.sock"}
+	vols := []string{i.dockerSocket()}
 	res, err := client.ContainerCreate(ctx,
 		&container.Config{
 			Image:        i.watchtowerImage,
@@ -279,15 +541,27 @@ func (i *installer) setupWatchtower(ctx context.Context, client docker.APIClient
 }
 
 func (i *installer) setupGarbageCollectoer(ctx context.Context, client docker.APIClient) error {
-	vols := []string{"/var/run/docker.sock:/var/run/docker.sock"}
+	auth, err := i.imageAuth(i.gcImage)
+	if err != nil {
+		return err
+	}
+	err = i.pull(ctx, client, i.gcImage, auth)
+	if err != nil {
+		return err
+	}
+	vols := []string{i.dockerSocket()}
 	envs := []string{
 		fmt.Sprintf("GC_CACHE=%s", i.gcCache),
 		fmt.Sprintf("GC_DEBUG=%v", i.gcDebug),
 		fmt.Sprintf("GC_INTERVAL=%s", i.gcInterval),
 	}
-	if len(i.gcIgnore) > 0 {
+	// the pre-pulled images are excluded from garbage
+	// collection, otherwise the images are removed before
+	// the first build.
+	ignore := append(append([]string{}, i.gcIgnore...), i.prepull...)
+	if len(ignore) > 0 {
 		envs = append(envs,
-			fmt.Sprintf("GC_IGNORE=%s", strings.Join(i.gcIgnore, ",")),
+			fmt.Sprintf("GC_IGNORE=%s", strings.Join(ignore, ",")),
 		)
 	}
 	res, err := client.ContainerCreate(ctx,
@@ -313,8 +587,164 @@ func (i *installer) setupGarbageCollectoer(ctx context.Context, client docker.AP
 	return client.ContainerStart(ctx, res.ID, types.ContainerStartOptions{})
 }
 
+// helper function pulls the pre-pull images. An image that
+// cannot be pulled is logged and skipped, since the image is
+// pulled again by the first build that requires it.
+func (i *installer) pullImages(ctx context.Context, client docker.APIClient) {
+	logger := log.Ctx(ctx)
+	for _, image := range i.prepull {
+		auth, err := i.imageAuth(image)
+		if err == nil {
+			err = i.pull(ctx, client, image, auth)
+		}
+		if err != nil {
+			logger.Warn().Err(err).
+				Str("image", image).
+				Msg("cannot pre-pull docker image")
+		}
+	}
+}
+
+// helper function pulls the image with the encoded registry
+// credentials. The docker api does not pull missing images
+// when a container is created, so images are pulled before
+// the container is created.
+func (i *installer) pull(ctx context.Context, client docker.APIClient, image, auth string) error {
+	rc, err := client.ImagePull(ctx, image, types.ImagePullOptions{
+		RegistryAuth: auth,
+	})
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, rc)
+	return rc.Close()
+}
+
+// helper function returns the proxy environment variables of
+// the agent container. The variables are provided in upper
+// and lower case, since tools disagree on the convention.
+func (i *installer) proxy() []string {
+	var envs []string
+	for _, env := range [][2]string{
+		{"HTTP_PROXY", i.httpProxy},
+		{"HTTPS_PROXY", i.httpsProxy},
+		{"NO_PROXY", i.noProxy},
+	} {
+		name, value := env[0], env[1]
+		if value == "" {
+			continue
+		}
+		envs = append(envs,
+			name+"="+value,
+			strings.ToLower(name)+"="+value,
+		)
+	}
+	return envs
+}
+
+// helper function returns the docker socket volume mounted
+// into the agent container.
+func (i *installer) dockerSocket() string {
+	if i.os == "windows" {
+		return `\\.\pipe\docker_engine:\\.\pipe\docker_engine`
+	}
+	if i.podman {
+		return podmanUnixSocket + ":/var/run/docker.sock"
+	}
+	return "/var/run/docker.sock:/var/run/docker.sock"
+}
+
+// helper function returns the labels of the agent container.
+func agentLabels(instance *autoscaler.Server) map[string]string {
+	return map[string]string{
+		"io.drone.agent.name":     instance.Name,
+		"io.drone.agent.zone":     instance.Region,
+		"io.drone.agent.size":     instance.Size,
+		"io.drone.agent.instance": instance.ID,
+		"io.drone.agent.capacity": fmt.Sprint(instance.Capacity),
+	}
+}
+
+// helper function returns the platform environment variables
+// passed to the agent, so that the agent reports the same
+// platform used by the planner to match pending stages.
+func (i *installer) platform() []string {
+	var envs []string
+	if i.os != "" {
+		envs = append(envs, fmt.Sprintf("DRONE_PLATFORM_OS=%s", i.os))
+	}
+	if i.arch != "" {
+		envs = append(envs, fmt.Sprintf("DRONE_PLATFORM_ARCH=%s", i.arch))
+	}
+	if i.kernel != "" {
+		envs = append(envs, fmt.Sprintf("DRONE_PLATFORM_KERNEL=%s", i.kernel))
+	}
+	if i.variant != "" {
+		envs = append(envs, fmt.Sprintf("DRONE_PLATFORM_VARIANT=%s", i.variant))
+	}
+	return envs
+}
+
+// helper function returns the container runtime used to
+// run the agent. The nvidia runtime exposes the host gpus
+// to the agent container.
+func (i *installer) runtime() string {
+	if i.gpu {
+		return "nvidia"
+	}
+	return ""
+}
+
+// helper function returns the resource limits of the agent
+// container, which prevent the agent from starving the build
+// containers that share the host.
+func (i *installer) resources() container.Resources {
+	res := container.Resources{
+		NanoCPUs: int64(i.cpus * 1e9),
+		Memory:   i.memory,
+	}
+	// pids limits are not supported by windows containers.
+	if i.os != "windows" {
+		res.PidsLimit = i.pidsLimit
+	}
+	return res
+}
+
+// helper function returns an error if the container runtime
+// used by the agent is not registered with the docker daemon.
+func (i *installer) checkRuntime(ctx context.Context, client docker.APIClient) error {
+	runtime := i.runtime()
+	if runtime == "" {
+		return nil
+	}
+	info, err := client.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := info.Runtimes[runtime]; !ok {
+		return fmt.Errorf("runtime %s is not installed", runtime)
+	}
+	return nil
+}
+
+// helper function returns the agent image.
+func (i *installer) agentImage() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.image
+}
+
+// helper function sets the agent image used to install new
+// servers.
+func (i *installer) setAgentImage(image string) {
+	i.mu.Lock()
+	i.image = image
+	i.mu.Unlock()
+}
+
 func (i *installer) errorUpdate(ctx context.Context, server *autoscaler.Server, err error) error {
 	if err != nil {
+		installLogf(ctx, "install failed: %s", err)
 		server.State = autoscaler.StateError
 		server.Error = err.Error()
 		i.servers.Update(ctx, server)
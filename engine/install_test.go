@@ -5,8 +5,20 @@
 package engine
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/drone/autoscaler/mocks"
+
+	"docker.io/go-docker/api/types"
+	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/network"
+	"github.com/golang/mock/gomock"
 )
 
 func TestSplitVolumeParts(t *testing.T) {
@@ -75,4 +87,124 @@ func TestSplitVolumeParts(t *testing.T) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestInstallerRuntime(t *testing.T) {
+	i := installer{}
+	if got, want := i.runtime(), ""; got != want {
+		t.Errorf("Want default runtime, got %q", got)
+	}
+	i.gpu = true
+	if got, want := i.runtime(), "nvidia"; got != want {
+		t.Errorf("Want runtime %q, got %q", want, got)
+	}
+}
+
+func TestInstallerProxy(t *testing.T) {
+	i := installer{
+		httpsProxy: "http://proxy.company.com:3128",
+		noProxy:    "169.254.169.254,.internal",
+	}
+	want := []string{
+		"HTTPS_PROXY=http://proxy.company.com:3128",
+		"https_proxy=http://proxy.company.com:3128",
+		"NO_PROXY=169.254.169.254,.internal",
+		"no_proxy=169.254.169.254,.internal",
+	}
+	if got := i.proxy(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want proxy environment %v, got %v", want, got)
+	}
+}
+
+func TestInstallerCheckRuntime(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().Info(mockctx).Return(types.Info{Runtimes: map[string]types.Runtime{"runc": {}}}, nil)
+	client.EXPECT().Info(mockctx).Return(types.Info{Runtimes: map[string]types.Runtime{"runc": {}, "nvidia": {}}}, nil)
+
+	i := installer{}
+	if err := i.checkRuntime(mockctx, client); err != nil {
+		t.Errorf("Want default runtime skipped, got %s", err)
+	}
+	i.gpu = true
+	if err := i.checkRuntime(mockctx, client); err == nil {
+		t.Errorf("Want error when the nvidia runtime is not installed")
+	}
+	if err := i.checkRuntime(mockctx, client); err != nil {
+		t.Errorf("Want nvidia runtime installed, got %s", err)
+	}
+}
+
+func TestInstallerPullImages(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ImagePull(mockctx, "drone/git", gomock.Any()).Return(nil, errors.New("manifest unknown"))
+	client.EXPECT().ImagePull(mockctx, "golang:1.12", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil)
+
+	i := installer{prepull: []string{"drone/git", "golang:1.12"}}
+	i.pullImages(mockctx, client)
+}
+
+func TestInstallerResources(t *testing.T) {
+	i := installer{cpus: 0.5, memory: 536870912, pidsLimit: 1024}
+	res := i.resources()
+	if got, want := res.NanoCPUs, int64(500000000); got != want {
+		t.Errorf("Want nano cpus %d, got %d", want, got)
+	}
+	if got, want := res.Memory, int64(536870912); got != want {
+		t.Errorf("Want memory limit %d, got %d", want, got)
+	}
+	if got, want := res.PidsLimit, int64(1024); got != want {
+		t.Errorf("Want pids limit %d, got %d", want, got)
+	}
+
+	i.os = "windows"
+	if got := i.resources().PidsLimit; got != 0 {
+		t.Errorf("Want pids limit unset on windows, got %d", got)
+	}
+}
+
+func TestSetupGarbageCollector(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+
+	var config *container.Config
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ImagePull(mockctx, "drone/gc", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil)
+	client.EXPECT().ContainerCreate(mockctx, gomock.Any(), gomock.Any(), gomock.Any(), "drone-gc").Do(
+		func(_ context.Context, c *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ string) {
+			config = c
+		},
+	).Return(container.ContainerCreateCreatedBody{ID: "3f2a"}, nil)
+	client.EXPECT().ContainerStart(mockctx, "3f2a", gomock.Any()).Return(nil)
+
+	i := installer{
+		image:      "drone/agent:1",
+		gcImage:    "drone/gc",
+		gcCache:    "10gb",
+		gcInterval: time.Minute * 30,
+		gcIgnore:   []string{"drone/agent"},
+		prepull:    []string{"drone/git"},
+	}
+	if err := i.setupGarbageCollectoer(mockctx, client); err != nil {
+		t.Error(err)
+		return
+	}
+	want := []string{
+		"GC_CACHE=10gb",
+		"GC_DEBUG=false",
+		"GC_INTERVAL=30m0s",
+		"GC_IGNORE=drone/agent,drone/git",
+	}
+	if config == nil || !reflect.DeepEqual(config.Env, want) {
+		t.Errorf("Want garbage collector environment %v, got %v", want, config)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// leaseName defines the name of the lease held by the leader.
+const leaseName = "engine"
+
+// defaultLeaseTTL defines the default duration of the leader
+// lease. The lease is renewed at a third of the duration.
+const defaultLeaseTTL = 30 * time.Second
+
+// errNotLeader is returned when an operation is requested from
+// a replica that is not the leader.
+var errNotLeader = errors.New("Replica is not the leader")
+
+// NewLeader returns an Engine that runs the engine only while
+// the replica holds the leader lease, so that one of multiple
+// replicas runs the scaling loops, and the other replicas
+// stand by until the lease expires.
+func NewLeader(engine autoscaler.Engine, leases autoscaler.LeaseStore, id string, ttl time.Duration) autoscaler.Engine {
+	return &leader{
+		Engine: engine,
+		leases: leases,
+		id:     id,
+		ttl:    durationOr(ttl, defaultLeaseTTL),
+	}
+}
+
+type leader struct {
+	autoscaler.Engine
+
+	leases autoscaler.LeaseStore
+	id     string
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	leading bool
+}
+
+// Start acquires and renews the leader lease, and starts the
+// engine when the lease is acquired. The engine is stopped if
+// the lease is lost, or is not renewed within two thirds of the
+// lease duration, before the lease expires.
+func (l *leader) Start(ctx context.Context) {
+	logger := log.Ctx(ctx).With().
+		Str("replica", l.id).
+		Logger()
+
+	var (
+		cancel  context.CancelFunc
+		done    chan struct{}
+		renewed time.Time
+	)
+
+	start := func() {
+		var engineCtx context.Context
+		engineCtx, cancel = context.WithCancel(ctx)
+		done = make(chan struct{})
+		go func() {
+			l.Engine.Start(engineCtx)
+			close(done)
+		}()
+		l.setLeading(true)
+		logger.Info().Msg("elected leader")
+	}
+
+	stop := func() {
+		l.setLeading(false)
+		cancel()
+		<-done
+		cancel = nil
+		logger.Info().Msg("stopped leading")
+	}
+
+	for {
+		// the lease attempt is bounded, so that a replica
+		// blocked by a database outage stops leading before
+		// the lease expires.
+		attempt := time.Now()
+		acquireCtx, cancelAcquire := context.WithTimeout(ctx, l.ttl/6)
+		held, err := l.leases.Acquire(acquireCtx, leaseName, l.id, attempt.Add(l.ttl).Unix())
+		cancelAcquire()
+		switch {
+		case err != nil:
+			logger.Warn().Err(err).
+				Msg("cannot acquire leader lease")
+			// the replica continues to lead, to tolerate a
+			// brief database outage, but stops leading with
+			// a margin before the lease expires and another
+			// replica can acquire the lease.
+			if cancel != nil && time.Since(renewed) >= l.ttl*2/3 {
+				stop()
+			}
+		case held:
+			renewed = attempt
+			if cancel == nil {
+				start()
+			}
+		case cancel != nil:
+			logger.Warn().Msg("leader lease lost")
+			stop()
+		}
+
+		// the lease is renewed at a third of the duration, and
+		// the last attempt is made before the leading margin.
+		wait := l.ttl / 3
+		if cancel != nil {
+			if until := time.Until(renewed.Add(l.ttl * 2 / 3)); until < wait {
+				wait = until
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if cancel != nil {
+				stop()
+				l.leases.Release(context.Background(), leaseName, l.id)
+			}
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Leading returns true if the replica holds the leader lease.
+func (l *leader) Leading() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leading
+}
+
+func (l *leader) Pause() error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Pause()
+}
+
+func (l *leader) Resume() error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Resume()
+}
+
+func (l *leader) PausePool(pool string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.PausePool(pool)
+}
+
+func (l *leader) ResumePool(pool string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.ResumePool(pool)
+}
+
+func (l *leader) Upgrade(ctx context.Context, image string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Upgrade(ctx, image)
+}
+
+func (l *leader) Rotate(ctx context.Context, name string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Rotate(ctx, name)
+}
+
+func (l *leader) Repair(ctx context.Context, name string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Repair(ctx, name)
+}
+
+func (l *leader) Replace(ctx context.Context, name string) (*autoscaler.Server, error) {
+	if !l.Leading() {
+		return nil, errNotLeader
+	}
+	return l.Engine.Replace(ctx, name)
+}
+
+func (l *leader) Destroy(ctx context.Context, name string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Destroy(ctx, name)
+}
+
+func (l *leader) Cordon(ctx context.Context, name string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Cordon(ctx, name)
+}
+
+func (l *leader) Uncordon(ctx context.Context, name string) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Uncordon(ctx, name)
+}
+
+func (l *leader) Scale(ctx context.Context, pool string, n int) error {
+	if !l.Leading() {
+		return errNotLeader
+	}
+	return l.Engine.Scale(ctx, pool, n)
+}
+
+// LastPlan returns the time the planner of the engine last
+// completed a planning cycle, or the zero time if the replica
+// is not the leader, since the planner only runs on the
+// leader.
+func (l *leader) LastPlan() time.Time {
+	if !l.Leading() {
+		return time.Time{}
+	}
+	return l.Engine.LastPlan()
+}
+
+// Preview returns the plan of the engine. The plan requires
+// the leader, since the target server count is held in memory
+// by the engine of the leader, and is restored from the pool
+// state store when the replica is elected leader.
+func (l *leader) Preview(ctx context.Context) ([]*autoscaler.Plan, error) {
+	if !l.Leading() {
+		return nil, errNotLeader
+	}
+	return l.Engine.Preview(ctx)
+}
+
+func (l *leader) setLeading(leading bool) {
+	l.mu.Lock()
+	l.leading = leading
+	l.mu.Unlock()
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestLeader(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leases := mocks.NewMockLeaseStore(controller)
+	leases.EXPECT().Acquire(gomock.Any(), leaseName, "replica1", gomock.Any()).Return(true, nil).AnyTimes()
+	leases.EXPECT().Release(gomock.Any(), leaseName, "replica1").Return(nil)
+
+	started := make(chan struct{})
+	engine := mocks.NewMockEngine(controller)
+	engine.EXPECT().Start(gomock.Any()).Do(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+	})
+
+	l := NewLeader(engine, leases, "replica1", time.Millisecond*30).(*leader)
+	done := make(chan struct{})
+	go func() {
+		l.Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Errorf("Want engine started when the lease is acquired")
+		return
+	}
+	if !l.Leading() {
+		t.Errorf("Want replica leading when the lease is acquired")
+	}
+
+	cancel()
+	<-done
+	if l.Leading() {
+		t.Errorf("Want replica not leading once stopped")
+	}
+}
+
+func TestLeader_Standby(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	leases := mocks.NewMockLeaseStore(controller)
+	leases.EXPECT().Acquire(gomock.Any(), leaseName, "replica2", gomock.Any()).Return(false, nil).MinTimes(1)
+
+	// the engine is not started, and operations are rejected,
+	// while another replica holds the lease.
+	engine := mocks.NewMockEngine(controller)
+
+	l := NewLeader(engine, leases, "replica2", time.Millisecond*30)
+	l.Start(ctx)
+
+	if err := l.Upgrade(ctx, "drone/drone-runner-docker:1"); err != errNotLeader {
+		t.Errorf("Want not leader error, got %v", err)
+	}
+	if err := l.Repair(ctx, "agent-1"); err != errNotLeader {
+		t.Errorf("Want not leader error, got %v", err)
+	}
+	if err := l.Pause(); err != errNotLeader {
+		t.Errorf("Want not leader error, got %v", err)
+	}
+	if err := l.Resume(); err != errNotLeader {
+		t.Errorf("Want not leader error, got %v", err)
+	}
+	if err := l.PausePool("default"); err != errNotLeader {
+		t.Errorf("Want not leader error, got %v", err)
+	}
+	if err := l.ResumePool("default"); err != errNotLeader {
+		t.Errorf("Want not leader error, got %v", err)
+	}
+}
+
+func TestLeader_RenewFailed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the lease is acquired once, and every renewal blocks
+	// until the attempt times out.
+	var acquired time.Time
+	leases := mocks.NewMockLeaseStore(controller)
+	gomock.InOrder(
+		leases.EXPECT().Acquire(gomock.Any(), leaseName, "replica1", gomock.Any()).Do(
+			func(context.Context, string, string, int64) {
+				acquired = time.Now()
+			}).Return(true, nil),
+		leases.EXPECT().Acquire(gomock.Any(), leaseName, "replica1", gomock.Any()).Do(
+			func(ctx context.Context, _, _ string, _ int64) {
+				<-ctx.Done()
+			}).Return(false, context.DeadlineExceeded).AnyTimes(),
+	)
+
+	stopped := make(chan time.Time)
+	engine := mocks.NewMockEngine(controller)
+	engine.EXPECT().Start(gomock.Any()).Do(func(ctx context.Context) {
+		<-ctx.Done()
+		stopped <- time.Now()
+	})
+
+	l := NewLeader(engine, leases, "replica1", time.Millisecond*300).(*leader)
+	go l.Start(ctx)
+
+	select {
+	case at := <-stopped:
+		if deadline := acquired.Add(time.Millisecond * 300); !at.Before(deadline) {
+			t.Errorf("Want engine stopped before the lease expires at %v, stopped at %v", deadline, at)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Want engine stopped when the lease is not renewed")
+		return
+	}
+	if l.Leading() {
+		t.Errorf("Want replica not leading once the renewal failed")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api/types"
+)
+
+// maxLogSize is the maximum size of the install logs stored
+// with the server. The oldest output is discarded once the
+// logs exceed the maximum size.
+const maxLogSize = 32 << 10
+
+type logKey struct{}
+
+// an installLog captures the output of the install steps to
+// the server logs, which are stored with the server.
+type installLog struct {
+	mu     sync.Mutex
+	server *autoscaler.Server
+}
+
+// Write appends the output to the server logs.
+func (l *installLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	logs := append(l.server.Logs, p...)
+	if len(logs) > maxLogSize {
+		logs = append([]byte(nil), logs[len(logs)-maxLogSize:]...)
+	}
+	l.server.Logs = logs
+	l.mu.Unlock()
+	return len(p), nil
+}
+
+// helper function returns a context that captures the install
+// output to the server logs.
+func withInstallLog(ctx context.Context, server *autoscaler.Server) context.Context {
+	return context.WithValue(ctx, logKey{}, &installLog{server: server})
+}
+
+// helper function returns the writer that captures the install
+// output, or a writer that discards the output if the install
+// output is not captured.
+func installWriter(ctx context.Context) io.Writer {
+	if l, ok := ctx.Value(logKey{}).(*installLog); ok {
+		return l
+	}
+	return ioutil.Discard
+}
+
+// helper function writes a timestamped line to the install
+// logs.
+func installLogf(ctx context.Context, format string, args ...interface{}) {
+	if l, ok := ctx.Value(logKey{}).(*installLog); ok {
+		fmt.Fprintf(l, "%s %s\n", time.Now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	}
+}
+
+// helper function copies the output of the container to the
+// install logs, if the install output is captured.
+func captureLogs(ctx context.Context, client docker.APIClient, name, id string) {
+	w, ok := ctx.Value(logKey{}).(*installLog)
+	if !ok {
+		return
+	}
+	rc, err := client.ContainerLogs(ctx, id, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		installLogf(ctx, "cannot read %s output: %s", name, err)
+		return
+	}
+	defer rc.Close()
+	installLogf(ctx, "%s output:", name)
+	demux(w, rc)
+}
+
+// helper function copies the multiplexed stdout and stderr
+// stream of a container, without a tty, to the writer.
+func demux(w io.Writer, r io.Reader) error {
+	header := make([]byte, 8)
+	for {
+		_, err := io.ReadFull(r, header)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		_, err = io.CopyN(w, r, size)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drone/autoscaler"
+)
+
+func TestInstallLog(t *testing.T) {
+	server := &autoscaler.Server{}
+	ctx := withInstallLog(context.Background(), server)
+
+	installLogf(ctx, "phase %s started", phaseDocker)
+	if got := string(server.Logs); !strings.HasSuffix(got, " phase docker started\n") {
+		t.Errorf("Want install output captured to the server logs, got %q", got)
+	}
+
+	installWriter(ctx).Write(bytes.Repeat([]byte("x"), maxLogSize))
+	if got, want := len(server.Logs), maxLogSize; got != want {
+		t.Errorf("Want server logs truncated to %d bytes, got %d", want, got)
+	}
+}
+
+func TestInstallLog_Discard(t *testing.T) {
+	// the install output is discarded if not captured,
+	// for example, when running a script to rotate the
+	// server certificate.
+	installLogf(context.Background(), "phase %s started", phaseDocker)
+	installWriter(context.Background()).Write([]byte("output"))
+}
+
+func TestDemux(t *testing.T) {
+	stream := []byte{1, 0, 0, 0, 0, 0, 0, 6}
+	stream = append(stream, "hello\n"...)
+	stream = append(stream, 2, 0, 0, 0, 0, 0, 0, 6)
+	stream = append(stream, "world\n"...)
+
+	out := new(bytes.Buffer)
+	if err := demux(out, bytes.NewReader(stream)); err != nil {
+		t.Error(err)
+	}
+	if got, want := out.String(), "hello\nworld\n"; got != want {
+		t.Errorf("Want demultiplexed output %q, got %q", want, got)
+	}
+}
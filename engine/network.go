@@ -0,0 +1,46 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/drone/autoscaler"
+)
+
+// ParseAddressPools parses the default address pools of the
+// docker daemon. Pools use the syntax base:size, where the base
+// is a network in cidr notation and the size is the prefix
+// length of the networks allocated from the pool (for example
+// 10.10.0.0/16:24 or fd00:10::/48:64).
+func ParseAddressPools(pools []string) ([]autoscaler.AddressPool, error) {
+	var out []autoscaler.AddressPool
+	for _, pool := range pools {
+		// the size follows the last separator, since ipv6
+		// networks contain the separator.
+		i := strings.LastIndex(pool, ":")
+		if i == -1 || i < strings.Index(pool, "/") {
+			return nil, fmt.Errorf("Invalid address pool %s, want base:size", pool)
+		}
+		base := pool[:i]
+		_, network, err := net.ParseCIDR(base)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid address pool %s, base must be a cidr", pool)
+		}
+		ones, bits := network.Mask.Size()
+		size, err := strconv.Atoi(pool[i+1:])
+		if err != nil || size < ones || size > bits {
+			return nil, fmt.Errorf("Invalid address pool %s, size must be between %d and %d", pool, ones, bits)
+		}
+		out = append(out, autoscaler.AddressPool{
+			Base: base,
+			Size: size,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+)
+
+func TestParseAddressPools(t *testing.T) {
+	got, err := ParseAddressPools([]string{"10.10.0.0/16:24", "192.168.128.0/18:26"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []autoscaler.AddressPool{
+		{Base: "10.10.0.0/16", Size: 24},
+		{Base: "192.168.128.0/18", Size: 26},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want address pools %v, got %v", want, got)
+	}
+}
+
+func TestParseAddressPools_IPv6(t *testing.T) {
+	got, err := ParseAddressPools([]string{"fd00:10::/48:64", "2001:db8::/32:48"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []autoscaler.AddressPool{
+		{Base: "fd00:10::/48", Size: 64},
+		{Base: "2001:db8::/32", Size: 48},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want address pools %v, got %v", want, got)
+	}
+}
+
+func TestParseAddressPools_Invalid(t *testing.T) {
+	for _, pool := range []string{
+		"10.10.0.0/16",
+		"10.10.0.0:24",
+		"10.10.0.0/16:8",
+		"10.10.0.0/16:33",
+		"10.10.0.0/16:large",
+		"fd00:10::/48",
+		"fd00:10::/48:32",
+		"fd00:10::/48:129",
+	} {
+		if _, err := ParseAddressPools([]string{pool}); err == nil {
+			t.Errorf("Want error for invalid address pool %s", pool)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defines the delay between a wake-up and the process run,
+// which coalesces bursts of notifications into a single run.
+const wakeDelay = time.Second * 2
+
+// A Notifier notifies subscribers when servers change state,
+// or when the build queue is updated.
+type Notifier interface {
+	// Subscribe returns a channel that receives a value when
+	// the notifier is notified. Notifications are dropped if
+	// the subscriber is not ready to receive.
+	Subscribe() <-chan struct{}
+}
+
+// Broadcaster is an in-process Notifier.
+type Broadcaster struct {
+	mu   sync.Mutex
+	subs []chan struct{}
+}
+
+// NewBroadcaster returns a new in-process Notifier.
+func NewBroadcaster() *Broadcaster {
+	return new(Broadcaster)
+}
+
+// Subscribe returns a channel that receives a value when
+// the broadcaster is notified.
+func (b *Broadcaster) Subscribe() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	b.subs = append(b.subs, ch)
+	return ch
+}
+
+// Notify notifies the subscribers, without blocking.
+func (b *Broadcaster) Notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// helper function returns the notifier subscription, or nil
+// if the notifier is not configured. A nil channel never
+// receives, and the process runs at the fixed interval.
+func subscribe(n Notifier) <-chan struct{} {
+	if n == nil {
+		return nil
+	}
+	return n.Subscribe()
+}
+
+// helper function runs the function at the interval, and
+// shortly after a wake-up. Each run resets the interval. A
+// wake-up does not defer a run that is already due, so that
+// a steady stream of notifications cannot delay the process.
+func schedule(ctx context.Context, interval time.Duration, wake <-chan struct{}, fn func()) {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	next := time.Now().Add(interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+			if time.Until(next) <= wakeDelay {
+				continue
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(wakeDelay)
+			next = time.Now().Add(wakeDelay)
+		case <-timer.C:
+			fn()
+			timer.Reset(interval)
+			next = time.Now().Add(interval)
+		}
+	}
+}
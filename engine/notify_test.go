@@ -0,0 +1,78 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster()
+	ch1 := b.Subscribe()
+	ch2 := b.Subscribe()
+
+	// notifications are dropped while a subscriber has a
+	// pending notification.
+	b.Notify()
+	b.Notify()
+
+	for _, ch := range []<-chan struct{}{ch1, ch2} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("Want subscriber notified")
+		}
+		select {
+		case <-ch:
+			t.Errorf("Want notifications coalesced")
+		default:
+		}
+	}
+}
+
+func TestSubscribe_Disabled(t *testing.T) {
+	if subscribe(nil) != nil {
+		t.Errorf("Want nil subscription without a notifier")
+	}
+}
+
+func TestSchedule_Wake(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wake := make(chan struct{}, 1)
+	runs := make(chan struct{}, 1)
+	go schedule(ctx, time.Hour, wake, func() {
+		runs <- struct{}{}
+	})
+
+	wake <- struct{}{}
+	select {
+	case <-runs:
+	case <-time.After(wakeDelay * 2):
+		t.Errorf("Want process run when woken")
+	}
+}
+
+func TestSchedule_Interval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan struct{}, 1)
+	go schedule(ctx, time.Millisecond, nil, func() {
+		select {
+		case runs <- struct{}{}:
+		default:
+		}
+	})
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Errorf("Want process run at the interval")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import "github.com/drone/autoscaler"
+
+// Option configures an engine option.
+type Option func(*engine)
+
+// WithNotifier returns an option to wake the planner and the
+// collector when the notifier is notified, in addition to
+// running at a fixed interval.
+func WithNotifier(n Notifier) Option {
+	return func(e *engine) {
+		e.notifier = n
+	}
+}
+
+// WithScaleEvents returns an option to record the scaling
+// actions of the planner in the scale event store.
+func WithScaleEvents(scales autoscaler.ScaleEventStore) Option {
+	return func(e *engine) {
+		e.planner.scales = scales
+	}
+}
+
+// WithPoolStates returns an option to persist the paused
+// state and the target server count of the pool in the pool
+// state store, which is shared by the replicas.
+func WithPoolStates(states autoscaler.PoolStateStore) Option {
+	return func(e *engine) {
+		e.states = states
+	}
+}
+
+// WithPlans returns an option to record the inputs and the
+// resulting action of each planning cycle in the plan store.
+func WithPlans(plans autoscaler.PlanStore) Option {
+	return func(e *engine) {
+		e.planner.plans = plans
+	}
+}
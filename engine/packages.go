@@ -0,0 +1,47 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"strings"
+
+	docker "docker.io/go-docker"
+)
+
+// packagesScript installs the packages with the package
+// manager of the host distribution. It runs in the host
+// namespaces, entered with nsenter.
+const packagesScript = `set -e
+if command -v apt-get >/dev/null 2>&1; then
+	export DEBIAN_FRONTEND=noninteractive
+	apt-get update -q
+	apt-get install -y -q $DRONE_PACKAGES
+elif command -v dnf >/dev/null 2>&1; then
+	dnf install -y -q $DRONE_PACKAGES
+elif command -v yum >/dev/null 2>&1; then
+	yum install -y -q $DRONE_PACKAGES
+elif command -v zypper >/dev/null 2>&1; then
+	zypper --non-interactive install $DRONE_PACKAGES
+elif command -v apk >/dev/null 2>&1; then
+	apk add --no-cache $DRONE_PACKAGES
+else
+	echo "cannot find a supported package manager" >&2
+	exit 1
+fi
+`
+
+// helper function installs the additional packages on the
+// host, before the agent is started. The packages are
+// installed by a privileged container that enters the host
+// namespaces, and the proxy settings are passed to the
+// package manager.
+func (i *installer) setupPackages(ctx context.Context, client docker.APIClient) error {
+	envs := []string{
+		"DRONE_PACKAGES=" + strings.Join(i.packages, " "),
+	}
+	envs = append(envs, i.proxy()...)
+	return i.runHost(ctx, client, "drone-packages", packagesScript, envs)
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler/mocks"
+
+	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/network"
+	"github.com/golang/mock/gomock"
+)
+
+func TestSetupPackages(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+
+	wait := make(chan container.ContainerWaitOKBody, 1)
+	wait <- container.ContainerWaitOKBody{StatusCode: 0}
+	errc := make(chan error)
+
+	var config *container.Config
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ImagePull(mockctx, "alpine:3", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil)
+	client.EXPECT().ContainerCreate(mockctx, gomock.Any(), gomock.Any(), gomock.Any(), "drone-packages").Do(
+		func(_ context.Context, c *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ string) {
+			config = c
+		},
+	).Return(container.ContainerCreateCreatedBody{ID: "3f2a"}, nil)
+	client.EXPECT().ContainerStart(mockctx, "3f2a", gomock.Any()).Return(nil)
+	client.EXPECT().ContainerWait(mockctx, "3f2a", container.WaitConditionNotRunning).Return((<-chan container.ContainerWaitOKBody)(wait), (<-chan error)(errc))
+	client.EXPECT().ContainerRemove(gomock.Any(), "3f2a", gomock.Any()).Return(nil)
+
+	i := installer{
+		packages:  []string{"git-lfs", "zstd"},
+		httpProxy: "http://proxy.company.com:3128",
+	}
+	if err := i.setupPackages(mockctx, client); err != nil {
+		t.Error(err)
+		return
+	}
+	want := []string{
+		"DRONE_PACKAGES=git-lfs zstd",
+		"HTTP_PROXY=http://proxy.company.com:3128",
+		"http_proxy=http://proxy.company.com:3128",
+	}
+	if config == nil || !reflect.DeepEqual(config.Env, want) {
+		t.Errorf("Want package environment %v, got %v", want, config)
+	}
+}
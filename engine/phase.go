@@ -0,0 +1,116 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// install phases, in the order they are run. The last
+// completed phase is persisted to the server, so that an
+// interrupted or failed install resumes at the next phase.
+const (
+	phaseInstance = "instance" // server provisioned
+	phaseDocker   = "docker"   // docker daemon reachable
+	phaseImage    = "image"    // agent image pulled
+	phaseAgent    = "agent"    // agent started
+)
+
+// default phase settings.
+const (
+	defaultInstanceTimeout = 30 * time.Minute
+	defaultDockerTimeout   = 30 * time.Minute
+	defaultImageTimeout    = 15 * time.Minute
+	defaultAgentTimeout    = 10 * time.Minute
+)
+
+// a phase is a named step of the install sequence that is
+// retried until it succeeds, or the timeout is reached.
+type phase struct {
+	name     string
+	timeout  time.Duration
+	interval time.Duration
+	run      func(context.Context) error
+}
+
+// helper function runs the install phases, skipping the
+// phases completed by a previous attempt. The server is
+// updated as each phase completes.
+func (i *installer) runPhases(ctx context.Context, server *autoscaler.Server, phases []phase) error {
+	logger := log.Ctx(ctx)
+
+	skip := 0
+	for n, p := range phases {
+		if p.name == server.Phase {
+			skip = n + 1
+		}
+	}
+
+	for _, p := range phases[skip:] {
+		logger.Debug().
+			Str("phase", p.name).
+			Msg("run install phase")
+		installLogf(ctx, "phase %s started", p.name)
+
+		err := runPhase(ctx, p)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("phase", p.name).
+				Msg("install phase failed")
+			return fmt.Errorf("install phase %s: %s", p.name, err)
+		}
+
+		installLogf(ctx, "phase %s complete", p.name)
+		server.Phase = p.name
+		err = i.servers.Update(ctx, server)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("phase", p.name).
+				Msg("cannot update server phase")
+			return err
+		}
+	}
+	return nil
+}
+
+// helper function runs the phase, and retries at the phase
+// interval until the phase succeeds or the timeout is
+// reached. The last error is returned on timeout.
+func runPhase(ctx context.Context, p phase) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	for {
+		err := p.run(ctx)
+		if err == nil {
+			return nil
+		}
+		log.Ctx(ctx).Debug().Err(err).
+			Str("phase", p.name).
+			Msgf("install phase not complete, retry in %v", p.interval)
+		installLogf(ctx, "phase %s: %s, retry in %v", p.name, err, p.interval)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+// helper function returns the duration, or the default
+// duration if zero.
+func durationOr(d, def time.Duration) time.Duration {
+	if d == 0 {
+		return def
+	}
+	return d
+}
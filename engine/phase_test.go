@@ -0,0 +1,125 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestRunPhase(t *testing.T) {
+	attempts := 0
+	err := runPhase(context.Background(), phase{
+		name:     phaseDocker,
+		timeout:  time.Second,
+		interval: time.Millisecond,
+		run: func(context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := attempts, 3; got != want {
+		t.Errorf("Want %d attempts, got %d", want, got)
+	}
+}
+
+func TestRunPhase_Timeout(t *testing.T) {
+	err := runPhase(context.Background(), phase{
+		name:     phaseDocker,
+		timeout:  10 * time.Millisecond,
+		interval: time.Millisecond,
+		run: func(context.Context) error {
+			return errors.New("connection refused")
+		},
+	})
+	if err == nil || err.Error() != "connection refused" {
+		t.Errorf("Want the last phase error on timeout, got %v", err)
+	}
+}
+
+func TestRunPhases_Resume(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", Phase: phaseDocker}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Update(mockctx, mockServer).Times(2).Return(nil)
+
+	var ran []string
+	run := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+
+	i := installer{servers: store}
+	err := i.runPhases(mockctx, mockServer, []phase{
+		{name: phaseInstance, timeout: time.Second, run: run(phaseInstance)},
+		{name: phaseDocker, timeout: time.Second, run: run(phaseDocker)},
+		{name: phaseImage, timeout: time.Second, run: run(phaseImage)},
+		{name: phaseAgent, timeout: time.Second, run: run(phaseAgent)},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := len(ran), 2; got != want {
+		t.Errorf("Want %d phases run, got %d", want, got)
+		return
+	}
+	if ran[0] != phaseImage || ran[1] != phaseAgent {
+		t.Errorf("Want install resumed after the completed phases, got %v", ran)
+	}
+	if got, want := mockServer.Phase, phaseAgent; got != want {
+		t.Errorf("Want server phase %q, got %q", want, got)
+	}
+}
+
+func TestRunPhases_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1"}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Update(mockctx, mockServer).Return(nil)
+
+	i := installer{servers: store}
+	err := i.runPhases(mockctx, mockServer, []phase{
+		{
+			name:    phaseInstance,
+			timeout: time.Second,
+			run:     func(context.Context) error { return nil },
+		},
+		{
+			name:     phaseDocker,
+			timeout:  10 * time.Millisecond,
+			interval: time.Millisecond,
+			run:      func(context.Context) error { return errors.New("connection refused") },
+		},
+	})
+	if err == nil {
+		t.Errorf("Want error when the phase times out")
+	}
+	if got, want := mockServer.Phase, phaseInstance; got != want {
+		t.Errorf("Want the last completed phase %q persisted, got %q", want, got)
+	}
+}
@@ -7,6 +7,8 @@ package engine
 import (
 	"context"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/drone/autoscaler"
@@ -30,35 +32,89 @@ type planner struct {
 	cap     int           // capacity per-server
 	ttu     time.Duration // minimum server age
 	labels  map[string]string
+	exec    bool // servers run the exec runner
+
+	client   drone.Client
+	servers  autoscaler.ServerStore
+	provider autoscaler.Provider
+	scales   autoscaler.ScaleEventStore // optional
+	plans    autoscaler.PlanStore       // optional
+	pool     string
+
+	mu       sync.Mutex
+	target   int  // target number of servers
+	targeted bool // target set manually
+}
+
+// helper function sets the target server count, which the
+// planner maintains as the minimum server count regardless
+// of build volume. A negative count clears the target.
+func (p *planner) setTarget(n int) {
+	p.mu.Lock()
+	p.target = max(n, 0)
+	p.targeted = n >= 0
+	p.mu.Unlock()
+}
 
-	client  drone.Client
-	servers autoscaler.ServerStore
+// helper function returns the target server count, and true
+// if the target is set.
+func (p *planner) getTarget() (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.target, p.targeted
 }
 
 func (p *planner) Plan(ctx context.Context) error {
+	_, err := p.plan(ctx, false)
+	return err
+}
+
+// Preview returns the plan of a planning cycle executed
+// against the current build volume and server capacity,
+// without allocating or terminating servers.
+func (p *planner) Preview(ctx context.Context) (*autoscaler.Plan, error) {
+	return p.plan(ctx, true)
+}
+
+// helper function executes a planning cycle, and returns the
+// plan. The servers are not allocated or marked for
+// termination if dry run is true, and the plan is returned
+// with the number of servers that would be.
+func (p *planner) plan(ctx context.Context, dryRun bool) (*autoscaler.Plan, error) {
 	// generate a unique identifier for the current
 	// execution cycle for tracing and grouping logs.
 	cycle := uniuri.New()
 
 	logger := log.Ctx(ctx).With().Str("id", cycle).Logger()
+	if dryRun {
+		logger = logger.With().Bool("dry-run", true).Logger()
+	}
 
 	pending, running, err := p.count(ctx)
 	if err != nil {
 		logger.Error().Err(err).
 			Msg("cannot fetch queue details")
-		return err
+		return nil, err
 	}
 
 	capacity, servers, err := p.capacity(ctx)
 	if err != nil {
 		logger.Error().Err(err).
 			Msg("cannot calculate server capacity")
-		return err
+		return nil, err
+	}
+
+	// the target server count, if set, replaces the minimum
+	// server count, and raises the maximum server count.
+	floor, ceiling := p.min, p.max
+	target, targeted := p.getTarget()
+	if targeted {
+		floor, ceiling = target, max(p.max, target)
 	}
 
 	logger.Debug().
-		Int("min-pool", p.min).
-		Int("max-pool", p.max).
+		Int("min-pool", floor).
+		Int("max-pool", ceiling).
 		Int("server-capacity", capacity).
 		Int("server-count", servers).
 		Int("pending-builds", pending).
@@ -75,78 +131,213 @@ func (p *planner) Plan(ctx context.Context) error {
 	free := max(capacity-running, 0)
 	diff := serverDiff(pending, free, p.cap)
 
+	// if the target server count is set, servers are
+	// allocated until the target is reached, regardless
+	// of build volume.
+	if targeted && servers+diff < target {
+		diff = target - servers
+	}
+
+	// the queue and capacity metrics are recorded with the
+	// scaling action for capacity reporting.
+	trigger := autoscaler.ScaleEvent{
+		Pool:     p.pool,
+		Pending:  pending,
+		Running:  running,
+		Capacity: capacity,
+		Servers:  servers,
+	}
+
+	// the inputs of the planning cycle are recorded with the
+	// resulting action, whether or not capacity changes.
+	plan := &autoscaler.Plan{
+		Cycle:    cycle,
+		Pool:     p.pool,
+		Pending:  pending,
+		Running:  running,
+		Capacity: capacity,
+		Servers:  servers,
+		Min:      floor,
+		Max:      ceiling,
+		Diff:     diff,
+	}
+
 	// if the server differential to handle the build volume
 	// is positive, we can reduce server capacity.
 	if diff < 0 {
-		return p.mark(ctx,
+		n, err := p.mark(ctx,
 			// we should adjust the desired capacity to ensure
 			// we maintain the minimum required server count.
-			serverFloor(servers, abs(diff), p.min),
+			serverFloor(servers, abs(diff), floor),
+			dryRun,
 		)
+		plan.Reason = "free capacity exceeds the build volume"
+		resolve(plan, autoscaler.ScaleTerminate, n, err)
+		if !dryRun {
+			p.record(ctx, trigger, autoscaler.ScaleTerminate, n)
+			p.save(ctx, plan)
+		}
+		return plan, err
 	}
 
 	// if the server differential to handle the build volume
 	// is positive, we need to allocate more server capacity.
 	if diff > 0 {
-		return p.alloc(ctx,
+		n, err := p.alloc(ctx,
 			// we should adjust the desired capacity to ensure
 			// it does not exceed the max server count.
-			serverCeil(servers, diff, p.max),
+			serverCeil(servers, diff, ceiling),
+			dryRun,
 		)
+		plan.Reason = "build volume exceeds the free capacity"
+		if targeted && servers < target {
+			plan.Reason = "server count below the target"
+		}
+		resolve(plan, autoscaler.ScaleAlloc, n, err)
+		if !dryRun {
+			p.record(ctx, trigger, autoscaler.ScaleAlloc, n)
+			p.save(ctx, plan)
+		}
+		return plan, err
 	}
 
 	logger.Debug().
 		Msg("no capacity changes required")
 
-	return nil
+	plan.Reason = "no capacity changes required"
+	resolve(plan, autoscaler.ScaleNone, 0, nil)
+	if !dryRun {
+		p.save(ctx, plan)
+	}
+	return plan, nil
 }
 
-// helper function allocates n new server instances.
-func (p *planner) alloc(ctx context.Context, n int) error {
+// helper function sets the resulting action of the plan. The
+// action is none if no servers were allocated or marked for
+// termination.
+func resolve(plan *autoscaler.Plan, action autoscaler.ScaleAction, n int, err error) {
+	plan.Action = action
+	plan.Count = n
+	if n == 0 {
+		plan.Action = autoscaler.ScaleNone
+	}
+	if err != nil {
+		plan.Error = err.Error()
+	}
+}
+
+// helper function records the plan, if a plan store is
+// configured.
+func (p *planner) save(ctx context.Context, plan *autoscaler.Plan) {
+	if p.plans == nil {
+		return
+	}
+	if err := p.plans.Create(ctx, plan); err != nil {
+		log.Ctx(ctx).Warn().Err(err).
+			Msg("cannot record plan")
+	}
+}
+
+// helper function records the scaling action, if servers
+// were allocated or marked for termination.
+func (p *planner) record(ctx context.Context, event autoscaler.ScaleEvent, action autoscaler.ScaleAction, n int) {
+	if p.scales == nil || n == 0 {
+		return
+	}
+	event.Action = action
+	event.Count = n
+	err := p.scales.Create(ctx, &event)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).
+			Str("action", string(action)).
+			Msg("cannot record scale event")
+	}
+}
+
+// helper function allocates n new server instances, and
+// returns the number of servers allocated.
+func (p *planner) alloc(ctx context.Context, n int, dryRun bool) (int, error) {
 	logger := log.Ctx(ctx)
 
+	// cap the allocation to the remaining provider quota
+	// to prevent creating servers that cannot succeed.
+	n = p.quota(ctx, n)
+
 	logger.Debug().
 		Msgf("allocate %d servers", n)
 
+	if dryRun {
+		return n, nil
+	}
+
 	for i := 0; i < n; i++ {
 		server := &autoscaler.Server{
 			Name:     "agent-" + uniuri.NewLen(8),
 			State:    autoscaler.StatePending,
 			Secret:   uniuri.New(),
 			Capacity: p.cap,
+			Labels:   autoscaler.Labels(p.labels),
 		}
 
 		err := p.servers.Create(ctx, server)
 		if limiter.IsError(err) {
 			logger.Warn().Err(err).
 				Msg("cannot create server")
-			return err
+			return i, err
 		}
 		if err != nil {
 			logger.Error().Err(err).
 				Msg("cannot create server")
-			return err
+			return i, err
 		}
 	}
-	return nil
+	return n, nil
+}
+
+// helper function returns the number of servers that can
+// be allocated without exceeding the provider quota.
+func (p *planner) quota(ctx context.Context, n int) int {
+	quoter, ok := p.provider.(autoscaler.Quoter)
+	if !ok || n == 0 {
+		return n
+	}
+
+	logger := log.Ctx(ctx)
+
+	remaining, err := quoter.Quota(ctx)
+	if err != nil {
+		logger.Warn().Err(err).
+			Msg("cannot fetch provider quota")
+		return n
+	}
+	if remaining < n {
+		logger.Warn().
+			Int("requested", n).
+			Int("quota", remaining).
+			Msg("insufficient provider quota, reducing server allocation")
+		return max(remaining, 0)
+	}
+	return n
 }
 
-// helper funciton marks instances for termination.
-func (p *planner) mark(ctx context.Context, n int) error {
+// helper funciton marks instances for termination, and
+// returns the number of servers marked. The idle servers are
+// counted, but not marked, if dry run is true.
+func (p *planner) mark(ctx context.Context, n int, dryRun bool) (int, error) {
 	logger := log.Ctx(ctx)
 
 	logger.Debug().
 		Msgf("terminate %d servers", n)
 
 	if n == 0 {
-		return nil
+		return 0, nil
 	}
 
 	servers, err := p.servers.ListState(ctx, autoscaler.StateRunning)
 	if err != nil {
 		logger.Error().Err(err).
 			Msg("cannot fetch server list")
-		return err
+		return 0, err
 	}
 	sort.Sort(sort.Reverse(byCreated(servers)))
 
@@ -154,7 +345,7 @@ func (p *planner) mark(ctx context.Context, n int) error {
 	if err != nil {
 		logger.Error().Err(err).
 			Msg("cannot ascertain busy server list")
-		return err
+		return 0, err
 	}
 
 	var idle []*autoscaler.Server
@@ -194,6 +385,11 @@ func (p *planner) mark(ctx context.Context, n int) error {
 		idle = idle[:n]
 	}
 
+	if dryRun {
+		return len(idle), nil
+	}
+
+	marked := 0
 	for _, server := range idle {
 		server.State = autoscaler.StateShutdown
 		err := p.servers.Update(ctx, server)
@@ -203,10 +399,12 @@ func (p *planner) mark(ctx context.Context, n int) error {
 				Str("server", server.Name).
 				Str("state", "shutdown").
 				Msg("cannot update server state")
+			continue
 		}
+		marked++
 	}
 
-	return nil
+	return marked, nil
 }
 
 // helper function returns the number of pending and
@@ -269,17 +467,37 @@ func (p *planner) listBusy(ctx context.Context) (map[string]struct{}, error) {
 // helper function returns true if the os, arch, variant
 // and kernel match the stage.
 func (p *planner) match(stage *drone.Stage) bool {
-	labelMatch := true
+	return p.mismatch(stage) == ""
+}
 
-	if len(p.labels) > 0 || len(stage.Labels) > 0 {
-		labelMatch = checkLabels(p.labels, stage.Labels)
+// helper function returns the reason the stage is not matched
+// by the planner, or an empty string if the stage is matched.
+func (p *planner) mismatch(stage *drone.Stage) string {
+	switch {
+	case !strings.EqualFold(stage.OS, p.os):
+		return "os"
+	case !strings.EqualFold(stage.Arch, p.arch):
+		return "arch"
+	case stage.Variant != p.version:
+		return "variant"
+	case stage.Kernel != p.kernel:
+		return "kernel"
+	case !p.matchType(stage):
+		return "type"
+	case (len(p.labels) > 0 || len(stage.Labels) > 0) && !checkLabels(p.labels, stage.Labels):
+		return "labels"
 	}
+	return ""
+}
 
-	return stage.OS == p.os &&
-		stage.Arch == p.arch &&
-		stage.Variant == p.version &&
-		stage.Kernel == p.kernel &&
-		labelMatch
+// helper function returns true if the stage is executed by
+// the runner installed on the servers. Stages without a type
+// are executed by the docker runner.
+func (p *planner) matchType(stage *drone.Stage) bool {
+	if p.exec {
+		return stage.Type == stageTypeExec
+	}
+	return stage.Type != stageTypeExec
 }
 
 func checkLabels(a, b map[string]string) bool {
@@ -6,6 +6,7 @@ package engine
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
@@ -156,9 +157,274 @@ func TestPlan_MoreCapacity(t *testing.T) {
 	}
 }
 
+// This test verifies that the allocation is recorded in the
+// scale event store, with the metrics that triggered it.
+func TestPlan_ScaleEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Capacity: 1, State: autoscaler.StateRunning},
+	}
+
+	builds := []*drone.Stage{
+		{Status: drone.StatusRunning},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+	store.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return(builds, nil)
+
+	scales := mocks.NewMockScaleEventStore(controller)
+	scales.EXPECT().Create(gomock.Any(), &autoscaler.ScaleEvent{
+		Pool:     "arm64",
+		Action:   autoscaler.ScaleAlloc,
+		Count:    1,
+		Pending:  2,
+		Running:  1,
+		Capacity: 1,
+		Servers:  1,
+	}).Return(nil)
+
+	p := planner{
+		cap:     2,
+		min:     1,
+		max:     4,
+		client:  client,
+		servers: store,
+		scales:  scales,
+		pool:    "arm64",
+	}
+
+	err := p.Plan(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// This test verifies that the planning cycle is recorded in
+// the plan store, with the inputs and the resulting action.
+func TestPlan_Plans(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Capacity: 1, State: autoscaler.StateRunning},
+	}
+
+	builds := []*drone.Stage{
+		{Status: drone.StatusRunning},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+	store.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return(builds, nil)
+
+	var got *autoscaler.Plan
+	plans := mocks.NewMockPlanStore(controller)
+	plans.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *autoscaler.Plan) {
+		got = plan
+	}).Return(nil)
+
+	p := planner{
+		cap:     2,
+		min:     1,
+		max:     4,
+		client:  client,
+		servers: store,
+		plans:   plans,
+		pool:    "arm64",
+	}
+
+	err := p.Plan(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got == nil {
+		t.Errorf("Want plan recorded")
+		return
+	}
+	if got.Cycle == "" {
+		t.Errorf("Want plan cycle identifier")
+	}
+	// the cycle identifier is random.
+	got.Cycle = ""
+	want := &autoscaler.Plan{
+		Pool:     "arm64",
+		Pending:  2,
+		Running:  1,
+		Capacity: 1,
+		Servers:  1,
+		Min:      1,
+		Max:      4,
+		Diff:     1,
+		Action:   autoscaler.ScaleAlloc,
+		Count:    1,
+		Reason:   "build volume exceeds the free capacity",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want plan %+v, got %+v", want, got)
+	}
+}
+
+// This test verifies that the planning cycle is recorded if
+// no capacity changes are required.
+func TestPlan_PlansNoop(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Capacity: 2, State: autoscaler.StateRunning},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return([]*drone.Stage{
+		{Status: drone.StatusRunning},
+	}, nil)
+
+	var got *autoscaler.Plan
+	plans := mocks.NewMockPlanStore(controller)
+	plans.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *autoscaler.Plan) {
+		got = plan
+	}).Return(nil)
+
+	p := planner{
+		cap:     2,
+		min:     1,
+		max:     4,
+		client:  client,
+		servers: store,
+		plans:   plans,
+	}
+
+	err := p.Plan(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got == nil {
+		t.Errorf("Want plan recorded")
+		return
+	}
+	if got.Action != autoscaler.ScaleNone || got.Count != 0 {
+		t.Errorf("Want no scaling action, got %s %d", got.Action, got.Count)
+	}
+}
+
+// This test verifies that the preview returns the plan
+// without allocating servers, or recording the plan.
+func TestPlan_Preview(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Capacity: 1, State: autoscaler.StateRunning},
+	}
+
+	builds := []*drone.Stage{
+		{Status: drone.StatusRunning},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return(builds, nil)
+
+	p := planner{
+		cap:     2,
+		min:     1,
+		max:     4,
+		client:  client,
+		servers: store,
+		scales:  mocks.NewMockScaleEventStore(controller),
+		plans:   mocks.NewMockPlanStore(controller),
+	}
+
+	plan, err := p.Preview(context.TODO())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := plan.Action, autoscaler.ScaleAlloc; got != want {
+		t.Errorf("Want plan action %s, got %s", want, got)
+	}
+	if got, want := plan.Count, 2; got != want {
+		t.Errorf("Want plan to allocate %d servers, got %d", want, got)
+	}
+}
+
 // This test verifies that if that no servers are
 // destroyed if there is excess capacity and the
 // the server count <= the min pool size.
+// This test verifies that the planner caps the number of
+// servers allocated to the remaining provider quota.
+func TestPlan_Quota(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Capacity: 1, State: autoscaler.StateRunning},
+	}
+
+	// x1 running builds
+	// x4 pending builds
+	builds := []*drone.Stage{
+		{Status: drone.StatusRunning},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+		{Status: drone.StatusPending},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+	store.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return(builds, nil)
+
+	quoter := mocks.NewMockQuoter(controller)
+	quoter.EXPECT().Quota(gomock.Any()).Return(1, nil)
+
+	provider := struct {
+		*mocks.MockProvider
+		*mocks.MockQuoter
+	}{mocks.NewMockProvider(controller), quoter}
+
+	p := planner{
+		cap:      1,
+		min:      1,
+		max:      4,
+		client:   client,
+		servers:  store,
+		provider: provider,
+	}
+
+	err := p.Plan(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestPlan_MinPool(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -428,6 +694,24 @@ func TestMatch(t *testing.T) {
 				Arch: "arm",
 			},
 		},
+		{
+			match: true,
+			os:    "windows",
+			arch:  "amd64",
+			stage: &drone.Stage{
+				OS:   "windows",
+				Arch: "amd64",
+			},
+		},
+		{
+			match: false,
+			os:    "windows",
+			arch:  "amd64",
+			stage: &drone.Stage{
+				OS:   "linux",
+				Arch: "amd64",
+			},
+		},
 		{
 			match: false,
 			os:    "linux",
@@ -530,3 +814,84 @@ func TestMatch(t *testing.T) {
 		}
 	}
 }
+
+// This test verifies that servers are allocated until the
+// target server count is reached, regardless of the build
+// volume and the pool maximum size.
+func TestPlan_Target(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Capacity: 2, State: autoscaler.StateRunning},
+		{Name: "server2", Capacity: 2, State: autoscaler.StateRunning},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+	store.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return(nil, nil)
+
+	p := planner{
+		cap:     2,
+		min:     1,
+		max:     2,
+		client:  client,
+		servers: store,
+	}
+	p.setTarget(4)
+
+	err := p.Plan(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// This test verifies that idle servers are not terminated
+// below the target server count.
+func TestPlan_TargetFloor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Capacity: 2, State: autoscaler.StateRunning},
+		{Name: "server2", Capacity: 2, State: autoscaler.StateRunning},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return(nil, nil)
+
+	p := planner{
+		cap:     2,
+		min:     0,
+		max:     4,
+		client:  client,
+		servers: store,
+	}
+	p.setTarget(2)
+
+	err := p.Plan(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPlanner_Target(t *testing.T) {
+	p := planner{}
+	if _, ok := p.getTarget(); ok {
+		t.Errorf("Want target unset")
+	}
+	p.setTarget(3)
+	if n, ok := p.getTarget(); !ok || n != 3 {
+		t.Errorf("Want target 3, got %d", n)
+	}
+	p.setTarget(-1)
+	if _, ok := p.getTarget(); ok {
+		t.Errorf("Want target cleared")
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// poolStore scopes the server store to a single pool. Servers
+// created with the store are assigned to the pool, and servers
+// assigned to other pools are excluded from list results.
+type poolStore struct {
+	autoscaler.ServerStore
+	pool string
+}
+
+func (s *poolStore) List(ctx context.Context) ([]*autoscaler.Server, error) {
+	return s.ServerStore.ListByPool(ctx, s.pool)
+}
+
+func (s *poolStore) ListState(ctx context.Context, state autoscaler.ServerState) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListState(ctx, state)
+	return s.filter(servers), err
+}
+
+func (s *poolStore) ListDeleted(ctx context.Context) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListDeleted(ctx)
+	return s.filter(servers), err
+}
+
+func (s *poolStore) ListByPool(ctx context.Context, pool string) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListByPool(ctx, pool)
+	return s.filter(servers), err
+}
+
+func (s *poolStore) ListByLabels(ctx context.Context, labels autoscaler.Labels) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListByLabels(ctx, labels)
+	return s.filter(servers), err
+}
+
+func (s *poolStore) ListFilter(ctx context.Context, filter autoscaler.ServerFilter) ([]*autoscaler.Server, error) {
+	// the default pool has no name, and cannot be selected
+	// with the filter, so the servers of the default pool are
+	// filtered from the results.
+	if s.pool == "" {
+		servers, err := s.ServerStore.ListFilter(ctx, filter)
+		return s.filter(servers), err
+	}
+	filter.Pool = s.pool
+	return s.ServerStore.ListFilter(ctx, filter)
+}
+
+func (s *poolStore) Create(ctx context.Context, server *autoscaler.Server) error {
+	server.Pool = s.pool
+	return s.ServerStore.Create(ctx, server)
+}
+
+// helper function returns the servers assigned to the pool.
+func (s *poolStore) filter(servers []*autoscaler.Server) []*autoscaler.Server {
+	var out []*autoscaler.Server
+	for _, server := range servers {
+		if server.Pool == s.pool {
+			out = append(out, server)
+		}
+	}
+	return out
+}
+
+// group is an engine that runs the engine of each pool.
+type group []autoscaler.Engine
+
+// NewGroup returns an Engine that runs the engines of
+// multiple pools, each bound to its own provider.
+func NewGroup(engines ...autoscaler.Engine) autoscaler.Engine {
+	return group(engines)
+}
+
+func (g group) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(len(g))
+	for _, engine := range g {
+		go func(engine autoscaler.Engine) {
+			engine.Start(ctx)
+			wg.Done()
+		}(engine)
+	}
+	wg.Wait()
+}
+
+// Pause pauses the engine of each pool.
+func (g group) Pause() error {
+	for _, engine := range g {
+		if err := engine.Pause(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Paused returns true if the engine of each pool is paused.
+func (g group) Paused() bool {
+	for _, engine := range g {
+		if !engine.Paused() {
+			return false
+		}
+	}
+	return len(g) != 0
+}
+
+// LastPlan returns the earliest time the planner of a pool
+// last completed a planning cycle, which identifies a pool
+// that is no longer planned. Paused pools are ignored.
+func (g group) LastPlan() time.Time {
+	var planned time.Time
+	for _, engine := range g {
+		if engine.Paused() {
+			continue
+		}
+		t := engine.LastPlan()
+		if !t.IsZero() && (planned.IsZero() || t.Before(planned)) {
+			planned = t
+		}
+	}
+	return planned
+}
+
+// Resume resumes the engine of each pool.
+func (g group) Resume() error {
+	for _, engine := range g {
+		if err := engine.Resume(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PausePool pauses the engine of the named pool.
+func (g group) PausePool(pool string) error {
+	for _, engine := range g {
+		err := engine.PausePool(pool)
+		if err != autoscaler.ErrPoolNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrPoolNotFound
+}
+
+// ResumePool resumes the engine of the named pool.
+func (g group) ResumePool(pool string) error {
+	for _, engine := range g {
+		err := engine.ResumePool(pool)
+		if err != autoscaler.ErrPoolNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrPoolNotFound
+}
+
+// Upgrade starts a rolling upgrade of the agent image. The
+// pools may run different agent images, so the upgrade is
+// only supported if a single pool is configured.
+func (g group) Upgrade(ctx context.Context, image string) error {
+	if len(g) != 1 {
+		return errUpgradePools
+	}
+	return g[0].Upgrade(ctx, image)
+}
+
+// Rotate starts the certificate rotation of the named server
+// with the engine of the pool that manages the server.
+func (g group) Rotate(ctx context.Context, name string) error {
+	for _, engine := range g {
+		err := engine.Rotate(ctx, name)
+		if err != autoscaler.ErrServerNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrServerNotFound
+}
+
+// Repair starts the repair of the named server with the
+// engine of the pool that manages the server.
+func (g group) Repair(ctx context.Context, name string) error {
+	for _, engine := range g {
+		err := engine.Repair(ctx, name)
+		if err != autoscaler.ErrServerNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrServerNotFound
+}
+
+// Replace replaces the named server with the engine of the
+// pool that manages the server.
+func (g group) Replace(ctx context.Context, name string) (*autoscaler.Server, error) {
+	for _, engine := range g {
+		server, err := engine.Replace(ctx, name)
+		if err != autoscaler.ErrServerNotFound {
+			return server, err
+		}
+	}
+	return nil, autoscaler.ErrServerNotFound
+}
+
+// Cordon cordons the named server with the engine of the
+// pool that manages the server.
+func (g group) Cordon(ctx context.Context, name string) error {
+	for _, engine := range g {
+		err := engine.Cordon(ctx, name)
+		if err != autoscaler.ErrServerNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrServerNotFound
+}
+
+// Destroy destroys the named server with the engine of the
+// pool that manages the server.
+func (g group) Destroy(ctx context.Context, name string) error {
+	for _, engine := range g {
+		err := engine.Destroy(ctx, name)
+		if err != autoscaler.ErrServerNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrServerNotFound
+}
+
+// Uncordon uncordons the named server with the engine of the
+// pool that manages the server.
+func (g group) Uncordon(ctx context.Context, name string) error {
+	for _, engine := range g {
+		err := engine.Uncordon(ctx, name)
+		if err != autoscaler.ErrServerNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrServerNotFound
+}
+
+// Queue returns the view of the build queue of the planner
+// of each pool.
+func (g group) Queue(ctx context.Context) ([]*autoscaler.QueueSnapshot, error) {
+	var snapshots []*autoscaler.QueueSnapshot
+	for _, engine := range g {
+		snapshot, err := engine.Queue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot...)
+	}
+	return snapshots, nil
+}
+
+// Preview returns the plan of the planner of each pool.
+func (g group) Preview(ctx context.Context) ([]*autoscaler.Plan, error) {
+	var plans []*autoscaler.Plan
+	for _, engine := range g {
+		plan, err := engine.Preview(ctx)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan...)
+	}
+	return plans, nil
+}
+
+// Scale sets the target server count of the named pool with
+// the engine of the pool.
+func (g group) Scale(ctx context.Context, pool string, n int) error {
+	for _, engine := range g {
+		err := engine.Scale(ctx, pool, n)
+		if err != autoscaler.ErrPoolNotFound {
+			return err
+		}
+	}
+	return autoscaler.ErrPoolNotFound
+}
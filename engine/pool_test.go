@@ -0,0 +1,156 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestPoolStore(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServers := []*autoscaler.Server{
+		{Name: "agent-1", Pool: "amd64"},
+		{Name: "agent-2", Pool: "arm64"},
+		{Name: "agent-3", Pool: "arm64"},
+	}
+	mockServer := &autoscaler.Server{Name: "agent-4"}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return(mockServers, nil)
+	store.EXPECT().Create(mockctx, mockServer).Return(nil)
+
+	s := &poolStore{ServerStore: store, pool: "arm64"}
+	servers, err := s.ListState(mockctx, autoscaler.StateRunning)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := len(servers), 2; got != want {
+		t.Errorf("Want %d servers in the pool, got %d", want, got)
+	}
+
+	err = s.Create(mockctx, mockServer)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.Pool, "arm64"; got != want {
+		t.Errorf("Want server assigned to pool %q, got %q", want, got)
+	}
+}
+
+func TestPoolStore_List(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServers := []*autoscaler.Server{
+		{Name: "agent-2", Pool: "arm64"},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListByPool(mockctx, "arm64").Return(mockServers, nil)
+
+	s := &poolStore{ServerStore: store, pool: "arm64"}
+	servers, err := s.List(mockctx)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := len(servers), 1; got != want {
+		t.Errorf("Want %d servers in the pool, got %d", want, got)
+	}
+}
+
+func TestGroup_Pause(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	engine1 := mocks.NewMockEngine(controller)
+	engine1.EXPECT().Pause()
+	engine1.EXPECT().Paused().Return(true)
+
+	engine2 := mocks.NewMockEngine(controller)
+	engine2.EXPECT().Pause()
+	engine2.EXPECT().Paused().Return(false)
+
+	g := NewGroup(engine1, engine2)
+	g.Pause()
+	if g.Paused() {
+		t.Errorf("Want group running while a pool is running")
+	}
+}
+
+func TestGroup_Scale(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+
+	engine1 := mocks.NewMockEngine(controller)
+	engine1.EXPECT().Scale(mockctx, "arm64", 5).Return(autoscaler.ErrPoolNotFound)
+
+	engine2 := mocks.NewMockEngine(controller)
+	engine2.EXPECT().Scale(mockctx, "arm64", 5).Return(nil)
+
+	g := NewGroup(engine1, engine2)
+	if err := g.Scale(mockctx, "arm64", 5); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScale_PoolNotFound(t *testing.T) {
+	e := engine{pool: "amd64", planner: &planner{}}
+	if err := e.Scale(context.Background(), "arm64", 5); err != autoscaler.ErrPoolNotFound {
+		t.Errorf("Want pool not found error, got %v", err)
+	}
+	unnamed := engine{planner: &planner{}}
+	if err := unnamed.Scale(context.Background(), "default", 5); err != nil {
+		t.Errorf("Want the unnamed pool addressed as default, got %v", err)
+	}
+	if n, _ := unnamed.planner.getTarget(); n != 5 {
+		t.Errorf("Want target server count 5, got %d", n)
+	}
+}
+
+func TestGroup_PausePool(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	engine1 := mocks.NewMockEngine(controller)
+	engine1.EXPECT().PausePool("arm64").Return(nil)
+
+	engine2 := mocks.NewMockEngine(controller)
+
+	g := NewGroup(engine1, engine2)
+	if err := g.PausePool("arm64"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPausePool(t *testing.T) {
+	e := engine{pool: "arm64"}
+	if err := e.PausePool("amd64"); err != autoscaler.ErrPoolNotFound {
+		t.Errorf("Want pool not found error, got %v", err)
+	}
+	if err := e.PausePool("arm64"); err != nil {
+		t.Error(err)
+	}
+	if !e.Paused() {
+		t.Errorf("Want pool paused")
+	}
+	if err := e.ResumePool("arm64"); err != nil {
+		t.Error(err)
+	}
+	if e.Paused() {
+		t.Errorf("Want pool resumed")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/drone-go/drone"
+
+	"github.com/rs/zerolog/log"
+)
+
+// helper function returns the view of the build queue of the
+// planner. The matched stages are grouped by status, and the
+// stages that are not matched are excluded, with the reason
+// the stage is not matched.
+func (p *planner) snapshot(ctx context.Context) (*autoscaler.QueueSnapshot, error) {
+	stages, err := p.client.Queue()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Msg("cannot fetch queue details")
+		return nil, err
+	}
+	snapshot := &autoscaler.QueueSnapshot{
+		Pending:  []*autoscaler.QueueStage{},
+		Running:  []*autoscaler.QueueStage{},
+		Excluded: []*autoscaler.QueueStage{},
+	}
+	for _, stage := range stages {
+		out := toQueueStage(stage)
+		if out.Reason = p.mismatch(stage); out.Reason != "" {
+			snapshot.Excluded = append(snapshot.Excluded, out)
+			continue
+		}
+		switch stage.Status {
+		case drone.StatusPending:
+			snapshot.Pending = append(snapshot.Pending, out)
+		case drone.StatusRunning:
+			snapshot.Running = append(snapshot.Running, out)
+		}
+	}
+	return snapshot, nil
+}
+
+// helper function converts the drone stage to the queue stage.
+func toQueueStage(stage *drone.Stage) *autoscaler.QueueStage {
+	return &autoscaler.QueueStage{
+		ID:      stage.ID,
+		BuildID: stage.BuildID,
+		Name:    stage.Name,
+		Status:  stage.Status,
+		Type:    stage.Type,
+		OS:      stage.OS,
+		Arch:    stage.Arch,
+		Variant: stage.Variant,
+		Kernel:  stage.Kernel,
+		Labels:  stage.Labels,
+		Server:  stage.Machine,
+		Created: stage.Created,
+		Started: stage.Started,
+	}
+}
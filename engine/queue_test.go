@@ -0,0 +1,62 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/drone-go/drone"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestQueue(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return([]*drone.Stage{
+		{ID: 1, Status: drone.StatusPending, OS: "linux", Arch: "amd64"},
+		{ID: 2, Status: drone.StatusRunning, OS: "linux", Arch: "amd64", Machine: "agent-1"},
+		{ID: 3, Status: drone.StatusPending, OS: "linux", Arch: "arm64"},
+		{ID: 4, Status: drone.StatusPending, OS: "linux", Arch: "amd64", Labels: map[string]string{"gpu": "true"}},
+	}, nil)
+
+	e := engine{planner: &planner{client: client, os: "linux", arch: "amd64"}}
+	snapshots, err := e.Queue(context.Background())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(snapshots), 1; got != want {
+		t.Errorf("Want %d queue snapshots, got %d", want, got)
+		return
+	}
+
+	snapshot := snapshots[0]
+	if got, want := snapshot.Pool, "default"; got != want {
+		t.Errorf("Want unnamed pool named %s, got %s", want, got)
+	}
+	if got, want := len(snapshot.Pending), 1; got != want {
+		t.Errorf("Want %d pending stages, got %d", want, got)
+	}
+	if got, want := len(snapshot.Running), 1; got != want {
+		t.Errorf("Want %d running stages, got %d", want, got)
+	} else if got, want := snapshot.Running[0].Server, "agent-1"; got != want {
+		t.Errorf("Want running stage server %s, got %s", want, got)
+	}
+	if got, want := len(snapshot.Excluded), 2; got != want {
+		t.Errorf("Want %d excluded stages, got %d", want, got)
+		return
+	}
+	if got, want := snapshot.Excluded[0].Reason, "arch"; got != want {
+		t.Errorf("Want stage excluded by %s, got %s", want, got)
+	}
+	if got, want := snapshot.Excluded[1].Reason, "labels"; got != want {
+		t.Errorf("Want stage excluded by %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api/types"
+)
+
+// default readiness settings of the agent container.
+const (
+	defaultReadyTimeout  = 5 * time.Minute
+	defaultReadyPeriod   = 15 * time.Second
+	defaultReadyInterval = 5 * time.Second
+)
+
+// errAgentNotReady is returned when the agent container is
+// not ready before the readiness timeout.
+var errAgentNotReady = errors.New("Agent container is not ready")
+
+// helper function waits until the agent container is running
+// and healthy. The container must keep running, without being
+// restarted, for the readiness period, so that an agent that
+// crash-loops is not reported as running.
+func (i *installer) waitAgent(ctx context.Context, client docker.APIClient) error {
+	timeout, period, interval := i.readyTimeout, i.readyPeriod, i.readyInterval
+	if timeout == 0 {
+		timeout = defaultReadyTimeout
+	}
+	if period == 0 {
+		period = defaultReadyPeriod
+	}
+	if interval == 0 {
+		interval = defaultReadyInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var id string
+	var since time.Time
+	for {
+		info, err := client.ContainerInspect(ctx, "agent")
+		if err == nil {
+			err = checkAgent(info, id)
+			if err != nil {
+				return err
+			}
+			id = info.ID
+		} else if id != "" && docker.IsErrNotFound(err) {
+			// the systemd unit removes the container when the
+			// agent exits.
+			return errors.New("Agent container exited")
+		}
+
+		switch {
+		case id == "" || starting(info):
+			since = time.Time{}
+		case since.IsZero():
+			since = time.Now()
+		case time.Since(since) >= period:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errAgentNotReady
+		case <-time.After(interval):
+		}
+	}
+}
+
+// helper function returns an error if the agent container is
+// not running, was restarted, or is unhealthy.
+func checkAgent(info types.ContainerJSON, id string) error {
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return nil
+	}
+	state := info.State
+	switch {
+	case id != "" && info.ID != id:
+		return errors.New("Agent container was restarted")
+	case state.Restarting || info.RestartCount != 0:
+		return fmt.Errorf("Agent container was restarted, exit code %d", state.ExitCode)
+	case !state.Running && state.Status != "created":
+		return fmt.Errorf("Agent container is not running, exit code %d", state.ExitCode)
+	case state.Health != nil && state.Health.Status == types.Unhealthy:
+		return errors.New("Agent container is unhealthy")
+	}
+	return nil
+}
+
+// helper function returns true if the agent container is
+// starting, or its health check has not yet passed.
+func starting(info types.ContainerJSON) bool {
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return true
+	}
+	if !info.State.Running {
+		return true
+	}
+	return info.State.Health != nil && info.State.Health.Status != types.Healthy
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler/mocks"
+
+	"docker.io/go-docker/api/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestWaitAgent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	starting := mockAgent("3f2a", &types.ContainerState{Running: true, Health: &types.Health{Status: types.Starting}})
+	healthy := mockAgent("3f2a", &types.ContainerState{Running: true, Health: &types.Health{Status: types.Healthy}})
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerInspect(gomock.Any(), "agent").Return(starting, nil)
+	client.EXPECT().ContainerInspect(gomock.Any(), "agent").Return(healthy, nil).Times(2)
+
+	i := installer{readyPeriod: time.Nanosecond, readyInterval: time.Millisecond}
+	if err := i.waitAgent(context.Background(), client); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitAgent_Restarted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	agent := mockAgent("3f2a", &types.ContainerState{Running: true, ExitCode: 1})
+	agent.RestartCount = 2
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerInspect(gomock.Any(), "agent").Return(agent, nil)
+
+	i := installer{readyPeriod: time.Nanosecond, readyInterval: time.Millisecond}
+	if err := i.waitAgent(context.Background(), client); err == nil {
+		t.Errorf("Want error when the agent container restarts")
+	}
+}
+
+func TestWaitAgent_Unhealthy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	agent := mockAgent("3f2a", &types.ContainerState{Running: true, Health: &types.Health{Status: types.Unhealthy}})
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerInspect(gomock.Any(), "agent").Return(agent, nil)
+
+	i := installer{readyPeriod: time.Nanosecond, readyInterval: time.Millisecond}
+	if err := i.waitAgent(context.Background(), client); err == nil {
+		t.Errorf("Want error when the agent container is unhealthy")
+	}
+}
+
+func TestWaitAgent_Timeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	agent := mockAgent("3f2a", &types.ContainerState{Status: "created"})
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerInspect(gomock.Any(), "agent").Return(agent, nil).AnyTimes()
+
+	i := installer{
+		readyTimeout:  10 * time.Millisecond,
+		readyPeriod:   time.Nanosecond,
+		readyInterval: time.Millisecond,
+	}
+	if err := i.waitAgent(context.Background(), client); err != errAgentNotReady {
+		t.Errorf("Want agent not ready error, got %v", err)
+	}
+}
+
+func mockAgent(id string, state *types.ContainerState) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			State: state,
+		},
+	}
+}
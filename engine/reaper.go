@@ -62,6 +62,42 @@ func (r *reaper) Reap(ctx context.Context) error {
 	return nil
 }
 
+// Expire destroys the errored servers that have not been
+// updated since the given unix timestamp, and soft-deletes
+// the server records. The servers are expired regardless of
+// the reaper feature flag, one server at a time.
+func (r *reaper) Expire(ctx context.Context, before int64) error {
+	logger := log.Ctx(ctx)
+
+	servers, err := r.servers.ListState(ctx, autoscaler.StateError)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot fetch server list")
+		return err
+	}
+
+	for _, server := range servers {
+		if server.Updated >= before {
+			continue
+		}
+		if err := r.reap(ctx, server); err != nil {
+			continue
+		}
+		server.Deleted = time.Now().Unix()
+		err := r.servers.Update(ctx, server)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", server.Name).
+				Msg("cannot delete expired server")
+			continue
+		}
+		logger.Info().
+			Str("server", server.Name).
+			Msg("expired errored server")
+	}
+	return nil
+}
+
 func (r *reaper) reap(ctx context.Context, server *autoscaler.Server) error {
 	logger := log.Ctx(ctx)
 	logger.Debug().
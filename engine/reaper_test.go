@@ -3,3 +3,86 @@
 // that can be found in the LICENSE file.
 
 package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestExpire(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+	before := now.Add(-time.Hour).Unix()
+
+	mockctx := context.Background()
+	mockExpired := &autoscaler.Server{Name: "agent-1", ID: "i-5203422c", State: autoscaler.StateError, Updated: before - 60}
+	mockUnprovisioned := &autoscaler.Server{Name: "agent-2", State: autoscaler.StateError, Updated: before - 60}
+	mockRecent := &autoscaler.Server{Name: "agent-3", ID: "i-5203422d", State: autoscaler.StateError, Updated: now.Unix()}
+	mockServers := []*autoscaler.Server{mockExpired, mockUnprovisioned, mockRecent}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateError).Return(mockServers, nil)
+	store.EXPECT().Update(mockctx, mockExpired).Return(nil).Times(2)
+	store.EXPECT().Update(mockctx, mockUnprovisioned).Return(nil).Times(2)
+
+	provider := mocks.NewMockProvider(controller)
+	provider.EXPECT().Destroy(mockctx, gomock.Any()).Return(autoscaler.ErrInstanceNotFound)
+
+	r := reaper{
+		servers:  store,
+		provider: provider,
+	}
+	if err := r.Expire(mockctx, before); err != nil {
+		t.Error(err)
+	}
+
+	for _, server := range []*autoscaler.Server{mockExpired, mockUnprovisioned} {
+		if got, want := server.State, autoscaler.StateStopped; got != want {
+			t.Errorf("Want server state %s, got %s", want, got)
+		}
+		if server.Deleted == 0 {
+			t.Errorf("Want expired server %s soft-deleted", server.Name)
+		}
+	}
+	if got, want := mockRecent.State, autoscaler.StateError; got != want {
+		t.Errorf("Want recent server state %s, got %s", want, got)
+	}
+	if mockRecent.Deleted != 0 {
+		t.Errorf("Want recent server retained")
+	}
+}
+
+// this test verifies the server record is retained when the
+// instance cannot be destroyed.
+func TestExpire_DestroyError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", ID: "i-5203422c", State: autoscaler.StateError}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateError).Return([]*autoscaler.Server{mockServer}, nil)
+
+	provider := mocks.NewMockProvider(controller)
+	provider.EXPECT().Destroy(mockctx, gomock.Any()).Return(context.DeadlineExceeded)
+
+	r := reaper{
+		servers:  store,
+		provider: provider,
+	}
+	if err := r.Expire(mockctx, time.Now().Unix()); err != nil {
+		t.Error(err)
+	}
+	if mockServer.Deleted != 0 {
+		t.Errorf("Want server retained when the instance cannot be destroyed")
+	}
+}
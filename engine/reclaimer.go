@@ -0,0 +1,108 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+//
+// The reclaimer looks for running servers that were stopped
+// or reclaimed by the provider (e.g. preempted spot instances)
+// and retires them, so that the planner can allocate
+// replacement capacity. This is only enabled for providers
+// that implement the optional Inspector interface.
+//
+
+type reclaimer struct {
+	wg sync.WaitGroup
+
+	servers  autoscaler.ServerStore
+	provider autoscaler.Provider
+}
+
+func (r *reclaimer) Reclaim(ctx context.Context) error {
+	inspector, ok := r.provider.(autoscaler.Inspector)
+	if !ok {
+		return nil
+	}
+
+	servers, err := r.servers.ListState(ctx, autoscaler.StateRunning)
+	if err != nil {
+		return err
+	}
+
+	for _, server := range servers {
+		r.wg.Add(1)
+		go func(server *autoscaler.Server) {
+			r.reclaim(ctx, inspector, server)
+			r.wg.Done()
+		}(server)
+	}
+	return nil
+}
+
+func (r *reclaimer) reclaim(ctx context.Context, inspector autoscaler.Inspector, server *autoscaler.Server) error {
+	logger := log.Ctx(ctx).With().
+		Str("server", server.Name).
+		Logger()
+
+	in := &autoscaler.Instance{
+		ID:       server.ID,
+		Provider: server.Provider,
+		Name:     server.Name,
+		Address:  server.Address,
+		Region:   server.Region,
+		Image:    server.Image,
+		Size:     server.Size,
+	}
+
+	_, reason := inspector.Inspect(ctx, in)
+	switch reason {
+	case nil:
+		return nil
+	case autoscaler.ErrInstanceNotFound, autoscaler.ErrInstanceReclaimed:
+		// the instance was reclaimed and needs to be
+		// replaced. handled below.
+	default:
+		logger.Warn().Err(reason).
+			Msg("cannot inspect server")
+		return reason
+	}
+
+	server, err := r.servers.Find(ctx, server.Name)
+	if err != nil {
+		return err
+	}
+	if server.State != autoscaler.StateRunning {
+		// if the server was mutated by another goroutine
+		// we should exit without making any changes.
+		return nil
+	}
+
+	if reason == autoscaler.ErrInstanceNotFound {
+		logger.Info().
+			Msg("server no longer exists")
+
+		server.Error = "Instance no longer exists"
+		server.Stopped = time.Now().Unix()
+		server.State = autoscaler.StateStopped
+	} else {
+		logger.Info().
+			Msg("server reclaimed by provider")
+
+		// the instance still exists in a stopped state and
+		// must be destroyed by the collector.
+		server.Error = "Instance reclaimed by provider"
+		server.State = autoscaler.StateShutdown
+	}
+	return r.servers.Update(ctx, server)
+}
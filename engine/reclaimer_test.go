@@ -0,0 +1,140 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+// inspectingProvider combines a mock provider and a mock
+// inspector to emulate a provider with inspection support.
+type inspectingProvider struct {
+	*mocks.MockProvider
+	*mocks.MockInspector
+}
+
+func TestReclaim_Preempted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+	mockServers := []*autoscaler.Server{mockServer}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return(mockServers, nil)
+	store.EXPECT().Find(mockctx, mockServer.Name).Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Return(nil)
+
+	inspector := mocks.NewMockInspector(controller)
+	inspector.EXPECT().Inspect(mockctx, gomock.Any()).Return(nil, autoscaler.ErrInstanceReclaimed)
+
+	r := reclaimer{
+		servers: store,
+		provider: inspectingProvider{
+			MockProvider:  mocks.NewMockProvider(controller),
+			MockInspector: inspector,
+		},
+	}
+	err := r.Reclaim(mockctx)
+	r.wg.Wait()
+
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateShutdown; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+func TestReclaim_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+	mockServers := []*autoscaler.Server{mockServer}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return(mockServers, nil)
+	store.EXPECT().Find(mockctx, mockServer.Name).Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Return(nil)
+
+	inspector := mocks.NewMockInspector(controller)
+	inspector.EXPECT().Inspect(mockctx, gomock.Any()).Return(nil, autoscaler.ErrInstanceNotFound)
+
+	r := reclaimer{
+		servers: store,
+		provider: inspectingProvider{
+			MockProvider:  mocks.NewMockProvider(controller),
+			MockInspector: inspector,
+		},
+	}
+	err := r.Reclaim(mockctx)
+	r.wg.Wait()
+
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateStopped; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+	if mockServer.Stopped == 0 {
+		t.Errorf("Want server stopped timestamp set")
+	}
+}
+
+func TestReclaim_Running(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+	mockServers := []*autoscaler.Server{mockServer}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return(mockServers, nil)
+
+	inspector := mocks.NewMockInspector(controller)
+	inspector.EXPECT().Inspect(mockctx, gomock.Any()).Return(&autoscaler.Instance{}, nil)
+
+	r := reclaimer{
+		servers: store,
+		provider: inspectingProvider{
+			MockProvider:  mocks.NewMockProvider(controller),
+			MockInspector: inspector,
+		},
+	}
+	err := r.Reclaim(mockctx)
+	r.wg.Wait()
+
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+// this test verifies the reclaimer is a no-op when the
+// provider does not support inspection.
+func TestReclaim_NotSupported(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	r := reclaimer{
+		servers:  mocks.NewMockServerStore(controller),
+		provider: mocks.NewMockProvider(controller),
+	}
+	if err := r.Reclaim(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
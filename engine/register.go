@@ -0,0 +1,118 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api/types"
+	"github.com/rs/zerolog/log"
+)
+
+// default registration timeout of the agent.
+const defaultRegisterTimeout = 5 * time.Minute
+
+// registerPatterns defines the message logged by the agent
+// images once the Drone server accepts the ping request, by
+// image repository and major version. The Drone server does
+// not expose the connected agents, so the agent logs are the
+// only record of the registration.
+var registerPatterns = map[string]string{
+	"drone/drone-runner-docker:1":      "successfully pinged the remote server",
+	"drone/drone-runner-docker:latest": "successfully pinged the remote server",
+	"drone/drone-runner-podman:1":      "successfully pinged the remote server",
+	"drone/drone-runner-podman:latest": "successfully pinged the remote server",
+}
+
+// errAgentNotRegistered is returned when the agent does not
+// connect to the Drone server before the registration timeout,
+// for example, when the rpc secret is not valid.
+var errAgentNotRegistered = errors.New("Agent did not connect to the Drone server")
+
+// helper function waits until the agent connects to the Drone
+// server. The agent logs a message once the Drone server
+// accepts the ping request, which requires a valid rpc secret,
+// so the agent logs are polled until the message is found. The
+// check is skipped if the message logged by the agent image is
+// not known, and no pattern is configured.
+func (i *installer) waitRegistered(ctx context.Context, client docker.APIClient, image string) error {
+	if !i.registerCheck {
+		return nil
+	}
+
+	pattern := i.registerPattern
+	if pattern == "" {
+		pattern = registerPattern(image)
+	}
+	if pattern == "" {
+		log.Ctx(ctx).Debug().
+			Str("image", image).
+			Msg("registration message of the agent image is unknown")
+		return nil
+	}
+
+	timeout, interval := i.registerTimeout, i.readyInterval
+	if timeout == 0 {
+		timeout = defaultRegisterTimeout
+	}
+	if interval == 0 {
+		interval = defaultReadyInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		if agentLogged(ctx, client, pattern) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errAgentNotRegistered
+		case <-time.After(interval):
+		}
+	}
+}
+
+// helper function returns true if the agent container logs
+// contain the pattern.
+func agentLogged(ctx context.Context, client docker.APIClient, pattern string) bool {
+	rc, err := client.ContainerLogs(ctx, "agent", types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return false
+	}
+	defer rc.Close()
+	out, _ := ioutil.ReadAll(rc)
+	return bytes.Contains(out, []byte(pattern))
+}
+
+// helper function returns the registration message logged by
+// the agent image, or an empty string if the message is not
+// known for the image repository and major version.
+func registerPattern(image string) string {
+	image = strings.TrimPrefix(image, "docker.io/")
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	repo, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repo, tag = image[:i], image[i+1:]
+	}
+	// the major version is the tag up to the first separator,
+	// for example 1 for the tags 1.8.3 and 1-linux-amd64.
+	if i := strings.IndexAny(tag, ".-"); i != -1 {
+		tag = tag[:i]
+	}
+	return registerPatterns[repo+":"+tag]
+}
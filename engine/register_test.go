@@ -0,0 +1,88 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestWaitRegistered(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	pinging := "level=error msg=\"cannot ping the remote server\" error=\"Unauthorized\"\n"
+	pinged := pinging + "level=info msg=\"successfully pinged the remote server\"\n"
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerLogs(gomock.Any(), "agent", gomock.Any()).Return(ioutil.NopCloser(bytes.NewBufferString(pinging)), nil)
+	client.EXPECT().ContainerLogs(gomock.Any(), "agent", gomock.Any()).Return(ioutil.NopCloser(bytes.NewBufferString(pinged)), nil)
+
+	i := installer{registerCheck: true, readyInterval: time.Millisecond}
+	if err := i.waitRegistered(context.Background(), client, "drone/drone-runner-docker:1"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitRegistered_Timeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerLogs(gomock.Any(), "agent", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil).AnyTimes()
+
+	i := installer{
+		registerCheck:   true,
+		registerTimeout: 10 * time.Millisecond,
+		readyInterval:   time.Millisecond,
+	}
+	if err := i.waitRegistered(context.Background(), client, "drone/drone-runner-docker:1"); err != errAgentNotRegistered {
+		t.Errorf("Want agent not registered error, got %v", err)
+	}
+}
+
+func TestWaitRegistered_Disabled(t *testing.T) {
+	i := installer{}
+	if err := i.waitRegistered(context.Background(), nil, "drone/drone-runner-docker:1"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitRegistered_UnknownImage(t *testing.T) {
+	i := installer{registerCheck: true}
+	if err := i.waitRegistered(context.Background(), nil, "drone/agent:1"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegisterPattern(t *testing.T) {
+	const pinged = "successfully pinged the remote server"
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "drone/drone-runner-docker", want: pinged},
+		{image: "drone/drone-runner-docker:1", want: pinged},
+		{image: "drone/drone-runner-docker:1.8.3", want: pinged},
+		{image: "drone/drone-runner-docker:1-linux-amd64", want: pinged},
+		{image: "docker.io/drone/drone-runner-docker:1.8", want: pinged},
+		{image: "drone/drone-runner-docker@sha256:4a5b1a", want: pinged},
+		{image: "drone/drone-runner-docker:2", want: ""},
+		{image: "drone/agent:1", want: ""},
+		{image: "registry.company.com:5000/drone-runner-docker:1", want: ""},
+	}
+	for _, test := range tests {
+		if got := registerPattern(test.image); got != test.want {
+			t.Errorf("Want pattern %q for image %s, got %q", test.want, test.image, got)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"docker.io/go-docker/api/types"
+)
+
+// defaultRegistry is the address of the docker hub registry,
+// used when the image name does not include a registry.
+const defaultRegistry = "https://index.docker.io/v1/"
+
+// helper function returns the encoded registry credentials
+// used to pull the agent image, or an empty string if the
+// registry does not require authentication. If a credential
+// helper is configured, the credentials are retrieved from
+// the helper, which is useful for registries that issue
+// short-lived tokens (e.g. ecr).
+func (i *installer) registryAuth(image string) (string, error) {
+	if i.registryHelper == "" && i.registryUser == "" {
+		return "", nil
+	}
+	auth := types.AuthConfig{
+		Username:      i.registryUser,
+		Password:      i.registryPass,
+		ServerAddress: registryHost(image),
+	}
+	if i.registryHelper != "" {
+		var err error
+		auth.Username, auth.Password, err = credentialHelper(i.registryHelper, auth.ServerAddress)
+		if err != nil {
+			return "", err
+		}
+	}
+	out, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(out), nil
+}
+
+// helper function returns the encoded registry credentials
+// of an image installed alongside the agent. The credentials
+// are only sent to the registry of the agent image.
+func (i *installer) imageAuth(image string) (string, error) {
+	if registryHost(image) != registryHost(i.agentImage()) {
+		return "", nil
+	}
+	return i.registryAuth(image)
+}
+
+// helper function returns the registry hostname of the image.
+func registryHost(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return defaultRegistry
+	}
+	if !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost" {
+		return defaultRegistry
+	}
+	return parts[0]
+}
+
+// helper function retrieves the registry credentials from the
+// named docker credential helper, using the docker credential
+// helper protocol.
+func credentialHelper(helper, registry string) (username, password string, err error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(registry)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+	creds := struct {
+		Username string
+		Secret   string
+	}{}
+	err = json.Unmarshal(out, &creds)
+	return creds.Username, creds.Secret, err
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"docker.io/go-docker/api/types"
+)
+
+func TestRegistryAuth(t *testing.T) {
+	i := installer{
+		image:        "ghcr.io/company/agent:1",
+		registryUser: "octocat",
+		registryPass: "correct-horse-battery-staple",
+	}
+	encoded, err := i.registryAuth(i.image)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	decoded, err := base64.URLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	auth := types.AuthConfig{}
+	json.Unmarshal(decoded, &auth)
+	if got, want := auth.Username, "octocat"; got != want {
+		t.Errorf("Want username %q, got %q", want, got)
+	}
+	if got, want := auth.Password, "correct-horse-battery-staple"; got != want {
+		t.Errorf("Want password %q, got %q", want, got)
+	}
+	if got, want := auth.ServerAddress, "ghcr.io"; got != want {
+		t.Errorf("Want server address %q, got %q", want, got)
+	}
+}
+
+func TestRegistryAuth_Anonymous(t *testing.T) {
+	i := installer{image: "drone/agent:1"}
+	auth, err := i.registryAuth(i.image)
+	if err != nil {
+		t.Error(err)
+	}
+	if auth != "" {
+		t.Errorf("Want empty registry credentials, got %q", auth)
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	tests := []struct {
+		image string
+		host  string
+	}{
+		{"drone/agent:1", defaultRegistry},
+		{"alpine", defaultRegistry},
+		{"ghcr.io/company/agent:1", "ghcr.io"},
+		{"123456789012.dkr.ecr.us-east-1.amazonaws.com/agent", "123456789012.dkr.ecr.us-east-1.amazonaws.com"},
+		{"localhost/agent", "localhost"},
+		{"registry:5000/agent", "registry:5000"},
+	}
+	for _, test := range tests {
+		if got, want := registryHost(test.image), test.host; got != want {
+			t.Errorf("Want registry %q for image %s, got %q", want, test.image, got)
+		}
+	}
+}
+
+func TestImageAuth(t *testing.T) {
+	i := installer{
+		image:        "ghcr.io/company/agent:1",
+		registryUser: "octocat",
+		registryPass: "correct-horse-battery-staple",
+	}
+	if auth, _ := i.imageAuth("ghcr.io/company/golang:1.12"); auth == "" {
+		t.Errorf("Want registry credentials for the agent image registry")
+	}
+	if auth, _ := i.imageAuth("drone/gc"); auth != "" {
+		t.Errorf("Want registry credentials withheld from other registries, got %q", auth)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drone/autoscaler"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api/types"
+	"github.com/rs/zerolog/log"
+)
+
+// errRepairState is returned when repairing a server that is
+// not running, or not in an error state.
+var errRepairState = errors.New("Server cannot be repaired in its current state")
+
+// helper function re-runs the install sequence on the server,
+// and refreshes the docker tls certificate once the agent is
+// running.
+func (e *engine) repair(ctx context.Context, server *autoscaler.Server) {
+	logger := log.Ctx(ctx).With().
+		Str("server", server.Name).
+		Logger()
+
+	err := e.installer.install(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot repair server")
+		return
+	}
+	if !e.rotator.supported(server) {
+		logger.Info().
+			Msg("server repaired")
+		return
+	}
+	if err := e.rotator.cordon(ctx, server); err != nil {
+		return
+	}
+	if err := e.rotator.rotate(ctx, server); err != nil {
+		return
+	}
+	logger.Info().
+		Msg("server repaired")
+}
+
+// helper function removes the containers of a previous
+// install, so that the install sequence can be repeated to
+// repair a server.
+func (i *installer) removeContainers(ctx context.Context, client docker.APIClient) {
+	for _, name := range []string{"agent", "drone-gc", "watchtower"} {
+		err := client.ContainerRemove(ctx, name, types.ContainerRemoveOptions{Force: true})
+		if err != nil && !docker.IsErrNotFound(err) {
+			log.Ctx(ctx).Debug().Err(err).
+				Str("container", name).
+				Msg("cannot remove container")
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestRepair_State(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateShutdown}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+	e := engine{installer: &installer{servers: store}}
+	if err := e.Repair(mockctx, "agent-1"); err != errRepairState {
+		t.Errorf("Want repair state error, got %v", err)
+	}
+}
+
+func TestRepair_Pool(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateError, Pool: "arm64"}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+	e := engine{installer: &installer{servers: store}}
+	if err := e.Repair(mockctx, "agent-1"); err != autoscaler.ErrServerNotFound {
+		t.Errorf("Want server assigned to another pool not found, got %v", err)
+	}
+}
+
+func TestRepair_ResetPhase(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockErr := errors.New("cannot update server")
+	mockServer := &autoscaler.Server{
+		Name:  "agent-1",
+		State: autoscaler.StateError,
+		Phase: phaseAgent,
+		Error: "cannot start agent",
+	}
+
+	// the update fails, so that the install sequence is not
+	// started in the background.
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Return(mockErr)
+
+	e := engine{installer: &installer{servers: store}}
+	if err := e.Repair(mockctx, "agent-1"); err != mockErr {
+		t.Errorf("Want update error, got %v", err)
+	}
+	if got, want := mockServer.State, autoscaler.StateStaging; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+	if got := mockServer.Phase; got != "" {
+		t.Errorf("Want install phase reset, got %q", got)
+	}
+	if got := mockServer.Error; got != "" {
+		t.Errorf("Want server error cleared, got %q", got)
+	}
+}
+
+func TestRemoveContainers(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerRemove(mockctx, "agent", gomock.Any()).Return(nil)
+	client.EXPECT().ContainerRemove(mockctx, "drone-gc", gomock.Any()).Return(errors.New("No such container"))
+	client.EXPECT().ContainerRemove(mockctx, "watchtower", gomock.Any()).Return(nil)
+
+	i := installer{}
+	i.removeContainers(mockctx, client)
+}
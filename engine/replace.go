@@ -0,0 +1,210 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/dchest/uniuri"
+	"github.com/rs/zerolog/log"
+)
+
+// default replace settings.
+const (
+	defaultReplaceTimeout  = time.Hour
+	defaultReplaceInterval = 10 * time.Second
+)
+
+var (
+	// errReplaceState is returned when replacing a server that
+	// is not running, cordoned, or in an error state.
+	errReplaceState = errors.New("Server cannot be replaced in its current state")
+
+	// errReplaceFailed is returned when the replacement server
+	// cannot be provisioned.
+	errReplaceFailed = errors.New("Replacement server failed to provision")
+
+	// errReplaceTimeout is returned when the replacement server
+	// is not running before the replace timeout.
+	errReplaceTimeout = errors.New("Timeout waiting for the replacement server")
+)
+
+// Replace provisions a replacement for the named server. The
+// named server is drained and destroyed in the background once
+// the replacement is running, so that the capacity of the pool
+// is preserved throughout.
+func (e *engine) Replace(ctx context.Context, name string) (*autoscaler.Server, error) {
+	servers := e.planner.servers
+	server, err := servers.Find(ctx, name)
+	if err != nil || server.Pool != e.pool {
+		return nil, autoscaler.ErrServerNotFound
+	}
+	switch server.State {
+	case autoscaler.StateRunning, autoscaler.StateCordoned, autoscaler.StateError:
+	default:
+		return nil, errReplaceState
+	}
+
+	replacement := &autoscaler.Server{
+		Name:     "agent-" + uniuri.NewLen(8),
+		State:    autoscaler.StatePending,
+		Secret:   uniuri.New(),
+		Capacity: e.planner.cap,
+		Labels:   autoscaler.Labels(e.planner.labels),
+	}
+	err = servers.Create(ctx, replacement)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Str("server", name).
+			Msg("cannot create replacement server")
+		return nil, err
+	}
+	go e.replace(ctx, server, replacement)
+	return replacement, nil
+}
+
+// helper function waits until the replacement server is
+// running, and then cordons the server, drains the running
+// builds and marks the server for shutdown. The server is
+// retained if the replacement cannot be provisioned, and the
+// replacement is destroyed if the server cannot be drained.
+func (e *engine) replace(ctx context.Context, server, replacement *autoscaler.Server) error {
+	logger := log.Ctx(ctx).With().
+		Str("server", server.Name).
+		Str("replacement", replacement.Name).
+		Logger()
+
+	servers := e.planner.servers
+	err := waitRunning(ctx, servers, replacement.Name, 0, e.upgrader.interval)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot replace server")
+		return err
+	}
+
+	// the server is reloaded, since the state may have changed
+	// while the replacement was provisioned.
+	server, err = servers.Find(ctx, server.Name)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot find server")
+		return err
+	}
+
+	switch server.State {
+	case autoscaler.StateRunning, autoscaler.StateCordoned:
+		// the agent is paused, unless the server is already
+		// cordoned, so that the server does not accept new
+		// builds while the running builds complete. The
+		// server is still replaced if the agent cannot be
+		// paused, since the docker daemon may be degraded.
+		prev := server.State
+		paused := false
+		if prev == autoscaler.StateRunning {
+			paused = pauseAgent(logger.WithContext(ctx), e.installer, server) == nil
+		}
+		// the server is moved to the staging state, which
+		// excludes the server from scale down and pinging
+		// while the running builds complete.
+		server.State = autoscaler.StateStaging
+		err = servers.Update(ctx, server)
+		if err != nil {
+			logger.Error().Err(err).
+				Msg("cannot update server state")
+			return err
+		}
+		err = waitIdle(ctx, e.planner, server, e.upgrader.timeout, e.upgrader.interval)
+		if err != nil {
+			logger.Warn().Err(err).
+				Msg("cannot replace server, server is busy")
+			if paused {
+				unpauseAgent(logger.WithContext(ctx), e.installer, server)
+			}
+			server.State = prev
+			servers.Update(ctx, server)
+			e.discard(ctx, replacement)
+			return err
+		}
+	case autoscaler.StateError:
+	default:
+		logger.Debug().
+			Str("state", string(server.State)).
+			Msg("server is already shutting down")
+		return nil
+	}
+
+	server.State = autoscaler.StateShutdown
+	err = servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server state")
+		return err
+	}
+
+	logger.Info().
+		Msg("server replaced")
+	return nil
+}
+
+// helper function marks the replacement server for shutdown,
+// so that the pool does not retain the capacity of both the
+// server and the replacement when the server is not replaced.
+func (e *engine) discard(ctx context.Context, replacement *autoscaler.Server) error {
+	servers := e.planner.servers
+	server, err := servers.Find(ctx, replacement.Name)
+	if err != nil {
+		return err
+	}
+	server.State = autoscaler.StateShutdown
+	err = servers.Update(ctx, server)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Str("server", server.Name).
+			Msg("cannot update server state")
+		return err
+	}
+	log.Ctx(ctx).Info().
+		Str("server", server.Name).
+		Msg("replacement server discarded")
+	return nil
+}
+
+// helper function waits until the named server is running,
+// or fails to provision, or the replace timeout is reached.
+func waitRunning(ctx context.Context, servers autoscaler.ServerStore, name string, timeout, interval time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultReplaceTimeout
+	}
+	if interval == 0 {
+		interval = defaultReplaceInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		server, err := servers.Find(ctx, name)
+		if err == nil {
+			switch server.State {
+			case autoscaler.StateRunning:
+				return nil
+			case autoscaler.StateError,
+				autoscaler.StateShutdown,
+				autoscaler.StateStopping,
+				autoscaler.StateStopped:
+				return errReplaceFailed
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return errReplaceTimeout
+		case <-time.After(interval):
+		}
+	}
+}
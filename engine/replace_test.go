@@ -0,0 +1,162 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/drone-go/drone"
+
+	docker "docker.io/go-docker"
+	"github.com/golang/mock/gomock"
+)
+
+func TestReplace_State(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateShutdown}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+	e := engine{planner: &planner{servers: store}}
+	if _, err := e.Replace(mockctx, "agent-1"); err != errReplaceState {
+		t.Errorf("Want replace state error, got %v", err)
+	}
+}
+
+func TestReplace_Pool(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning, Pool: "arm64"}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+
+	e := engine{planner: &planner{servers: store}}
+	if _, err := e.Replace(mockctx, "agent-1"); err != autoscaler.ErrServerNotFound {
+		t.Errorf("Want server assigned to another pool not found, got %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+	mockReplacement := &autoscaler.Server{Name: "agent-2", State: autoscaler.StateRunning}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-2").Return(mockReplacement, nil)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Times(2).Return(nil)
+
+	queue := mocks.NewMockClient(controller)
+	queue.EXPECT().Queue().Return([]*drone.Stage{
+		{Machine: "agent-1", Status: drone.StatusRunning},
+	}, nil)
+	queue.EXPECT().Queue().Return(nil, nil)
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerPause(gomock.Any(), "agent").Return(nil)
+
+	p := &planner{client: queue, servers: store}
+	e := engine{
+		planner: p,
+		installer: &installer{
+			servers: store,
+			client: func(*autoscaler.Server) (docker.APIClient, error) {
+				return client, nil
+			},
+		},
+		upgrader: &upgrader{planner: p, interval: time.Millisecond},
+	}
+	if err := e.replace(mockctx, mockServer, mockReplacement); err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateShutdown; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+// this test verifies that the replacement server is destroyed
+// and the server is uncordoned if the server cannot be drained,
+// so that the pool does not retain double capacity.
+func TestReplace_Busy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+	mockReplacement := &autoscaler.Server{Name: "agent-2", State: autoscaler.StateRunning}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-2").Return(mockReplacement, nil).Times(2)
+	store.EXPECT().Find(mockctx, "agent-1").Return(mockServer, nil)
+	store.EXPECT().Update(mockctx, mockServer).Times(2).Return(nil)
+	store.EXPECT().Update(mockctx, mockReplacement).Return(nil)
+
+	queue := mocks.NewMockClient(controller)
+	queue.EXPECT().Queue().Return([]*drone.Stage{
+		{Machine: "agent-1", Status: drone.StatusRunning},
+	}, nil).AnyTimes()
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerPause(gomock.Any(), "agent").Return(nil)
+	client.EXPECT().ContainerUnpause(gomock.Any(), "agent").Return(nil)
+
+	p := &planner{client: queue, servers: store}
+	e := engine{
+		planner: p,
+		installer: &installer{
+			servers: store,
+			client: func(*autoscaler.Server) (docker.APIClient, error) {
+				return client, nil
+			},
+		},
+		upgrader: &upgrader{planner: p, timeout: 10 * time.Millisecond, interval: time.Millisecond},
+	}
+	if err := e.replace(mockctx, mockServer, mockReplacement); err != errDrainTimeout {
+		t.Errorf("Want drain timeout error, got %v", err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state restored to %s, got %s", want, got)
+	}
+	if got, want := mockReplacement.State, autoscaler.StateShutdown; got != want {
+		t.Errorf("Want replacement state %s, got %s", want, got)
+	}
+}
+
+func TestReplace_Failed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+	mockReplacement := &autoscaler.Server{Name: "agent-2", State: autoscaler.StateError}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-2").Return(mockReplacement, nil)
+
+	e := engine{
+		planner:  &planner{servers: store},
+		upgrader: &upgrader{interval: time.Millisecond},
+	}
+	if err := e.replace(mockctx, mockServer, mockReplacement); err != errReplaceFailed {
+		t.Errorf("Want replace failed error, got %v", err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state retained, got %s", got)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/engine/certs"
+
+	docker "docker.io/go-docker"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// errRotateState is returned when rotating the certificate
+	// of a server that is not running.
+	errRotateState = errors.New("Server is not running")
+
+	// errRotateUnsupported is returned when rotating the
+	// certificate of a server that does not use tls, or if
+	// rotation is not supported by the server os.
+	errRotateUnsupported = errors.New("Certificate rotation is not supported by the server")
+)
+
+// rotateScript replaces the docker daemon tls certificate and
+// key on the host, and restarts the docker daemon. The restart
+// is delayed, so that the container running the script exits
+// before the daemon is restarted.
+const rotateScript = `set -e
+printf '%s\n' "$DOCKER_TLS_CERT" > /etc/docker/server-cert.pem.new
+printf '%s\n' "$DOCKER_TLS_KEY" > /etc/docker/server-key.pem.new
+chmod 0600 /etc/docker/server-key.pem.new
+mv /etc/docker/server-cert.pem.new /etc/docker/server-cert.pem
+mv /etc/docker/server-key.pem.new /etc/docker/server-key.pem
+systemd-run --on-active=5 systemctl restart docker
+`
+
+// a rotator regenerates the docker tls certificates of the
+// running servers, signed by the certificate authority of the
+// server, and pushes the certificates to the host.
+type rotator struct {
+	servers   autoscaler.ServerStore
+	installer *installer
+	planner   *planner
+
+	age      time.Duration // certificate age, zero if disabled
+	timeout  time.Duration // drain timeout
+	interval time.Duration // poll interval
+}
+
+// Rotate rotates the certificates of the running servers that
+// are older than the certificate age, one server at a time.
+func (r *rotator) Rotate(ctx context.Context) error {
+	if r.age == 0 {
+		return nil
+	}
+
+	logger := log.Ctx(ctx)
+
+	servers, err := r.servers.ListState(ctx, autoscaler.StateRunning)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot fetch server list")
+		return err
+	}
+
+	for _, server := range servers {
+		if !r.supported(server) {
+			continue
+		}
+		issued, err := certIssued(server.TLSCert)
+		if err != nil {
+			logger.Warn().Err(err).
+				Str("server", server.Name).
+				Msg("cannot parse server certificate")
+			continue
+		}
+		if time.Since(issued) < r.age {
+			continue
+		}
+		if err := r.cordon(ctx, server); err != nil {
+			continue
+		}
+		r.rotate(ctx, server)
+	}
+	return nil
+}
+
+// helper function returns true if certificate rotation is
+// supported by the server.
+func (r *rotator) supported(server *autoscaler.Server) bool {
+	return len(server.TLSCert) != 0 && len(server.CAKey) != 0 && r.installer.os != "windows"
+}
+
+// helper function moves the server to the staging state, which
+// excludes the server from scale down and pinging while the
+// certificate is rotated.
+func (r *rotator) cordon(ctx context.Context, server *autoscaler.Server) error {
+	if server.State != autoscaler.StateRunning {
+		return errRotateState
+	}
+	if !r.supported(server) {
+		return errRotateUnsupported
+	}
+	server.State = autoscaler.StateStaging
+	err := r.servers.Update(ctx, server)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Str("server", server.Name).
+			Msg("cannot update server state")
+	}
+	return err
+}
+
+// helper function rotates the certificate of the cordoned
+// server. The docker daemon is restarted once the running
+// builds complete, and the store is updated with the new
+// certificate before the daemon is restarted, so that the
+// store always matches the certificate on the host.
+func (r *rotator) rotate(ctx context.Context, server *autoscaler.Server) error {
+	logger := log.Ctx(ctx).With().
+		Str("server", server.Name).
+		Logger()
+
+	err := waitIdle(ctx, r.planner, server, r.timeout, r.interval)
+	if err != nil {
+		logger.Warn().Err(err).
+			Msg("cannot rotate certificate, server is busy")
+		server.State = autoscaler.StateRunning
+		r.servers.Update(ctx, server)
+		return err
+	}
+
+	cert, err := certs.GenerateCert(server.Name, &certs.Certificate{
+		Cert: server.CACert,
+		Key:  server.CAKey,
+	})
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot generate certificate")
+		return r.installer.errorUpdate(ctx, server, err)
+	}
+
+	client, err := r.installer.client(server)
+	if err != nil {
+		return r.installer.errorUpdate(ctx, server, err)
+	}
+
+	// the agent container is restarted with the docker daemon,
+	// and the start time is used to detect the restart.
+	started := agentStarted(ctx, client)
+
+	err = r.installer.runHost(ctx, client, "drone-rotate-certs", rotateScript, []string{
+		"DOCKER_TLS_CERT=" + string(cert.Cert),
+		"DOCKER_TLS_KEY=" + string(cert.Key),
+	})
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot push certificate to the server")
+		server.State = autoscaler.StateRunning
+		r.servers.Update(ctx, server)
+		return err
+	}
+
+	server.TLSCert = cert.Cert
+	server.TLSKey = cert.Key
+	err = r.servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server certificate")
+		return err
+	}
+
+	client, err = r.installer.client(server)
+	if err != nil {
+		return r.installer.errorUpdate(ctx, server, err)
+	}
+	err = r.waitRestart(ctx, client, started)
+	if err == nil {
+		err = r.installer.waitAgent(ctx, client)
+	}
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot connect after certificate rotation")
+		return r.installer.errorUpdate(ctx, server, err)
+	}
+
+	logger.Info().
+		Msg("certificate rotated")
+
+	server.State = autoscaler.StateRunning
+	return r.servers.Update(ctx, server)
+}
+
+// helper function waits until the docker daemon is restarted
+// with the rotated certificate, and the agent container is
+// restarted by the daemon.
+func (r *rotator) waitRestart(ctx context.Context, client docker.APIClient, started string) error {
+	timeout, interval := r.timeout, r.interval
+	if timeout == 0 {
+		timeout = defaultDrainTimeout
+	}
+	if interval == 0 {
+		interval = defaultDrainInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errAgentNotReady
+		case <-time.After(interval):
+		}
+		if now := agentStarted(ctx, client); now != "" && now != started {
+			return nil
+		}
+	}
+}
+
+// helper function returns the start time of the running agent
+// container, or an empty string if the agent is not running.
+func agentStarted(ctx context.Context, client docker.APIClient) string {
+	info, err := client.ContainerInspect(ctx, "agent")
+	if err != nil || info.ContainerJSONBase == nil || info.State == nil || !info.State.Running {
+		return ""
+	}
+	return info.State.StartedAt
+}
+
+// helper function returns the time the certificate was issued.
+func certIssued(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, errors.New("Invalid certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotBefore, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/engine/certs"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestCertIssued(t *testing.T) {
+	ca, err := certs.GenerateCA()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	issued, err := certIssued(ca.Cert)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if time.Since(issued) > time.Hour {
+		t.Errorf("Want certificate issued within the hour, got %s", issued)
+	}
+	if _, err := certIssued([]byte("invalid")); err == nil {
+		t.Errorf("Want error parsing an invalid certificate")
+	}
+}
+
+func TestRotate_Disabled(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mocks.NewMockServerStore(controller)
+
+	r := rotator{servers: store, installer: &installer{}}
+	if err := r.Rotate(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRotate_Recent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ca, err := certs.GenerateCA()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	cert, err := certs.GenerateCert("agent-1", ca)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{
+		Name:    "agent-1",
+		State:   autoscaler.StateRunning,
+		CACert:  ca.Cert,
+		CAKey:   ca.Key,
+		TLSCert: cert.Cert,
+		TLSKey:  cert.Key,
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return([]*autoscaler.Server{mockServer}, nil)
+
+	r := rotator{servers: store, installer: &installer{}, age: time.Hour * 24 * 30}
+	if err := r.Rotate(mockctx); err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+func TestRotateCordon(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{
+		Name:    "agent-1",
+		State:   autoscaler.StateRunning,
+		CAKey:   []byte("key"),
+		TLSCert: []byte("cert"),
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Update(mockctx, mockServer).Return(nil)
+
+	r := rotator{servers: store, installer: &installer{}}
+	if err := r.cordon(mockctx, mockServer); err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateStaging; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+	if err := r.cordon(mockctx, mockServer); err != errRotateState {
+		t.Errorf("Want error rotating a server that is not running, got %v", err)
+	}
+}
+
+func TestRotateCordon_Unsupported(t *testing.T) {
+	r := rotator{installer: &installer{os: "windows"}}
+	server := &autoscaler.Server{
+		Name:    "agent-1",
+		State:   autoscaler.StateRunning,
+		CAKey:   []byte("key"),
+		TLSCert: []byte("cert"),
+	}
+	if err := r.cordon(context.Background(), server); err != errRotateUnsupported {
+		t.Errorf("Want error rotating a windows server, got %v", err)
+	}
+	r.installer.os = "linux"
+	server.TLSCert = nil
+	if err := r.cordon(context.Background(), server); err != errRotateUnsupported {
+		t.Errorf("Want error rotating a server without tls, got %v", err)
+	}
+}
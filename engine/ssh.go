@@ -0,0 +1,234 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/drone/autoscaler"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api"
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultScript installs docker using the docker convenience
+// script, if docker is not already installed.
+const defaultScript = "command -v docker >/dev/null 2>&1 || curl -fsSL https://get.docker.com | sh"
+
+// helper function returns the default install script, pinned
+// to the docker engine release.
+func versionScript(version string) string {
+	if version == "" {
+		return defaultScript
+	}
+	return "command -v docker >/dev/null 2>&1 || curl -fsSL https://get.docker.com | VERSION=" + shellQuote(version) + " sh"
+}
+
+// defaultPodmanScript installs podman, if podman is not already
+// installed, and enables the podman api socket. The socket is
+// accessible to the podman group, and containers with a restart
+// policy are restarted when the host is rebooted.
+const defaultPodmanScript = `set -e
+if ! command -v podman >/dev/null 2>&1; then
+  if command -v apt-get >/dev/null 2>&1; then
+    apt-get update && apt-get install -y podman
+  else
+    dnf install -y podman || yum install -y podman
+  fi
+fi
+getent group podman >/dev/null || groupadd podman
+mkdir -p /etc/systemd/system/podman.socket.d
+printf '[Socket]\nSocketMode=0660\nSocketGroup=podman\n' > /etc/systemd/system/podman.socket.d/override.conf
+systemctl daemon-reload
+systemctl enable podman.socket podman-restart.service
+systemctl restart podman.socket`
+
+// dockerUnixSocket and podmanUnixSocket are the paths of the
+// docker daemon and podman api unix sockets.
+const (
+	dockerUnixSocket = "/var/run/docker.sock"
+	podmanUnixSocket = "/run/podman/podman.sock"
+)
+
+// sshInstaller provisions servers over ssh, for environments
+// where the docker daemon must not be exposed on the network.
+// The docker daemon unix socket is forwarded over ssh.
+type sshInstaller struct {
+	user       string
+	keyfile    string
+	knownHosts string
+	script     string
+	socket     string
+	group      string
+	dial       dialFunc
+}
+
+// newSSHInstaller returns a new ssh installer. The user
+// defaults to root, and the script defaults to installing
+// docker with the docker convenience script, or podman if
+// podman provides the container runtime.
+func newSSHInstaller(user, keyfile, knownHosts, script string, podman bool, dial dialFunc) *sshInstaller {
+	s := &sshInstaller{
+		user:       user,
+		keyfile:    keyfile,
+		knownHosts: knownHosts,
+		script:     script,
+		socket:     dockerUnixSocket,
+		group:      "docker",
+		dial:       dial,
+	}
+	if podman {
+		s.socket = podmanUnixSocket
+		s.group = "podman"
+	}
+	if s.user == "" {
+		s.user = "root"
+	}
+	if s.script == "" && podman {
+		s.script = defaultPodmanScript
+	} else if s.script == "" {
+		s.script = defaultScript
+	}
+	if s.dial == nil {
+		s.dial = new(net.Dialer).DialContext
+	}
+	return s
+}
+
+// Provision runs the install script on the server. The script
+// must be idempotent, since it is run again if the installation
+// is retried.
+func (s *sshInstaller) Provision(ctx context.Context, server *autoscaler.Server) error {
+	return s.run(ctx, server, s.command())
+}
+
+// helper function runs the command on the server.
+func (s *sshInstaller) run(ctx context.Context, server *autoscaler.Server, command string) error {
+	client, err := s.connect(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	out, err := session.CombinedOutput(command)
+	installWriter(ctx).Write(out)
+	if err != nil {
+		return fmt.Errorf("install script failed: %s: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// Client returns a docker client for the server that connects
+// to the docker daemon, or the docker compatible podman api,
+// unix socket over ssh. Each connection
+// uses a separate ssh connection, which is closed with the
+// connection, and keep-alives are therefore disabled.
+func (s *sshInstaller) Client(server *autoscaler.Server) (docker.APIClient, error) {
+	dial := func(ctx context.Context, _, _ string) (net.Conn, error) {
+		client, err := s.connect(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := client.Dial("unix", s.socket)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		return &sshConn{Conn: conn, client: client}, nil
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext:       dial,
+			DisableKeepAlives: true,
+		},
+	}
+	return docker.NewClient("http://docker", api.DefaultVersion, client, nil)
+}
+
+// helper function returns the command used to run the install
+// script. If the user is not root, the script is run with sudo
+// and the user is added to the docker or podman group, so that
+// the user can access the unix socket.
+func (s *sshInstaller) command() string {
+	if s.user == "root" {
+		return s.script
+	}
+	return fmt.Sprintf("sudo sh -c %s && sudo usermod -aG %s %s",
+		shellQuote(s.script), s.group, shellQuote(s.user))
+}
+
+// helper function returns the command used to run the script
+// as root, with sudo if the user is not root.
+func (s *sshInstaller) sudo(script string) string {
+	if s.user == "root" {
+		return script
+	}
+	return "sudo sh -c " + shellQuote(script)
+}
+
+// helper function connects to the server ssh daemon. If no
+// known hosts file is set, the host key presented on the first
+// connection is pinned to the server, and is stored with the
+// server when the server is next updated.
+func (s *sshInstaller) connect(ctx context.Context, server *autoscaler.Server) (*ssh.Client, error) {
+	callback, err := hostKeyCallback(s.knownHosts, server.HostKey, func(key string) {
+		server.HostKey = key
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the managed ssh key of the server is used if the key
+	// was generated by the autoscaler.
+	var config *ssh.ClientConfig
+	if len(server.SSHKey) != 0 {
+		config, err = sshKeyConfig(s.user, server.SSHKey, callback)
+	} else {
+		config, err = sshConfig(s.user, s.keyfile, callback)
+	}
+	if err != nil {
+		return nil, err
+	}
+	addr := net.JoinHostPort(server.Address, "22")
+	conn, err := s.dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// sshConn is a connection forwarded over ssh that closes the
+// ssh connection when closed.
+type sshConn struct {
+	net.Conn
+	client *ssh.Client
+}
+
+func (c *sshConn) Close() error {
+	c.Conn.Close()
+	return c.client.Close()
+}
+
+// helper function quotes the string for the posix shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
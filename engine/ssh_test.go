@@ -0,0 +1,143 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/drone/autoscaler"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHInstaller_Defaults(t *testing.T) {
+	s := newSSHInstaller("", "/root/.ssh/id_rsa", "", "", false, nil)
+	if got, want := s.user, "root"; got != want {
+		t.Errorf("Want default user %q, got %q", want, got)
+	}
+	if got, want := s.script, defaultScript; got != want {
+		t.Errorf("Want default script %q, got %q", want, got)
+	}
+	if s.dial == nil {
+		t.Errorf("Want default dial function")
+	}
+	if got, want := s.command(), defaultScript; got != want {
+		t.Errorf("Want script run as root, got %q", got)
+	}
+}
+
+func TestSSHInstaller_Sudo(t *testing.T) {
+	s := newSSHInstaller("ubuntu", "/root/.ssh/id_rsa", "", "echo 'hello'", false, nil)
+	want := `sudo sh -c 'echo '\''hello'\''' && sudo usermod -aG docker 'ubuntu'`
+	if got := s.command(); got != want {
+		t.Errorf("Want command %s, got %s", want, got)
+	}
+}
+
+func TestSSHInstaller_Podman(t *testing.T) {
+	s := newSSHInstaller("fedora", "/root/.ssh/id_rsa", "", "", true, nil)
+	if got, want := s.socket, "/run/podman/podman.sock"; got != want {
+		t.Errorf("Want podman socket %q, got %q", want, got)
+	}
+	if got, want := s.script, defaultPodmanScript; got != want {
+		t.Errorf("Want default podman script, got %q", got)
+	}
+	if got := s.command(); !strings.HasSuffix(got, "&& sudo usermod -aG podman 'fedora'") {
+		t.Errorf("Want user added to the podman group, got %s", got)
+	}
+}
+
+func TestVersionScript(t *testing.T) {
+	if got, want := versionScript(""), defaultScript; got != want {
+		t.Errorf("Want default script %q, got %q", want, got)
+	}
+	want := "command -v docker >/dev/null 2>&1 || curl -fsSL https://get.docker.com | VERSION='24.0.7' sh"
+	if got := versionScript("24.0.7"); got != want {
+		t.Errorf("Want script %q, got %q", want, got)
+	}
+}
+
+func TestSSHInstaller_PinHostKey(t *testing.T) {
+	key, _, err := generateSSHKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &autoscaler.Server{Name: "agent-1", Address: "10.0.0.1", SSHKey: key}
+
+	dial, done := testSSHServer(t, testHostSigner(t))
+	defer done()
+
+	s := newSSHInstaller("root", "", "", "", false, dial)
+	client, err := s.connect(context.Background(), server)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	client.Close()
+	if server.HostKey == "" {
+		t.Errorf("Want host key pinned to the server on first connect")
+	}
+
+	dial, done = testSSHServer(t, testHostSigner(t))
+	defer done()
+
+	s.dial = dial
+	if _, err := s.connect(context.Background(), server); err == nil {
+		t.Errorf("Want error when the host key does not match the pinned key")
+	}
+}
+
+// helper function starts an ssh server that presents the host
+// key, and returns a dial function that connects to the server,
+// regardless of the address.
+func testSSHServer(t *testing.T, hostKey ssh.Signer) (dialFunc, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostKey)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				server, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					ch.Reject(ssh.Prohibited, "")
+				}
+				server.Close()
+			}()
+		}
+	}()
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return new(net.Dialer).DialContext(ctx, network, listener.Addr().String())
+	}
+	return dial, func() { listener.Close() }
+}
+
+func testHostSigner(t *testing.T) ssh.Signer {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
@@ -0,0 +1,210 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshKeyScript adds the public key to, and removes the public
+// key from, the authorized keys of the ssh user.
+const sshKeyScript = `set -e
+home=$(getent passwd "$SSH_USER" | cut -d: -f6)
+keys="$home/.ssh/authorized_keys"
+mkdir -p "$home/.ssh"
+touch "$keys"
+if [ -n "$SSH_KEY_ADD" ] && ! grep -qxF "$SSH_KEY_ADD" "$keys"; then
+  printf '%s\n' "$SSH_KEY_ADD" >> "$keys"
+fi
+if [ -n "$SSH_KEY_REMOVE" ]; then
+  grep -vxF "$SSH_KEY_REMOVE" "$keys" > "$keys.new" || true
+  cat "$keys.new" > "$keys"
+  rm -f "$keys.new"
+fi
+chown "$SSH_USER" "$home/.ssh" "$keys"
+chmod 0700 "$home/.ssh"
+chmod 0600 "$keys"
+`
+
+// helper function generates an ssh keypair for the server.
+// The private key is pem encoded, with the time the key was
+// created, and the public key is returned in authorized_keys
+// format.
+func generateSSHKey() ([]byte, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, "", err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+	private := pem.EncodeToMemory(&pem.Block{
+		Type: "EC PRIVATE KEY",
+		Headers: map[string]string{
+			"Created": strconv.FormatInt(time.Now().Unix(), 10),
+		},
+		Bytes: der,
+	})
+	public, err := authorizedKey(private)
+	return private, public, err
+}
+
+// helper function returns the public key of the pem encoded
+// private key, in authorized_keys format.
+func authorizedKey(private []byte) (string, error) {
+	signer, err := ssh.ParsePrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+	public := ssh.MarshalAuthorizedKey(signer.PublicKey())
+	return strings.TrimSpace(string(public)), nil
+}
+
+// helper function returns the time the ssh key was created.
+func sshKeyCreated(private []byte) (time.Time, error) {
+	block, _ := pem.Decode(private)
+	if block == nil {
+		return time.Time{}, errors.New("Invalid ssh key")
+	}
+	created, err := strconv.ParseInt(block.Headers["Created"], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(created, 0), nil
+}
+
+// a keyRotator replaces the managed ssh keys of the running
+// servers that are older than the key age.
+type keyRotator struct {
+	servers   autoscaler.ServerStore
+	installer *installer
+
+	user string        // ssh user, defaults to root
+	age  time.Duration // key age, zero if disabled
+}
+
+// Rotate rotates the ssh keys of the running servers that are
+// older than the key age, one server at a time.
+func (k *keyRotator) Rotate(ctx context.Context) error {
+	if k.age == 0 || k.installer.os == "windows" {
+		return nil
+	}
+
+	logger := log.Ctx(ctx)
+
+	servers, err := k.servers.ListState(ctx, autoscaler.StateRunning)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot fetch server list")
+		return err
+	}
+
+	for _, server := range servers {
+		if len(server.SSHKey) == 0 {
+			continue
+		}
+		created, err := sshKeyCreated(server.SSHKey)
+		if err != nil {
+			logger.Warn().Err(err).
+				Str("server", server.Name).
+				Msg("cannot parse server ssh key")
+			continue
+		}
+		if time.Since(created) < k.age {
+			continue
+		}
+		k.rotate(ctx, server)
+	}
+	return nil
+}
+
+// helper function rotates the ssh key of the server. The new
+// key is authorized before the store is updated, and the old
+// key is removed once the new key is stored, so that the
+// server is reachable with the stored key if the rotation
+// fails.
+func (k *keyRotator) rotate(ctx context.Context, server *autoscaler.Server) error {
+	logger := log.Ctx(ctx).With().
+		Str("server", server.Name).
+		Logger()
+
+	user := k.user
+	if user == "" {
+		user = "root"
+	}
+
+	old, err := authorizedKey(server.SSHKey)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot parse server ssh key")
+		return err
+	}
+
+	private, public, err := generateSSHKey()
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot generate ssh key")
+		return err
+	}
+
+	client, err := k.installer.client(server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot create docker client")
+		return err
+	}
+	err = k.installer.runHost(ctx, client, "drone-ssh-key", sshKeyScript, []string{
+		"SSH_USER=" + user,
+		"SSH_KEY_ADD=" + public,
+	})
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot authorize the ssh key")
+		return err
+	}
+
+	server.SSHKey = private
+	err = k.servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server ssh key")
+		return err
+	}
+
+	client, err = k.installer.client(server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot create docker client")
+		return err
+	}
+	err = k.installer.runHost(ctx, client, "drone-ssh-key", sshKeyScript, []string{
+		"SSH_USER=" + user,
+		"SSH_KEY_REMOVE=" + old,
+	})
+	if err != nil {
+		logger.Warn().Err(err).
+			Msg("cannot remove the previous ssh key")
+		return err
+	}
+
+	logger.Info().
+		Msg("ssh key rotated")
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSSHKey(t *testing.T) {
+	private, public, err := generateSSHKey()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.HasPrefix(public, "ecdsa-sha2-nistp256 ") {
+		t.Errorf("Want public key in authorized_keys format, got %q", public)
+	}
+	if got, err := authorizedKey(private); err != nil || got != public {
+		t.Errorf("Want public key derived from the private key")
+	}
+	created, err := sshKeyCreated(private)
+	if err != nil {
+		t.Error(err)
+	}
+	if time.Since(created) > time.Minute {
+		t.Errorf("Want ssh key creation time, got %s", created)
+	}
+}
+
+func TestSSHKeyCreated_Invalid(t *testing.T) {
+	if _, err := sshKeyCreated([]byte("invalid")); err == nil {
+		t.Errorf("Want error parsing an invalid ssh key")
+	}
+}
+
+func TestKeyRotator_Disabled(t *testing.T) {
+	k := keyRotator{installer: &installer{}}
+	if err := k.Rotate(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
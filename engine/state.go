@@ -0,0 +1,71 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// helper function persists the paused state and the target
+// server count of the pool, if the engine is configured with
+// a pool state store, so that the state is restored by the
+// replica that is elected leader after a failover.
+func (e *engine) saveState(ctx context.Context) error {
+	if e.states == nil {
+		return nil
+	}
+	target, targeted := e.planner.getTarget()
+	if !targeted {
+		target = -1
+	}
+	state := &autoscaler.PoolState{
+		Pool:   e.poolName(),
+		Paused: e.Paused(),
+		Target: target,
+	}
+	err := e.states.Update(ctx, state)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Str("pool", state.Pool).
+			Msg("cannot persist pool state")
+	}
+	return err
+}
+
+// helper function restores the paused state and the target
+// server count of the pool, if persisted.
+func (e *engine) restoreState(ctx context.Context) {
+	if e.states == nil {
+		return
+	}
+	state, err := e.states.Find(ctx, e.poolName())
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).
+			Msg("no pool state to restore")
+		return
+	}
+	e.mu.Lock()
+	e.paused = state.Paused
+	e.mu.Unlock()
+	e.planner.setTarget(state.Target)
+
+	log.Ctx(ctx).Info().
+		Bool("paused", state.Paused).
+		Int("target", state.Target).
+		Msg("pool state restored")
+}
+
+// helper function returns the name of the pool managed by
+// the engine. The unnamed pool is named default.
+func (e *engine) poolName() string {
+	if e.pool == "" {
+		return defaultPool
+	}
+	return e.pool
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestSaveState(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	states := mocks.NewMockPoolStateStore(controller)
+	states.EXPECT().Update(gomock.Any(), &autoscaler.PoolState{Pool: "arm64", Paused: true, Target: -1}).Return(nil)
+	states.EXPECT().Update(gomock.Any(), &autoscaler.PoolState{Pool: "arm64", Paused: true, Target: 5}).Return(nil)
+
+	e := &engine{pool: "arm64", planner: &planner{}, states: states}
+	if err := e.PausePool("arm64"); err != nil {
+		t.Error(err)
+	}
+	if err := e.Scale(context.Background(), "arm64", 5); err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the paused state and the target
+// server count are restored when the replica is elected
+// leader.
+func TestRestoreState(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	states := mocks.NewMockPoolStateStore(controller)
+	states.EXPECT().Find(gomock.Any(), "default").Return(&autoscaler.PoolState{Pool: "default", Paused: true, Target: 3}, nil)
+
+	e := &engine{planner: &planner{}, states: states}
+	e.restoreState(context.Background())
+
+	if !e.Paused() {
+		t.Errorf("Want paused state restored")
+	}
+	if target, ok := e.planner.getTarget(); !ok || target != 3 {
+		t.Errorf("Want target restored, got %d", target)
+	}
+}
+
+func TestRestoreState_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	states := mocks.NewMockPoolStateStore(controller)
+	states.EXPECT().Find(gomock.Any(), "default").Return(nil, sql.ErrNoRows)
+
+	e := &engine{planner: &planner{}, states: states}
+	e.restoreState(context.Background())
+
+	if e.Paused() {
+		t.Errorf("Want engine not paused")
+	}
+	if _, ok := e.planner.getTarget(); ok {
+		t.Errorf("Want target not set")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/drone/autoscaler"
+
+	docker "docker.io/go-docker"
+)
+
+// systemdScript writes the agent systemd unit and environment
+// file to the host, and enables and starts the unit. It runs
+// in the host namespaces, entered with nsenter.
+const systemdScript = `set -e
+mkdir -p /etc/drone
+printf '%s\n' "$DRONE_AGENT_ENV" > /etc/drone/agent.env
+chmod 0600 /etc/drone/agent.env
+printf '%s\n' "$DRONE_AGENT_UNIT" > /etc/systemd/system/drone-agent.service
+systemctl daemon-reload
+systemctl enable drone-agent.service
+systemctl restart drone-agent.service
+`
+
+// helper function installs the agent as a systemd unit, which
+// runs the agent container in the foreground so that systemd
+// supervises the agent and the agent logs are sent to journald.
+// The unit is installed by a privileged container that enters
+// the host namespaces.
+func (i *installer) setupSystemd(ctx context.Context, client docker.APIClient, instance *autoscaler.Server, image string, envs, volumes []string) error {
+	return i.runHost(ctx, client, "drone-agent-systemd", systemdScript, []string{
+		"DRONE_AGENT_ENV=" + strings.Join(envs, "\n"),
+		"DRONE_AGENT_UNIT=" + i.systemdUnit(instance, image, volumes),
+	})
+}
+
+// helper function returns the agent systemd unit. The agent
+// container is excluded from watchtower updates, since the
+// container is re-created by systemd when restarted.
+func (i *installer) systemdUnit(instance *autoscaler.Server, image string, volumes []string) string {
+	binary, service := "/usr/bin/docker", "docker.service"
+	if i.podman {
+		binary, service = "/usr/bin/podman", "podman.socket"
+	}
+	args := []string{
+		binary, "run", "--rm",
+		"--name", "agent",
+		"--env-file", "/etc/drone/agent.env",
+		"--log-driver", "journald",
+	}
+	for _, volume := range volumes {
+		args = append(args, "--volume", volume)
+	}
+	if runtime := i.runtime(); runtime != "" {
+		args = append(args, "--runtime", runtime)
+	}
+	if i.cpus != 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(i.cpus, 'f', -1, 64))
+	}
+	if i.memory != 0 {
+		args = append(args, "--memory", strconv.FormatInt(i.memory, 10))
+	}
+	if i.pidsLimit != 0 {
+		args = append(args, "--pids-limit", strconv.FormatInt(i.pidsLimit, 10))
+	}
+	labels := agentLabels(instance)
+	labels["com.centurylinklabs.watchtower.enable"] = "false"
+	var keys []string
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--label", k+"="+labels[k])
+	}
+	args = append(args, image)
+
+	var quoted []string
+	for _, arg := range args {
+		quoted = append(quoted, systemdQuote(arg))
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=Drone Agent
+After=%[1]s
+Requires=%[1]s
+
+[Service]
+Restart=always
+RestartSec=5
+ExecStartPre=-%[2]s rm --force agent
+ExecStart=%[3]s
+ExecStop=%[2]s stop agent
+
+[Install]
+WantedBy=multi-user.target`, service, binary, strings.Join(quoted, " "))
+}
+
+// helper function quotes the argument for a systemd command
+// line, escaping specifier and variable expansion.
+func systemdQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "%", "%%", -1)
+	s = strings.Replace(s, "$", "$$", -1)
+	return `"` + s + `"`
+}
@@ -0,0 +1,116 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"docker.io/go-docker/api/types/container"
+	"github.com/golang/mock/gomock"
+)
+
+func TestSetupSystemd(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1"}
+
+	wait := make(chan container.ContainerWaitOKBody, 1)
+	wait <- container.ContainerWaitOKBody{StatusCode: 0}
+	errc := make(chan error)
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ImagePull(mockctx, "alpine:3", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil)
+	client.EXPECT().ContainerCreate(mockctx, gomock.Any(), gomock.Any(), gomock.Any(), "drone-agent-systemd").Return(container.ContainerCreateCreatedBody{ID: "3f2a"}, nil)
+	client.EXPECT().ContainerStart(mockctx, "3f2a", gomock.Any()).Return(nil)
+	client.EXPECT().ContainerWait(mockctx, "3f2a", container.WaitConditionNotRunning).Return((<-chan container.ContainerWaitOKBody)(wait), (<-chan error)(errc))
+	client.EXPECT().ContainerRemove(gomock.Any(), "3f2a", gomock.Any()).Return(nil)
+
+	i := installer{image: "drone/agent:1"}
+	err := i.setupSystemd(mockctx, client, mockServer, i.image, []string{"DRONE_RPC_HOST=drone.company.com"}, nil)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSetupSystemd_ExitCode(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1"}
+
+	wait := make(chan container.ContainerWaitOKBody, 1)
+	wait <- container.ContainerWaitOKBody{StatusCode: 1}
+	errc := make(chan error)
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ImagePull(mockctx, "alpine:3.18", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil)
+	client.EXPECT().ContainerCreate(mockctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(container.ContainerCreateCreatedBody{ID: "3f2a"}, nil)
+	client.EXPECT().ContainerStart(mockctx, "3f2a", gomock.Any()).Return(nil)
+	client.EXPECT().ContainerWait(mockctx, "3f2a", gomock.Any()).Return((<-chan container.ContainerWaitOKBody)(wait), (<-chan error)(errc))
+	client.EXPECT().ContainerRemove(gomock.Any(), "3f2a", gomock.Any()).Return(nil)
+
+	i := installer{image: "drone/agent:1", systemdImage: "alpine:3.18"}
+	err := i.setupSystemd(mockctx, client, mockServer, i.image, nil, nil)
+	if err == nil {
+		t.Errorf("Want error when the unit install fails")
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	i := installer{image: "drone/agent:1", gpu: true}
+	unit := i.systemdUnit(
+		&autoscaler.Server{Name: "agent-1", Size: "t3.medium"},
+		i.image,
+		[]string{"/var/run/docker.sock:/var/run/docker.sock"},
+	)
+	for _, want := range []string{
+		`"--log-driver" "journald"`,
+		`"--volume" "/var/run/docker.sock:/var/run/docker.sock"`,
+		`"--runtime" "nvidia"`,
+		`"--label" "io.drone.agent.size=t3.medium"`,
+		`"--label" "com.centurylinklabs.watchtower.enable=false"`,
+		`"drone/agent:1"`,
+		"Restart=always",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Want %s in the systemd unit, got\n%s", want, unit)
+		}
+	}
+}
+
+func TestSystemdUnit_Podman(t *testing.T) {
+	i := installer{image: "drone/agent:1", podman: true}
+	unit := i.systemdUnit(
+		&autoscaler.Server{Name: "agent-1"},
+		i.image,
+		[]string{i.dockerSocket()},
+	)
+	for _, want := range []string{
+		`"/usr/bin/podman" "run"`,
+		`"--volume" "/run/podman/podman.sock:/var/run/docker.sock"`,
+		"Requires=podman.socket",
+		"ExecStop=/usr/bin/podman stop agent",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Want %s in the systemd unit, got\n%s", want, unit)
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	if got, want := systemdQuote(`50% "$HOME"`), `"50%% \"$$HOME\""`; got != want {
+		t.Errorf("Want quoted argument %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// an updater checks the registry for a new digest of the agent
+// image tag, so that the servers can be upgraded when a new
+// agent release is published under the same tag.
+type updater struct {
+	servers   autoscaler.ServerStore
+	installer *installer
+
+	image  string // image of the last known digest
+	digest string // last known digest
+}
+
+// Check returns the digest of the agent image tag, and true if
+// the digest changed since the last accepted digest. The registry is
+// queried through the docker daemon of a running server. The
+// first digest is accepted as the baseline.
+func (u *updater) Check(ctx context.Context) (string, bool, error) {
+	logger := log.Ctx(ctx)
+
+	image := u.installer.agentImage()
+	servers, err := u.servers.ListState(ctx, autoscaler.StateRunning)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot fetch server list")
+		return "", false, err
+	}
+	if len(servers) == 0 {
+		return "", false, nil
+	}
+
+	client, err := u.installer.client(servers[0])
+	if err != nil {
+		return "", false, err
+	}
+	auth, err := u.installer.registryAuth(image)
+	if err != nil {
+		return "", false, err
+	}
+	inspect, err := client.DistributionInspect(ctx, image, auth)
+	if err != nil {
+		logger.Warn().Err(err).
+			Str("image", image).
+			Msg("cannot inspect the agent image digest")
+		return "", false, err
+	}
+
+	digest := string(inspect.Descriptor.Digest)
+	if u.image != image || u.digest == "" {
+		u.Accept(image, digest)
+		return digest, false, nil
+	}
+	return digest, digest != u.digest, nil
+}
+
+// Accept accepts the digest of the image, once the upgrade to
+// the digest is started.
+func (u *updater) Accept(image, digest string) {
+	u.image = image
+	u.digest = digest
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api/types/registry"
+	"github.com/golang/mock/gomock"
+)
+
+func TestUpdaterCheck(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServers := []*autoscaler.Server{{Name: "agent-1", State: autoscaler.StateRunning}}
+
+	v1 := registry.DistributionInspect{}
+	v1.Descriptor.Digest = "sha256:3f2a"
+	v2 := registry.DistributionInspect{}
+	v2.Descriptor.Digest = "sha256:8b1c"
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return(mockServers, nil).Times(3)
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().DistributionInspect(mockctx, "drone/agent:1", "").Return(v1, nil).Times(2)
+	client.EXPECT().DistributionInspect(mockctx, "drone/agent:1", "").Return(v2, nil)
+
+	u := updater{
+		servers: store,
+		installer: &installer{
+			image: "drone/agent:1",
+			client: func(*autoscaler.Server) (docker.APIClient, error) {
+				return client, nil
+			},
+		},
+	}
+
+	// the first digest is the baseline.
+	if _, changed, err := u.Check(mockctx); err != nil || changed {
+		t.Errorf("Want baseline digest unchanged, got changed %v, error %v", changed, err)
+	}
+	if _, changed, _ := u.Check(mockctx); changed {
+		t.Errorf("Want digest unchanged")
+	}
+	digest, changed, _ := u.Check(mockctx)
+	if !changed {
+		t.Errorf("Want digest changed")
+	}
+	if got, want := digest, "sha256:8b1c"; got != want {
+		t.Errorf("Want digest %s, got %s", want, got)
+	}
+}
+
+func TestUpdaterCheck_NoServers(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return(nil, nil)
+
+	u := updater{servers: store, installer: &installer{image: "drone/agent:1"}}
+	if _, changed, err := u.Check(mockctx); err != nil || changed {
+		t.Errorf("Want digest unchanged without running servers, got changed %v, error %v", changed, err)
+	}
+}
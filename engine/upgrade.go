@@ -0,0 +1,183 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"docker.io/go-docker/api/types"
+	"github.com/rs/zerolog/log"
+)
+
+// default upgrade settings.
+const (
+	defaultDrainTimeout  = time.Hour
+	defaultDrainInterval = 10 * time.Second
+	defaultStopTimeout   = time.Minute
+)
+
+var (
+	// errUpgradeInProgress is returned when an upgrade is
+	// requested while an upgrade is in progress.
+	errUpgradeInProgress = errors.New("Upgrade in progress")
+
+	// errUpgradePools is returned when an upgrade is requested
+	// and multiple pools are configured.
+	errUpgradePools = errors.New("Upgrade is not supported with multiple pools")
+
+	// errDrainTimeout is returned when the builds running on
+	// a server do not complete before the drain timeout.
+	errDrainTimeout = errors.New("Timeout waiting for running builds to complete")
+)
+
+// an upgrader rolls a new agent image across the running
+// servers, one server at a time.
+type upgrader struct {
+	servers   autoscaler.ServerStore
+	installer *installer
+	planner   *planner
+
+	timeout  time.Duration // drain timeout
+	interval time.Duration // drain poll interval
+}
+
+// Upgrade upgrades the agent of each running server. The
+// upgrade stops at the first server that cannot be upgraded.
+// Once every server is upgraded, new servers are installed
+// with the upgraded image.
+func (u *upgrader) Upgrade(ctx context.Context, image string) error {
+	logger := log.Ctx(ctx).With().
+		Str("image", image).
+		Logger()
+
+	servers, err := u.servers.ListState(ctx, autoscaler.StateRunning)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot fetch server list")
+		return err
+	}
+
+	for _, server := range servers {
+		err := u.upgrade(ctx, server, image)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", server.Name).
+				Msg("cannot upgrade server, upgrade aborted")
+			return err
+		}
+	}
+
+	u.installer.setAgentImage(image)
+	logger.Info().
+		Int("servers", len(servers)).
+		Msg("upgrade complete")
+	return nil
+}
+
+// helper function upgrades the agent of the server. The server
+// is moved to the staging state, which excludes the server from
+// scale down and pinging, and the agent is paused, so that the
+// server does not accept new builds while the running builds
+// complete. The agent is then restarted with the new image.
+func (u *upgrader) upgrade(ctx context.Context, server *autoscaler.Server, image string) error {
+	logger := log.Ctx(ctx).With().
+		Str("server", server.Name).
+		Str("image", image).
+		Logger()
+
+	server.State = autoscaler.StateStaging
+	err := u.servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server state")
+		return err
+	}
+
+	err = pauseAgent(logger.WithContext(ctx), u.installer, server)
+	if err != nil {
+		server.State = autoscaler.StateRunning
+		u.servers.Update(ctx, server)
+		return err
+	}
+
+	logger.Debug().
+		Msg("waiting for running builds to complete")
+
+	err = waitIdle(ctx, u.planner, server, u.timeout, u.interval)
+	if err != nil {
+		unpauseAgent(logger.WithContext(ctx), u.installer, server)
+		server.State = autoscaler.StateRunning
+		u.servers.Update(ctx, server)
+		return err
+	}
+
+	client, err := u.installer.client(server)
+	if err != nil {
+		return u.installer.errorUpdate(ctx, server, err)
+	}
+
+	// the agent systemd unit removes the previous agent
+	// container when restarted.
+	if !u.installer.systemd || u.installer.os == "windows" {
+		timeout := defaultStopTimeout
+		client.ContainerStop(ctx, "agent", &timeout)
+		err = client.ContainerRemove(ctx, "agent", types.ContainerRemoveOptions{Force: true})
+		if err != nil {
+			logger.Error().Err(err).
+				Msg("cannot remove the agent container")
+			return u.installer.errorUpdate(ctx, server, err)
+		}
+	}
+
+	err = u.installer.startAgent(ctx, client, server, image)
+	if err != nil {
+		return u.installer.errorUpdate(ctx, server, err)
+	}
+
+	err = u.installer.waitAgent(ctx, client)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("agent container is not ready")
+		return u.installer.errorUpdate(ctx, server, err)
+	}
+
+	logger.Info().
+		Msg("server upgraded")
+
+	server.State = autoscaler.StateRunning
+	return u.servers.Update(ctx, server)
+}
+
+// helper function waits until the server has no running
+// builds, or the drain timeout is reached.
+func waitIdle(ctx context.Context, planner *planner, server *autoscaler.Server, timeout, interval time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultDrainTimeout
+	}
+	if interval == 0 {
+		interval = defaultDrainInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		busy, err := planner.listBusy(ctx)
+		if err == nil {
+			if _, ok := busy[server.Name]; !ok {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return errDrainTimeout
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/drone-go/drone"
+
+	docker "docker.io/go-docker"
+	"docker.io/go-docker/api/types"
+	"docker.io/go-docker/api/types/container"
+	"github.com/golang/mock/gomock"
+)
+
+func TestUpgrade(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+	agent := mockAgent("3f2a", &types.ContainerState{Running: true})
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return([]*autoscaler.Server{mockServer}, nil)
+	store.EXPECT().Update(mockctx, mockServer).Times(2).Return(nil)
+
+	queue := mocks.NewMockClient(controller)
+	queue.EXPECT().Queue().Return([]*drone.Stage{
+		{Machine: "agent-1", Status: drone.StatusRunning},
+	}, nil)
+	queue.EXPECT().Queue().Return(nil, nil)
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerPause(gomock.Any(), "agent").Return(nil)
+	client.EXPECT().ContainerStop(mockctx, "agent", gomock.Any()).Return(nil)
+	client.EXPECT().ContainerRemove(mockctx, "agent", gomock.Any()).Return(nil)
+	client.EXPECT().ImagePull(mockctx, "drone/agent:1.1", gomock.Any()).Return(ioutil.NopCloser(new(bytes.Buffer)), nil)
+	client.EXPECT().ContainerCreate(mockctx, gomock.Any(), gomock.Any(), gomock.Any(), "agent").Return(container.ContainerCreateCreatedBody{ID: "3f2a"}, nil)
+	client.EXPECT().ContainerStart(mockctx, "3f2a", gomock.Any()).Return(nil)
+	client.EXPECT().ContainerInspect(gomock.Any(), "agent").Return(agent, nil).Times(2)
+
+	installer := &installer{
+		image:         "drone/agent:1",
+		servers:       store,
+		readyPeriod:   time.Nanosecond,
+		readyInterval: time.Millisecond,
+		client: func(*autoscaler.Server) (docker.APIClient, error) {
+			return client, nil
+		},
+	}
+	u := upgrader{
+		servers:   store,
+		installer: installer,
+		planner:   &planner{client: queue},
+		interval:  time.Millisecond,
+	}
+	if err := u.Upgrade(mockctx, "drone/agent:1.1"); err != nil {
+		t.Error(err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+	if got, want := installer.agentImage(), "drone/agent:1.1"; got != want {
+		t.Errorf("Want new servers installed with image %s, got %s", want, got)
+	}
+}
+
+func TestUpgrade_DrainTimeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateRunning}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListState(mockctx, autoscaler.StateRunning).Return([]*autoscaler.Server{mockServer}, nil)
+	store.EXPECT().Update(mockctx, mockServer).Times(2).Return(nil)
+
+	queue := mocks.NewMockClient(controller)
+	queue.EXPECT().Queue().Return([]*drone.Stage{
+		{Machine: "agent-1", Status: drone.StatusRunning},
+	}, nil).AnyTimes()
+
+	client := mocks.NewMockAPIClient(controller)
+	client.EXPECT().ContainerPause(gomock.Any(), "agent").Return(nil)
+	client.EXPECT().ContainerUnpause(gomock.Any(), "agent").Return(nil)
+
+	installer := &installer{
+		image:   "drone/agent:1",
+		servers: store,
+		client: func(*autoscaler.Server) (docker.APIClient, error) {
+			return client, nil
+		},
+	}
+	u := upgrader{
+		servers:   store,
+		installer: installer,
+		planner:   &planner{client: queue},
+		timeout:   10 * time.Millisecond,
+		interval:  time.Millisecond,
+	}
+	if err := u.Upgrade(mockctx, "drone/agent:1.1"); err != errDrainTimeout {
+		t.Errorf("Want drain timeout error, got %v", err)
+	}
+	if got, want := mockServer.State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state restored to %s, got %s", want, got)
+	}
+	if got, want := installer.agentImage(), "drone/agent:1"; got != want {
+		t.Errorf("Want image unchanged after a failed upgrade, got %s", got)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/drone-go/drone"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defines the interval at which the build queue is polled to
+// track the server usage.
+const usageInterval = time.Second * 10
+
+// a usageTracker records the number of stages executed by
+// each server, and the cumulative time each server is busy,
+// derived from the running stages in the build queue.
+type usageTracker struct {
+	client  drone.Client
+	servers autoscaler.ServerStore
+	planner *planner
+
+	last time.Time          // time of the previous poll
+	seen map[int64]struct{} // running stages of the previous poll
+}
+
+// Track polls the build queue, and increments the usage of the
+// servers executing the running stages. A stage is counted
+// once, when the stage is first seen running, and a server
+// is busy for the time elapsed since the previous poll if
+// the server is executing a stage. Stages running when the
+// tracker starts are counted, since the tracker does not know
+// if the stages were counted before a restart.
+func (u *usageTracker) Track(ctx context.Context) error {
+	logger := log.Ctx(ctx)
+
+	now := time.Now()
+	stages, err := u.client.Queue()
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot fetch queue details")
+		return err
+	}
+
+	// the elapsed time is capped, so that a server is not
+	// considered busy for the time the queue could not be
+	// polled.
+	var elapsed time.Duration
+	if !u.last.IsZero() {
+		elapsed = now.Sub(u.last)
+		if elapsed > usageInterval*2 {
+			elapsed = usageInterval * 2
+		}
+	}
+	u.last = now
+
+	counts := map[string]int{}
+	seen := map[int64]struct{}{}
+	for _, stage := range stages {
+		if stage.Status != drone.StatusRunning || stage.Machine == "" {
+			continue
+		}
+		if !u.planner.match(stage) {
+			continue
+		}
+		if _, ok := counts[stage.Machine]; !ok {
+			counts[stage.Machine] = 0
+		}
+		if _, ok := u.seen[stage.ID]; !ok {
+			counts[stage.Machine]++
+		}
+		seen[stage.ID] = struct{}{}
+	}
+	u.seen = seen
+
+	busy := int64(elapsed / time.Second)
+	for name, count := range counts {
+		if count == 0 && busy == 0 {
+			continue
+		}
+		err := u.servers.AddUsage(ctx, name, count, busy)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", name).
+				Msg("cannot update server usage")
+		}
+	}
+	return nil
+}
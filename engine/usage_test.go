@@ -0,0 +1,60 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/drone-go/drone"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestUsage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockctx := context.Background()
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return([]*drone.Stage{
+		{ID: 1, OS: "linux", Arch: "amd64", Status: drone.StatusRunning, Machine: "agent-1"},
+		{ID: 2, OS: "linux", Arch: "amd64", Status: drone.StatusRunning, Machine: "agent-1"},
+		{ID: 3, OS: "linux", Arch: "amd64", Status: drone.StatusRunning, Machine: "agent-2"},
+		{ID: 4, OS: "linux", Arch: "amd64", Status: drone.StatusPending},
+		{ID: 5, OS: "windows", Arch: "amd64", Status: drone.StatusRunning, Machine: "agent-3"},
+	}, nil)
+	client.EXPECT().Queue().Return([]*drone.Stage{
+		{ID: 2, OS: "linux", Arch: "amd64", Status: drone.StatusRunning, Machine: "agent-1"},
+		{ID: 4, OS: "linux", Arch: "amd64", Status: drone.StatusRunning, Machine: "agent-2"},
+	}, nil)
+
+	// the first poll counts the running stages, and the
+	// second poll counts the newly running stage, and the
+	// time elapsed since the first poll.
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().AddUsage(mockctx, "agent-1", 2, int64(0)).Return(nil)
+	store.EXPECT().AddUsage(mockctx, "agent-2", 1, int64(0)).Return(nil)
+	store.EXPECT().AddUsage(mockctx, "agent-1", 0, int64(20)).Return(nil)
+	store.EXPECT().AddUsage(mockctx, "agent-2", 1, int64(20)).Return(nil)
+
+	u := usageTracker{
+		client:  client,
+		servers: store,
+		planner: &planner{os: "linux", arch: "amd64"},
+	}
+	if err := u.Track(mockctx); err != nil {
+		t.Error(err)
+	}
+
+	// the elapsed time is capped at twice the interval.
+	u.last = time.Now().Add(-time.Hour)
+	if err := u.Track(mockctx); err != nil {
+		t.Error(err)
+	}
+}
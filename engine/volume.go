@@ -0,0 +1,34 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// drive matches a windows drive letter.
+var drive = regexp.MustCompile(`^[a-zA-Z]:`)
+
+// ValidateVolumes returns an error if a volume mounted into
+// the agent container is malformed. Volumes use the docker
+// bind syntax, source:target[:mode], where the source is a
+// host path or a named volume, and the target is an absolute
+// path in the agent container.
+func ValidateVolumes(volumes []string) error {
+	for _, volume := range volumes {
+		parts, err := splitVolumeParts(volume)
+		if err != nil {
+			return err
+		}
+		if len(parts) < 2 || parts[0] == "" {
+			return fmt.Errorf("Invalid agent volume %s, want source:target[:mode]", volume)
+		}
+		if target := parts[1]; target[0] != '/' && !drive.MatchString(target) {
+			return fmt.Errorf("Invalid agent volume %s, target must be an absolute path", volume)
+		}
+	}
+	return nil
+}
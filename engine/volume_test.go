@@ -0,0 +1,26 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package engine
+
+import "testing"
+
+func TestValidateVolumes(t *testing.T) {
+	valid := []string{
+		"/etc/ssl/certs/ca.pem:/etc/ssl/certs/ca.pem:ro",
+		"cache:/cache",
+		`C:\cache:C:\cache`,
+	}
+	if err := ValidateVolumes(valid); err != nil {
+		t.Error(err)
+	}
+	for _, volume := range []string{
+		"/cache",
+		"/cache:cache",
+	} {
+		if err := ValidateVolumes([]string{volume}); err == nil {
+			t.Errorf("Want error for invalid volume %s", volume)
+		}
+	}
+}
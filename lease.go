@@ -0,0 +1,27 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package autoscaler
+
+import "context"
+
+// A LeaseStore grants time limited leases, which are used to
+// elect the leader of multiple autoscaler replicas.
+type LeaseStore interface {
+	// Acquire acquires or renews the named lease for the
+	// holder until the timestamp, and returns true if the
+	// lease is held by the holder.
+	Acquire(ctx context.Context, name, holder string, expires int64) (bool, error)
+
+	// Release releases the named lease, if the lease is held
+	// by the holder.
+	Release(ctx context.Context, name, holder string) error
+}
+
+// Lease stores the lease details.
+type Lease struct {
+	Name    string `db:"lease_name"    json:"name"`
+	Holder  string `db:"lease_holder"  json:"holder"`
+	Expires int64  `db:"lease_expires" json:"expires"`
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Statter reports the database connection pool statistics.
+// It is implemented by *sql.DB and *sqlx.DB.
+type Statter interface {
+	Stats() sql.DBStats
+}
+
+// DatabaseStats provides metrics for the database connection
+// pool.
+func DatabaseStats(db Statter) {
+	prometheus.MustRegister(&databaseCollector{db: db})
+}
+
+var (
+	databaseMaxOpenDesc = prometheus.NewDesc(
+		"drone_database_max_open_connections",
+		"Maximum number of open database connections.",
+		nil, nil,
+	)
+	databaseOpenDesc = prometheus.NewDesc(
+		"drone_database_open_connections",
+		"Number of open database connections.",
+		nil, nil,
+	)
+	databaseInUseDesc = prometheus.NewDesc(
+		"drone_database_in_use_connections",
+		"Number of database connections in use.",
+		nil, nil,
+	)
+	databaseIdleDesc = prometheus.NewDesc(
+		"drone_database_idle_connections",
+		"Number of idle database connections.",
+		nil, nil,
+	)
+	databaseWaitCountDesc = prometheus.NewDesc(
+		"drone_database_wait_count_total",
+		"Total number of database connections waited for.",
+		nil, nil,
+	)
+	databaseWaitDurationDesc = prometheus.NewDesc(
+		"drone_database_wait_duration_seconds_total",
+		"Total time blocked waiting for a database connection.",
+		nil, nil,
+	)
+	databaseMaxIdleClosedDesc = prometheus.NewDesc(
+		"drone_database_max_idle_closed_total",
+		"Total number of database connections closed due to the idle limit.",
+		nil, nil,
+	)
+	databaseMaxLifetimeClosedDesc = prometheus.NewDesc(
+		"drone_database_max_lifetime_closed_total",
+		"Total number of database connections closed due to the lifetime limit.",
+		nil, nil,
+	)
+)
+
+// databaseCollector collects the connection pool statistics
+// each time the metrics are gathered.
+type databaseCollector struct {
+	db Statter
+}
+
+func (c *databaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- databaseMaxOpenDesc
+	ch <- databaseOpenDesc
+	ch <- databaseInUseDesc
+	ch <- databaseIdleDesc
+	ch <- databaseWaitCountDesc
+	ch <- databaseWaitDurationDesc
+	ch <- databaseMaxIdleClosedDesc
+	ch <- databaseMaxLifetimeClosedDesc
+}
+
+func (c *databaseCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+	ch <- prometheus.MustNewConstMetric(databaseMaxOpenDesc, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(databaseOpenDesc, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(databaseInUseDesc, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(databaseIdleDesc, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(databaseWaitCountDesc, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(databaseWaitDurationDesc, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(databaseMaxIdleClosedDesc, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(databaseMaxLifetimeClosedDesc, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
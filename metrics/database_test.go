@@ -0,0 +1,71 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type mockStatter sql.DBStats
+
+func (m mockStatter) Stats() sql.DBStats { return sql.DBStats(m) }
+
+func TestDatabaseStats(t *testing.T) {
+	// restore the default prometheus registerer
+	// when the unit test is complete.
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+	}()
+
+	// creates a blank registry
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	DatabaseStats(mockStatter{
+		MaxOpenConnections: 10,
+		OpenConnections:    8,
+		InUse:              6,
+		Idle:               2,
+		WaitCount:          4,
+		WaitDuration:       1500 * time.Millisecond,
+	})
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 8; want != got {
+		t.Errorf("Expect %d registered metrics, got %d", want, got)
+		return
+	}
+
+	want := map[string]float64{
+		"drone_database_max_open_connections":        10,
+		"drone_database_open_connections":            8,
+		"drone_database_in_use_connections":          6,
+		"drone_database_idle_connections":            2,
+		"drone_database_wait_count_total":            4,
+		"drone_database_wait_duration_seconds_total": 1.5,
+	}
+	for _, metric := range metrics {
+		value, ok := want[metric.GetName()]
+		if !ok {
+			continue
+		}
+		got := metric.Metric[0].GetGauge().GetValue()
+		if metric.Metric[0].Counter != nil {
+			got = metric.Metric[0].GetCounter().GetValue()
+		}
+		if got != value {
+			t.Errorf("Expect metric %s value %f, got %f", metric.GetName(), value, got)
+		}
+	}
+}
@@ -6,6 +6,45 @@ package metrics
 
 import (
 	"context"
+
+	"github.com/drone/autoscaler"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var noContext = context.Background()
+
+// helper function returns the wrapped provider, preserving
+// the optional Inspector and Quoter interfaces of the base
+// provider.
+func preserve(wrapped, base autoscaler.Provider) autoscaler.Provider {
+	inspector, isInspector := base.(autoscaler.Inspector)
+	quoter, isQuoter := base.(autoscaler.Quoter)
+	switch {
+	case isInspector && isQuoter:
+		return struct {
+			autoscaler.Provider
+			autoscaler.Inspector
+			autoscaler.Quoter
+		}{wrapped, inspector, quoter}
+	case isInspector:
+		return struct {
+			autoscaler.Provider
+			autoscaler.Inspector
+		}{wrapped, inspector}
+	case isQuoter:
+		return struct {
+			autoscaler.Provider
+			autoscaler.Quoter
+		}{wrapped, quoter}
+	}
+	return wrapped
+}
+
+// helper function returns the metric labels of the named
+// pool, or nil if the pool is not named.
+func poolLabels(pool string) prometheus.Labels {
+	if pool == "" {
+		return nil
+	}
+	return prometheus.Labels{"pool": pool}
+}
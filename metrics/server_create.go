@@ -13,21 +13,29 @@ import (
 
 // ServerCreate provides metrics for servers created.
 func ServerCreate(provider autoscaler.Provider) autoscaler.Provider {
+	return ServerCreatePool(provider, "")
+}
+
+// ServerCreatePool provides metrics for servers created in the
+// named pool. The metrics are labeled with the pool name.
+func ServerCreatePool(provider autoscaler.Provider, pool string) autoscaler.Provider {
 	counter := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "drone_servers_created",
-		Help: "Total number of servers created.",
+		Name:        "drone_servers_created",
+		Help:        "Total number of servers created.",
+		ConstLabels: poolLabels(pool),
 	})
 	errors := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "drone_servers_created_err",
-		Help: "Total number of server creation errors.",
+		Name:        "drone_servers_created_err",
+		Help:        "Total number of server creation errors.",
+		ConstLabels: poolLabels(pool),
 	})
 	prometheus.MustRegister(counter)
 	prometheus.MustRegister(errors)
-	return &providerWrapCreate{
+	return preserve(&providerWrapCreate{
 		Provider: provider,
 		created:  counter,
 		errors:   errors,
-	}
+	}, provider)
 }
 
 // instruments the Provider to count server create events.
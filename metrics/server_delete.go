@@ -13,21 +13,29 @@ import (
 
 // ServerDelete provides metrics for servers deleted.
 func ServerDelete(provider autoscaler.Provider) autoscaler.Provider {
+	return ServerDeletePool(provider, "")
+}
+
+// ServerDeletePool provides metrics for servers deleted in the
+// named pool. The metrics are labeled with the pool name.
+func ServerDeletePool(provider autoscaler.Provider, pool string) autoscaler.Provider {
 	created := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "drone_servers_deleted",
-		Help: "Total number of servers deleted.",
+		Name:        "drone_servers_deleted",
+		Help:        "Total number of servers deleted.",
+		ConstLabels: poolLabels(pool),
 	})
 	errors := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "drone_servers_deleted_err",
-		Help: "Total number of server deletion errors.",
+		Name:        "drone_servers_deleted_err",
+		Help:        "Total number of server deletion errors.",
+		ConstLabels: poolLabels(pool),
 	})
 	prometheus.MustRegister(created)
 	prometheus.MustRegister(errors)
-	return &providerWrapDestroy{
+	return preserve(&providerWrapDestroy{
 		Provider: provider,
 		created:  created,
 		errors:   errors,
-	}
+	}, provider)
 }
 
 // instruments the Provider to count server destroy events.
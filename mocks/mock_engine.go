@@ -6,8 +6,10 @@ package mocks
 
 import (
 	context "context"
+	autoscaler "github.com/drone/autoscaler"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockEngine is a mock of Engine interface
@@ -34,8 +36,10 @@ func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
 }
 
 // Pause mocks base method
-func (m *MockEngine) Pause() {
-	m.ctrl.Call(m, "Pause")
+func (m *MockEngine) Pause() error {
+	ret := m.ctrl.Call(m, "Pause")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Pause indicates an expected call of Pause
@@ -56,8 +60,10 @@ func (mr *MockEngineMockRecorder) Paused() *gomock.Call {
 }
 
 // Resume mocks base method
-func (m *MockEngine) Resume() {
-	m.ctrl.Call(m, "Resume")
+func (m *MockEngine) Resume() error {
+	ret := m.ctrl.Call(m, "Resume")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Resume indicates an expected call of Resume
@@ -74,3 +80,162 @@ func (m *MockEngine) Start(arg0 context.Context) {
 func (mr *MockEngineMockRecorder) Start(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockEngine)(nil).Start), arg0)
 }
+
+// Upgrade mocks base method
+func (m *MockEngine) Upgrade(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Upgrade", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upgrade indicates an expected call of Upgrade
+func (mr *MockEngineMockRecorder) Upgrade(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upgrade", reflect.TypeOf((*MockEngine)(nil).Upgrade), arg0, arg1)
+}
+
+// Rotate mocks base method
+func (m *MockEngine) Rotate(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Rotate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *MockEngineMockRecorder) Rotate(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockEngine)(nil).Rotate), arg0, arg1)
+}
+
+// Repair mocks base method
+func (m *MockEngine) Repair(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Repair", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Repair indicates an expected call of Repair
+func (mr *MockEngineMockRecorder) Repair(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockEngine)(nil).Repair), arg0, arg1)
+}
+
+// Replace mocks base method
+func (m *MockEngine) Replace(arg0 context.Context, arg1 string) (*autoscaler.Server, error) {
+	ret := m.ctrl.Call(m, "Replace", arg0, arg1)
+	ret0, _ := ret[0].(*autoscaler.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace
+func (mr *MockEngineMockRecorder) Replace(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockEngine)(nil).Replace), arg0, arg1)
+}
+
+// Queue mocks base method
+func (m *MockEngine) Queue(arg0 context.Context) ([]*autoscaler.QueueSnapshot, error) {
+	ret := m.ctrl.Call(m, "Queue", arg0)
+	ret0, _ := ret[0].([]*autoscaler.QueueSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Queue indicates an expected call of Queue
+func (mr *MockEngineMockRecorder) Queue(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockEngine)(nil).Queue), arg0)
+}
+
+// LastPlan mocks base method
+func (m *MockEngine) LastPlan() time.Time {
+	ret := m.ctrl.Call(m, "LastPlan")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// LastPlan indicates an expected call of LastPlan
+func (mr *MockEngineMockRecorder) LastPlan() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastPlan", reflect.TypeOf((*MockEngine)(nil).LastPlan))
+}
+
+// Preview mocks base method
+func (m *MockEngine) Preview(arg0 context.Context) ([]*autoscaler.Plan, error) {
+	ret := m.ctrl.Call(m, "Preview", arg0)
+	ret0, _ := ret[0].([]*autoscaler.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preview indicates an expected call of Preview
+func (mr *MockEngineMockRecorder) Preview(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preview", reflect.TypeOf((*MockEngine)(nil).Preview), arg0)
+}
+
+// Destroy mocks base method
+func (m *MockEngine) Destroy(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Destroy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Destroy indicates an expected call of Destroy
+func (mr *MockEngineMockRecorder) Destroy(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Destroy", reflect.TypeOf((*MockEngine)(nil).Destroy), arg0, arg1)
+}
+
+// Cordon mocks base method
+func (m *MockEngine) Cordon(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Cordon", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cordon indicates an expected call of Cordon
+func (mr *MockEngineMockRecorder) Cordon(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cordon", reflect.TypeOf((*MockEngine)(nil).Cordon), arg0, arg1)
+}
+
+// Uncordon mocks base method
+func (m *MockEngine) Uncordon(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Uncordon", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Uncordon indicates an expected call of Uncordon
+func (mr *MockEngineMockRecorder) Uncordon(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uncordon", reflect.TypeOf((*MockEngine)(nil).Uncordon), arg0, arg1)
+}
+
+// Scale mocks base method
+func (m *MockEngine) Scale(arg0 context.Context, arg1 string, arg2 int) error {
+	ret := m.ctrl.Call(m, "Scale", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scale indicates an expected call of Scale
+func (mr *MockEngineMockRecorder) Scale(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scale", reflect.TypeOf((*MockEngine)(nil).Scale), arg0, arg1, arg2)
+}
+
+// PausePool mocks base method
+func (m *MockEngine) PausePool(arg0 string) error {
+	ret := m.ctrl.Call(m, "PausePool", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PausePool indicates an expected call of PausePool
+func (mr *MockEngineMockRecorder) PausePool(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PausePool", reflect.TypeOf((*MockEngine)(nil).PausePool), arg0)
+}
+
+// ResumePool mocks base method
+func (m *MockEngine) ResumePool(arg0 string) error {
+	ret := m.ctrl.Call(m, "ResumePool", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumePool indicates an expected call of ResumePool
+func (mr *MockEngineMockRecorder) ResumePool(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumePool", reflect.TypeOf((*MockEngine)(nil).ResumePool), arg0)
+}
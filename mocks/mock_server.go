@@ -34,6 +34,30 @@ func (m *MockServerStore) EXPECT() *MockServerStoreMockRecorder {
 	return m.recorder
 }
 
+// AddUsage mocks base method
+func (m *MockServerStore) AddUsage(arg0 context.Context, arg1 string, arg2 int, arg3 int64) error {
+	ret := m.ctrl.Call(m, "AddUsage", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUsage indicates an expected call of AddUsage
+func (mr *MockServerStoreMockRecorder) AddUsage(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUsage", reflect.TypeOf((*MockServerStore)(nil).AddUsage), arg0, arg1, arg2, arg3)
+}
+
+// Annotate mocks base method
+func (m *MockServerStore) Annotate(arg0 context.Context, arg1 *autoscaler.Server) error {
+	ret := m.ctrl.Call(m, "Annotate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Annotate indicates an expected call of Annotate
+func (mr *MockServerStoreMockRecorder) Annotate(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Annotate", reflect.TypeOf((*MockServerStore)(nil).Annotate), arg0, arg1)
+}
+
 // Create mocks base method
 func (m *MockServerStore) Create(arg0 context.Context, arg1 *autoscaler.Server) error {
 	ret := m.ctrl.Call(m, "Create", arg0, arg1)
@@ -84,6 +108,58 @@ func (mr *MockServerStoreMockRecorder) List(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockServerStore)(nil).List), arg0)
 }
 
+// ListByLabels mocks base method
+func (m *MockServerStore) ListByLabels(arg0 context.Context, arg1 autoscaler.Labels) ([]*autoscaler.Server, error) {
+	ret := m.ctrl.Call(m, "ListByLabels", arg0, arg1)
+	ret0, _ := ret[0].([]*autoscaler.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByLabels indicates an expected call of ListByLabels
+func (mr *MockServerStoreMockRecorder) ListByLabels(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByLabels", reflect.TypeOf((*MockServerStore)(nil).ListByLabels), arg0, arg1)
+}
+
+// ListByPool mocks base method
+func (m *MockServerStore) ListByPool(arg0 context.Context, arg1 string) ([]*autoscaler.Server, error) {
+	ret := m.ctrl.Call(m, "ListByPool", arg0, arg1)
+	ret0, _ := ret[0].([]*autoscaler.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPool indicates an expected call of ListByPool
+func (mr *MockServerStoreMockRecorder) ListByPool(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPool", reflect.TypeOf((*MockServerStore)(nil).ListByPool), arg0, arg1)
+}
+
+// ListDeleted mocks base method
+func (m *MockServerStore) ListDeleted(arg0 context.Context) ([]*autoscaler.Server, error) {
+	ret := m.ctrl.Call(m, "ListDeleted", arg0)
+	ret0, _ := ret[0].([]*autoscaler.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeleted indicates an expected call of ListDeleted
+func (mr *MockServerStoreMockRecorder) ListDeleted(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockServerStore)(nil).ListDeleted), arg0)
+}
+
+// ListFilter mocks base method
+func (m *MockServerStore) ListFilter(arg0 context.Context, arg1 autoscaler.ServerFilter) ([]*autoscaler.Server, error) {
+	ret := m.ctrl.Call(m, "ListFilter", arg0, arg1)
+	ret0, _ := ret[0].([]*autoscaler.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFilter indicates an expected call of ListFilter
+func (mr *MockServerStoreMockRecorder) ListFilter(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFilter", reflect.TypeOf((*MockServerStore)(nil).ListFilter), arg0, arg1)
+}
+
 // ListState mocks base method
 func (m *MockServerStore) ListState(arg0 context.Context, arg1 autoscaler.ServerState) ([]*autoscaler.Server, error) {
 	ret := m.ctrl.Call(m, "ListState", arg0, arg1)
@@ -97,6 +173,18 @@ func (mr *MockServerStoreMockRecorder) ListState(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListState", reflect.TypeOf((*MockServerStore)(nil).ListState), arg0, arg1)
 }
 
+// Prune mocks base method
+func (m *MockServerStore) Prune(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "Prune", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prune indicates an expected call of Prune
+func (mr *MockServerStoreMockRecorder) Prune(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockServerStore)(nil).Prune), arg0, arg1)
+}
+
 // Purge mocks base method
 func (m *MockServerStore) Purge(arg0 context.Context, arg1 int64) error {
 	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
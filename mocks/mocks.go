@@ -6,6 +6,14 @@ package mocks
 
 //go:generate mockgen -package=mocks -destination=mock_engine.go   github.com/drone/autoscaler Engine
 //go:generate mockgen -package=mocks -destination=mock_server.go   github.com/drone/autoscaler ServerStore
+//go:generate mockgen -package=mocks -destination=mock_event.go    github.com/drone/autoscaler ServerEventStore
+//go:generate mockgen -package=mocks -destination=mock_lease.go    github.com/drone/autoscaler LeaseStore
+//go:generate mockgen -package=mocks -destination=mock_state.go    github.com/drone/autoscaler PoolStateStore
+//go:generate mockgen -package=mocks -destination=mock_scale.go    github.com/drone/autoscaler ScaleEventStore
+//go:generate mockgen -package=mocks -destination=mock_audit.go    github.com/drone/autoscaler AuditStore
+//go:generate mockgen -package=mocks -destination=mock_plan.go     github.com/drone/autoscaler PlanStore
 //go:generate mockgen -package=mocks -destination=mock_provider.go github.com/drone/autoscaler Provider
+//go:generate mockgen -package=mocks -destination=mock_inspector.go github.com/drone/autoscaler Inspector
+//go:generate mockgen -package=mocks -destination=mock_quoter.go github.com/drone/autoscaler Quoter
 //go:generate mockgen -package=mocks -destination=mock_drone.go    github.com/drone/drone-go/drone Client
 //go:generate mockgen -package=mocks -destination=mock_docker.go   docker.io/go-docker APIClient
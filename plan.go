@@ -0,0 +1,44 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package autoscaler
+
+import "context"
+
+// A PlanStore persists the decisions of the planner, which
+// are used to understand why the planner did, or did not,
+// scale a pool.
+type PlanStore interface {
+	// List returns the plans created at or after the
+	// timestamp, oldest first.
+	List(ctx context.Context, since int64) ([]*Plan, error)
+
+	// Create records a plan.
+	Create(context.Context, *Plan) error
+
+	// Prune permanently deletes the plans created before
+	// the timestamp.
+	Prune(ctx context.Context, before int64) error
+}
+
+// Plan records the inputs of a planning cycle, and the
+// resulting scaling action. The action is none if no capacity
+// changes were required.
+type Plan struct {
+	ID       int64       `db:"plan_id"       json:"id"`
+	Cycle    string      `db:"plan_cycle"    json:"cycle"`
+	Pool     string      `db:"plan_pool"     json:"pool"`
+	Pending  int         `db:"plan_pending"  json:"pending"`
+	Running  int         `db:"plan_running"  json:"running"`
+	Capacity int         `db:"plan_capacity" json:"capacity"`
+	Servers  int         `db:"plan_servers"  json:"servers"`
+	Min      int         `db:"plan_min"      json:"min"`
+	Max      int         `db:"plan_max"      json:"max"`
+	Diff     int         `db:"plan_diff"     json:"diff"`
+	Action   ScaleAction `db:"plan_action"   json:"action"`
+	Count    int         `db:"plan_count"    json:"count"`
+	Reason   string      `db:"plan_reason"   json:"reason"`
+	Error    string      `db:"plan_error"    json:"error,omitempty"`
+	Created  int64       `db:"plan_created"  json:"created"`
+}
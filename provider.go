@@ -36,6 +36,16 @@ const (
 // instance does not exist in the cloud provider.
 var ErrInstanceNotFound = errors.New("Not Found")
 
+// ErrInstanceReclaimed is returned when the requested
+// instance was stopped or reclaimed by the cloud provider,
+// for example a preempted spot instance.
+var ErrInstanceReclaimed = errors.New("Reclaimed")
+
+// ErrInsufficientCapacity is returned when the cloud
+// provider does not have sufficient capacity to create an
+// instance of the requested size.
+var ErrInsufficientCapacity = errors.New("Insufficient capacity")
+
 // A Provider represents a hosting provider, such as
 // Digital Ocean and is responsible for server management.
 type Provider interface {
@@ -45,6 +55,36 @@ type Provider interface {
 	Destroy(context.Context, *Instance) error
 }
 
+// An Inspector is an optional interface that may be
+// implemented by a Provider to report the status of an
+// existing server.
+type Inspector interface {
+	// Inspect returns the instance details. If the instance
+	// no longer exists ErrInstanceNotFound is returned. If
+	// the instance was reclaimed by the provider,
+	// ErrInstanceReclaimed is returned.
+	Inspect(context.Context, *Instance) (*Instance, error)
+}
+
+// A Quoter is an optional interface that may be
+// implemented by a Provider to report the remaining
+// provider quota, such as vCPU quotas or droplet limits.
+type Quoter interface {
+	// Quota returns the number of additional servers that
+	// can be created before the provider quota is exceeded.
+	Quota(context.Context) (int, error)
+}
+
+// A Validator is an optional interface that may be
+// implemented by a Provider to verify the provider
+// configuration, such as credentials, images and networks,
+// without creating an instance.
+type Validator interface {
+	// Validate returns an error if the provider is not
+	// configured correctly.
+	Validate(context.Context) error
+}
+
 // An Instance represents a server instance
 // (e.g Digital Ocean Droplet).
 type Instance struct {
@@ -55,6 +95,21 @@ type Instance struct {
 	Region   string
 	Image    string
 	Size     string
+
+	// Price is the estimated hourly price of the instance
+	// in the provider billing currency, or zero if the price
+	// is not known.
+	Price float64
+
+	// Pricing is the pricing model of the instance, such as
+	// on-demand or spot, and Status is the provider status
+	// of the instance. The public and private addresses are
+	// the network addresses of the instance. These are only
+	// reported by Inspect, and are empty if not known.
+	Pricing        string
+	Status         string
+	PublicAddress  string
+	PrivateAddress string
 }
 
 // InstanceCreateOpts define soptional instructions for
@@ -65,6 +120,114 @@ type InstanceCreateOpts struct {
 	CACert  []byte
 	TLSKey  []byte
 	TLSCert []byte
+
+	// OS is the instance operating system. The default
+	// userdata templates provision windows instances if
+	// the operating system is windows.
+	OS string
+
+	// Size is the instance size. If empty, the default size
+	// configured for the provider is used.
+	Size string
+
+	// Region is the instance region or zone. If empty, the
+	// default region configured for the provider is used.
+	Region string
+
+	// GPU instructs the provider to install the gpu drivers
+	// and container toolkit on the instance.
+	GPU bool
+
+	// Volume is the device path of the block volume that is
+	// mounted as the docker data directory. It is set by the
+	// provider when creating the instance with a volume.
+	Volume string
+
+	// Pool is the name of the pool, or empty if multiple
+	// pools are not configured.
+	Pool string
+
+	// Server is the address of the drone server, including
+	// the protocol, and Secret is the shared secret used by
+	// the agent to authenticate with the drone server.
+	Server string
+	Secret string
+
+	// SSHKey is the public key, in authorized_keys format,
+	// authorized to connect to the instance over ssh. It is
+	// set if the autoscaler manages the ssh keys.
+	SSHKey string
+
+	// Vars are user-defined variables of the pool, available
+	// to custom userdata templates.
+	Vars map[string]string
+
+	// Daemon configures the docker daemon of the instance.
+	Daemon DaemonOpts
+}
+
+// DaemonOpts configures the docker daemon of an instance.
+type DaemonOpts struct {
+	// RegistryMirrors is a list of registry mirrors used
+	// to pull images from docker hub.
+	RegistryMirrors []string
+
+	// InsecureRegistries is a list of registries that do
+	// not require tls.
+	InsecureRegistries []string
+
+	// Config is a json object merged into the docker daemon
+	// configuration (e.g. log driver and storage driver).
+	Config string
+
+	// HTTPProxy, HTTPSProxy and NoProxy configure the proxy
+	// used by the docker daemon to pull images.
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+
+	// StorageDriver and StorageOpts configure the storage
+	// driver of the docker daemon (e.g. overlay2).
+	StorageDriver string
+	StorageOpts   []string
+
+	// DataRoot is the root directory of the docker daemon
+	// storage (e.g. the mount point of an attached volume).
+	DataRoot string
+
+	// MTU is the mtu of the container networks.
+	MTU int
+
+	// Bip is the address of the default bridge network, in
+	// cidr notation.
+	Bip string
+
+	// AddressPools are the pools from which the networks
+	// created by the docker daemon are allocated.
+	AddressPools []AddressPool
+
+	// Version is the docker engine release installed on
+	// the instance (e.g. 24.0.7). The latest release is
+	// installed if empty.
+	Version string
+
+	// CgroupDriver is the cgroup driver of the docker
+	// daemon (e.g. systemd or cgroupfs).
+	CgroupDriver string
+
+	// CgroupnsMode is the default cgroup namespace mode of
+	// containers on cgroup v2 hosts (e.g. host or private).
+	CgroupnsMode string
+}
+
+// AddressPool is a default address pool of the docker daemon.
+type AddressPool struct {
+	// Base is the network of the pool, in cidr notation.
+	Base string `json:"base"`
+
+	// Size is the prefix length of the networks allocated
+	// from the pool.
+	Size int `json:"size"`
 }
 
 // InstanceError snapshots an error creating an instance
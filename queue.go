@@ -0,0 +1,35 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package autoscaler
+
+// QueueSnapshot is the view of the build queue of the planner
+// of a pool. The pending and running stages are matched by
+// the pool. The excluded stages are not matched by the pool,
+// and include the reason the stage is excluded.
+type QueueSnapshot struct {
+	Pool     string        `json:"pool"`
+	Pending  []*QueueStage `json:"pending"`
+	Running  []*QueueStage `json:"running"`
+	Excluded []*QueueStage `json:"excluded"`
+}
+
+// QueueStage describes a pending or running stage of the
+// build queue. The server is the machine running the stage.
+type QueueStage struct {
+	ID      int64             `json:"id"`
+	BuildID int64             `json:"build_id"`
+	Name    string            `json:"name"`
+	Status  string            `json:"status"`
+	Type    string            `json:"type,omitempty"`
+	OS      string            `json:"os"`
+	Arch    string            `json:"arch"`
+	Variant string            `json:"variant,omitempty"`
+	Kernel  string            `json:"kernel,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Server  string            `json:"server,omitempty"`
+	Reason  string            `json:"reason,omitempty"`
+	Created int64             `json:"created"`
+	Started int64             `json:"started,omitempty"`
+}
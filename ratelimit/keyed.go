@@ -0,0 +1,69 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// maxKeys defines the number of keys retained before the keys
+// with a replenished bucket are removed.
+const maxKeys = 1000
+
+// Keyed limits the rate of calls of each key, such as the user
+// or token of an api request, using a separate bucket for each
+// key.
+type Keyed struct {
+	mu       sync.Mutex
+	limit    float64
+	burst    int
+	limiters map[string]*limiter
+}
+
+// NewKeyed returns a Keyed limiter. The limit is defined in
+// calls per second for each key, with bursts of up to burst
+// calls.
+func NewKeyed(limit float64, burst int) *Keyed {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Keyed{
+		limit:    limit,
+		burst:    burst,
+		limiters: map[string]*limiter{},
+	}
+}
+
+// Reserve reserves a call of the key at the given time, and
+// returns the duration the caller must wait before making the
+// call. The call is not reserved if the caller must wait, so
+// that rejected calls do not delay later calls.
+func (k *Keyed) Reserve(key string, now time.Time) time.Duration {
+	k.mu.Lock()
+	l, ok := k.limiters[key]
+	if !ok {
+		if len(k.limiters) >= maxKeys {
+			k.prune(now)
+		}
+		l = newLimiter(k.limit, k.burst)
+		k.limiters[key] = l
+	}
+	k.mu.Unlock()
+	return l.tryReserve(now)
+}
+
+// helper function removes the keys with a replenished bucket,
+// which are equivalent to a new bucket.
+func (k *Keyed) prune(now time.Time) {
+	for key, l := range k.limiters {
+		l.Lock()
+		idle := !l.next.After(now)
+		l.Unlock()
+		if idle {
+			delete(k.limiters, key)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyed_Reserve(t *testing.T) {
+	k := NewKeyed(1, 2)
+	now := time.Now()
+
+	if got := k.Reserve("octocat", now); got > 0 {
+		t.Errorf("Want first call permitted, got delay %s", got)
+	}
+	if got := k.Reserve("octocat", now); got > 0 {
+		t.Errorf("Want burst call permitted, got delay %s", got)
+	}
+	if got, want := k.Reserve("octocat", now), time.Second; got != want {
+		t.Errorf("Want delay %s, got %s", want, got)
+	}
+
+	// the rejected call is not reserved, and does not delay
+	// the next call.
+	if got := k.Reserve("octocat", now.Add(time.Second)); got > 0 {
+		t.Errorf("Want call permitted after delay, got delay %s", got)
+	}
+
+	// each key is limited separately.
+	if got := k.Reserve("spaceghost", now); got > 0 {
+		t.Errorf("Want call of other key permitted, got delay %s", got)
+	}
+}
+
+func TestKeyed_Prune(t *testing.T) {
+	k := NewKeyed(1, 1)
+	now := time.Now()
+	k.Reserve("octocat", now)
+	k.prune(now.Add(time.Minute))
+	if got := len(k.limiters); got != 0 {
+		t.Errorf("Want replenished keys pruned, got %d keys", got)
+	}
+}
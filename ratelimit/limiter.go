@@ -0,0 +1,88 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// limiter implements a token bucket using the generic cell
+// rate algorithm. Calls are spaced by a fixed interval and
+// up to burst calls may be made without waiting.
+type limiter struct {
+	sync.Mutex
+
+	interval time.Duration
+	burst    int
+
+	// theoretical arrival time of the next call.
+	next time.Time
+}
+
+func newLimiter(limit float64, burst int) *limiter {
+	return &limiter{
+		interval: time.Duration(float64(time.Second) / limit),
+		burst:    burst,
+	}
+}
+
+// reserve reserves a call at the given time and returns the
+// duration the caller must wait before making the call.
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now) - time.Duration(l.burst-1)*l.interval
+	l.next = l.next.Add(l.interval)
+	return delay
+}
+
+// tryReserve reserves a call at the given time if the call is
+// permitted without waiting, and otherwise returns the duration
+// the caller must wait without reserving the call.
+func (l *limiter) tryReserve(now time.Time) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now) - time.Duration(l.burst-1)*l.interval
+	if delay > 0 {
+		return delay
+	}
+	l.next = l.next.Add(l.interval)
+	return 0
+}
+
+// helper function logs and blocks until the limiter permits
+// the named api call, or the context is cancelled.
+func (l *limiter) wait(ctx context.Context, name string) error {
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	log.Ctx(ctx).Debug().
+		Str("call", name).
+		Dur("delay", delay).
+		Msg("provider rate limit exceeded, waiting")
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
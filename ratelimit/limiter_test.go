@@ -0,0 +1,44 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	l := newLimiter(1, 2)
+	now := time.Now()
+
+	if got := l.reserve(now); got > 0 {
+		t.Errorf("Want first call permitted, got delay %s", got)
+	}
+	if got := l.reserve(now); got > 0 {
+		t.Errorf("Want burst call permitted, got delay %s", got)
+	}
+	if got, want := l.reserve(now), time.Second; got != want {
+		t.Errorf("Want delay %s, got %s", want, got)
+	}
+
+	// after the bucket is replenished calls are permitted
+	// without delay.
+	if got := l.reserve(now.Add(time.Minute)); got > 0 {
+		t.Errorf("Want replenished call permitted, got delay %s", got)
+	}
+}
+
+func TestLimiter_Cancel(t *testing.T) {
+	l := newLimiter(0.001, 1)
+	l.reserve(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.wait(ctx, "create"); err != context.Canceled {
+		t.Errorf("Want context cancelled error, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/drone/autoscaler"
+)
+
+// New returns a Provider that limits the rate of calls to
+// the cloud provider api. The limit is defined in requests
+// per second, with bursts of up to burst requests. A limit
+// of zero disables rate limiting.
+func New(provider autoscaler.Provider, limit float64, burst int) autoscaler.Provider {
+	if limit <= 0 {
+		return provider
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := newLimiter(limit, burst)
+	wrapped := &providerWrap{
+		Provider: provider,
+		limiter:  limiter,
+	}
+	inspector, isInspector := provider.(autoscaler.Inspector)
+	quoter, isQuoter := provider.(autoscaler.Quoter)
+	switch {
+	case isInspector && isQuoter:
+		return struct {
+			autoscaler.Provider
+			autoscaler.Inspector
+			autoscaler.Quoter
+		}{wrapped, &inspectorWrap{inspector, limiter}, &quoterWrap{quoter, limiter}}
+	case isInspector:
+		return struct {
+			autoscaler.Provider
+			autoscaler.Inspector
+		}{wrapped, &inspectorWrap{inspector, limiter}}
+	case isQuoter:
+		return struct {
+			autoscaler.Provider
+			autoscaler.Quoter
+		}{wrapped, &quoterWrap{quoter, limiter}}
+	}
+	return wrapped
+}
+
+// limits the rate of Provider calls.
+type providerWrap struct {
+	autoscaler.Provider
+	limiter *limiter
+}
+
+func (p *providerWrap) Create(ctx context.Context, opts autoscaler.InstanceCreateOpts) (*autoscaler.Instance, error) {
+	if err := p.limiter.wait(ctx, "create"); err != nil {
+		return nil, err
+	}
+	return p.Provider.Create(ctx, opts)
+}
+
+func (p *providerWrap) Destroy(ctx context.Context, instance *autoscaler.Instance) error {
+	if err := p.limiter.wait(ctx, "destroy"); err != nil {
+		return err
+	}
+	return p.Provider.Destroy(ctx, instance)
+}
+
+// limits the rate of Inspector calls.
+type inspectorWrap struct {
+	autoscaler.Inspector
+	limiter *limiter
+}
+
+func (p *inspectorWrap) Inspect(ctx context.Context, instance *autoscaler.Instance) (*autoscaler.Instance, error) {
+	if err := p.limiter.wait(ctx, "inspect"); err != nil {
+		return nil, err
+	}
+	return p.Inspector.Inspect(ctx, instance)
+}
+
+// limits the rate of Quoter calls.
+type quoterWrap struct {
+	autoscaler.Quoter
+	limiter *limiter
+}
+
+func (p *quoterWrap) Quota(ctx context.Context) (int, error) {
+	if err := p.limiter.wait(ctx, "quota"); err != nil {
+		return 0, err
+	}
+	return p.Quoter.Quota(ctx)
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestNew_Disabled(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	provider := mocks.NewMockProvider(controller)
+	if New(provider, 0, 0) != provider {
+		t.Errorf("Want provider unwrapped when rate limit disabled")
+	}
+}
+
+func TestNew(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	opts := autoscaler.InstanceCreateOpts{Name: "server1"}
+	instance := &autoscaler.Instance{}
+
+	provider := mocks.NewMockProvider(controller)
+	provider.EXPECT().Create(gomock.Any(), opts).Return(instance, nil)
+	provider.EXPECT().Destroy(gomock.Any(), instance).Return(nil)
+
+	limited := New(provider, 100, 2)
+	res, err := limited.Create(context.Background(), opts)
+	if err != nil {
+		t.Error(err)
+	}
+	if res != instance {
+		t.Errorf("Expect instance returned")
+	}
+	if err := limited.Destroy(context.Background(), instance); err != nil {
+		t.Error(err)
+	}
+	if _, ok := limited.(autoscaler.Inspector); ok {
+		t.Errorf("Want Inspector not implemented")
+	}
+}
+
+func TestNew_Inspector(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	instance := &autoscaler.Instance{}
+
+	inspector := mocks.NewMockInspector(controller)
+	inspector.EXPECT().Inspect(gomock.Any(), instance).Return(nil, autoscaler.ErrInstanceReclaimed)
+
+	provider := struct {
+		*mocks.MockProvider
+		*mocks.MockInspector
+	}{mocks.NewMockProvider(controller), inspector}
+
+	limited, ok := New(provider, 100, 1).(autoscaler.Inspector)
+	if !ok {
+		t.Errorf("Want Inspector preserved")
+		return
+	}
+	if _, err := limited.Inspect(context.Background(), instance); err != autoscaler.ErrInstanceReclaimed {
+		t.Errorf("Want reclaimed error, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/server"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryAudit returns a grpc interceptor that records who
+// performed each mutating request, when, the request message
+// and target, and the result, in the audit store. Requests to
+// read-only methods are not recorded. It must be chained after
+// the UnaryAuth interceptor.
+func UnaryAudit(audits autoscaler.AuditStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if scopes[info.FullMethod] == server.ScopeRead {
+			return handler(ctx, req)
+		}
+
+		res, err := handler(ctx, req)
+
+		event := &autoscaler.AuditEvent{
+			User:   server.UserFrom(ctx),
+			Scope:  server.ScopeFrom(ctx).String(),
+			Method: "GRPC",
+			Path:   info.FullMethod,
+			Target: auditTarget(req),
+			Status: httpStatus(status.Code(err)),
+		}
+		if msg, ok := req.(proto.Message); ok {
+			event.Query = proto.CompactTextString(msg)
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			address, _, err := net.SplitHostPort(p.Addr.String())
+			if err != nil {
+				address = p.Addr.String()
+			}
+			event.Address = address
+		}
+		if err := audits.Create(ctx, event); err != nil {
+			log.Ctx(ctx).
+				Error().
+				Err(err).
+				Str("method", event.Path).
+				Msg("cannot record audit event")
+		}
+		return res, err
+	}
+}
+
+// ChainUnary returns a grpc interceptor that invokes the
+// interceptors in order, with the last interceptor invoking
+// the method handler.
+func ChainUnary(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// helper function returns the name of the server or pool of
+// the request message.
+func auditTarget(req interface{}) string {
+	switch v := req.(type) {
+	case interface{ GetName() string }:
+		return v.GetName()
+	case interface{ GetPool() string }:
+		return v.GetPool()
+	default:
+		return ""
+	}
+}
+
+// helper function returns the http status code equivalent to
+// the grpc status code, so that the audit events of the http
+// and grpc requests are consistent.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/autoscaler/server"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kr/pretty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAudit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx := server.WithUser(context.Background(), "octocat")
+	ctx = server.WithScope(ctx, server.ScopeAdmin)
+	ctx = peer.NewContext(ctx, &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 50432},
+	})
+
+	want := &autoscaler.AuditEvent{
+		User:    "octocat",
+		Scope:   "admin",
+		Method:  "GRPC",
+		Path:    "/autoscaler.Autoscaler/DestroyServer",
+		Target:  "agent-1",
+		Status:  400,
+		Address: "10.0.0.1",
+	}
+	store := mocks.NewMockAuditStore(controller)
+	store.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, got *autoscaler.AuditEvent) {
+		// the text format of the request message is not
+		// stable, and is only checked for the force flag.
+		if !strings.Contains(got.Query, "force:true") {
+			t.Errorf("Want request message in audit query, got %q", got.Query)
+		}
+		got.Query = ""
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Unexpected audit event")
+			pretty.Ldiff(t, got, want)
+		}
+	}).Return(nil)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/autoscaler.Autoscaler/DestroyServer"}
+	req := &DestroyServerRequest{Name: "agent-1", Force: true}
+	_, err := UnaryAudit(store)(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.FailedPrecondition, "server is staging")
+	})
+	if got, want := status.Code(err), codes.FailedPrecondition; got != want {
+		t.Errorf("Want code %s, got %s", want, got)
+	}
+}
+
+func TestUnaryAudit_Read(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mocks.NewMockAuditStore(controller)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/autoscaler.Autoscaler/ListServers"}
+	_, err := UnaryAudit(store)(context.Background(), &ListServersRequest{}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &ListServersResponse{}, nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChainUnary(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name)
+			return handler(ctx, req)
+		}
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/autoscaler.Autoscaler/PausePool"}
+	_, err := ChainUnary(interceptor("auth"), interceptor("audit"))(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := calls, []string{"auth", "audit", "handler"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want interceptors called in order %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/autoscaler/server"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authenticate returns the name and scope of the token. It is
+// a variable so that the lookup can be replaced in tests.
+var authenticate = server.Authenticate
+
+// scopes defines the scope required by each method. Methods
+// that are not listed require the admin scope.
+var scopes = map[string]server.Scope{
+	"/autoscaler.Autoscaler/ListServers":      server.ScopeRead,
+	"/autoscaler.Autoscaler/GetServer":        server.ScopeRead,
+	"/autoscaler.Autoscaler/ListServerEvents": server.ScopeRead,
+	"/autoscaler.Autoscaler/WatchServers":     server.ScopeRead,
+	"/autoscaler.Autoscaler/ListQueue":        server.ScopeRead,
+	"/autoscaler.Autoscaler/GetPool":          server.ScopeRead,
+	"/autoscaler.Autoscaler/CreateServer":     server.ScopeOperator,
+	"/autoscaler.Autoscaler/PausePool":        server.ScopeOperator,
+	"/autoscaler.Autoscaler/ResumePool":       server.ScopeOperator,
+}
+
+// UnaryAuth returns a grpc interceptor that authorizes the
+// unary requests using the configured api tokens or the Drone
+// API, with the bearer token in the authorization metadata.
+// The token must have the scope required by the method.
+func UnaryAuth(conf config.Config, oidc *server.OIDC) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, conf, oidc, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth returns a grpc interceptor that authorizes the
+// streaming requests using the configured api tokens or the
+// Drone API, with the bearer token in the authorization
+// metadata. The token must have the scope required by the
+// method.
+func StreamAuth(conf config.Config, oidc *server.OIDC) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), conf, oidc, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{stream, ctx})
+	}
+}
+
+// helper function authorizes the request, and returns the
+// request context with the name, scope and logger of the
+// authorized user.
+func authorize(ctx context.Context, conf config.Config, oidc *server.OIDC, method string) (context.Context, error) {
+	logger := log.With().
+		Str("method", method).
+		Logger()
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) != 0 {
+			token = v[0]
+		}
+	}
+	token = strings.TrimPrefix(token, "Bearer ")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		logger.Debug().
+			Msg("missing authorization metadata")
+		return nil, status.Error(codes.Unauthenticated, "Invalid or missing token")
+	}
+
+	name, scope, err := authenticate(ctx, conf, oidc, token)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot authenticate user")
+		return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	required, ok := scopes[method]
+	if !ok {
+		required = server.ScopeAdmin
+	}
+	if scope < required {
+		logger.Error().
+			Str("username", name).
+			Str("scope", scope.String()).
+			Msg("insufficient privileges")
+		return nil, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	logger = logger.With().
+		Str("username", name).
+		Logger()
+	ctx = server.WithUser(ctx, name)
+	ctx = server.WithScope(ctx, scope)
+	return logger.WithContext(ctx), nil
+}
+
+// serverStream wraps the grpc server stream to replace the
+// stream context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/autoscaler/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorize(t *testing.T) {
+	defer func(fn func(context.Context, config.Config, *server.OIDC, string) (string, server.Scope, error)) {
+		authenticate = fn
+	}(authenticate)
+
+	authenticate = func(ctx context.Context, conf config.Config, oidc *server.OIDC, token string) (string, server.Scope, error) {
+		switch token {
+		case "admin":
+			return "octocat", server.ScopeAdmin, nil
+		case "dashboard":
+			return "token:5e2b0a1c", server.ScopeRead, nil
+		case "user":
+			return "spaceghost", server.ScopeNone, nil
+		default:
+			return "", server.ScopeNone, errors.New("Unauthorized")
+		}
+	}
+
+	tests := []struct {
+		token  string
+		method string
+		code   codes.Code
+	}{
+		{token: "", method: "ListServers", code: codes.Unauthenticated},
+		{token: "Bearer invalid", method: "ListServers", code: codes.Unauthenticated},
+		{token: "Bearer user", method: "ListServers", code: codes.PermissionDenied},
+		{token: "Bearer admin", method: "ListServers", code: codes.OK},
+		{token: "Bearer dashboard", method: "ListServers", code: codes.OK},
+		{token: "Bearer dashboard", method: "PausePool", code: codes.PermissionDenied},
+		{token: "Bearer dashboard", method: "DestroyServer", code: codes.PermissionDenied},
+		{token: "Bearer admin", method: "DestroyServer", code: codes.OK},
+	}
+	for _, test := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("authorization", test.token),
+		)
+		_, err := authorize(ctx, config.Config{}, nil, "/autoscaler.Autoscaler/"+test.method)
+		if got, want := status.Code(err), test.code; got != want {
+			t.Errorf("Want code %s for token %q calling %s, got %s", want, test.token, test.method, got)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package rpc
+
+//go:generate protoc --go_out=plugins=grpc:. autoscaler.proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/drone-go/drone"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchInterval defines the interval at which the servers are
+// polled for state changes when watched.
+var watchInterval = time.Second
+
+// New returns the grpc implementation of the autoscaler
+// service. The service exposes the same operations as the
+// rest api.
+func New(
+	engine autoscaler.Engine,
+	client drone.Client,
+	servers autoscaler.ServerStore,
+	events autoscaler.ServerEventStore,
+	conf config.Config,
+) AutoscalerServer {
+	return &service{
+		engine:  engine,
+		client:  client,
+		servers: servers,
+		events:  events,
+		conf:    conf,
+	}
+}
+
+type service struct {
+	engine  autoscaler.Engine
+	client  drone.Client
+	servers autoscaler.ServerStore
+	events  autoscaler.ServerEventStore
+	conf    config.Config
+}
+
+func (s *service) ListServers(ctx context.Context, in *ListServersRequest) (*ListServersResponse, error) {
+	if in.Limit < 0 || in.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid limit or offset")
+	}
+	list, err := s.servers.ListFilter(ctx, autoscaler.ServerFilter{
+		State:      autoscaler.ServerState(in.State),
+		Pool:       in.Pool,
+		Labels:     autoscaler.Labels(in.Labels),
+		Deleted:    in.IncludeDeleted,
+		Descending: in.Descending,
+		Limit:      int(in.Limit),
+		Offset:     int(in.Offset),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Msg("cannot get server list")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	out := &ListServersResponse{}
+	for _, server := range list {
+		out.Servers = append(out.Servers, toServer(server))
+	}
+	return out, nil
+}
+
+func (s *service) GetServer(ctx context.Context, in *GetServerRequest) (*Server, error) {
+	server, err := s.servers.Find(ctx, in.Name)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return toServer(server), nil
+}
+
+func (s *service) CreateServer(ctx context.Context, in *CreateServerRequest) (*Server, error) {
+	server := &autoscaler.Server{
+		Name:     "agent-" + uniuri.NewLen(8),
+		State:    autoscaler.StatePending,
+		Capacity: s.conf.Agent.Concurrency,
+		Pool:     in.Pool,
+	}
+	err := s.servers.Create(ctx, server)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Msg("cannot persist server")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return toServer(server), nil
+}
+
+func (s *service) DestroyServer(ctx context.Context, in *DestroyServerRequest) (*Server, error) {
+	logger := log.Ctx(ctx).With().
+		Str("server", in.Name).
+		Logger()
+
+	server, err := s.servers.Find(ctx, in.Name)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	// servers that failed to create, and are stuck in an
+	// error state, are deleted from the database.
+	if server.State == autoscaler.StateError && (server.ID == "" || in.Force) {
+		err = s.servers.Delete(ctx, server)
+		if err != nil {
+			logger.Error().Err(err).
+				Msg("cannot delete server")
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return toServer(server), nil
+	}
+
+	// provisioned servers are force destroyed by the engine,
+	// without draining, so that the instance is not leaked.
+	if in.Force {
+		err = s.engine.Destroy(logger.WithContext(context.Background()), in.Name)
+		if err == autoscaler.ErrServerNotFound {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Msg("cannot destroy server")
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		server.State = autoscaler.StateStopping
+		return toServer(server), nil
+	}
+
+	server.State = autoscaler.StateShutdown
+	err = s.servers.Update(ctx, server)
+	if err != nil {
+		logger.Error().Err(err).
+			Msg("cannot update server")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return toServer(server), nil
+}
+
+func (s *service) ListServerEvents(ctx context.Context, in *ListServerEventsRequest) (*ListServerEventsResponse, error) {
+	list, err := s.events.List(ctx, in.Name)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Str("server", in.Name).
+			Msg("cannot get server events")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	out := &ListServerEventsResponse{}
+	for _, event := range list {
+		out.Events = append(out.Events, &ServerEvent{
+			Id:      event.ID,
+			Server:  event.Server,
+			From:    string(event.From),
+			To:      string(event.To),
+			Reason:  event.Reason,
+			Created: event.Created,
+		})
+	}
+	return out, nil
+}
+
+// WatchServers sends the servers of the pool, if set, and then
+// sends each server when the server changes state, until the
+// client cancels the stream.
+func (s *service) WatchServers(in *WatchServersRequest, stream Autoscaler_WatchServersServer) error {
+	ctx := stream.Context()
+	states := map[string]autoscaler.ServerState{}
+	for {
+		list, err := s.servers.ListFilter(ctx, autoscaler.ServerFilter{Pool: in.Pool})
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).
+				Msg("cannot get server list")
+			return status.Error(codes.Internal, err.Error())
+		}
+		seen := map[string]bool{}
+		for _, server := range list {
+			seen[server.Name] = true
+			if state, ok := states[server.Name]; ok && state == server.State {
+				continue
+			}
+			states[server.Name] = server.State
+			if err := stream.Send(toServer(server)); err != nil {
+				return err
+			}
+		}
+		// the servers that are deleted from the list are no
+		// longer tracked.
+		for name := range states {
+			if !seen[name] {
+				delete(states, name)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+func (s *service) ListQueue(ctx context.Context, in *ListQueueRequest) (*ListQueueResponse, error) {
+	stages, err := s.client.Queue()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Msg("cannot fetch queue details")
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	out := &ListQueueResponse{}
+	for _, stage := range stages {
+		out.Stages = append(out.Stages, &Stage{
+			Id:      stage.ID,
+			BuildId: stage.BuildID,
+			Name:    stage.Name,
+			Status:  stage.Status,
+			Os:      stage.OS,
+			Arch:    stage.Arch,
+			Variant: stage.Variant,
+			Kernel:  stage.Kernel,
+			Machine: stage.Machine,
+			Labels:  stage.Labels,
+			Created: stage.Created,
+			Started: stage.Started,
+		})
+	}
+	return out, nil
+}
+
+func (s *service) GetPool(ctx context.Context, in *GetPoolRequest) (*Pool, error) {
+	return &Pool{Paused: s.engine.Paused()}, nil
+}
+
+func (s *service) PausePool(ctx context.Context, in *PausePoolRequest) (*Pool, error) {
+	if err := s.engine.Pause(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &Pool{Paused: s.engine.Paused()}, nil
+}
+
+func (s *service) ResumePool(ctx context.Context, in *ResumePoolRequest) (*Pool, error) {
+	if err := s.engine.Resume(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &Pool{Paused: s.engine.Paused()}, nil
+}
+
+// helper function converts the server to the protocol buffer
+// message. The secrets and private keys are not included.
+func toServer(server *autoscaler.Server) *Server {
+	return &Server{
+		Name:        server.Name,
+		Id:          server.ID,
+		Provider:    string(server.Provider),
+		State:       string(server.State),
+		Pool:        server.Pool,
+		Image:       server.Image,
+		Region:      server.Region,
+		Size:        server.Size,
+		Platform:    server.Platform,
+		Address:     server.Address,
+		Capacity:    int32(server.Capacity),
+		Price:       server.Price,
+		Phase:       server.Phase,
+		Error:       server.Error,
+		Labels:      server.Labels,
+		Annotations: server.Annotations,
+		Stages:      int64(server.Stages),
+		Busy:        server.Busy,
+		Created:     server.Created,
+		Updated:     server.Updated,
+		Started:     server.Started,
+		Stopped:     server.Stopped,
+		Deleted:     server.Deleted,
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/config"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/drone-go/drone"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListServers(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockServers := []*autoscaler.Server{
+		{Name: "agent-1", State: autoscaler.StateRunning, Pool: "arm64", Secret: "correct-horse-battery-staple"},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListFilter(gomock.Any(), autoscaler.ServerFilter{
+		State:      autoscaler.StateRunning,
+		Pool:       "arm64",
+		Labels:     autoscaler.Labels{"arch": "arm64"},
+		Descending: true,
+		Limit:      10,
+	}).Return(mockServers, nil)
+
+	s := New(nil, nil, store, nil, config.Config{})
+	out, err := s.ListServers(context.Background(), &ListServersRequest{
+		State:      "running",
+		Pool:       "arm64",
+		Labels:     map[string]string{"arch": "arm64"},
+		Descending: true,
+		Limit:      10,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(out.Servers), 1; got != want {
+		t.Errorf("Want %d servers, got %d", want, got)
+		return
+	}
+	if got, want := out.Servers[0].Name, "agent-1"; got != want {
+		t.Errorf("Want server %s, got %s", want, got)
+	}
+}
+
+func TestListServers_InvalidArgument(t *testing.T) {
+	s := New(nil, nil, nil, nil, config.Config{})
+	_, err := s.ListServers(context.Background(), &ListServersRequest{Limit: -1})
+	if got, want := status.Code(err), codes.InvalidArgument; got != want {
+		t.Errorf("Want code %s, got %s", want, got)
+	}
+}
+
+func TestGetServer_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-1").Return(nil, sql.ErrNoRows)
+
+	s := New(nil, nil, store, nil, config.Config{})
+	_, err := s.GetServer(context.Background(), &GetServerRequest{Name: "agent-1"})
+	if got, want := status.Code(err), codes.NotFound; got != want {
+		t.Errorf("Want code %s, got %s", want, got)
+	}
+}
+
+func TestDestroyServer(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockServer := &autoscaler.Server{Name: "agent-1", ID: "i-5203422c", State: autoscaler.StateRunning}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-1").Return(mockServer, nil)
+	store.EXPECT().Update(gomock.Any(), mockServer).Return(nil)
+
+	s := New(nil, nil, store, nil, config.Config{})
+	out, err := s.DestroyServer(context.Background(), &DestroyServerRequest{Name: "agent-1"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := out.State, string(autoscaler.StateShutdown); got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+func TestDestroyServer_Force(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockServer := &autoscaler.Server{Name: "agent-1", ID: "i-5203422c", State: autoscaler.StateRunning}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-1").Return(mockServer, nil)
+
+	engine := mocks.NewMockEngine(controller)
+	engine.EXPECT().Destroy(gomock.Any(), "agent-1").Return(nil)
+
+	s := New(engine, nil, store, nil, config.Config{})
+	out, err := s.DestroyServer(context.Background(), &DestroyServerRequest{Name: "agent-1", Force: true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := out.State, string(autoscaler.StateStopping); got != want {
+		t.Errorf("Want server state %s, got %s", want, got)
+	}
+}
+
+// this test verifies a server that failed to provision is
+// deleted from the database.
+func TestDestroyServer_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockServer := &autoscaler.Server{Name: "agent-1", State: autoscaler.StateError}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-1").Return(mockServer, nil)
+	store.EXPECT().Delete(gomock.Any(), mockServer).Return(nil)
+
+	s := New(nil, nil, store, nil, config.Config{})
+	if _, err := s.DestroyServer(context.Background(), &DestroyServerRequest{Name: "agent-1"}); err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies a server in an error state, with an
+// instance, is deleted from the database when forced.
+func TestDestroyServer_ForceError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockServer := &autoscaler.Server{Name: "agent-1", ID: "i-5203422c", State: autoscaler.StateError}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "agent-1").Return(mockServer, nil)
+	store.EXPECT().Delete(gomock.Any(), mockServer).Return(nil)
+
+	s := New(nil, nil, store, nil, config.Config{})
+	if _, err := s.DestroyServer(context.Background(), &DestroyServerRequest{Name: "agent-1", Force: true}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestListQueue(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	client := mocks.NewMockClient(controller)
+	client.EXPECT().Queue().Return([]*drone.Stage{
+		{ID: 1, Status: drone.StatusPending, OS: "linux", Arch: "arm64"},
+	}, nil)
+
+	s := New(nil, client, nil, nil, config.Config{})
+	out, err := s.ListQueue(context.Background(), &ListQueueRequest{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(out.Stages), 1; got != want {
+		t.Errorf("Want %d stages, got %d", want, got)
+		return
+	}
+	if got, want := out.Stages[0].Arch, "arm64"; got != want {
+		t.Errorf("Want stage arch %s, got %s", want, got)
+	}
+}
+
+func TestPausePool(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	engine := mocks.NewMockEngine(controller)
+	engine.EXPECT().Pause()
+	engine.EXPECT().Paused().Return(true)
+
+	s := New(engine, nil, nil, nil, config.Config{})
+	out, err := s.PausePool(context.Background(), &PausePoolRequest{})
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Paused {
+		t.Errorf("Want pool paused")
+	}
+}
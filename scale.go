@@ -0,0 +1,61 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package autoscaler
+
+import "context"
+
+// ScaleAction defines the scaling action of the planner.
+type ScaleAction string
+
+// ScaleAction type enumeration.
+const (
+	ScaleAlloc     = ScaleAction("alloc")
+	ScaleTerminate = ScaleAction("terminate")
+	ScaleNone      = ScaleAction("none")
+)
+
+// A ScaleEventStore persists the scaling actions of the
+// planner, which are used for capacity reporting.
+type ScaleEventStore interface {
+	// List returns the scale events created at or after the
+	// timestamp, oldest first.
+	List(ctx context.Context, since int64) ([]*ScaleEvent, error)
+
+	// Create records a scale event.
+	Create(context.Context, *ScaleEvent) error
+
+	// Summary returns the scale events created at or after
+	// the timestamp, aggregated by day, pool and action.
+	Summary(ctx context.Context, since int64) ([]*ScaleSummary, error)
+
+	// Prune permanently deletes the scale events created
+	// before the timestamp.
+	Prune(ctx context.Context, before int64) error
+}
+
+// ScaleEvent records a scaling action of the planner, and
+// the queue and capacity metrics that triggered the action.
+type ScaleEvent struct {
+	ID       int64       `db:"scale_id"       json:"id"`
+	Pool     string      `db:"scale_pool"     json:"pool"`
+	Action   ScaleAction `db:"scale_action"   json:"action"`
+	Count    int         `db:"scale_count"    json:"count"`
+	Pending  int         `db:"scale_pending"  json:"pending"`
+	Running  int         `db:"scale_running"  json:"running"`
+	Capacity int         `db:"scale_capacity" json:"capacity"`
+	Servers  int         `db:"scale_servers"  json:"servers"`
+	Created  int64       `db:"scale_created"  json:"created"`
+}
+
+// ScaleSummary aggregates the scale events of a pool and
+// action by day. The day is the unix timestamp of midnight
+// utc.
+type ScaleSummary struct {
+	Day    int64       `db:"scale_day"    json:"day"`
+	Pool   string      `db:"scale_pool"   json:"pool"`
+	Action ScaleAction `db:"scale_action" json:"action"`
+	Events int         `db:"scale_events" json:"events"`
+	Count  int         `db:"scale_count"  json:"count"`
+}
@@ -7,7 +7,9 @@ package autoscaler
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
 )
 
 // ServerState specifies the server state.
@@ -25,6 +27,7 @@ const (
 	StateCreated  = ServerState("created")
 	StateStaging  = ServerState("staging") // starting
 	StateRunning  = ServerState("running")
+	StateCordoned = ServerState("cordoned")
 	StateShutdown = ServerState("shutdown")
 	StateStopping = ServerState("stopping")
 	StateStopped  = ServerState("stopped")
@@ -35,28 +38,89 @@ const (
 // does not exist in the store.
 var ErrServerNotFound = errors.New("Not Found")
 
+// ErrPoolNotFound is returned when the requested pool is
+// not configured.
+var ErrPoolNotFound = errors.New("Pool not found")
+
 // A ServerStore persists server information.
 type ServerStore interface {
 	// Find a server by unique name.
 	Find(context.Context, string) (*Server, error)
 
-	// List returns all registered servers
+	// List returns all registered servers, excluding the
+	// soft-deleted servers.
 	List(context.Context) ([]*Server, error)
 
-	// ListState returns all servers with the given state.
+	// ListState returns all servers with the given state,
+	// excluding the soft-deleted servers.
 	ListState(context.Context, ServerState) ([]*Server, error)
 
+	// ListDeleted returns the soft-deleted servers.
+	ListDeleted(context.Context) ([]*Server, error)
+
+	// ListByPool returns the servers assigned to the named
+	// pool, excluding the soft-deleted servers.
+	ListByPool(context.Context, string) ([]*Server, error)
+
+	// ListByLabels returns the servers with all of the given
+	// labels, excluding the soft-deleted servers.
+	ListByLabels(context.Context, Labels) ([]*Server, error)
+
+	// ListFilter returns the servers that match the filter,
+	// in the order and page defined by the filter.
+	ListFilter(context.Context, ServerFilter) ([]*Server, error)
+
 	// Create the server record in the store.
 	Create(context.Context, *Server) error
 
 	// Update the server record in the store.
 	Update(context.Context, *Server) error
 
+	// Annotate updates the annotations of the server record
+	// in the store. The annotations are not changed by Update.
+	Annotate(context.Context, *Server) error
+
+	// AddUsage increments the stage count and busy time, in
+	// seconds, of the named server record in the store. The
+	// usage is not changed by Update.
+	AddUsage(ctx context.Context, name string, stages int, busy int64) error
+
 	// Delete the server record from the store.
 	Delete(context.Context, *Server) error
 
-	// Purge old server records from the store.
+	// Purge soft-deletes the servers stopped before the
+	// timestamp.
 	Purge(context.Context, int64) error
+
+	// Prune permanently deletes the servers soft-deleted
+	// before the timestamp.
+	Prune(context.Context, int64) error
+}
+
+// ServerFilter defines the filters, order and page of a
+// server list.
+type ServerFilter struct {
+	// State filters the servers by state, if not empty.
+	State ServerState
+
+	// Pool filters the servers by pool, if not empty.
+	Pool string
+
+	// Labels filters the servers with all of the labels, if
+	// not empty.
+	Labels Labels
+
+	// Deleted includes the soft-deleted servers.
+	Deleted bool
+
+	// Descending orders the servers newest first. The servers
+	// are ordered oldest first by default.
+	Descending bool
+
+	// Limit limits the number of servers returned, if not
+	// zero, after skipping the offset.
+	Limit  int
+	Offset int
 }
 
 // Server stores the server details.
@@ -71,14 +135,117 @@ type Server struct {
 	Platform string       `db:"server_platform" json:"platform"`
 	Address  string       `db:"server_address"  json:"address"`
 	Capacity int          `db:"server_capacity" json:"capacity"`
+	Price    float64      `db:"server_price"    json:"price"`
+	Pool     string       `db:"server_pool"     json:"pool"`
+	Phase    string       `db:"server_phase"    json:"phase"`
 	Secret   string       `db:"server_secret"   json:"secret"`
 	Error    string       `db:"server_error"    json:"error"`
 	CAKey    []byte       `db:"server_ca_key"   json:"ca_key"`
 	CACert   []byte       `db:"server_ca_cert"  json:"ca_cert"`
 	TLSKey   []byte       `db:"server_tls_key"  json:"tls_key"`
 	TLSCert  []byte       `db:"server_tls_cert" json:"tls_cert"`
+	SSHKey   []byte       `db:"server_ssh_key"  json:"-"`
+	HostKey  string       `db:"server_host_key" json:"host_key"`
 	Created  int64        `db:"server_created"  json:"created"`
 	Updated  int64        `db:"server_updated"  json:"updated"`
 	Started  int64        `db:"server_started"  json:"started"`
 	Stopped  int64        `db:"server_stopped"  json:"stopped"`
+	Deleted  int64        `db:"server_deleted"  json:"deleted"`
+	Logs     []byte       `db:"server_logs"     json:"-"`
+
+	Annotations Annotations `db:"server_annotations" json:"annotations,omitempty"`
+
+	// Labels are the agent labels of the server, which are
+	// assigned when the server is created, and not changed
+	// by Update.
+	Labels Labels `db:"server_labels" json:"labels,omitempty"`
+
+	// Stages is the number of pipeline stages the server has
+	// executed, and Busy is the cumulative time in seconds the
+	// server has spent executing stages.
+	Stages int   `db:"server_stages" json:"stages"`
+	Busy   int64 `db:"server_busy"   json:"busy"`
+}
+
+// Annotations stores arbitrary key value metadata attached
+// to a server by external tooling.
+type Annotations map[string]string
+
+// Value converts the value to a sql json string.
+func (a Annotations) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	out, err := json.Marshal(a)
+	return string(out), err
+}
+
+// Scan converts the sql json string to the value.
+func (a *Annotations) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("Cannot scan %T into annotations", value)
+	}
+	if len(data) == 0 {
+		*a = nil
+		return nil
+	}
+	return json.Unmarshal(data, a)
+}
+
+// Labels stores the agent labels of a server.
+type Labels map[string]string
+
+// Value converts the value to a sql json string.
+func (l Labels) Value() (driver.Value, error) {
+	return Annotations(l).Value()
+}
+
+// Scan converts the sql json string to the value.
+func (l *Labels) Scan(value interface{}) error {
+	return (*Annotations)(l).Scan(value)
+}
+
+// Match returns true if the labels include all of the given
+// labels.
+func (l Labels) Match(labels Labels) bool {
+	for k, v := range labels {
+		if got, ok := l[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// A ServerEventStore persists the state transitions of the
+// servers.
+type ServerEventStore interface {
+	// List returns the state transitions of the named server,
+	// oldest first.
+	List(context.Context, string) ([]*ServerEvent, error)
+
+	// ListSince returns the state transitions of all servers
+	// created at or after the timestamp, oldest first.
+	ListSince(ctx context.Context, since int64) ([]*ServerEvent, error)
+
+	// Create records a state transition.
+	Create(context.Context, *ServerEvent) error
+}
+
+// ServerEvent records a state transition of a server.
+type ServerEvent struct {
+	ID      int64       `db:"event_id"      json:"id"`
+	Server  string      `db:"event_server"  json:"server"`
+	From    ServerState `db:"event_from"    json:"from"`
+	To      ServerState `db:"event_to"      json:"to"`
+	Reason  string      `db:"event_reason"  json:"reason,omitempty"`
+	Created int64       `db:"event_created" json:"created"`
 }
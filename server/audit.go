@@ -0,0 +1,103 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/hlog"
+)
+
+// Audit returns a middleware function that records who
+// performed each mutating request, when, the query string and
+// target of the request, and the response status, in the audit
+// store. Read requests are not recorded. It must be used after
+// the CheckDrone middleware.
+func Audit(audits autoscaler.AuditStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET", "HEAD", "OPTIONS":
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			sw := &statusWriter{ResponseWriter: w, status: 200}
+			next.ServeHTTP(sw, r)
+
+			address, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				address = r.RemoteAddr
+			}
+			event := &autoscaler.AuditEvent{
+				User:    UserFrom(r.Context()),
+				Scope:   ScopeFrom(r.Context()).String(),
+				Method:  r.Method,
+				Path:    r.URL.Path,
+				Query:   r.URL.RawQuery,
+				Target:  auditTarget(r),
+				Status:  sw.status,
+				Address: address,
+			}
+			if err := audits.Create(r.Context(), event); err != nil {
+				hlog.FromRequest(r).
+					Error().
+					Err(err).
+					Str("method", event.Method).
+					Str("path", event.Path).
+					Msg("cannot record audit event")
+			}
+		})
+	}
+}
+
+// HandleAuditList returns an http.HandlerFunc that writes the
+// json-encoded audit events created since the timestamp of the
+// since query parameter to the response body.
+func HandleAuditList(audits autoscaler.AuditStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		since, err := parseSince(r)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		events, err := audits.List(r.Context(), since)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot get audit events")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, events, 200)
+	}
+}
+
+// helper function returns the name of the server or pool of
+// the request. The url parameters are read from the routing
+// context once the request is routed.
+func auditTarget(r *http.Request) string {
+	if name := chi.URLParam(r, "name"); name != "" {
+		return name
+	}
+	return chi.URLParam(r, "pool")
+}
+
+// statusWriter records the status code written to the
+// response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
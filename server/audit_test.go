@@ -0,0 +1,108 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/kr/pretty"
+)
+
+func TestAudit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/api/servers/agent-1?force=true", nil)
+	r.RemoteAddr = "10.0.0.1:50432"
+	ctx := WithScope(r.Context(), ScopeAdmin)
+	ctx = WithUser(ctx, "octocat")
+	r = r.WithContext(ctx)
+
+	want := &autoscaler.AuditEvent{
+		User:    "octocat",
+		Scope:   "admin",
+		Method:  "DELETE",
+		Path:    "/api/servers/agent-1",
+		Query:   "force=true",
+		Target:  "agent-1",
+		Status:  http.StatusTeapot,
+		Address: "10.0.0.1",
+	}
+	store := mocks.NewMockAuditStore(controller)
+	store.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, got *autoscaler.AuditEvent) {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Unexpected audit event")
+			pretty.Ldiff(t, got, want)
+		}
+	}).Return(nil)
+
+	router := chi.NewRouter()
+	router.Use(Audit(store))
+	router.Delete("/api/servers/{name}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, http.StatusTeapot; got != want {
+		t.Errorf("Want status code %d, got %d", want, got)
+	}
+}
+
+func TestAudit_Read(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers", nil)
+
+	store := mocks.NewMockAuditStore(controller)
+
+	Audit(store)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(200)
+		}),
+	).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; got != want {
+		t.Errorf("Want status code %d, got %d", want, got)
+	}
+}
+
+func TestHandleAuditList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/audit?since=1576029139", nil)
+
+	events := []*autoscaler.AuditEvent{
+		{ID: 1, User: "octocat", Scope: "admin", Method: "POST", Path: "/api/pause", Status: 204, Created: 1576029140},
+	}
+	store := mocks.NewMockAuditStore(controller)
+	store.EXPECT().List(gomock.Any(), int64(1576029139)).Return(events, nil)
+
+	HandleAuditList(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.AuditEvent{}, events
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
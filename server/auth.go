@@ -17,8 +17,11 @@ import (
 )
 
 // CheckDrone returns a middleware function that authorizes
-// the incoming http.Request using the Drone API.
-func CheckDrone(conf config.Config) func(http.Handler) http.Handler {
+// the incoming http.Request using the configured api tokens
+// or the Drone API, and adds the scope of the token to the
+// request context. Identity tokens are authenticated with the
+// OIDC authenticator, if not nil.
+func CheckDrone(conf config.Config, oidc *OIDC) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			logger := hlog.FromRequest(r)
@@ -35,22 +38,11 @@ func CheckDrone(conf config.Config) func(http.Handler) http.Handler {
 				return
 			}
 
-			// creates a new drone client using the bearer token
-			// in the incoming request to authenticate with drone.
-			config := new(oauth2.Config)
-			auther := config.Client(
-				oauth2.NoContext,
-				&oauth2.Token{
-					AccessToken: token,
-				},
-			)
-			server := conf.Server.Proto + "://" + conf.Server.Host
-			client := drone.NewClient(server, auther)
-
-			// fetch the user account associated with the currently
-			// authenticated bearer token. This user must exist in
-			// drone and must be an administrator.
-			user, err := client.Self()
+			// fetch the account associated with the currently
+			// authenticated bearer token. The token must be a
+			// configured api token, an identity token of a user
+			// in a mapped group, or a drone administrator.
+			name, scope, err := Authenticate(r.Context(), conf, oidc, token)
 			if err != nil {
 				logger.Error().
 					Err(err).
@@ -59,10 +51,9 @@ func CheckDrone(conf config.Config) func(http.Handler) http.Handler {
 				return
 			}
 
-			if !user.Admin {
+			if scope == ScopeNone {
 				logger.Error().
-					Err(err).
-					Str("username", user.Login).
+					Str("username", name).
 					Msg("insufficient privileges")
 				writeForbidden(w, errForbidden)
 				return
@@ -70,14 +61,32 @@ func CheckDrone(conf config.Config) func(http.Handler) http.Handler {
 
 			// add the authorized user to the logger context.
 			logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
-				return c.Str("username", user.Login)
+				return c.Str("username", name)
 			})
 
 			logger.Debug().
-				Str("username", user.Login).
+				Str("username", name).
+				Str("scope", scope.String()).
 				Msg("user authorized")
 
-			next.ServeHTTP(w, r)
+			ctx := WithScope(r.Context(), scope)
+			ctx = WithUser(ctx, name)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// LookupUser returns the drone user account of the bearer
+// token, using the token to authenticate with the Drone API.
+func LookupUser(conf config.Config, token string) (*drone.User, error) {
+	config := new(oauth2.Config)
+	auther := config.Client(
+		oauth2.NoContext,
+		&oauth2.Token{
+			AccessToken: token,
+		},
+	)
+	server := conf.Server.Proto + "://" + conf.Server.Host
+	client := drone.NewClient(server, auther)
+	return client.Self()
+}
@@ -0,0 +1,214 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+var (
+	// errMissingFilter is returned when a bulk request does not
+	// filter the servers, to prevent operating on every server
+	// by mistake.
+	errMissingFilter = errors.New("Bulk operations require a state, pool, label or older_than filter")
+
+	// errInvalidOlderThan is returned when the bulk request
+	// older_than query parameter is not a duration.
+	errInvalidOlderThan = errors.New("Invalid older_than, want a duration")
+)
+
+// bulkResult is the json-encoded result of a bulk request,
+// which includes the servers matching the filter, and the
+// errors of the servers that could not be updated, by name.
+type bulkResult struct {
+	Servers []*autoscaler.Server `json:"servers"`
+	Errors  map[string]string    `json:"errors,omitempty"`
+}
+
+// HandleServerBulkDelete returns an http.HandlerFunc that
+// destroys the servers matching the filter query parameters,
+// and the older_than query parameter, which filters servers
+// by age. Servers stuck in the error state are deleted, as
+// with the single server request. The servers are returned
+// without being destroyed if the dry_run query parameter is
+// true.
+func HandleServerBulkDelete(servers autoscaler.ServerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		force, _ := strconv.ParseBool(r.FormValue("force"))
+		dryRun, _ := strconv.ParseBool(r.FormValue("dry_run"))
+
+		filter, olderThan, err := parseBulkFilter(r)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		list, err := listBulk(ctx, servers, filter, olderThan)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot get server list")
+			writeError(w, err)
+			return
+		}
+
+		result := &bulkResult{Servers: list}
+		if dryRun {
+			writeJSON(w, result, 200)
+			return
+		}
+
+		logger := hlog.FromRequest(r)
+		for _, server := range list {
+			switch server.State {
+			case autoscaler.StateShutdown, autoscaler.StateStopping, autoscaler.StateStopped:
+				continue
+			}
+			if server.State == autoscaler.StateError && (server.ID == "" || force) {
+				err = servers.Delete(ctx, server)
+			} else {
+				server.State = autoscaler.StateShutdown
+				err = servers.Update(ctx, server)
+			}
+			if err != nil {
+				logger.Error().
+					Err(err).
+					Str("server", server.Name).
+					Msg("cannot destroy server")
+				result.addError(server.Name, err)
+			}
+		}
+
+		logger.Info().
+			Int("servers", len(list)).
+			Msg("bulk destroy requested")
+		writeJSON(w, result, 200)
+	}
+}
+
+// HandleServerBulkCordon returns an http.HandlerFunc that
+// cordons the running servers matching the filter query
+// parameters, and the older_than query parameter. Servers
+// that are not running are skipped. The servers are returned
+// without being cordoned if the dry_run query parameter is
+// true.
+func HandleServerBulkCordon(servers autoscaler.ServerStore, engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		dryRun, _ := strconv.ParseBool(r.FormValue("dry_run"))
+
+		filter, olderThan, err := parseBulkFilter(r)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		list, err := listBulk(ctx, servers, filter, olderThan)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot get server list")
+			writeError(w, err)
+			return
+		}
+
+		running := []*autoscaler.Server{}
+		for _, server := range list {
+			if server.State == autoscaler.StateRunning {
+				running = append(running, server)
+			}
+		}
+		result := &bulkResult{Servers: running}
+		if dryRun {
+			writeJSON(w, result.redact(ctx), 200)
+			return
+		}
+
+		logger := hlog.FromRequest(r)
+		for _, server := range running {
+			err := engine.Cordon(logger.WithContext(ctx), server.Name)
+			if err != nil {
+				logger.Error().
+					Err(err).
+					Str("server", server.Name).
+					Msg("cannot cordon server")
+				result.addError(server.Name, err)
+				continue
+			}
+			server.State = autoscaler.StateCordoned
+		}
+
+		logger.Info().
+			Int("servers", len(running)).
+			Msg("bulk cordon requested")
+		writeJSON(w, result.redact(ctx), 200)
+	}
+}
+
+// helper function returns the server filter and the minimum
+// server age from the bulk request query parameters. At least
+// one filter is required.
+func parseBulkFilter(r *http.Request) (autoscaler.ServerFilter, time.Duration, error) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return filter, 0, err
+	}
+	// soft-deleted servers are never included, since the
+	// servers are already destroyed.
+	filter.Deleted = false
+
+	var olderThan time.Duration
+	if v := r.FormValue("older_than"); v != "" {
+		olderThan, err = time.ParseDuration(v)
+		if err != nil || olderThan <= 0 {
+			return filter, 0, errInvalidOlderThan
+		}
+	}
+	if filter.State == "" && filter.Pool == "" && len(filter.Labels) == 0 && olderThan == 0 {
+		return filter, 0, errMissingFilter
+	}
+	return filter, olderThan, nil
+}
+
+// helper function returns the servers matching the filter,
+// created before the minimum server age, if not zero.
+func listBulk(ctx context.Context, servers autoscaler.ServerStore, filter autoscaler.ServerFilter, olderThan time.Duration) ([]*autoscaler.Server, error) {
+	list, err := servers.ListFilter(ctx, filter)
+	if err != nil || olderThan == 0 {
+		return list, err
+	}
+	before := time.Now().Add(-olderThan).Unix()
+	matched := []*autoscaler.Server{}
+	for _, server := range list {
+		if server.Created < before {
+			matched = append(matched, server)
+		}
+	}
+	return matched, nil
+}
+
+// helper function records the error of the named server.
+func (b *bulkResult) addError(name string, err error) {
+	if b.Errors == nil {
+		b.Errors = map[string]string{}
+	}
+	b.Errors[name] = err.Error()
+}
+
+// helper function returns a copy of the bulk result without
+// the agent secrets and the private keys, unless the request
+// is authorized with the admin scope.
+func (b *bulkResult) redact(ctx context.Context) *bulkResult {
+	return &bulkResult{Servers: redactList(ctx, b.Servers), Errors: b.Errors}
+}
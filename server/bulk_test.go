@@ -0,0 +1,143 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleServerBulkDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/api/servers?state=running&pool=arm64&older_than=24h", nil)
+
+	old := time.Now().Add(-48 * time.Hour).Unix()
+	servers := []*autoscaler.Server{
+		{Name: "agent-1", State: autoscaler.StateRunning, Pool: "arm64", Created: old},
+		{Name: "agent-2", State: autoscaler.StateRunning, Pool: "arm64", Created: time.Now().Unix()},
+	}
+
+	want := autoscaler.ServerFilter{State: autoscaler.StateRunning, Pool: "arm64"}
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListFilter(gomock.Any(), want).Return(servers, nil)
+	store.EXPECT().Update(gomock.Any(), servers[0]).Return(nil)
+
+	HandleServerBulkDelete(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := servers[0].State, autoscaler.StateShutdown; got != want {
+		t.Errorf("Want server older than the filter state %s, got %s", want, got)
+	}
+	if got, want := servers[1].State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want newer server state %s, got %s", want, got)
+	}
+
+	result := new(bulkResult)
+	json.NewDecoder(w.Body).Decode(result)
+	if got, want := len(result.Servers), 1; got != want {
+		t.Errorf("Want %d servers in the result, got %d", want, got)
+	}
+}
+
+func TestHandleServerBulkDelete_DryRun(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/api/servers?pool=arm64&dry_run=true", nil)
+
+	servers := []*autoscaler.Server{
+		{Name: "agent-1", State: autoscaler.StateRunning, Pool: "arm64"},
+	}
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListFilter(gomock.Any(), gomock.Any()).Return(servers, nil)
+
+	HandleServerBulkDelete(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := servers[0].State, autoscaler.StateRunning; got != want {
+		t.Errorf("Want server state unchanged by a dry run, got %s", got)
+	}
+}
+
+func TestHandleServerBulkDelete_MissingFilter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tests := []struct {
+		query string
+		err   error
+	}{
+		{"", errMissingFilter},
+		{"?include_deleted=true", errMissingFilter},
+		{"?older_than=1d", errInvalidOlderThan},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/api/servers"+test.query, nil)
+
+		store := mocks.NewMockServerStore(controller)
+		HandleServerBulkDelete(store).ServeHTTP(w, r)
+
+		if got, want := w.Code, 400; want != got {
+			t.Errorf("Want response code %d for query %q, got %d", want, test.query, got)
+		}
+		errjson := &Error{}
+		json.NewDecoder(w.Body).Decode(errjson)
+		if got, want := errjson.Message, test.err.Error(); got != want {
+			t.Errorf("Want error message %q, got %q", want, got)
+		}
+	}
+}
+
+func TestHandleServerBulkCordon(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/cordon?pool=arm64", nil)
+
+	servers := []*autoscaler.Server{
+		{Name: "agent-1", State: autoscaler.StateRunning, Pool: "arm64"},
+		{Name: "agent-2", State: autoscaler.StateStaging, Pool: "arm64"},
+		{Name: "agent-3", State: autoscaler.StateRunning, Pool: "arm64"},
+	}
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListFilter(gomock.Any(), gomock.Any()).Return(servers, nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Cordon(gomock.Any(), "agent-1").Return(nil)
+	e.EXPECT().Cordon(gomock.Any(), "agent-3").Return(errors.New("Server is not running"))
+
+	HandleServerBulkCordon(store, e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	result := new(bulkResult)
+	json.NewDecoder(w.Body).Decode(result)
+	if got, want := len(result.Servers), 2; got != want {
+		t.Errorf("Want %d running servers in the result, got %d", want, got)
+	}
+	if got, want := result.Errors["agent-3"], "Server is not running"; got != want {
+		t.Errorf("Want error of the failed server %q, got %q", want, got)
+	}
+}
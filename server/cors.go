@@ -0,0 +1,114 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/drone/autoscaler/config"
+)
+
+// default cors settings.
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type"}
+)
+
+// CORS returns a middleware function that writes the cross
+// origin resource sharing headers for requests from the
+// configured origins, and responds to preflight requests. The
+// origin * allows requests from any origin, without credentials.
+// Requests from other origins are served without the headers,
+// which the browser rejects.
+func CORS(conf config.Config) func(http.Handler) http.Handler {
+	methods := conf.CORS.Methods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := conf.CORS.Headers
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	maxAge := strconv.Itoa(int(conf.CORS.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			if !allowOrigin(conf.CORS.Origins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// credentials are only allowed for the origins
+			// that are explicitly configured, since the
+			// wildcard origin would allow any website to
+			// make authenticated requests.
+			if contains(conf.CORS.Origins, "*") {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if conf.CORS.Credentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			// preflight requests are answered without calling
+			// the next handler, since the preflight request is
+			// not authenticated.
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+				if conf.CORS.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(204)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ValidateCORS returns an error if the wildcard origin is
+// configured together with credentials, which must be used
+// with an explicit list of origins.
+func ValidateCORS(conf config.Config) error {
+	if conf.CORS.Credentials && contains(conf.CORS.Origins, "*") {
+		return errors.New("cors credentials cannot be allowed for the wildcard origin")
+	}
+	return nil
+}
+
+// helper function returns true if the origin matches one of
+// the allowed origins.
+func allowOrigin(origins []string, origin string) bool {
+	for _, allowed := range origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the list contains the
+// string.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler/config"
+)
+
+func TestCORS(t *testing.T) {
+	conf := config.Config{}
+	conf.CORS.Origins = []string{"https://dashboard.company.com"}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers", nil)
+	r.Header.Set("Origin", "https://dashboard.company.com")
+
+	CORS(conf)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Header().Get("Access-Control-Allow-Origin"), "https://dashboard.company.com"; got != want {
+		t.Errorf("Want allowed origin %q, got %q", want, got)
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	conf := config.Config{}
+	conf.CORS.Origins = []string{"*"}
+	conf.CORS.MaxAge = time.Hour
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("OPTIONS", "/api/servers", nil)
+	r.Header.Set("Origin", "https://dashboard.company.com")
+	r.Header.Set("Access-Control-Request-Method", "DELETE")
+
+	CORS(conf)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Want preflight request answered by the middleware")
+	})).ServeHTTP(w, r)
+
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Header().Get("Access-Control-Allow-Origin"), "*"; got != want {
+		t.Errorf("Want allowed origin %q, got %q", want, got)
+	}
+	if got, want := w.Header().Get("Access-Control-Allow-Methods"), "GET, POST, PUT, PATCH, DELETE"; got != want {
+		t.Errorf("Want allowed methods %q, got %q", want, got)
+	}
+	if got, want := w.Header().Get("Access-Control-Max-Age"), "3600"; got != want {
+		t.Errorf("Want max age %q, got %q", want, got)
+	}
+}
+
+func TestCORS_Disallowed(t *testing.T) {
+	conf := config.Config{}
+	conf.CORS.Origins = []string{"https://dashboard.company.com"}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers", nil)
+	r.Header.Set("Origin", "https://evil.com")
+
+	CORS(conf)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})).ServeHTTP(w, r)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Want no allowed origin, got %q", got)
+	}
+}
+
+func TestCORS_WildcardCredentials(t *testing.T) {
+	conf := config.Config{}
+	conf.CORS.Origins = []string{"*"}
+	conf.CORS.Credentials = true
+
+	if err := ValidateCORS(conf); err == nil {
+		t.Errorf("Want error for wildcard origin with credentials")
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers", nil)
+	r.Header.Set("Origin", "https://evil.example.com")
+
+	CORS(conf)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})).ServeHTTP(w, r)
+
+	if got, want := w.Header().Get("Access-Control-Allow-Origin"), "*"; got != want {
+		t.Errorf("Want allowed origin %q, got %q", want, got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Want credentials not allowed for the wildcard origin, got %q", got)
+	}
+}
@@ -5,16 +5,38 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/drone/autoscaler"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/hlog"
+)
+
+var (
+	// errMissingImage is returned when the upgrade request
+	// does not include the agent image.
+	errMissingImage = errors.New("Missing agent image")
+
+	// errInvalidCount is returned when the scale request does
+	// not include a positive server count.
+	errInvalidCount = errors.New("Invalid count, want a positive integer")
 )
 
 // HandleEnginePause returns an http.HandlerFunc that pauses
 // scaling engine.
 func HandleEnginePause(engine autoscaler.Engine) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		engine.Pause()
+		err := engine.Pause()
+		if err != nil {
+			hlog.FromRequest(r).Error().Err(err).
+				Msg("cannot pause engine")
+			writeErrorCode(w, err, 409)
+			return
+		}
 		w.WriteHeader(204)
 	}
 }
@@ -23,7 +45,270 @@ func HandleEnginePause(engine autoscaler.Engine) http.HandlerFunc {
 // scaling engine.
 func HandleEngineResume(engine autoscaler.Engine) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		engine.Resume()
+		err := engine.Resume()
+		if err != nil {
+			hlog.FromRequest(r).Error().Err(err).
+				Msg("cannot resume engine")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		w.WriteHeader(204)
+	}
+}
+
+// HandlePoolPause returns an http.HandlerFunc that pauses the
+// scaling engine of the named pool.
+func HandlePoolPause(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pool := chi.URLParam(r, "pool")
+		err := engine.PausePool(pool)
+		if err == autoscaler.ErrPoolNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			hlog.FromRequest(r).Error().Err(err).
+				Str("pool", pool).
+				Msg("cannot pause pool")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		w.WriteHeader(204)
+	}
+}
+
+// HandlePoolResume returns an http.HandlerFunc that resumes
+// the scaling engine of the named pool.
+func HandlePoolResume(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pool := chi.URLParam(r, "pool")
+		err := engine.ResumePool(pool)
+		if err == autoscaler.ErrPoolNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			hlog.FromRequest(r).Error().Err(err).
+				Str("pool", pool).
+				Msg("cannot resume pool")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		w.WriteHeader(204)
+	}
+}
+
+// HandleEngineUpgrade returns an http.HandlerFunc that starts
+// a rolling upgrade of the agent image. The upgrade continues
+// in the background once the request completes.
+func HandleEngineUpgrade(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		image := r.FormValue("image")
+		if image == "" {
+			writeBadRequest(w, errMissingImage)
+			return
+		}
+		logger := hlog.FromRequest(r)
+		err := engine.Upgrade(logger.WithContext(context.Background()), image)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("image", image).
+				Msg("cannot start upgrade")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		logger.Info().
+			Str("image", image).
+			Msg("upgrade started")
+		w.WriteHeader(202)
+	}
+}
+
+// HandleServerRotate returns an http.HandlerFunc that starts
+// the rotation of the docker tls certificate of the named
+// server. The rotation continues in the background once the
+// request completes.
+func HandleServerRotate(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		logger := hlog.FromRequest(r)
+		err := engine.Rotate(logger.WithContext(context.Background()), name)
+		if err == autoscaler.ErrServerNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", name).
+				Msg("cannot rotate server certificate")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		logger.Info().
+			Str("server", name).
+			Msg("certificate rotation started")
+		w.WriteHeader(202)
+	}
+}
+
+// HandleServerRepair returns an http.HandlerFunc that starts
+// the repair of the named server. The install sequence is run
+// again in the background once the request completes.
+func HandleServerRepair(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		logger := hlog.FromRequest(r)
+		err := engine.Repair(logger.WithContext(context.Background()), name)
+		if err == autoscaler.ErrServerNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", name).
+				Msg("cannot repair server")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		logger.Info().
+			Str("server", name).
+			Msg("server repair started")
+		w.WriteHeader(202)
+	}
+}
+
+// HandleServerReplace returns an http.HandlerFunc that
+// provisions a replacement for the named server, and writes
+// the json-encoded replacement to the response body. The named
+// server is drained and destroyed in the background once the
+// replacement is running.
+func HandleServerReplace(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		logger := hlog.FromRequest(r)
+		replacement, err := engine.Replace(logger.WithContext(context.Background()), name)
+		if err == autoscaler.ErrServerNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", name).
+				Msg("cannot replace server")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		logger.Info().
+			Str("server", name).
+			Str("replacement", replacement.Name).
+			Msg("server replacement started")
+		writeJSON(w, redact(r.Context(), replacement), 202)
+	}
+}
+
+// HandleServerCordon returns an http.HandlerFunc that cordons
+// the named server. The agent of a cordoned server is paused,
+// and does not accept new builds, but the server is not
+// terminated.
+func HandleServerCordon(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		logger := hlog.FromRequest(r)
+		err := engine.Cordon(logger.WithContext(r.Context()), name)
+		if err == autoscaler.ErrServerNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", name).
+				Msg("cannot cordon server")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		w.WriteHeader(204)
+	}
+}
+
+// HandleServerUncordon returns an http.HandlerFunc that
+// uncordons the named server, and returns the server to
+// service.
+func HandleServerUncordon(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		logger := hlog.FromRequest(r)
+		err := engine.Uncordon(logger.WithContext(r.Context()), name)
+		if err == autoscaler.ErrServerNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Str("server", name).
+				Msg("cannot uncordon server")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		w.WriteHeader(204)
+	}
+}
+
+// scaleRequest is the json-encoded scale request body.
+type scaleRequest struct {
+	Count *int `json:"count"`
+}
+
+// HandleEngineScale returns an http.HandlerFunc that sets the
+// target server count of the named pool to the json-encoded
+// count in the request body. The planner maintains the target
+// regardless of build volume until the target is cleared.
+func HandleEngineScale(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pool := chi.URLParam(r, "pool")
+		in := new(scaleRequest)
+		if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		if in.Count == nil || *in.Count < 0 {
+			writeBadRequest(w, errInvalidCount)
+			return
+		}
+		logger := hlog.FromRequest(r)
+		err := engine.Scale(logger.WithContext(r.Context()), pool, *in.Count)
+		if err == autoscaler.ErrPoolNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Str("pool", pool).
+				Msg("cannot scale pool")
+			writeErrorCode(w, err, 409)
+			return
+		}
+		w.WriteHeader(204)
+	}
+}
+
+// HandleEngineScaleClear returns an http.HandlerFunc that
+// clears the target server count of the named pool.
+func HandleEngineScaleClear(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pool := chi.URLParam(r, "pool")
+		logger := hlog.FromRequest(r)
+		err := engine.Scale(logger.WithContext(r.Context()), pool, -1)
+		if err == autoscaler.ErrPoolNotFound {
+			writeNotFound(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).
+				Str("pool", pool).
+				Msg("cannot clear pool target")
+			writeErrorCode(w, err, 409)
+			return
+		}
 		w.WriteHeader(204)
 	}
 }
@@ -5,10 +5,16 @@
 package server
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/drone/autoscaler"
 	"github.com/drone/autoscaler/mocks"
+
+	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 )
 
@@ -29,6 +35,23 @@ func TestHandleEnginePause(t *testing.T) {
 	}
 }
 
+func TestHandleEnginePause_NotLeader(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/pause", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Pause().Return(errors.New("Replica is not the leader"))
+
+	HandleEnginePause(e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 409; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
 func TestHandleEngineResume(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -45,3 +68,305 @@ func TestHandleEngineResume(t *testing.T) {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
 }
+
+func TestHandleEngineUpgrade(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/upgrade?image=drone/drone-runner-docker:1.8", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Upgrade(gomock.Any(), "drone/drone-runner-docker:1.8").Return(nil)
+
+	HandleEngineUpgrade(e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 202; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleEngineUpgrade_InProgress(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/upgrade?image=drone/drone-runner-docker:1.8", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Upgrade(gomock.Any(), "drone/drone-runner-docker:1.8").Return(errors.New("Upgrade in progress"))
+
+	HandleEngineUpgrade(e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 409; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleEngineUpgrade_MissingImage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/upgrade", nil)
+
+	e := mocks.NewMockEngine(controller)
+
+	HandleEngineUpgrade(e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerRotate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/rotate", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Rotate(gomock.Any(), "server1").Return(nil)
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/rotate", HandleServerRotate(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 202; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerRotate_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/rotate", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Rotate(gomock.Any(), "server1").Return(autoscaler.ErrServerNotFound)
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/rotate", HandleServerRotate(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerRepair(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/repair", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Repair(gomock.Any(), "server1").Return(nil)
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/repair", HandleServerRepair(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 202; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerRepair_State(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/repair", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Repair(gomock.Any(), "server1").Return(errors.New("Server cannot be repaired in its current state"))
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/repair", HandleServerRepair(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 409; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerReplace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/replace", nil)
+
+	replacement := &autoscaler.Server{Name: "server2", State: autoscaler.StatePending}
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Replace(gomock.Any(), "server1").Return(replacement, nil)
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/replace", HandleServerReplace(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 202; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	got := new(autoscaler.Server)
+	json.NewDecoder(w.Body).Decode(got)
+	if got.Name != "server2" {
+		t.Errorf("Want replacement server in the response body, got %q", got.Name)
+	}
+}
+
+func TestHandleServerReplace_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/replace", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Replace(gomock.Any(), "server1").Return(nil, autoscaler.ErrServerNotFound)
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/replace", HandleServerReplace(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerCordon(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/cordon", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Cordon(gomock.Any(), "server1").Return(nil)
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/cordon", HandleServerCordon(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerUncordon_Conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers/server1/uncordon", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Uncordon(gomock.Any(), "server1").Return(errors.New("Server is not cordoned"))
+
+	router := chi.NewRouter()
+	router.Post("/api/servers/{name}/uncordon", HandleServerUncordon(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 409; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleEngineScale(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/pools/arm64/scale", strings.NewReader(`{"count": 5}`))
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Scale(gomock.Any(), "arm64", 5).Return(nil)
+
+	router := chi.NewRouter()
+	router.Post("/api/pools/{pool}/scale", HandleEngineScale(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleEngineScale_InvalidCount(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/pools/arm64/scale", strings.NewReader(`{}`))
+
+	e := mocks.NewMockEngine(controller)
+
+	router := chi.NewRouter()
+	router.Post("/api/pools/{pool}/scale", HandleEngineScale(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleEngineScaleClear_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/api/pools/arm64/scale", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Scale(gomock.Any(), "arm64", -1).Return(autoscaler.ErrPoolNotFound)
+
+	router := chi.NewRouter()
+	router.Delete("/api/pools/{pool}/scale", HandleEngineScaleClear(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandlePoolPause(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/pools/arm64/pause", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().PausePool("arm64").Return(nil)
+
+	router := chi.NewRouter()
+	router.Post("/api/pools/{pool}/pause", HandlePoolPause(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandlePoolResume_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/pools/arm64/resume", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().ResumePool("arm64").Return(autoscaler.ErrPoolNotFound)
+
+	router := chi.NewRouter()
+	router.Post("/api/pools/{pool}/resume", HandlePoolResume(e))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
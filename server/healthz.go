@@ -5,16 +5,144 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/drone/autoscaler"
 )
 
-// HandleHealthz creates an http.HandlerFunc that returns performs system
-// healthchecks and returns 500 if the system is in an unhealthy state.
-func HandleHealthz() http.HandlerFunc {
+// defaultHealthTimeout defines the default duration a health
+// check may run before the component is reported as failing.
+const defaultHealthTimeout = 10 * time.Second
+
+// errHealthTimeout is returned when a health check does not
+// complete before the timeout.
+var errHealthTimeout = errors.New("Health check timed out")
+
+// HealthCheck checks the health of a named component of the
+// system, such as the database or the Drone API. The result is
+// cached for the ttl, if not zero, to prevent frequent health
+// checks from exhausting the rate limits of remote apis.
+type HealthCheck struct {
+	Name  string
+	Check func(context.Context) error
+	TTL   time.Duration
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+type (
+	healthz struct {
+		Status string                  `json:"status"`
+		Checks map[string]*healthCheck `json:"checks,omitempty"`
+	}
+
+	healthCheck struct {
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+)
+
+// HandleHealthz creates an http.HandlerFunc that performs the
+// health checks of each component, and writes the json-encoded
+// status of each component to the response body. The status
+// code is 503 if any component is failing.
+func HandleHealthz(checks ...*HealthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), defaultHealthTimeout)
+		defer cancel()
+
+		results := make([]error, len(checks))
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func(i int, check *HealthCheck) {
+				results[i] = check.run(ctx)
+				wg.Done()
+			}(i, check)
+		}
+		wg.Wait()
+
+		status := 200
+		data := &healthz{Status: "ok", Checks: map[string]*healthCheck{}}
+		for i, check := range checks {
+			if err := results[i]; err != nil {
+				status = 503
+				data.Status = "failing"
+				data.Checks[check.Name] = &healthCheck{Status: "failing", Error: err.Error()}
+			} else {
+				data.Checks[check.Name] = &healthCheck{Status: "ok"}
+			}
+		}
+		writeJSON(w, data, status)
+	}
+}
+
+// HandleLivez creates an http.HandlerFunc that returns 200 if
+// the process is able to serve requests. The components are not
+// checked, since a failing database or Drone API is not fixed
+// by restarting the process.
+func HandleLivez() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
 		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(200)
 		io.WriteString(w, "OK")
 	}
 }
+
+// PlannerCheck returns a health check that fails if the
+// planner has not completed a planning cycle within the
+// maximum age. The check passes if the engine is paused, or
+// if the planner has not yet completed a planning cycle, which
+// is expected of replicas that are not the leader.
+func PlannerCheck(engine autoscaler.Engine, maxAge time.Duration) *HealthCheck {
+	return &HealthCheck{
+		Name: "planner",
+		Check: func(context.Context) error {
+			if engine.Paused() {
+				return nil
+			}
+			planned := engine.LastPlan()
+			if planned.IsZero() {
+				return nil
+			}
+			if age := time.Since(planned); age > maxAge {
+				return fmt.Errorf("Planner last ran %s ago, at %s",
+					age.Truncate(time.Second),
+					planned.UTC().Format(time.RFC3339),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+// helper function runs the health check, or returns the
+// result of the previous check if checked within the ttl.
+func (c *HealthCheck) run(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.TTL != 0 && time.Since(c.checked) < c.TTL {
+		return c.err
+	}
+	// the check is abandoned when the context expires, since
+	// checks of remote apis may not support cancellation.
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Check(ctx)
+	}()
+	select {
+	case c.err = <-done:
+	case <-ctx.Done():
+		c.err = errHealthTimeout
+	}
+	c.checked = time.Now()
+	return c.err
+}
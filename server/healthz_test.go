@@ -5,10 +5,17 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/drone/autoscaler/mocks"
 	"github.com/golang/mock/gomock"
+	"github.com/kr/pretty"
 )
 
 func TestHandleHealthz(t *testing.T) {
@@ -24,3 +31,79 @@ func TestHandleHealthz(t *testing.T) {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
 }
+
+func TestHandleHealthz_Failing(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/healthz", nil)
+
+	HandleHealthz(
+		&HealthCheck{Name: "database", Check: func(context.Context) error { return nil }},
+		&HealthCheck{Name: "drone", Check: func(context.Context) error { return errors.New("401 Unauthorized") }},
+	).ServeHTTP(w, r)
+
+	if got, want := w.Code, 503; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := &healthz{}, &healthz{
+		Status: "failing",
+		Checks: map[string]*healthCheck{
+			"database": {Status: "ok"},
+			"drone":    {Status: "failing", Error: "401 Unauthorized"},
+		},
+	}
+	json.NewDecoder(w.Body).Decode(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
+
+func TestHealthCheck_TTL(t *testing.T) {
+	calls := 0
+	check := &HealthCheck{
+		Name: "provider",
+		TTL:  time.Minute,
+		Check: func(context.Context) error {
+			calls++
+			return nil
+		},
+	}
+	check.run(context.Background())
+	check.run(context.Background())
+	if got, want := calls, 1; got != want {
+		t.Errorf("Want %d health check within the ttl, got %d", want, got)
+	}
+}
+
+func TestPlannerCheck(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	engine := mocks.NewMockEngine(controller)
+	engine.EXPECT().Paused().Return(false).Times(2)
+	engine.EXPECT().LastPlan().Return(time.Now().Add(-time.Hour))
+	engine.EXPECT().LastPlan().Return(time.Now())
+
+	check := PlannerCheck(engine, 15*time.Minute)
+	if err := check.Check(context.Background()); err == nil {
+		t.Errorf("Want error if the planner has not run within the max age")
+	}
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("Want no error if the planner has run, got %s", err)
+	}
+}
+
+func TestHandleLivez(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/livez", nil)
+
+	HandleLivez().ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Body.String(), "OK"; want != got {
+		t.Errorf("Want response body %q, got %q", want, got)
+	}
+}
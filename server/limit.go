@@ -0,0 +1,82 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/autoscaler/ratelimit"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// defaultMaxBodySize defines the default maximum size of the
+// request body.
+const defaultMaxBodySize = 10 << 20
+
+var (
+	// errRateLimited is returned when the user or token
+	// exceeds the api rate limit.
+	errRateLimited = errors.New("Rate limit exceeded")
+
+	// errBodyTooLarge is returned when the request body
+	// exceeds the maximum size.
+	errBodyTooLarge = errors.New("Request body too large")
+)
+
+// RateLimit returns a middleware function that limits the rate
+// of api requests of each user or token. The limit is defined
+// in requests per second, with bursts of up to burst requests.
+// Requests that exceed the limit are rejected with a 429 status
+// code. A limit of zero disables rate limiting. It must be used
+// after the CheckDrone middleware.
+func RateLimit(limit float64, burst int) func(http.Handler) http.Handler {
+	if limit <= 0 {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	limiter := ratelimit.NewKeyed(limit, burst)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFrom(r.Context())
+			delay := limiter.Reserve(user, time.Now())
+			if delay > 0 {
+				hlog.FromRequest(r).Debug().
+					Dur("delay", delay).
+					Msg("api rate limit exceeded")
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				writeErrorCode(w, errRateLimited, 429)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MaxBodySize returns a middleware function that limits the
+// size of the request body. Requests with a declared length
+// above the limit are rejected with a 413 status code, and
+// reading beyond the limit fails otherwise. A size of zero uses
+// the default size.
+func MaxBodySize(size int64) func(http.Handler) http.Handler {
+	if size <= 0 {
+		size = defaultMaxBodySize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > size {
+				writeErrorCode(w, errBodyTooLarge, 413)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, size)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
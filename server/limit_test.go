@@ -0,0 +1,78 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRateLimit(t *testing.T) {
+	handler := RateLimit(0.001, 1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+
+	r := httptest.NewRequest("GET", "/api/servers", nil)
+	r = r.WithContext(WithUser(r.Context(), "octocat"))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if got, want := w.Code, 429; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got := w.Header().Get("Retry-After"); got == "" {
+		t.Errorf("Want Retry-After header")
+	}
+
+	// each user is limited separately.
+	r = r.WithContext(WithUser(r.Context(), "spaceghost"))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	handler := MaxBodySize(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		w.WriteHeader(204)
+	}))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/servers", strings.NewReader("{}"))
+	handler.ServeHTTP(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/api/servers", strings.NewReader(`{"name":"agent-1"}`))
+	handler.ServeHTTP(w, r)
+	if got, want := w.Code, 413; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	// the body is limited if the length is not declared.
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/api/servers", strings.NewReader(`{"name":"agent-1"}`))
+	r.ContentLength = -1
+	handler.ServeHTTP(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/drone/autoscaler/config"
+
+	oidc "github.com/coreos/go-oidc"
+)
+
+// errMissingClientID is returned when the openid connect
+// client id is not configured, since identity tokens cannot be
+// verified without the token audience.
+var errMissingClientID = errors.New("Missing openid connect client id")
+
+// OIDC authenticates identity tokens issued by an OpenID
+// Connect provider, and grants the scope mapped to the groups
+// of the user.
+type OIDC struct {
+	verifier *oidc.IDTokenVerifier
+	claim    string
+	groups   map[string]Scope
+}
+
+// NewOIDC returns an OIDC authenticator for the configured
+// issuer. The signing keys of the issuer are fetched using the
+// context, which must remain valid while the authenticator is
+// in use.
+func NewOIDC(ctx context.Context, conf config.Config) (*OIDC, error) {
+	if conf.OIDC.ClientID == "" {
+		return nil, errMissingClientID
+	}
+	provider, err := oidc.NewProvider(ctx, conf.OIDC.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	claim := conf.OIDC.GroupsClaim
+	if claim == "" {
+		claim = "groups"
+	}
+	groups := map[string]Scope{}
+	for group, name := range conf.OIDC.Groups {
+		groups[group] = ParseScope(name)
+	}
+	return &OIDC{
+		verifier: provider.Verifier(&oidc.Config{
+			ClientID: conf.OIDC.ClientID,
+		}),
+		claim:  claim,
+		groups: groups,
+	}, nil
+}
+
+// Authenticate verifies the identity token, and returns the
+// name of the user and the highest scope mapped to the groups
+// of the user.
+func (o *OIDC) Authenticate(ctx context.Context, token string) (string, Scope, error) {
+	idtoken, err := o.verifier.Verify(ctx, token)
+	if err != nil {
+		return "", ScopeNone, err
+	}
+	claims := map[string]interface{}{}
+	if err := idtoken.Claims(&claims); err != nil {
+		return "", ScopeNone, err
+	}
+	name := idtoken.Subject
+	for _, key := range []string{"email", "preferred_username"} {
+		if s, ok := claims[key].(string); ok && s != "" {
+			name = s
+			break
+		}
+	}
+	return name, o.scope(claims), nil
+}
+
+// helper function returns the highest scope mapped to the
+// groups in the token claims.
+func (o *OIDC) scope(claims map[string]interface{}) Scope {
+	var groups []string
+	switch v := claims[o.claim].(type) {
+	case string:
+		groups = append(groups, v)
+	case []interface{}:
+		for _, group := range v {
+			if s, ok := group.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+	scope := ScopeNone
+	for _, group := range groups {
+		if s := o.groups[group]; s > scope {
+			scope = s
+		}
+	}
+	return scope
+}
+
+// helper function returns true if the token is formatted as a
+// json web token, which distinguishes identity tokens from
+// drone tokens.
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
@@ -0,0 +1,330 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/drone/autoscaler"
+)
+
+// openapi document, loosely based on the OpenAPI 3.0
+// specification. Only the subset of the specification used
+// to describe the autoscaler api is implemented.
+type (
+	openapiDoc struct {
+		OpenAPI    string                           `json:"openapi"`
+		Info       openapiInfo                      `json:"info"`
+		Servers    []openapiServer                  `json:"servers"`
+		Paths      map[string]map[string]*openapiOp `json:"paths"`
+		Components openapiComponents                `json:"components"`
+		Security   []map[string][]string            `json:"security"`
+	}
+
+	openapiInfo struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	}
+
+	openapiServer struct {
+		URL string `json:"url"`
+	}
+
+	openapiComponents struct {
+		Schemas         map[string]*openapiSchema `json:"schemas"`
+		SecuritySchemes map[string]interface{}    `json:"securitySchemes"`
+	}
+
+	openapiOp struct {
+		OperationID string                      `json:"operationId"`
+		Summary     string                      `json:"summary"`
+		Tags        []string                    `json:"tags"`
+		Parameters  []openapiParam              `json:"parameters,omitempty"`
+		RequestBody *openapiBody                `json:"requestBody,omitempty"`
+		Responses   map[string]*openapiResponse `json:"responses"`
+		Security    []map[string][]string       `json:"security,omitempty"`
+	}
+
+	openapiParam struct {
+		Name     string         `json:"name"`
+		In       string         `json:"in"`
+		Required bool           `json:"required,omitempty"`
+		Schema   *openapiSchema `json:"schema"`
+	}
+
+	openapiBody struct {
+		Required bool                     `json:"required"`
+		Content  map[string]*openapiMedia `json:"content"`
+	}
+
+	openapiResponse struct {
+		Description string                   `json:"description"`
+		Content     map[string]*openapiMedia `json:"content,omitempty"`
+	}
+
+	openapiMedia struct {
+		Schema *openapiSchema `json:"schema"`
+	}
+
+	openapiSchema struct {
+		Ref                  string                    `json:"$ref,omitempty"`
+		Type                 string                    `json:"type,omitempty"`
+		Format               string                    `json:"format,omitempty"`
+		Items                *openapiSchema            `json:"items,omitempty"`
+		Properties           map[string]*openapiSchema `json:"properties,omitempty"`
+		AdditionalProperties *openapiSchema            `json:"additionalProperties,omitempty"`
+	}
+)
+
+// openapiRoute describes an api endpoint. The request and
+// response types are converted to schemas when the document
+// is generated.
+type openapiRoute struct {
+	method   string
+	path     string
+	id       string
+	summary  string
+	tag      string
+	public   bool
+	params   []openapiParam
+	request  interface{}
+	response interface{}
+	status   int
+}
+
+// openapiRoutes defines the endpoints registered by the
+// autoscaler http server.
+var openapiRoutes = []openapiRoute{
+	{method: "get", path: "/version", id: "getVersion", summary: "Get the version and build details", tag: "system", public: true, response: versionInfo{}},
+	{method: "get", path: "/livez", id: "getLiveness", summary: "Get the liveness of the server process", tag: "system", public: true, status: 200},
+	{method: "get", path: "/readyz", id: "getReadiness", summary: "Get the readiness of the database and the Drone API", tag: "system", public: true, response: &healthz{}},
+	{method: "get", path: "/healthz", id: "getHealth", summary: "Get the health of each component of the server", tag: "system", public: true, response: &healthz{}},
+	{method: "get", path: "/metrics", id: "getMetrics", summary: "Get the prometheus metrics", tag: "system", status: 200},
+	{method: "post", path: "/api/pause", id: "pauseEngine", summary: "Pause the scaling engine", tag: "engine", status: 204},
+	{method: "post", path: "/api/resume", id: "resumeEngine", summary: "Resume the scaling engine", tag: "engine", status: 204},
+	{method: "post", path: "/api/upgrade", id: "upgradeEngine", summary: "Start a rolling upgrade of the agent image", tag: "engine", params: []openapiParam{queryParam("image", "string", true)}, status: 202},
+	{method: "post", path: "/api/pools/{pool}/pause", id: "pausePool", summary: "Pause the scaling engine of a pool", tag: "engine", params: []openapiParam{pathParam("pool")}, status: 204},
+	{method: "post", path: "/api/pools/{pool}/resume", id: "resumePool", summary: "Resume the scaling engine of a pool", tag: "engine", params: []openapiParam{pathParam("pool")}, status: 204},
+	{method: "post", path: "/api/pools/{pool}/scale", id: "scalePool", summary: "Set the target server count of a pool", tag: "engine", params: []openapiParam{pathParam("pool")}, request: scaleRequest{}, status: 204},
+	{method: "delete", path: "/api/pools/{pool}/scale", id: "clearPoolScale", summary: "Clear the target server count of a pool", tag: "engine", params: []openapiParam{pathParam("pool")}, status: 204},
+	{method: "get", path: "/api/queue", id: "getQueue", summary: "Get the view of the build queue of the planner of each pool", tag: "engine", response: []*autoscaler.QueueSnapshot{}},
+	{method: "get", path: "/api/servers", id: "listServers", summary: "List the servers", tag: "servers", params: []openapiParam{
+		queryParam("state", "string", false),
+		queryParam("pool", "string", false),
+		queryParam("label", "string", false),
+		queryParam("include_deleted", "boolean", false),
+		queryParam("sort", "string", false),
+		queryParam("limit", "integer", false),
+		queryParam("offset", "integer", false),
+	}, response: []*autoscaler.Server{}},
+	{method: "post", path: "/api/servers", id: "createServer", summary: "Create a server", tag: "servers", params: []openapiParam{queryParam("pool", "string", false)}, response: &autoscaler.Server{}},
+	{method: "delete", path: "/api/servers", id: "destroyServers", summary: "Destroy the servers matching the filter", tag: "servers", params: []openapiParam{
+		queryParam("state", "string", false),
+		queryParam("pool", "string", false),
+		queryParam("label", "string", false),
+		queryParam("older_than", "string", false),
+		queryParam("force", "boolean", false),
+		queryParam("dry_run", "boolean", false),
+	}, response: &bulkResult{}},
+	{method: "post", path: "/api/servers/cordon", id: "cordonServers", summary: "Exclude the running servers matching the filter from new builds", tag: "servers", params: []openapiParam{
+		queryParam("state", "string", false),
+		queryParam("pool", "string", false),
+		queryParam("label", "string", false),
+		queryParam("older_than", "string", false),
+		queryParam("dry_run", "boolean", false),
+	}, response: &bulkResult{}},
+	{method: "get", path: "/api/servers/{name}", id: "getServer", summary: "Get a server", tag: "servers", params: []openapiParam{pathParam("name")}, response: &serverDetail{}},
+	{method: "delete", path: "/api/servers/{name}", id: "destroyServer", summary: "Destroy a server, or force destroy a hung server", tag: "servers", params: []openapiParam{pathParam("name"), queryParam("force", "boolean", false)}, response: &autoscaler.Server{}},
+	{method: "get", path: "/api/servers/{name}/logs", id: "getServerLogs", summary: "Get the install logs of a server", tag: "servers", params: []openapiParam{pathParam("name")}, status: 200},
+	{method: "get", path: "/api/servers/{name}/events", id: "listServerEvents", summary: "List the state changes of a server", tag: "servers", params: []openapiParam{pathParam("name")}, response: []*autoscaler.ServerEvent{}},
+	{method: "patch", path: "/api/servers/{name}/annotations", id: "annotateServer", summary: "Update the annotations of a server", tag: "servers", params: []openapiParam{pathParam("name")}, request: autoscaler.Annotations{}, response: &autoscaler.Server{}},
+	{method: "post", path: "/api/servers/{name}/rotate", id: "rotateServer", summary: "Rotate the docker certificate of a server", tag: "servers", params: []openapiParam{pathParam("name")}, status: 202},
+	{method: "post", path: "/api/servers/{name}/repair", id: "repairServer", summary: "Repair a server", tag: "servers", params: []openapiParam{pathParam("name")}, status: 202},
+	{method: "post", path: "/api/servers/{name}/replace", id: "replaceServer", summary: "Provision a replacement server, then drain and destroy the server", tag: "servers", params: []openapiParam{pathParam("name")}, response: &autoscaler.Server{}, status: 202},
+	{method: "post", path: "/api/servers/{name}/cordon", id: "cordonServer", summary: "Exclude a server from new builds", tag: "servers", params: []openapiParam{pathParam("name")}, status: 204},
+	{method: "post", path: "/api/servers/{name}/uncordon", id: "uncordonServer", summary: "Return a cordoned server to service", tag: "servers", params: []openapiParam{pathParam("name")}, status: 204},
+	{method: "get", path: "/api/events", id: "streamEvents", summary: "Stream the scale events and server state transitions", tag: "scale", status: 200},
+	{method: "get", path: "/api/plans", id: "listPlans", summary: "List the inputs and resulting actions of the planning cycles", tag: "scale", params: []openapiParam{queryParam("since", "integer", false)}, response: []*autoscaler.Plan{}},
+	{method: "post", path: "/api/plan/preview", id: "previewPlan", summary: "Preview the actions of a planning cycle without applying them", tag: "scale", response: []*autoscaler.Plan{}},
+	{method: "get", path: "/api/scale/events", id: "listScaleEvents", summary: "List the scale events", tag: "scale", params: []openapiParam{queryParam("since", "integer", false)}, response: []*autoscaler.ScaleEvent{}},
+	{method: "get", path: "/api/scale/summary", id: "getScaleSummary", summary: "Get the scale events aggregated by day", tag: "scale", params: []openapiParam{queryParam("since", "integer", false)}, response: []*autoscaler.ScaleSummary{}},
+	{method: "get", path: "/api/audit", id: "listAuditEvents", summary: "List the audit events of the mutating requests", tag: "audit", params: []openapiParam{queryParam("since", "integer", false)}, response: []*autoscaler.AuditEvent{}},
+}
+
+// HandleOpenAPI returns an http.HandlerFunc that writes the
+// json-encoded OpenAPI document of the http api to the
+// response body. The document is generated from the api
+// routes and types, with paths relative to the root path. The
+// server url includes the path prefix stripped by a trusted
+// reverse proxy, if forwarded.
+func HandleOpenAPI(root, version string) http.HandlerFunc {
+	doc := generateOpenAPI(root, version)
+	return func(w http.ResponseWriter, r *http.Request) {
+		prefix := forwardedPrefix(r.Context())
+		if prefix == "" {
+			writeJSON(w, doc, 200)
+			return
+		}
+		out := *doc
+		out.Servers = []openapiServer{{URL: path.Join(prefix, path.Clean("/"+root))}}
+		writeJSON(w, &out, 200)
+	}
+}
+
+// helper function generates the OpenAPI document of the api
+// routes.
+func generateOpenAPI(root, version string) *openapiDoc {
+	if version == "" {
+		version = "latest"
+	}
+	doc := &openapiDoc{
+		OpenAPI: "3.0.0",
+		Info: openapiInfo{
+			Title:   "Drone Autoscaler",
+			Version: version,
+		},
+		Servers: []openapiServer{{URL: path.Clean("/" + root)}},
+		Paths:   map[string]map[string]*openapiOp{},
+		Components: openapiComponents{
+			Schemas: map[string]*openapiSchema{},
+			SecuritySchemes: map[string]interface{}{
+				"bearer": map[string]string{
+					"type":   "http",
+					"scheme": "bearer",
+				},
+			},
+		},
+		Security: []map[string][]string{{"bearer": {}}},
+	}
+	schemaOf(reflect.TypeOf(Error{}), doc.Components.Schemas)
+
+	for _, route := range openapiRoutes {
+		op := &openapiOp{
+			OperationID: route.id,
+			Summary:     route.summary,
+			Tags:        []string{route.tag},
+			Parameters:  route.params,
+			Responses:   map[string]*openapiResponse{},
+		}
+		if route.public {
+			op.Security = []map[string][]string{{}}
+		}
+		if route.request != nil {
+			op.RequestBody = &openapiBody{
+				Required: true,
+				Content: map[string]*openapiMedia{
+					"application/json": {Schema: schemaOf(reflect.TypeOf(route.request), doc.Components.Schemas)},
+				},
+			}
+		}
+		status := route.status
+		if status == 0 {
+			status = 200
+		}
+		op.Responses[strconv.Itoa(status)] = &openapiResponse{
+			Description: http.StatusText(status),
+		}
+		if route.response != nil {
+			op.Responses[strconv.Itoa(status)].Content = map[string]*openapiMedia{
+				"application/json": {Schema: schemaOf(reflect.TypeOf(route.response), doc.Components.Schemas)},
+			}
+		}
+		op.Responses["default"] = &openapiResponse{
+			Description: "Error",
+			Content: map[string]*openapiMedia{
+				"application/json": {Schema: &openapiSchema{Ref: "#/components/schemas/Error"}},
+			},
+		}
+		if doc.Paths[route.path] == nil {
+			doc.Paths[route.path] = map[string]*openapiOp{}
+		}
+		doc.Paths[route.path][route.method] = op
+	}
+	return doc
+}
+
+// helper function returns the schema of the type. Named
+// struct types are added to the component schemas, and
+// referenced by name.
+func schemaOf(t reflect.Type, schemas map[string]*openapiSchema) *openapiSchema {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openapiSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Uint, reflect.Uint32:
+		return &openapiSchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openapiSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openapiSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openapiSchema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openapiSchema{Type: "string", Format: "byte"}
+		}
+		return &openapiSchema{Type: "array", Items: schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return &openapiSchema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := strings.Title(t.Name())
+		ref := &openapiSchema{Ref: "#/components/schemas/" + name}
+		if _, ok := schemas[name]; ok {
+			return ref
+		}
+		schema := &openapiSchema{Type: "object", Properties: map[string]*openapiSchema{}}
+		schemas[name] = schema
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")[0]
+			if tag == "-" || field.PkgPath != "" {
+				continue
+			}
+			// the fields of embedded structs are promoted to
+			// the parent schema.
+			if field.Anonymous && tag == "" {
+				embedded := schemaOf(field.Type, schemas)
+				if promoted, ok := schemas[strings.TrimPrefix(embedded.Ref, "#/components/schemas/")]; ok {
+					for k, v := range promoted.Properties {
+						schema.Properties[k] = v
+					}
+				}
+				continue
+			}
+			if tag == "" {
+				tag = field.Name
+			}
+			schema.Properties[tag] = schemaOf(field.Type, schemas)
+		}
+		return ref
+	}
+	return &openapiSchema{}
+}
+
+// helper function returns a required path parameter.
+func pathParam(name string) openapiParam {
+	return openapiParam{
+		Name:     name,
+		In:       "path",
+		Required: true,
+		Schema:   &openapiSchema{Type: "string"},
+	}
+}
+
+// helper function returns a query parameter.
+func queryParam(name, typ string, required bool) openapiParam {
+	return openapiParam{
+		Name:     name,
+		In:       "query",
+		Required: required,
+		Schema:   &openapiSchema{Type: typ},
+	}
+}
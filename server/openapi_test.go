@@ -0,0 +1,83 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleOpenAPI(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/openapi.json", nil)
+
+	HandleOpenAPI("/", "1.0.0").ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	doc := &openapiDoc{}
+	if err := json.NewDecoder(w.Body).Decode(doc); err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := doc.OpenAPI, "3.0.0"; got != want {
+		t.Errorf("Want openapi version %s, got %s", want, got)
+	}
+	if got, want := doc.Info.Version, "1.0.0"; got != want {
+		t.Errorf("Want api version %s, got %s", want, got)
+	}
+	if doc.Paths["/api/servers/{name}"]["delete"] == nil {
+		t.Errorf("Want destroy server operation")
+	}
+	if doc.Paths["/api/pause"]["post"] == nil {
+		t.Errorf("Want pause engine operation")
+	}
+}
+
+func TestGenerateOpenAPI_Schemas(t *testing.T) {
+	doc := generateOpenAPI("/autoscaler/", "")
+	if got, want := doc.Servers[0].URL, "/autoscaler"; got != want {
+		t.Errorf("Want server url %s, got %s", want, got)
+	}
+	server, ok := doc.Components.Schemas["Server"]
+	if !ok {
+		t.Errorf("Want server schema")
+		return
+	}
+	if got, want := server.Properties["created"].Format, "int64"; got != want {
+		t.Errorf("Want created format %s, got %s", want, got)
+	}
+	if got, want := server.Properties["labels"].Type, "object"; got != want {
+		t.Errorf("Want labels type %s, got %s", want, got)
+	}
+	if _, ok := server.Properties["ssh_key"]; ok {
+		t.Errorf("Want ssh key excluded from the server schema")
+	}
+	if _, ok := doc.Components.Schemas["Error"]; !ok {
+		t.Errorf("Want error schema")
+	}
+	// the public endpoints override the bearer token security
+	// requirement.
+	if got := doc.Paths["/version"]["get"].Security; len(got) != 1 || len(got[0]) != 0 {
+		t.Errorf("Want version endpoint without authentication")
+	}
+}
+
+func TestGenerateOpenAPI_Embedded(t *testing.T) {
+	doc := generateOpenAPI("/", "")
+	detail, ok := doc.Components.Schemas["ServerDetail"]
+	if !ok {
+		t.Errorf("Want server detail schema")
+		return
+	}
+	if _, ok := detail.Properties["name"]; !ok {
+		t.Errorf("Want server fields promoted to the server detail schema")
+	}
+	if _, ok := detail.Properties["history"]; !ok {
+		t.Errorf("Want server history in the server detail schema")
+	}
+}
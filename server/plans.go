@@ -0,0 +1,55 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// HandlePlans returns an http.HandlerFunc that writes the
+// json-encoded plans created since the timestamp of the since
+// query parameter to the response body.
+func HandlePlans(plans autoscaler.PlanStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		since, err := parseSince(r)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		list, err := plans.List(r.Context(), since)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot get plans")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, list, 200)
+	}
+}
+
+// HandlePlanPreview returns an http.HandlerFunc that executes
+// a planning cycle of each pool without allocating or
+// terminating servers, and writes the json-encoded plans to
+// the response body.
+func HandlePlanPreview(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := hlog.FromRequest(r)
+		plans, err := engine.Preview(logger.WithContext(r.Context()))
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Msg("cannot preview plan")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, plans, 200)
+	}
+}
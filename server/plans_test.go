@@ -0,0 +1,86 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/kr/pretty"
+)
+
+func TestHandlePlans(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/plans?since=1576029139", nil)
+
+	plans := []*autoscaler.Plan{
+		{ID: 1, Cycle: "tfp2kblvn", Pending: 4, Capacity: 2, Servers: 1, Min: 1, Max: 4, Diff: 2, Action: autoscaler.ScaleAlloc, Count: 2, Reason: "build volume exceeds the free capacity", Created: 1576029140},
+	}
+	store := mocks.NewMockPlanStore(controller)
+	store.EXPECT().List(gomock.Any(), int64(1576029139)).Return(plans, nil)
+
+	HandlePlans(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.Plan{}, plans
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
+
+func TestHandlePlans_InvalidSince(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/plans?since=yesterday", nil)
+
+	store := mocks.NewMockPlanStore(controller)
+	HandlePlans(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandlePlanPreview(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/plan/preview", nil)
+
+	plans := []*autoscaler.Plan{
+		{Cycle: "tfp2kblvn", Pending: 4, Capacity: 2, Servers: 1, Min: 1, Max: 4, Diff: 2, Action: autoscaler.ScaleAlloc, Count: 2, Reason: "build volume exceeds the free capacity"},
+	}
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Preview(gomock.Any()).Return(plans, nil)
+
+	HandlePlanPreview(e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.Plan{}, plans
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
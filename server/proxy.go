@@ -0,0 +1,115 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+)
+
+type prefixKey struct{}
+
+// Proxy returns a middleware function that updates the request
+// with the client address, scheme and host forwarded by a
+// reverse proxy. The forwarded headers can be set by any
+// client, and the middleware must only be used if the server
+// is reachable exclusively through a trusted proxy. The client
+// address is the last forwarded address that is not one of the
+// trusted proxy networks.
+func Proxy(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := forwardedFor(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+			if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+				r.URL.Scheme = proto
+			}
+			if host := r.Header.Get("X-Forwarded-Host"); host != "" {
+				r.Host = host
+			}
+			// the prefix is stripped by the proxy, and is used
+			// to write the urls of the server as seen by the
+			// client.
+			if prefix := r.Header.Get("X-Forwarded-Prefix"); prefix != "" {
+				r = r.WithContext(
+					context.WithValue(r.Context(), prefixKey{}, path.Clean("/"+prefix)),
+				)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// helper function returns the path prefix stripped by the
+// reverse proxy, or an empty string if no prefix was
+// forwarded.
+func forwardedPrefix(ctx context.Context) string {
+	prefix, _ := ctx.Value(prefixKey{}).(string)
+	return prefix
+}
+
+// helper function returns the client address forwarded by the
+// reverse proxy. Each proxy appends the address of its client
+// to the forwarded for header, and the addresses to the left
+// of the last untrusted address are set by the client. The
+// real ip header is used if no addresses are forwarded.
+func forwardedFor(r *http.Request, trusted []*net.IPNet) string {
+	if v := r.Header.Get("X-Forwarded-For"); v != "" {
+		addrs := strings.Split(v, ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(addrs[i]))
+			if ip == nil {
+				return ""
+			}
+			if i == 0 || !containsIP(trusted, ip) {
+				return ip.String()
+			}
+		}
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return ""
+}
+
+// helper function returns true if one of the networks contains
+// the ip address.
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNetworks parses the list of ip addresses and networks
+// in cidr notation. An ip address is parsed as a network that
+// contains only the address.
+func ParseNetworks(values []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if ip := net.ParseIP(value); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q", value)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxy(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers", nil)
+	r.Header.Set("X-Forwarded-For", "203.0.113.1, 10.0.0.1")
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Host", "ci.company.com")
+
+	trusted, _ := ParseNetworks([]string{"10.0.0.0/8"})
+	Proxy(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.RemoteAddr, "203.0.113.1"; got != want {
+			t.Errorf("Want remote address %s, got %s", want, got)
+		}
+		if got, want := r.URL.Scheme, "https"; got != want {
+			t.Errorf("Want scheme %s, got %s", want, got)
+		}
+		if got, want := r.Host, "ci.company.com"; got != want {
+			t.Errorf("Want host %s, got %s", want, got)
+		}
+	})).ServeHTTP(w, r)
+}
+
+func TestProxy_Prefix(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/openapi.json", nil)
+	r.Header.Set("X-Forwarded-Prefix", "/autoscaler/")
+
+	Proxy(nil)(HandleOpenAPI("/", "1.0.0")).ServeHTTP(w, r)
+
+	doc := &openapiDoc{}
+	if err := json.NewDecoder(w.Body).Decode(doc); err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := doc.Servers[0].URL, "/autoscaler"; got != want {
+		t.Errorf("Want server url %s, got %s", want, got)
+	}
+}
+
+func TestForwardedFor(t *testing.T) {
+	trusted, err := ParseNetworks([]string{"10.0.0.0/8", "192.0.2.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		forwarded string
+		realIP    string
+		want      string
+	}{
+		// the client cannot spoof the address by prepending
+		// addresses to the header.
+		{forwarded: "198.51.100.7, 203.0.113.1", want: "203.0.113.1"},
+		{forwarded: "198.51.100.7, 203.0.113.1, 10.0.0.1, 192.0.2.10", want: "203.0.113.1"},
+		{forwarded: "10.0.0.2, 10.0.0.1", want: "10.0.0.2"},
+		{forwarded: "2001:db8::1, 10.0.0.1", want: "2001:db8::1"},
+		{forwarded: "203.0.113.1, invalid", want: ""},
+		{realIP: "203.0.113.1", want: "203.0.113.1"},
+		{want: ""},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/api/servers", nil)
+		if test.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", test.forwarded)
+		}
+		if test.realIP != "" {
+			r.Header.Set("X-Real-IP", test.realIP)
+		}
+		if got := forwardedFor(r, trusted); got != test.want {
+			t.Errorf("Want address %q forwarded for %q, got %q", test.want, test.forwarded, got)
+		}
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks([]string{"10.0.0.0/8", "192.0.2.10", "2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(networks), 3; got != want {
+		t.Fatalf("Want %d networks, got %d", want, got)
+	}
+	if got, want := networks[1].String(), "192.0.2.10/32"; got != want {
+		t.Errorf("Want network %s, got %s", want, got)
+	}
+	if _, err := ParseNetworks([]string{"invalid"}); err == nil {
+		t.Errorf("Want error for invalid network")
+	}
+}
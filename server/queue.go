@@ -0,0 +1,31 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// HandleQueue returns an http.HandlerFunc that writes the
+// json-encoded view of the build queue of the planner of each
+// pool to the response body.
+func HandleQueue(engine autoscaler.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		snapshots, err := engine.Queue(r.Context())
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot get queue snapshot")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, snapshots, 200)
+	}
+}
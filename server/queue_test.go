@@ -0,0 +1,67 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/kr/pretty"
+)
+
+func TestHandleQueue(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/queue", nil)
+
+	snapshots := []*autoscaler.QueueSnapshot{
+		{
+			Pool:     "default",
+			Pending:  []*autoscaler.QueueStage{{ID: 1, Status: "pending", OS: "linux", Arch: "amd64"}},
+			Running:  []*autoscaler.QueueStage{{ID: 2, Status: "running", OS: "linux", Arch: "amd64", Server: "agent-1"}},
+			Excluded: []*autoscaler.QueueStage{{ID: 3, Status: "pending", OS: "linux", Arch: "arm64", Reason: "arch"}},
+		},
+	}
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Queue(gomock.Any()).Return(snapshots, nil)
+
+	HandleQueue(e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.QueueSnapshot{}, snapshots
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
+
+func TestHandleQueue_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/queue", nil)
+
+	e := mocks.NewMockEngine(controller)
+	e.EXPECT().Queue(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	HandleQueue(e).ServeHTTP(w, r)
+
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
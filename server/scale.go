@@ -0,0 +1,85 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// defaultReportPeriod defines the default period of the scale
+// reports, if the since query parameter is not provided.
+const defaultReportPeriod = time.Hour * 24 * 30
+
+// errInvalidSince is returned when the since query parameter
+// is not a unix timestamp.
+var errInvalidSince = errors.New("Invalid since, want a unix timestamp")
+
+// HandleScaleEvents returns an http.HandlerFunc that writes
+// the json-encoded scale events created since the timestamp
+// of the since query parameter to the response body.
+func HandleScaleEvents(scales autoscaler.ScaleEventStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		since, err := parseSince(r)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		events, err := scales.List(r.Context(), since)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot get scale events")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, events, 200)
+	}
+}
+
+// HandleScaleSummary returns an http.HandlerFunc that writes
+// the json-encoded scale events created since the timestamp
+// of the since query parameter, aggregated by day, pool and
+// action, to the response body.
+func HandleScaleSummary(scales autoscaler.ScaleEventStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		since, err := parseSince(r)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		summary, err := scales.Summary(r.Context(), since)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot get scale summary")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, summary, 200)
+	}
+}
+
+// helper function returns the timestamp of the since query
+// parameter, or the start of the default report period.
+func parseSince(r *http.Request) (int64, error) {
+	param := r.FormValue("since")
+	if param == "" {
+		return time.Now().Add(-defaultReportPeriod).Unix(), nil
+	}
+	since, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || since < 0 {
+		return 0, errInvalidSince
+	}
+	return since, nil
+}
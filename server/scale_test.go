@@ -0,0 +1,85 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/kr/pretty"
+)
+
+func TestHandleScaleEvents(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/scale/events?since=1576029139", nil)
+
+	events := []*autoscaler.ScaleEvent{
+		{ID: 1, Action: autoscaler.ScaleAlloc, Count: 2, Pending: 4, Created: 1576029140},
+	}
+	store := mocks.NewMockScaleEventStore(controller)
+	store.EXPECT().List(gomock.Any(), int64(1576029139)).Return(events, nil)
+
+	HandleScaleEvents(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.ScaleEvent{}, events
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
+
+func TestHandleScaleSummary(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/scale/summary", nil)
+
+	summary := []*autoscaler.ScaleSummary{
+		{Day: 1575936000, Pool: "arm64", Action: autoscaler.ScaleAlloc, Events: 2, Count: 3},
+	}
+	store := mocks.NewMockScaleEventStore(controller)
+	store.EXPECT().Summary(gomock.Any(), gomock.Any()).Return(summary, nil)
+
+	HandleScaleSummary(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.ScaleSummary{}, summary
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
+
+func TestHandleScaleSummary_InvalidSince(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/scale/summary?since=yesterday", nil)
+
+	HandleScaleSummary(mocks.NewMockScaleEventStore(controller)).ServeHTTP(w, r)
+
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/drone/autoscaler/config"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// Scope defines the operations an api token is permitted to
+// perform. Each scope includes the operations of the scopes
+// below it.
+type Scope int
+
+// Scope values.
+const (
+	ScopeNone Scope = iota
+	ScopeRead
+	ScopeOperator
+	ScopeAdmin
+)
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	switch s {
+	case ScopeRead:
+		return "read"
+	case ScopeOperator:
+		return "operator"
+	case ScopeAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// ParseScope returns the scope with the given name. Unknown
+// names return the empty scope, which permits no operations.
+func ParseScope(name string) Scope {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "read", "read-only", "readonly":
+		return ScopeRead
+	case "operator":
+		return ScopeOperator
+	case "admin":
+		return ScopeAdmin
+	default:
+		return ScopeNone
+	}
+}
+
+type (
+	scopeKey struct{}
+	userKey  struct{}
+)
+
+// WithScope returns a copy of the context with the scope of
+// the authorized request.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFrom returns the scope of the authorized request from
+// the context, or the empty scope if the request is not
+// authorized.
+func ScopeFrom(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+// WithUser returns a copy of the context with the name of the
+// user or token that authorized the request.
+func WithUser(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, userKey{}, name)
+}
+
+// UserFrom returns the name of the user or token that
+// authorized the request from the context.
+func UserFrom(ctx context.Context) string {
+	name, _ := ctx.Value(userKey{}).(string)
+	return name
+}
+
+// RequireScope returns a middleware function that restricts
+// access to requests authorized with at least the given scope.
+// It must be used after the CheckDrone middleware.
+func RequireScope(scope Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := ScopeFrom(r.Context()); got < scope {
+				hlog.FromRequest(r).Debug().
+					Str("scope", got.String()).
+					Str("required", scope.String()).
+					Msg("insufficient scope")
+				writeForbidden(w, errForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Authenticate returns the name and scope of the bearer token.
+// The token is first matched against the configured api tokens.
+// Identity tokens are verified with the OIDC authenticator, if
+// configured, and other tokens are used to authenticate with
+// the Drone API, where administrators are granted the admin
+// scope and other users are granted no scope.
+func Authenticate(ctx context.Context, conf config.Config, oidc *OIDC, token string) (string, Scope, error) {
+	for key, name := range conf.API.Tokens {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			return tokenName(key), ParseScope(name), nil
+		}
+	}
+	if oidc != nil && isJWT(token) {
+		return oidc.Authenticate(ctx, token)
+	}
+	user, err := LookupUser(conf, token)
+	if err != nil {
+		return "", ScopeNone, err
+	}
+	if !user.Admin {
+		return user.Login, ScopeNone, nil
+	}
+	return user.Login, ScopeAdmin, nil
+}
+
+// helper function returns the name of the api token used in
+// the logs, which identifies the token without revealing it.
+func tokenName(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:4])
+}
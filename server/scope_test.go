@@ -0,0 +1,80 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/autoscaler/config"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := map[string]Scope{
+		"read":      ScopeRead,
+		"read-only": ScopeRead,
+		"Operator":  ScopeOperator,
+		"admin":     ScopeAdmin,
+		"root":      ScopeNone,
+		"":          ScopeNone,
+	}
+	for name, want := range tests {
+		if got := ParseScope(name); got != want {
+			t.Errorf("Want scope %s for %q, got %s", want, name, got)
+		}
+	}
+}
+
+func TestAuthorizeScopedToken(t *testing.T) {
+	c := config.Config{}
+	c.API.Tokens = map[string]string{
+		"cmVhZC1vbmx5LXRva2Vu": "read",
+		"b3BlcmF0b3ItdG9rZW4=": "operator",
+	}
+
+	tests := []struct {
+		token string
+		scope Scope
+		code  int
+	}{
+		{token: "cmVhZC1vbmx5LXRva2Vu", scope: ScopeOperator, code: 403},
+		{token: "b3BlcmF0b3ItdG9rZW4=", scope: ScopeOperator, code: 204},
+		{token: "b3BlcmF0b3ItdG9rZW4=", scope: ScopeAdmin, code: 403},
+		{token: "cmVhZC1vbmx5LXRva2Vu", scope: ScopeRead, code: 204},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+test.token)
+
+		CheckDrone(c, nil)(
+			RequireScope(test.scope)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(204)
+				}),
+			),
+		).ServeHTTP(w, r)
+
+		if got, want := w.Code, test.code; got != want {
+			t.Errorf("Want status code %d for scope %s, got %d", want, test.scope, got)
+		}
+	}
+}
+
+func TestRequireScope_Unauthorized(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	RequireScope(ScopeRead)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Expect access to handler is restricted")
+		}),
+	).ServeHTTP(w, r)
+
+	if got, want := w.Code, 403; got != want {
+		t.Errorf("Want status code %d, got %d", want, got)
+	}
+}
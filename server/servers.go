@@ -5,8 +5,12 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/dchest/uniuri"
 	"github.com/drone/autoscaler"
@@ -16,12 +20,40 @@ import (
 	"github.com/rs/zerolog/hlog"
 )
 
+var (
+	// errInvalidSort is returned when the list request sort
+	// order is not supported.
+	errInvalidSort = errors.New("Invalid sort, want created or -created")
+
+	// errInvalidLimit is returned when the list request limit
+	// is not a positive integer.
+	errInvalidLimit = errors.New("Invalid limit")
+
+	// errInvalidOffset is returned when the list request offset
+	// is not a positive integer.
+	errInvalidOffset = errors.New("Invalid offset")
+
+	// errInvalidLabel is returned when the list request label
+	// is not formatted as key:value.
+	errInvalidLabel = errors.New("Invalid label, want key:value")
+)
+
 // HandleServerList returns an http.HandlerFunc that writes
-// the json-encoded server list to the the response body.
+// the json-encoded server list to the the response body. The
+// list is filtered by the state, pool and label query
+// parameters, includes the soft-deleted servers if the include_deleted
+// query parameter is true, is ordered by the sort query
+// parameter, and is paginated with the limit and offset query
+// parameters.
 func HandleServerList(servers autoscaler.ServerStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		list, err := servers.List(ctx)
+		filter, err := parseFilter(r)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		list, err := servers.ListFilter(ctx, filter)
 		if err != nil {
 			hlog.FromRequest(r).
 				Error().
@@ -30,13 +62,95 @@ func HandleServerList(servers autoscaler.ServerStore) http.HandlerFunc {
 			writeError(w, err)
 			return
 		}
-		writeJSON(w, list, 200)
+		writeJSON(w, redactList(ctx, list), 200)
 	}
 }
 
+// serverDetail is the json-encoded server detail, which
+// includes the provider metadata of the instance and the
+// state history of the server.
+type serverDetail struct {
+	*autoscaler.Server
+	Instance *instanceDetail           `json:"instance,omitempty"`
+	History  []*autoscaler.ServerEvent `json:"history,omitempty"`
+}
+
+// instanceDetail is the json-encoded provider metadata of
+// the server instance.
+type instanceDetail struct {
+	Status         string `json:"status,omitempty"`
+	Pricing        string `json:"pricing,omitempty"`
+	PublicAddress  string `json:"public_address,omitempty"`
+	PrivateAddress string `json:"private_address,omitempty"`
+}
+
 // HandleServerFind returns an http.HandlerFunc that finds
 // and writes the json-encoded server to the the response body.
-func HandleServerFind(servers autoscaler.ServerStore) http.HandlerFunc {
+// The server includes the state history, and the provider
+// metadata of the instance if the provider of the server
+// pool implements the Inspector interface.
+func HandleServerFind(
+	servers autoscaler.ServerStore,
+	events autoscaler.ServerEventStore,
+	providers map[string]autoscaler.Provider,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		name := chi.URLParam(r, "name")
+		logger := hlog.FromRequest(r).With().
+			Str("server", name).
+			Logger()
+
+		server, err := servers.Find(ctx, name)
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Msg("cannot get server")
+			writeNotFound(w, err)
+			return
+		}
+
+		detail := &serverDetail{Server: redact(ctx, server)}
+		if events != nil {
+			detail.History, err = events.List(ctx, name)
+			if err != nil {
+				logger.Warn().
+					Err(err).
+					Msg("cannot get server events")
+			}
+		}
+
+		inspector, ok := providers[server.Pool].(autoscaler.Inspector)
+		if ok && server.ID != "" && server.State == autoscaler.StateRunning {
+			instance, err := inspector.Inspect(ctx, &autoscaler.Instance{
+				ID:       server.ID,
+				Provider: server.Provider,
+				Name:     server.Name,
+				Address:  server.Address,
+				Region:   server.Region,
+				Image:    server.Image,
+				Size:     server.Size,
+			})
+			if err != nil {
+				logger.Warn().
+					Err(err).
+					Msg("cannot inspect server")
+			} else {
+				detail.Instance = &instanceDetail{
+					Status:         instance.Status,
+					Pricing:        instance.Pricing,
+					PublicAddress:  instance.PublicAddress,
+					PrivateAddress: instance.PrivateAddress,
+				}
+			}
+		}
+		writeJSON(w, detail, 200)
+	}
+}
+
+// HandleServerLogs returns an http.HandlerFunc that writes
+// the install logs of the named server to the response body.
+func HandleServerLogs(servers autoscaler.ServerStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		name := chi.URLParam(r, "name")
@@ -50,14 +164,92 @@ func HandleServerFind(servers autoscaler.ServerStore) http.HandlerFunc {
 			writeNotFound(w, err)
 			return
 		}
-		writeJSON(w, server, 200)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(200)
+		w.Write(server.Logs)
+	}
+}
+
+// HandleServerEvents returns an http.HandlerFunc that writes
+// the json-encoded state transitions of the named server to
+// the response body.
+func HandleServerEvents(events autoscaler.ServerEventStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		name := chi.URLParam(r, "name")
+		list, err := events.List(ctx, name)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Str("server", name).
+				Msg("cannot get server events")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, list, 200)
+	}
+}
+
+// HandleServerAnnotate returns an http.HandlerFunc that
+// merges the json-encoded annotations in the request body
+// with the annotations of the named server. Annotations with
+// an empty value are removed.
+func HandleServerAnnotate(servers autoscaler.ServerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		name := chi.URLParam(r, "name")
+
+		in := autoscaler.Annotations{}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+
+		server, err := servers.Find(ctx, name)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Str("server", name).
+				Msg("cannot get server")
+			writeNotFound(w, err)
+			return
+		}
+
+		if server.Annotations == nil {
+			server.Annotations = autoscaler.Annotations{}
+		}
+		for k, v := range in {
+			if v == "" {
+				delete(server.Annotations, k)
+			} else {
+				server.Annotations[k] = v
+			}
+		}
+
+		err = servers.Annotate(ctx, server)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Str("server", name).
+				Msg("cannot annotate server")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, redact(ctx, server), 200)
 	}
 }
 
 // HandleServerDelete returns an http.HandlerFunc that destroys
-// and then deletes the named server.
+// and then deletes the named server. If the force query
+// parameter is true, the server is destroyed immediately,
+// without draining the running builds, which is used to
+// destroy servers that are hung.
 func HandleServerDelete(
 	servers autoscaler.ServerStore,
+	engine autoscaler.Engine,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -93,6 +285,27 @@ func HandleServerDelete(
 			return
 		}
 
+		if force {
+			logger := hlog.FromRequest(r)
+			err = engine.Destroy(logger.WithContext(context.Background()), name)
+			if err == autoscaler.ErrServerNotFound {
+				writeNotFound(w, err)
+				return
+			}
+			if err != nil {
+				logger.Error().Err(err).
+					Str("server", name).
+					Msg("cannot destroy server")
+				writeErrorCode(w, err, 409)
+				return
+			}
+			logger.Warn().
+				Str("server", name).
+				Msg("server force destroy started")
+			w.WriteHeader(202)
+			return
+		}
+
 		server.State = autoscaler.StateShutdown
 		err = servers.Update(ctx, server)
 		if err != nil {
@@ -109,7 +322,8 @@ func HandleServerDelete(
 }
 
 // HandleServerCreate returns an http.HandlerFunc that creates
-// and a new server.
+// and a new server. The optional pool query parameter assigns
+// the server to a named pool.
 func HandleServerCreate(
 	servers autoscaler.ServerStore,
 	config config.Config,
@@ -120,6 +334,7 @@ func HandleServerCreate(
 			Name:     "agent-" + uniuri.NewLen(8),
 			State:    autoscaler.StatePending,
 			Capacity: config.Agent.Concurrency,
+			Pool:     r.URL.Query().Get("pool"),
 		}
 		err := servers.Create(ctx, server)
 		if err != nil {
@@ -130,6 +345,81 @@ func HandleServerCreate(
 			writeError(w, err)
 			return
 		}
-		writeJSON(w, server, 200)
+		writeJSON(w, redact(ctx, server), 200)
+	}
+}
+
+// helper function returns a copy of the server without the
+// agent secret and the private keys, unless the request is
+// authorized with the admin scope. The secret and keys grant
+// access to the agent, and must not be disclosed to read-only
+// or operator tokens.
+func redact(ctx context.Context, server *autoscaler.Server) *autoscaler.Server {
+	if ScopeFrom(ctx) >= ScopeAdmin {
+		return server
+	}
+	copy := *server
+	copy.Secret = ""
+	copy.CAKey = nil
+	copy.TLSKey = nil
+	return &copy
+}
+
+// helper function returns a copy of the server list without
+// the agent secrets and the private keys, unless the request
+// is authorized with the admin scope.
+func redactList(ctx context.Context, list []*autoscaler.Server) []*autoscaler.Server {
+	if ScopeFrom(ctx) >= ScopeAdmin {
+		return list
+	}
+	redacted := make([]*autoscaler.Server, len(list))
+	for i, server := range list {
+		redacted[i] = redact(ctx, server)
+	}
+	return redacted
+}
+
+// helper function returns the server filter from the request
+// query parameters.
+func parseFilter(r *http.Request) (autoscaler.ServerFilter, error) {
+	filter := autoscaler.ServerFilter{
+		State: autoscaler.ServerState(r.FormValue("state")),
+		Pool:  r.FormValue("pool"),
+	}
+	filter.Deleted, _ = strconv.ParseBool(r.FormValue("include_deleted"))
+
+	// the label parameter is repeated to filter the servers
+	// with multiple labels.
+	r.ParseForm()
+	for _, label := range r.Form["label"] {
+		parts := strings.SplitN(label, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return filter, errInvalidLabel
+		}
+		if filter.Labels == nil {
+			filter.Labels = autoscaler.Labels{}
+		}
+		filter.Labels[parts[0]] = parts[1]
+	}
+
+	switch r.FormValue("sort") {
+	case "", "created":
+	case "-created":
+		filter.Descending = true
+	default:
+		return filter, errInvalidSort
+	}
+
+	var err error
+	if v := r.FormValue("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 0 {
+			return filter, errInvalidLimit
+		}
+	}
+	if v := r.FormValue("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil || filter.Offset < 0 {
+			return filter, errInvalidOffset
+		}
 	}
+	return filter, nil
 }
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/drone/autoscaler"
@@ -32,7 +33,7 @@ func TestHandleServerList(t *testing.T) {
 	}
 
 	store := mocks.NewMockServerStore(controller)
-	store.EXPECT().List(gomock.Any()).Return(servers, nil)
+	store.EXPECT().ListFilter(gomock.Any(), autoscaler.ServerFilter{}).Return(servers, nil)
 
 	HandleServerList(store).ServeHTTP(w, r)
 
@@ -48,6 +49,136 @@ func TestHandleServerList(t *testing.T) {
 	}
 }
 
+// this test verifies that the agent secret and the private
+// keys are not disclosed to read-only tokens.
+func TestHandleServerList_Redacted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", Secret: "correct-horse-battery-staple", CAKey: []byte("ca-key"), TLSKey: []byte("tls-key")},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListFilter(gomock.Any(), autoscaler.ServerFilter{}).Return(servers, nil).Times(2)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers", nil)
+	r = r.WithContext(WithScope(r.Context(), ScopeRead))
+	HandleServerList(store).ServeHTTP(w, r)
+
+	body := w.Body.String()
+	for _, key := range []string{"secret", "ca_key", "tls_key"} {
+		if !strings.Contains(body, `"`+key+`":""`) && !strings.Contains(body, `"`+key+`":null`) {
+			t.Errorf("Want %s redacted for read scope, got %s", key, body)
+		}
+	}
+	if got, want := servers[0].Secret, "correct-horse-battery-staple"; got != want {
+		t.Errorf("Want stored server not modified")
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/api/servers", nil)
+	r = r.WithContext(WithScope(r.Context(), ScopeAdmin))
+	HandleServerList(store).ServeHTTP(w, r)
+
+	if !strings.Contains(w.Body.String(), "correct-horse-battery-staple") {
+		t.Errorf("Want secret included for admin scope")
+	}
+}
+
+func TestHandleServerFind_Redacted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers/server1", nil)
+	r = r.WithContext(WithScope(r.Context(), ScopeRead))
+
+	server := &autoscaler.Server{Name: "server1", Secret: "correct-horse-battery-staple", CAKey: []byte("ca-key"), TLSKey: []byte("tls-key")}
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "server1").Return(server, nil)
+
+	router := chi.NewRouter()
+	router.Get("/api/servers/{name}", HandleServerFind(store, nil, nil))
+	router.ServeHTTP(w, r)
+
+	got := new(autoscaler.Server)
+	json.NewDecoder(w.Body).Decode(got)
+	if got.Secret != "" || len(got.CAKey) != 0 || len(got.TLSKey) != 0 {
+		t.Errorf("Want secret and private keys redacted for read scope")
+	}
+}
+
+func TestHandleServerList_Filter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers?state=stopped&pool=arm64&include_deleted=true&sort=-created&limit=10&offset=20", nil)
+
+	servers := []*autoscaler.Server{
+		{Name: "server1", State: autoscaler.StateStopped, Pool: "arm64", Deleted: 1},
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListFilter(gomock.Any(), autoscaler.ServerFilter{
+		State:      autoscaler.StateStopped,
+		Pool:       "arm64",
+		Deleted:    true,
+		Descending: true,
+		Limit:      10,
+		Offset:     20,
+	}).Return(servers, nil)
+
+	HandleServerList(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.Server{}, servers
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
+
+func TestHandleServerList_Labels(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers?label=arch:arm64&label=region:us-east", nil)
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().ListFilter(gomock.Any(), autoscaler.ServerFilter{
+		Labels: autoscaler.Labels{"arch": "arm64", "region": "us-east"},
+	}).Return([]*autoscaler.Server{}, nil)
+
+	HandleServerList(store).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerList_BadRequest(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mocks.NewMockServerStore(controller)
+	for _, query := range []string{"sort=name", "limit=-1", "offset=ten", "label=arch"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/servers?"+query, nil)
+		HandleServerList(store).ServeHTTP(w, r)
+		if got, want := w.Code, 400; want != got {
+			t.Errorf("Want response code %d for %s, got %d", want, query, got)
+		}
+	}
+}
+
 func TestHandleServerListErr(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -57,7 +188,7 @@ func TestHandleServerListErr(t *testing.T) {
 
 	err := errors.New("not found")
 	store := mocks.NewMockServerStore(controller)
-	store.EXPECT().List(gomock.Any()).Return(nil, err)
+	store.EXPECT().ListFilter(gomock.Any(), gomock.Any()).Return(nil, err)
 
 	HandleServerList(store).ServeHTTP(w, r)
 
@@ -72,6 +203,36 @@ func TestHandleServerListErr(t *testing.T) {
 	}
 }
 
+func TestHandleServerAnnotate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/api/servers/server1/annotations",
+		strings.NewReader(`{"owner": "ci", "incident": ""}`))
+
+	server := &autoscaler.Server{
+		Name:        "server1",
+		Annotations: autoscaler.Annotations{"incident": "INC-42", "team": "infra"},
+	}
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "server1").Return(server, nil)
+	store.EXPECT().Annotate(gomock.Any(), server).Return(nil)
+
+	router := chi.NewRouter()
+	router.Patch("/api/servers/{name}/annotations", HandleServerAnnotate(store))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	want := autoscaler.Annotations{"owner": "ci", "team": "infra"}
+	if !reflect.DeepEqual(server.Annotations, want) {
+		t.Errorf("Want annotations merged, got %v", server.Annotations)
+	}
+}
+
 func TestHandleServerFind(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -84,7 +245,7 @@ func TestHandleServerFind(t *testing.T) {
 	store.EXPECT().Find(gomock.Any(), "server1").Return(server, nil)
 
 	router := chi.NewRouter()
-	router.Get("/api/servers/{name}", HandleServerFind(store))
+	router.Get("/api/servers/{name}", HandleServerFind(store, nil, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 200; want != got {
@@ -99,6 +260,61 @@ func TestHandleServerFind(t *testing.T) {
 	}
 }
 
+func TestHandleServerFind_Detail(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers/server1", nil)
+
+	server := &autoscaler.Server{Name: "server1", ID: "i-5203422c", Pool: "arm64", State: autoscaler.StateRunning}
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "server1").Return(server, nil)
+
+	history := []*autoscaler.ServerEvent{
+		{ID: 1, Server: "server1", From: autoscaler.StateStaging, To: autoscaler.StateRunning},
+	}
+	events := mocks.NewMockServerEventStore(controller)
+	events.EXPECT().List(gomock.Any(), "server1").Return(history, nil)
+
+	inspector := mocks.NewMockInspector(controller)
+	inspector.EXPECT().Inspect(gomock.Any(), gomock.Any()).Return(&autoscaler.Instance{
+		ID:             "i-5203422c",
+		Pricing:        "spot",
+		PrivateAddress: "10.0.0.2",
+	}, nil)
+	providers := map[string]autoscaler.Provider{
+		"arm64": inspectingProvider{
+			MockProvider:  mocks.NewMockProvider(controller),
+			MockInspector: inspector,
+		},
+	}
+
+	router := chi.NewRouter()
+	router.Get("/api/servers/{name}", HandleServerFind(store, events, providers))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := &serverDetail{}
+	json.NewDecoder(w.Body).Decode(got)
+	if got.Instance == nil || got.Instance.Pricing != "spot" || got.Instance.PrivateAddress != "10.0.0.2" {
+		t.Errorf("Want provider metadata in the server detail")
+	}
+	if len(got.History) != 1 {
+		t.Errorf("Want state history in the server detail")
+	}
+}
+
+// inspectingProvider combines a mock provider and a mock
+// inspector to emulate a provider with inspection support.
+type inspectingProvider struct {
+	*mocks.MockProvider
+	*mocks.MockInspector
+}
+
 func TestHandleServerFindErr(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -111,7 +327,7 @@ func TestHandleServerFindErr(t *testing.T) {
 	store.EXPECT().Find(gomock.Any(), "server1").Return(nil, err)
 
 	router := chi.NewRouter()
-	router.Get("/api/servers/{name}", HandleServerFind(store))
+	router.Get("/api/servers/{name}", HandleServerFind(store, nil, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 404; want != got {
@@ -125,6 +341,78 @@ func TestHandleServerFindErr(t *testing.T) {
 	}
 }
 
+func TestHandleServerLogs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers/server1/logs", nil)
+
+	server := &autoscaler.Server{Name: "server1", Logs: []byte("phase instance started\n")}
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "server1").Return(server, nil)
+
+	router := chi.NewRouter()
+	router.Get("/api/servers/{name}/logs", HandleServerLogs(store))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Body.String(), "phase instance started\n"; got != want {
+		t.Errorf("Want server logs %q, got %q", want, got)
+	}
+}
+
+func TestHandleServerLogsNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers/server1/logs", nil)
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), "server1").Return(nil, errors.New("not found"))
+
+	router := chi.NewRouter()
+	router.Get("/api/servers/{name}/logs", HandleServerLogs(store))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerEvents(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/servers/server1/events", nil)
+
+	events := []*autoscaler.ServerEvent{
+		{ID: 1, Server: "server1", To: autoscaler.StatePending, Created: 1576029139},
+		{ID: 2, Server: "server1", From: autoscaler.StatePending, To: autoscaler.StateCreating, Created: 1576029140},
+	}
+	store := mocks.NewMockServerEventStore(controller)
+	store.EXPECT().List(gomock.Any(), "server1").Return(events, nil)
+
+	router := chi.NewRouter()
+	router.Get("/api/servers/{name}/events", HandleServerEvents(store))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*autoscaler.ServerEvent{}, events
+	json.NewDecoder(w.Body).Decode(&got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response body does match expected result")
+		pretty.Ldiff(t, got, want)
+	}
+}
+
 func TestHandleServerCreate(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -186,7 +474,7 @@ func TestHandleServerDelete(t *testing.T) {
 	store.EXPECT().Update(gomock.Any(), server).Return(nil)
 
 	router := chi.NewRouter()
-	router.Delete("/api/servers/{name}", HandleServerDelete(store))
+	router.Delete("/api/servers/{name}", HandleServerDelete(store, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 200; want != got {
@@ -210,7 +498,7 @@ func TestHandleServerDeleteNotFound(t *testing.T) {
 	store.EXPECT().Find(gomock.Any(), "i-5203422c").Return(nil, err)
 
 	router := chi.NewRouter()
-	router.Delete("/api/servers/{name}", HandleServerDelete(store))
+	router.Delete("/api/servers/{name}", HandleServerDelete(store, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 404; want != got {
@@ -245,7 +533,7 @@ func TestHandleServerDeleteFailure(t *testing.T) {
 	store.EXPECT().Update(gomock.Any(), server).Return(err)
 
 	router := chi.NewRouter()
-	router.Delete("/api/servers/{name}", HandleServerDelete(store))
+	router.Delete("/api/servers/{name}", HandleServerDelete(store, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 500; want != got {
@@ -280,7 +568,7 @@ func TestHandleServerDeleteErrorState(t *testing.T) {
 	store.EXPECT().Delete(gomock.Any(), server).Return(nil)
 
 	router := chi.NewRouter()
-	router.Delete("/api/servers/{name}", HandleServerDelete(store))
+	router.Delete("/api/servers/{name}", HandleServerDelete(store, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 204; want != got {
@@ -309,10 +597,67 @@ func TestHandleServerForceDeleteErrorState(t *testing.T) {
 	store.EXPECT().Delete(gomock.Any(), server).Return(nil)
 
 	router := chi.NewRouter()
-	router.Delete("/api/servers/{name}", HandleServerDelete(store))
+	router.Delete("/api/servers/{name}", HandleServerDelete(store, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 204; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
 }
+
+func TestHandleServerForceDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/api/servers/i-5203422c?force=true", nil)
+
+	server := &autoscaler.Server{
+		ID:    "i-5203422c",
+		State: autoscaler.StateRunning,
+		Name:  "i-5203422c",
+	}
+
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), server.Name).Return(server, nil)
+
+	engine := mocks.NewMockEngine(controller)
+	engine.EXPECT().Destroy(gomock.Any(), server.Name).Return(nil)
+
+	router := chi.NewRouter()
+	router.Delete("/api/servers/{name}", HandleServerDelete(store, engine))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 202; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleServerForceDeleteConflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/api/servers/i-5203422c?force=true", nil)
+
+	server := &autoscaler.Server{
+		ID:    "i-5203422c",
+		State: autoscaler.StateStopped,
+		Name:  "i-5203422c",
+	}
+
+	err := errors.New("Server cannot be destroyed in its current state")
+	store := mocks.NewMockServerStore(controller)
+	store.EXPECT().Find(gomock.Any(), server.Name).Return(server, nil)
+
+	engine := mocks.NewMockEngine(controller)
+	engine.EXPECT().Destroy(gomock.Any(), server.Name).Return(err)
+
+	router := chi.NewRouter()
+	router.Delete("/api/servers/{name}", HandleServerDelete(store, engine))
+	router.ServeHTTP(w, r)
+
+	if got, want := w.Code, 409; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/store"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// importResult is the json-encoded result of a snapshot
+// import.
+type importResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+// HandleExport returns an http.HandlerFunc that writes the
+// json-encoded snapshot of the servers, including soft-deleted
+// servers, and the server events to the response body.
+func HandleExport(servers autoscaler.ServerStore, events autoscaler.ServerEventStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		snapshot, err := store.Export(r.Context(), servers, events)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot export snapshot")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, snapshot, 200)
+	}
+}
+
+// HandleImport returns an http.HandlerFunc that creates the
+// servers and server events of the json-encoded snapshot in
+// the request body. Servers that already exist are skipped.
+func HandleImport(servers autoscaler.ServerStore, events autoscaler.ServerEventStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		snapshot := new(store.Snapshot)
+		if err := json.NewDecoder(r.Body).Decode(snapshot); err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		if snapshot.Version != store.SnapshotVersion {
+			writeBadRequest(w, store.ErrSnapshotVersion)
+			return
+		}
+		imported, err := store.Import(r.Context(), servers, events, snapshot)
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot import snapshot")
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, &importResult{
+			Imported: imported,
+			Skipped:  len(snapshot.Servers) - imported,
+		}, 200)
+	}
+}
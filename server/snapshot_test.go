@@ -0,0 +1,105 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+	"github.com/drone/autoscaler/store"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleExport(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/export", nil)
+
+	servers := mocks.NewMockServerStore(controller)
+	servers.EXPECT().List(gomock.Any()).Return([]*autoscaler.Server{{Name: "server1", SSHKey: []byte("key")}}, nil)
+	servers.EXPECT().ListDeleted(gomock.Any()).Return([]*autoscaler.Server{{Name: "server2"}}, nil)
+	servers.EXPECT().Find(gomock.Any(), "server1").Return(&autoscaler.Server{Name: "server1", Logs: []byte("logs")}, nil)
+	servers.EXPECT().Find(gomock.Any(), "server2").Return(&autoscaler.Server{Name: "server2"}, nil)
+
+	events := mocks.NewMockServerEventStore(controller)
+	events.EXPECT().List(gomock.Any(), "server1").Return([]*autoscaler.ServerEvent{{ID: 1, Server: "server1"}}, nil)
+	events.EXPECT().List(gomock.Any(), "server2").Return(nil, nil)
+
+	HandleExport(servers, events).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	snapshot := new(store.Snapshot)
+	json.NewDecoder(w.Body).Decode(snapshot)
+	if got, want := len(snapshot.Servers), 2; got != want {
+		t.Errorf("Want %d servers, got %d", want, got)
+	}
+	if got, want := string(snapshot.Servers[0].Logs), "logs"; got != want {
+		t.Errorf("Want exported logs %q, got %q", want, got)
+	}
+	if got, want := string(snapshot.Servers[0].SSHKey), "key"; got != want {
+		t.Errorf("Want ssh key exported, got %q", got)
+	}
+	if got, want := len(snapshot.Events), 1; got != want {
+		t.Errorf("Want %d events, got %d", want, got)
+	}
+}
+
+func TestHandleImport(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/import", strings.NewReader(`{
+		"version": 1,
+		"servers": [{"name": "server1"}, {"name": "server2"}],
+		"events": [{"server": "server1"}, {"server": "server2"}]
+	}`))
+
+	servers := mocks.NewMockServerStore(controller)
+	servers.EXPECT().Find(gomock.Any(), "server1").Return(&autoscaler.Server{Name: "server1"}, nil)
+	servers.EXPECT().Find(gomock.Any(), "server2").Return(nil, autoscaler.ErrServerNotFound)
+	servers.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	events := mocks.NewMockServerEventStore(controller)
+	events.EXPECT().Create(gomock.Any(), &autoscaler.ServerEvent{Server: "server2"}).Return(nil)
+
+	HandleImport(servers, events).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(importResult)
+	json.NewDecoder(w.Body).Decode(got)
+	if got.Imported != 1 || got.Skipped != 1 {
+		t.Errorf("Want 1 server imported and 1 skipped, got %+v", got)
+	}
+}
+
+func TestHandleImport_Version(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/import", strings.NewReader(`{"version": 2}`))
+
+	servers := mocks.NewMockServerStore(controller)
+	events := mocks.NewMockServerEventStore(controller)
+
+	HandleImport(servers, events).ServeHTTP(w, r)
+
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// streamInterval defines the interval at which the event
+// stores are polled for new events.
+var streamInterval = time.Second
+
+// streamPing defines the interval at which a comment is sent
+// to keep idle connections open.
+var streamPing = 30 * time.Second
+
+// errStreamUnsupported is returned when the response writer
+// does not support streaming.
+var errStreamUnsupported = errors.New("Streaming unsupported")
+
+// HandleEvents returns an http.HandlerFunc that streams the
+// scale events and server state transitions to the client as
+// server-sent events, until the client disconnects. Servers
+// that transition to the error state are sent as error events.
+// The event stores are polled, so that the events of every
+// replica are streamed.
+func HandleEvents(events autoscaler.ServerEventStore, scales autoscaler.ScaleEventStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, errStreamUnsupported)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("Connection", "keep-alive")
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(200)
+		flusher.Flush()
+
+		ctx := r.Context()
+		logger := hlog.FromRequest(r)
+
+		now := time.Now().Unix()
+		serverSince, scaleSince := now, now
+		var serverLast, scaleLast int64
+		pinged := time.Now()
+
+		for {
+			sent := false
+			if events != nil {
+				list, err := events.ListSince(ctx, serverSince)
+				if err != nil {
+					logger.Warn().Err(err).
+						Msg("cannot get server events")
+				}
+				for _, event := range list {
+					if event.ID <= serverLast {
+						continue
+					}
+					serverLast, serverSince = event.ID, event.Created
+					name := "server"
+					if event.To == autoscaler.StateError {
+						name = "error"
+					}
+					writeEvent(w, name, event)
+					sent = true
+				}
+			}
+			if scales != nil {
+				list, err := scales.List(ctx, scaleSince)
+				if err != nil {
+					logger.Warn().Err(err).
+						Msg("cannot get scale events")
+				}
+				for _, event := range list {
+					if event.ID <= scaleLast {
+						continue
+					}
+					scaleLast, scaleSince = event.ID, event.Created
+					writeEvent(w, "scale", event)
+					sent = true
+				}
+			}
+			if !sent && time.Since(pinged) > streamPing {
+				io.WriteString(w, ": ping\n\n")
+				sent = true
+			}
+			if sent {
+				pinged = time.Now()
+				flusher.Flush()
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(streamInterval):
+			}
+		}
+	}
+}
+
+// helper function writes the json-encoded value to the
+// response as a server-sent event.
+func writeEvent(w io.Writer, name string, v interface{}) {
+	data, _ := json.Marshal(v)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
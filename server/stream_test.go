@@ -0,0 +1,59 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+	"github.com/drone/autoscaler/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleEvents(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defer func(d time.Duration) {
+		streamInterval = d
+	}(streamInterval)
+	streamInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Now().Unix()
+	events := mocks.NewMockServerEventStore(controller)
+	events.EXPECT().ListSince(gomock.Any(), gomock.Any()).Return([]*autoscaler.ServerEvent{
+		{ID: 1, Server: "agent-1", From: autoscaler.StateCreating, To: autoscaler.StateRunning, Created: now},
+		{ID: 2, Server: "agent-2", From: autoscaler.StateCreating, To: autoscaler.StateError, Created: now},
+	}, nil)
+
+	scales := mocks.NewMockScaleEventStore(controller)
+	scales.EXPECT().List(gomock.Any(), gomock.Any()).Do(func(context.Context, int64) {
+		cancel()
+	}).Return([]*autoscaler.ScaleEvent{
+		{ID: 1, Action: autoscaler.ScaleAlloc, Count: 2, Created: now},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/events", nil).WithContext(ctx)
+	HandleEvents(events, scales).ServeHTTP(w, r)
+
+	if got, want := w.Header().Get("Content-Type"), "text/event-stream"; got != want {
+		t.Errorf("Want content type %s, got %s", want, got)
+	}
+	body := w.Body.String()
+	for _, name := range []string{"event: server\n", "event: error\n", "event: scale\n"} {
+		if !strings.Contains(body, name) {
+			t.Errorf("Want %q in the event stream", strings.TrimSpace(name))
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// HandleWebhook returns an http.HandlerFunc that notifies the
+// engine that the build queue is updated, so that capacity is
+// planned immediately. The webhook secret is provided in the
+// secret query parameter, or in the Authorization header, so
+// that the endpoint can be configured as a Drone webhook.
+func HandleWebhook(secret string, notify func(context.Context) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.FormValue("secret")
+		if token == "" {
+			token = r.Header.Get("Authorization")
+			token = strings.TrimPrefix(token, "Bearer ")
+			token = strings.TrimSpace(token)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			hlog.FromRequest(r).
+				Debug().
+				Msg("invalid webhook secret")
+			writeUnauthorized(w, errInvalidToken)
+			return
+		}
+		err := notify(r.Context())
+		if err != nil {
+			hlog.FromRequest(r).
+				Error().
+				Err(err).
+				Msg("cannot send notification")
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(204)
+	}
+}
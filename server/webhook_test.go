@@ -0,0 +1,80 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleWebhook(t *testing.T) {
+	var notified bool
+	notify := func(context.Context) error {
+		notified = true
+		return nil
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/hooks/queue?secret=correct-horse", nil)
+	HandleWebhook("correct-horse", notify).ServeHTTP(w, r)
+
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if !notified {
+		t.Errorf("Want engine notified")
+	}
+}
+
+func TestHandleWebhook_Header(t *testing.T) {
+	var notified bool
+	notify := func(context.Context) error {
+		notified = true
+		return nil
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/hooks/queue", nil)
+	r.Header.Set("Authorization", "Bearer correct-horse")
+	HandleWebhook("correct-horse", notify).ServeHTTP(w, r)
+
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if !notified {
+		t.Errorf("Want engine notified")
+	}
+}
+
+func TestHandleWebhook_Unauthorized(t *testing.T) {
+	notify := func(context.Context) error {
+		t.Errorf("Want engine not notified")
+		return nil
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/hooks/queue?secret=battery-staple", nil)
+	HandleWebhook("correct-horse", notify).ServeHTTP(w, r)
+
+	if got, want := w.Code, 401; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleWebhook_Error(t *testing.T) {
+	notify := func(context.Context) error {
+		return errors.New("connection refused")
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/hooks/queue?secret=correct-horse", nil)
+	HandleWebhook("correct-horse", notify).ServeHTTP(w, r)
+
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
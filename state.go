@@ -0,0 +1,28 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package autoscaler
+
+import "context"
+
+// A PoolStateStore persists the operator controlled state of
+// the engine of each pool, which is shared by the replicas, so
+// that the state is retained when the leader fails over.
+type PoolStateStore interface {
+	// Find returns the state of the named pool.
+	Find(ctx context.Context, pool string) (*PoolState, error)
+
+	// Update creates or updates the state of the pool.
+	Update(context.Context, *PoolState) error
+}
+
+// PoolState stores the operator controlled state of the
+// engine of a pool. The target is negative if no target
+// server count is set.
+type PoolState struct {
+	Pool    string `db:"state_pool"    json:"pool"`
+	Paused  bool   `db:"state_paused"  json:"paused"`
+	Target  int    `db:"state_target"  json:"target"`
+	Updated int64  `db:"state_updated" json:"updated"`
+}
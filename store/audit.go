@@ -0,0 +1,85 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewAuditStore returns a new audit store.
+func NewAuditStore(db *sqlx.DB) autoscaler.AuditStore {
+	return &auditStore{db}
+}
+
+type auditStore struct {
+	*sqlx.DB
+}
+
+func (db *auditStore) List(ctx context.Context, since int64) ([]*autoscaler.AuditEvent, error) {
+	dest := []*autoscaler.AuditEvent{}
+	stmt, args, err := db.BindNamed(auditListStmt, &autoscaler.AuditEvent{Created: since})
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (db *auditStore) Create(ctx context.Context, event *autoscaler.AuditEvent) error {
+	if event.Created == 0 {
+		event.Created = time.Now().Unix()
+	}
+	stmt, args, err := db.BindNamed(auditInsertStmt, event)
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+const auditListStmt = `
+SELECT
+ audit_id
+,audit_user
+,audit_scope
+,audit_method
+,audit_path
+,audit_query
+,audit_target
+,audit_status
+,audit_address
+,audit_created
+FROM audit_events
+WHERE audit_created >= :audit_created
+ORDER BY audit_created ASC, audit_id ASC
+`
+
+const auditInsertStmt = `
+INSERT INTO audit_events (
+ audit_user
+,audit_scope
+,audit_method
+,audit_path
+,audit_query
+,audit_target
+,audit_status
+,audit_address
+,audit_created
+) VALUES (
+ :audit_user
+,:audit_scope
+,:audit_method
+,:audit_path
+,:audit_query
+,:audit_target
+,:audit_status
+,:audit_address
+,:audit_created
+)
+`
@@ -0,0 +1,54 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/kr/pretty"
+)
+
+func TestAuditEvents(t *testing.T) {
+	conn, err := connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	store := NewAuditStore(conn)
+	for _, event := range []*autoscaler.AuditEvent{
+		{User: "octocat", Scope: "admin", Method: "POST", Path: "/api/pause", Status: 204, Created: 86400},
+		{User: "octocat", Scope: "admin", Method: "DELETE", Path: "/api/servers/agent-1", Query: "force=true", Target: "agent-1", Status: 200, Address: "10.0.0.1", Created: 86401},
+	} {
+		if err := store.Create(context.TODO(), event); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	events, err := store.List(context.TODO(), 86401)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(events), 1; got != want {
+		t.Errorf("Want %d audit events, got %d", want, got)
+		return
+	}
+	// the identifier is generated by the database, and is
+	// not sequential in each dialect.
+	events[0].ID = 0
+	want := []*autoscaler.AuditEvent{
+		{User: "octocat", Scope: "admin", Method: "DELETE", Path: "/api/servers/agent-1", Query: "force=true", Target: "agent-1", Status: 200, Address: "10.0.0.1", Created: 86401},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Unexpected audit events")
+		pretty.Ldiff(t, events, want)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// auth methods.
+const (
+	AuthAWS    = "aws"    // rds iam authentication
+	AuthAzure  = "azure"  // azure managed identity
+	AuthGoogle = "google" // cloud sql iam authentication
+)
+
+// A PasswordFunc returns the password used to open a new
+// database connection, for the database host and port, and
+// the database user.
+type PasswordFunc func(ctx context.Context, addr, user string) (string, error)
+
+// Password returns the password function of the named auth
+// method. The region is the aws region of the rds database,
+// and defaults to the region of the aws session. The client
+// id selects the azure user-assigned managed identity, and
+// defaults to the system-assigned managed identity.
+func Password(method, region, clientID string) (PasswordFunc, error) {
+	switch method {
+	case AuthAWS:
+		return AWSPassword(region), nil
+	case AuthAzure:
+		return AzurePassword(clientID), nil
+	case AuthGoogle:
+		return GooglePassword(), nil
+	default:
+		return nil, fmt.Errorf("Invalid database auth method %q", method)
+	}
+}
+
+// AWSPassword returns a password function that generates an
+// rds iam authentication token, signed with the credentials of
+// the aws session. The token is signed locally, and expires
+// after 15 minutes.
+func AWSPassword(region string) PasswordFunc {
+	return func(ctx context.Context, addr, user string) (string, error) {
+		sess, err := session.NewSession()
+		if err != nil {
+			return "", err
+		}
+		if region == "" {
+			region = aws.StringValue(sess.Config.Region)
+		}
+		return rdsutils.BuildAuthToken(addr, region, user, sess.Config.Credentials)
+	}
+}
+
+// azureTokenURL is the instance metadata endpoint that returns
+// the managed identity access tokens.
+const azureTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// azureResource is the resource of the azure database access
+// tokens, for mysql and postgres.
+const azureResource = "https://ossrdbms-aad.database.windows.net"
+
+// AzurePassword returns a password function that returns the
+// access token of the azure managed identity. The token is
+// cached until shortly before it expires.
+func AzurePassword(clientID string) PasswordFunc {
+	var (
+		mu      sync.Mutex
+		token   string
+		expires time.Time
+	)
+	return func(ctx context.Context, addr, user string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if token != "" && time.Now().Before(expires) {
+			return token, nil
+		}
+
+		params := url.Values{}
+		params.Set("api-version", "2018-02-01")
+		params.Set("resource", azureResource)
+		if clientID != "" {
+			params.Set("client_id", clientID)
+		}
+		req, err := http.NewRequest("GET", azureTokenURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata", "true")
+		res, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("Cannot fetch azure access token: %s", res.Status)
+		}
+		out := struct {
+			AccessToken string `json:"access_token"`
+			ExpiresOn   string `json:"expires_on"`
+		}{}
+		if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+			return "", err
+		}
+		seconds, _ := strconv.ParseInt(out.ExpiresOn, 10, 64)
+		token = out.AccessToken
+		expires = time.Unix(seconds, 0).Add(-5 * time.Minute)
+		return token, nil
+	}
+}
+
+// googleScope is the oauth2 scope of the cloud sql iam
+// authentication tokens.
+const googleScope = "https://www.googleapis.com/auth/sqlservice.login"
+
+// GooglePassword returns a password function that returns the
+// oauth2 access token of the google application default
+// credentials. The token is reused until it expires.
+func GooglePassword() PasswordFunc {
+	var (
+		mu     sync.Mutex
+		source oauth2.TokenSource
+	)
+	return func(ctx context.Context, addr, user string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if source == nil {
+			s, err := google.DefaultTokenSource(context.Background(), googleScope)
+			if err != nil {
+				return "", err
+			}
+			source = oauth2.ReuseTokenSource(nil, s)
+		}
+		token, err := source.Token()
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	}
+}
+
+// connector opens database connections with the password
+// returned by the password function.
+type connector struct {
+	driver     driver.Driver
+	name       string
+	datasource string
+	password   PasswordFunc
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	datasource, err := withPassword(ctx, c.name, c.datasource, c.password)
+	if err != nil {
+		return nil, err
+	}
+	return c.driver.Open(datasource)
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// helper function returns the datasource with the password
+// returned by the password function. The mysql datasource
+// allows cleartext passwords, which is required to send the
+// authentication token, and should be combined with tls.
+func withPassword(ctx context.Context, name, datasource string, fn PasswordFunc) (string, error) {
+	switch name {
+	case "postgres":
+		datasource, err := pgNormalize(datasource)
+		if err != nil {
+			return "", err
+		}
+		params := pgParse(datasource)
+		host, port := params["host"], params["port"]
+		if host == "" {
+			host = "localhost"
+		}
+		if port == "" {
+			port = "5432"
+		}
+		password, err := fn(ctx, net.JoinHostPort(host, port), params["user"])
+		if err != nil {
+			return "", err
+		}
+		return pgAppend(datasource, "password", password), nil
+	case "mysql":
+		cfg, err := mysql.ParseDSN(datasource)
+		if err != nil {
+			return "", err
+		}
+		addr := cfg.Addr
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "3306")
+		}
+		password, err := fn(ctx, addr, cfg.User)
+		if err != nil {
+			return "", err
+		}
+		cfg.Passwd = password
+		cfg.AllowCleartextPasswords = true
+		return cfg.FormatDSN(), nil
+	default:
+		return "", fmt.Errorf("Database auth is not supported by %s", name)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// NewAuditStore returns a new audit store. The events are
+// stored below the audit key.
+func NewAuditStore(client *Client) autoscaler.AuditStore {
+	return &auditStore{client}
+}
+
+type auditStore struct {
+	*Client
+}
+
+func (s *auditStore) List(ctx context.Context, since int64) ([]*autoscaler.AuditEvent, error) {
+	values, err := s.list(ctx, "audit")
+	if err != nil {
+		return nil, err
+	}
+	events := []*autoscaler.AuditEvent{}
+	for _, value := range values {
+		event := new(autoscaler.AuditEvent)
+		if err := json.Unmarshal(value, event); err != nil {
+			return nil, err
+		}
+		if event.Created >= since {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+func (s *auditStore) Create(ctx context.Context, event *autoscaler.AuditEvent) error {
+	now := time.Now()
+	event.ID = now.UnixNano()
+	if event.Created == 0 {
+		event.Created = now.Unix()
+	}
+	return s.put(ctx, fmt.Sprintf("audit/%020d", event.ID), event, true)
+}
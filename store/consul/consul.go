@@ -0,0 +1,257 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+// Package consul implements the server and event stores on
+// top of the Consul key value store, using the Consul http api.
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNotFound is returned when the key does not exist.
+var errNotFound = errors.New("Key not found")
+
+// errConflict is returned when the key cannot be created
+// because the key already exists.
+var errConflict = errors.New("Key already exists")
+
+// errModified is returned when the key cannot be updated
+// because the key is repeatedly modified concurrently.
+var errModified = errors.New("Key modified concurrently")
+
+// defaultPrefix defines the default key prefix.
+const defaultPrefix = "drone/autoscaler"
+
+// watchTimeout defines the maximum duration of a blocking
+// query, after which the query returns without changes.
+const watchTimeout = 5 * time.Minute
+
+// Client provides access to the Consul key value store.
+type Client struct {
+	client  *http.Client
+	address string
+	prefix  string
+	token   string
+}
+
+// pair is a key value pair returned by the Consul api.
+type pair struct {
+	Key         string
+	Value       []byte
+	ModifyIndex uint64
+}
+
+// Open returns a Consul client for the datasource, which is
+// the url of the Consul agent, with the key prefix as the url
+// path. The acl token is read from the token query parameter,
+// or the CONSUL_HTTP_TOKEN environment variable.
+//
+//	http://localhost:8500/drone/autoscaler?token=...
+func Open(datasource string) (*Client, error) {
+	u, err := url.Parse(datasource)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("Invalid consul address %q", datasource)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	token := u.Query().Get("token")
+	if token == "" {
+		token = os.Getenv("CONSUL_HTTP_TOKEN")
+	}
+	return &Client{
+		client:  http.DefaultClient,
+		address: u.Scheme + "://" + u.Host,
+		prefix:  prefix,
+		token:   token,
+	}, nil
+}
+
+// Ping verifies the Consul agent is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	res, err := c.do(ctx, "GET", "/v1/status/leader", nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// Watch blocks until a key below the prefix changes, or the
+// watch times out, and returns the index of the change. The
+// index of the previous call is passed to wait for the next
+// change, or zero to return the current index.
+func (c *Client) Watch(ctx context.Context, prefix string, index uint64) (uint64, error) {
+	path := c.path(prefix) + "/?recurse=true&wait=" + watchTimeout.String() +
+		"&index=" + strconv.FormatUint(index, 10)
+	res, err := c.request(ctx, "GET", path, nil)
+	if err != nil {
+		return index, err
+	}
+	res.Body.Close()
+	if res.StatusCode != 200 && res.StatusCode != 404 {
+		return index, fmt.Errorf("consul: %s", res.Status)
+	}
+	return strconv.ParseUint(res.Header.Get("X-Consul-Index"), 10, 64)
+}
+
+// helper function returns the value of the key.
+func (c *Client) get(ctx context.Context, key string, v interface{}) error {
+	res, err := c.do(ctx, "GET", c.path(key)+"?raw=true", nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// helper function returns the values of the keys below the
+// prefix.
+func (c *Client) list(ctx context.Context, prefix string) ([][]byte, error) {
+	res, err := c.do(ctx, "GET", c.path(prefix)+"/?recurse=true", nil)
+	if err == errNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	pairs := []*pair{}
+	if err := json.NewDecoder(res.Body).Decode(&pairs); err != nil {
+		return nil, err
+	}
+	var values [][]byte
+	for _, p := range pairs {
+		values = append(values, p.Value)
+	}
+	return values, nil
+}
+
+// helper function writes the value of the key. If create is
+// true the key is written only if the key does not exist.
+func (c *Client) put(ctx context.Context, key string, v interface{}, create bool) error {
+	path := c.path(key)
+	if create {
+		path = path + "?cas=0"
+	}
+	ok, err := c.write(ctx, "PUT", path, v)
+	if err == nil && !ok {
+		return errConflict
+	}
+	return err
+}
+
+// helper function writes the value of the key only if the key
+// is unchanged since the modify index, or does not exist if
+// the index is zero, and returns true if the key is written.
+func (c *Client) cas(ctx context.Context, key string, v interface{}, index uint64) (bool, error) {
+	return c.write(ctx, "PUT", c.path(key)+"?cas="+strconv.FormatUint(index, 10), v)
+}
+
+// helper function returns the key value pair of the key.
+func (c *Client) pair(ctx context.Context, key string) (*pair, error) {
+	res, err := c.do(ctx, "GET", c.path(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	pairs := []*pair{}
+	if err := json.NewDecoder(res.Body).Decode(&pairs); err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return nil, errNotFound
+	}
+	return pairs[0], nil
+}
+
+// helper function performs the write request, and returns
+// true if the write is applied.
+func (c *Client) write(ctx context.Context, method, path string, v interface{}) (bool, error) {
+	var body []byte
+	if v != nil {
+		var err error
+		if body, err = json.Marshal(v); err != nil {
+			return false, err
+		}
+	}
+	res, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	out, _ := ioutil.ReadAll(res.Body)
+	return strings.TrimSpace(string(out)) == "true", nil
+}
+
+// helper function deletes the key, and the keys below the key
+// if recurse is true.
+func (c *Client) delete(ctx context.Context, key string, recurse bool) error {
+	path := c.path(key)
+	if recurse {
+		path = path + "?recurse=true"
+	}
+	res, err := c.do(ctx, "DELETE", path, nil)
+	if err == errNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// helper function returns the api path of the key.
+func (c *Client) path(key string) string {
+	return "/v1/kv/" + c.prefix + "/" + key
+}
+
+// helper function performs the http request, and returns an
+// error if the response status is not 2xx.
+func (c *Client) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	res, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == 404 {
+		res.Body.Close()
+		return nil, errNotFound
+	}
+	if res.StatusCode > 299 {
+		out, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		return nil, fmt.Errorf("consul: %s: %s", res.Status, bytes.TrimSpace(out))
+	}
+	return res, nil
+}
+
+// helper function performs the http request.
+func (c *Client) request(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.address+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+	return c.client.Do(req)
+}
@@ -0,0 +1,459 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+func TestOpen(t *testing.T) {
+	client, err := Open("http://localhost:8500/drone/pool1?token=secret")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := client.address, "http://localhost:8500"; got != want {
+		t.Errorf("Want address %q, got %q", want, got)
+	}
+	if got, want := client.prefix, "drone/pool1"; got != want {
+		t.Errorf("Want prefix %q, got %q", want, got)
+	}
+	if got, want := client.token, "secret"; got != want {
+		t.Errorf("Want token %q, got %q", want, got)
+	}
+
+	client, _ = Open("http://localhost:8500")
+	if got, want := client.prefix, defaultPrefix; got != want {
+		t.Errorf("Want default prefix %q, got %q", want, got)
+	}
+
+	if _, err := Open("localhost"); err == nil {
+		t.Errorf("Want error parsing an invalid address")
+	}
+}
+
+func TestServerStore(t *testing.T) {
+	kv := newFakeKV()
+	srv := httptest.NewServer(kv)
+	defer srv.Close()
+
+	client, _ := Open(srv.URL + "/drone")
+	store := NewServerStore(client)
+	ctx := context.Background()
+
+	for _, server := range []*autoscaler.Server{
+		{Name: "server1", State: autoscaler.StateRunning, SSHKey: []byte("key"), Logs: []byte("logs"), Labels: autoscaler.Labels{"arch": "arm64"}},
+		{Name: "server2", State: autoscaler.StateStopped, Stopped: 100, Pool: "arm64"},
+	} {
+		if err := store.Create(ctx, server); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	if err := store.Create(ctx, &autoscaler.Server{Name: "server1"}); err != errConflict {
+		t.Errorf("Want conflict creating a duplicate server, got %v", err)
+	}
+
+	server, err := store.Find(ctx, "server1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(server.SSHKey) != "key" || string(server.Logs) != "logs" {
+		t.Errorf("Want ssh key and logs stored with the server")
+	}
+	if _, err := store.Find(ctx, "server3"); err != autoscaler.ErrServerNotFound {
+		t.Errorf("Want server not found, got %v", err)
+	}
+
+	servers, err := store.ListFilter(ctx, autoscaler.ServerFilter{Descending: true, Limit: 1})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(servers) != 1 || servers[0].Name != "server2" {
+		t.Errorf("Want the newest server listed first")
+	}
+
+	servers, err = store.ListByPool(ctx, "arm64")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(servers) != 1 || servers[0].Name != "server2" {
+		t.Errorf("Want the servers of the pool listed")
+	}
+	servers, err = store.ListByLabels(ctx, autoscaler.Labels{"arch": "arm64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(servers) != 1 || servers[0].Name != "server1" {
+		t.Errorf("Want the servers with the labels listed")
+	}
+
+	server.Annotations = autoscaler.Annotations{"owner": "ci"}
+	if err := store.Annotate(ctx, server); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the annotations are preserved when a stale copy of the
+	// server is updated.
+	server.Annotations = nil
+	server.State = autoscaler.StateShutdown
+	if err := store.Update(ctx, server); err != nil {
+		t.Error(err)
+		return
+	}
+	if found, _ := store.Find(ctx, "server1"); found.Annotations["owner"] != "ci" {
+		t.Errorf("Want annotations preserved across updates")
+	}
+
+	if err := store.AddUsage(ctx, "server1", 2, 30); err != nil {
+		t.Error(err)
+		return
+	}
+	// the usage is preserved when a stale copy of the server
+	// is updated.
+	if err := store.Update(ctx, server); err != nil {
+		t.Error(err)
+		return
+	}
+	if found, _ := store.Find(ctx, "server1"); found.Stages != 2 || found.Busy != 30 {
+		t.Errorf("Want usage preserved across updates, got %d stages, %d seconds", found.Stages, found.Busy)
+	}
+	servers, err = store.ListState(ctx, autoscaler.StateShutdown)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(servers) != 1 || servers[0].Name != "server1" {
+		t.Errorf("Want the updated server listed by state")
+	}
+
+	if err := store.Purge(ctx, 200); err != nil {
+		t.Error(err)
+		return
+	}
+	servers, _ = store.List(ctx)
+	if got, want := len(servers), 1; got != want {
+		t.Errorf("Want %d servers after purge, got %d", want, got)
+	}
+	deleted, _ := store.ListDeleted(ctx)
+	if got, want := len(deleted), 1; got != want {
+		t.Errorf("Want %d soft-deleted servers, got %d", want, got)
+	}
+
+	if err := store.Prune(ctx, deleted[0].Deleted+1); err != nil {
+		t.Error(err)
+		return
+	}
+	deleted, _ = store.ListDeleted(ctx)
+	if got, want := len(deleted), 0; got != want {
+		t.Errorf("Want %d soft-deleted servers after prune, got %d", want, got)
+	}
+
+	if err := store.Delete(ctx, server); err != nil {
+		t.Error(err)
+	}
+	servers, _ = store.List(ctx)
+	if got, want := len(servers), 0; got != want {
+		t.Errorf("Want %d servers after delete, got %d", want, got)
+	}
+}
+
+func TestEventStore(t *testing.T) {
+	srv := httptest.NewServer(newFakeKV())
+	defer srv.Close()
+
+	client, _ := Open(srv.URL + "/drone")
+	store := NewEventStore(client)
+	ctx := context.Background()
+
+	for _, to := range []autoscaler.ServerState{autoscaler.StateCreating, autoscaler.StateRunning} {
+		event := &autoscaler.ServerEvent{Server: "server1", To: to}
+		if err := store.Create(ctx, event); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	store.Create(ctx, &autoscaler.ServerEvent{Server: "server2", To: autoscaler.StateCreating})
+
+	events, err := store.List(ctx, "server1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(events), 2; got != want {
+		t.Errorf("Want %d events, got %d", want, got)
+		return
+	}
+	if events[0].To != autoscaler.StateCreating || events[1].To != autoscaler.StateRunning {
+		t.Errorf("Want events ordered oldest first")
+	}
+
+	events, err = store.ListSince(ctx, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(events), 3; got != want {
+		t.Errorf("Want %d events of all servers, got %d", want, got)
+	}
+}
+
+func TestScaleEventStore(t *testing.T) {
+	srv := httptest.NewServer(newFakeKV())
+	defer srv.Close()
+
+	client, _ := Open(srv.URL + "/drone")
+	store := NewScaleEventStore(client)
+	ctx := context.Background()
+
+	for _, event := range []*autoscaler.ScaleEvent{
+		{Action: autoscaler.ScaleAlloc, Count: 2, Created: 86400},
+		{Action: autoscaler.ScaleAlloc, Count: 1, Created: 86401},
+		{Action: autoscaler.ScaleTerminate, Count: 3, Created: 86402},
+		{Action: autoscaler.ScaleAlloc, Count: 5, Created: 172800},
+	} {
+		if err := store.Create(ctx, event); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	summary, err := store.Summary(ctx, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(summary), 3; got != want {
+		t.Errorf("Want %d summaries, got %d", want, got)
+		return
+	}
+	if got := summary[0]; got.Day != 86400 || got.Action != autoscaler.ScaleAlloc || got.Events != 2 || got.Count != 3 {
+		t.Errorf("Want allocations aggregated by day, got %+v", got)
+	}
+
+	if err := store.Prune(ctx, 172800); err != nil {
+		t.Error(err)
+		return
+	}
+	events, _ := store.List(ctx, 0)
+	if got, want := len(events), 1; got != want {
+		t.Errorf("Want %d scale events after prune, got %d", want, got)
+	}
+}
+
+func TestAuditStore(t *testing.T) {
+	srv := httptest.NewServer(newFakeKV())
+	defer srv.Close()
+
+	client, _ := Open(srv.URL + "/drone")
+	store := NewAuditStore(client)
+	ctx := context.Background()
+
+	for _, event := range []*autoscaler.AuditEvent{
+		{User: "octocat", Method: "POST", Path: "/api/pause", Status: 204, Created: 86400},
+		{User: "octocat", Method: "DELETE", Path: "/api/servers/agent-1", Status: 200, Created: 86401},
+	} {
+		if err := store.Create(ctx, event); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	events, err := store.List(ctx, 86401)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(events), 1; got != want {
+		t.Errorf("Want %d audit events, got %d", want, got)
+		return
+	}
+	if got, want := events[0].Path, "/api/servers/agent-1"; got != want {
+		t.Errorf("Want audit event path %s, got %s", want, got)
+	}
+}
+
+func TestPlanStore(t *testing.T) {
+	srv := httptest.NewServer(newFakeKV())
+	defer srv.Close()
+
+	client, _ := Open(srv.URL + "/drone")
+	store := NewPlanStore(client)
+	ctx := context.Background()
+
+	for _, plan := range []*autoscaler.Plan{
+		{Cycle: "a", Action: autoscaler.ScaleNone, Created: 86400},
+		{Cycle: "b", Action: autoscaler.ScaleAlloc, Count: 1, Created: 86401},
+	} {
+		if err := store.Create(ctx, plan); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	plans, err := store.List(ctx, 86401)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(plans), 1; got != want {
+		t.Errorf("Want %d plans, got %d", want, got)
+		return
+	}
+	if got, want := plans[0].Cycle, "b"; got != want {
+		t.Errorf("Want plan cycle %s, got %s", want, got)
+	}
+
+	if err := store.Prune(ctx, 86401); err != nil {
+		t.Error(err)
+		return
+	}
+	plans, _ = store.List(ctx, 0)
+	if got, want := len(plans), 1; got != want {
+		t.Errorf("Want %d plans after prune, got %d", want, got)
+	}
+}
+
+func TestPoolStateStore(t *testing.T) {
+	srv := httptest.NewServer(newFakeKV())
+	defer srv.Close()
+
+	client, _ := Open(srv.URL + "/drone")
+	store := NewPoolStateStore(client)
+	ctx := context.Background()
+
+	if _, err := store.Find(ctx, "default"); err == nil {
+		t.Errorf("Want error finding a pool state that does not exist")
+	}
+	if err := store.Update(ctx, &autoscaler.PoolState{Pool: "default", Paused: true, Target: -1}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := store.Update(ctx, &autoscaler.PoolState{Pool: "default", Target: 5}); err != nil {
+		t.Error(err)
+		return
+	}
+	state, err := store.Find(ctx, "default")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if state.Paused || state.Target != 5 {
+		t.Errorf("Want pool state updated, got %+v", state)
+	}
+}
+
+func TestLeaseStore(t *testing.T) {
+	srv := httptest.NewServer(newFakeKV())
+	defer srv.Close()
+
+	client, _ := Open(srv.URL + "/drone")
+	store := NewLeaseStore(client)
+	ctx := context.Background()
+	expires := time.Now().Add(time.Minute).Unix()
+
+	if held, err := store.Acquire(ctx, "engine", "replica1", expires); err != nil || !held {
+		t.Errorf("Want lease acquired by the first replica, got %v", err)
+	}
+	if held, _ := store.Acquire(ctx, "engine", "replica2", expires); held {
+		t.Errorf("Want lease held by another replica not acquired")
+	}
+	if held, _ := store.Acquire(ctx, "engine", "replica1", expires+1); !held {
+		t.Errorf("Want lease renewed by the holder")
+	}
+	if err := store.Release(ctx, "engine", "replica2"); err != nil {
+		t.Error(err)
+	}
+	if held, _ := store.Acquire(ctx, "engine", "replica2", expires); held {
+		t.Errorf("Want lease not released by another replica")
+	}
+	if err := store.Release(ctx, "engine", "replica1"); err != nil {
+		t.Error(err)
+	}
+	if held, _ := store.Acquire(ctx, "engine", "replica2", expires); !held {
+		t.Errorf("Want released lease acquired by another replica")
+	}
+}
+
+// fakeKV implements the subset of the Consul key value api
+// used by the client.
+type fakeKV struct {
+	sync.Mutex
+	data  map[string][]byte
+	index map[string]uint64
+	last  uint64
+}
+
+func newFakeKV() *fakeKV {
+	return &fakeKV{
+		data:  map[string][]byte{},
+		index: map[string]uint64{},
+	}
+}
+
+func (kv *fakeKV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kv.Lock()
+	defer kv.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	cas, hasCAS := r.URL.Query()["cas"]
+	switch r.Method {
+	case "GET":
+		var keys []string
+		for k := range kv.data {
+			if k == key || (r.FormValue("recurse") != "" && strings.HasPrefix(k, key)) {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			w.WriteHeader(404)
+			return
+		}
+		if r.FormValue("raw") != "" {
+			w.Write(kv.data[key])
+			return
+		}
+		sort.Strings(keys)
+		pairs := []*pair{}
+		for _, k := range keys {
+			pairs = append(pairs, &pair{Key: k, Value: kv.data[k], ModifyIndex: kv.index[k]})
+		}
+		json.NewEncoder(w).Encode(pairs)
+	case "PUT":
+		if hasCAS && cas[0] != strconv.FormatUint(kv.index[key], 10) {
+			w.Write([]byte("false"))
+			return
+		}
+		kv.last++
+		kv.data[key], _ = ioutil.ReadAll(r.Body)
+		kv.index[key] = kv.last
+		w.Write([]byte("true"))
+	case "DELETE":
+		if hasCAS && cas[0] != strconv.FormatUint(kv.index[key], 10) {
+			w.Write([]byte("false"))
+			return
+		}
+		delete(kv.data, key)
+		delete(kv.index, key)
+		w.Write([]byte("true"))
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// NewEventStore returns a new server event store. The events
+// are stored below the events key, grouped by server.
+func NewEventStore(client *Client) autoscaler.ServerEventStore {
+	return &eventStore{client}
+}
+
+type eventStore struct {
+	*Client
+}
+
+func (s *eventStore) List(ctx context.Context, name string) ([]*autoscaler.ServerEvent, error) {
+	values, err := s.list(ctx, "events/"+name)
+	if err != nil {
+		return nil, err
+	}
+	events := []*autoscaler.ServerEvent{}
+	for _, value := range values {
+		event := new(autoscaler.ServerEvent)
+		if err := json.Unmarshal(value, event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+func (s *eventStore) ListSince(ctx context.Context, since int64) ([]*autoscaler.ServerEvent, error) {
+	values, err := s.list(ctx, "events")
+	if err != nil {
+		return nil, err
+	}
+	events := []*autoscaler.ServerEvent{}
+	for _, value := range values {
+		event := new(autoscaler.ServerEvent)
+		if err := json.Unmarshal(value, event); err != nil {
+			return nil, err
+		}
+		if event.Created >= since {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+func (s *eventStore) Create(ctx context.Context, event *autoscaler.ServerEvent) error {
+	now := time.Now()
+	event.ID = now.UnixNano()
+	if event.Created == 0 {
+		event.Created = now.Unix()
+	}
+	key := fmt.Sprintf("events/%s/%020d", event.Server, event.ID)
+	return s.put(ctx, key, event, true)
+}
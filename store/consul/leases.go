@@ -0,0 +1,65 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// NewLeaseStore returns a new lease store. The leases are
+// stored below the leases key, and are updated with
+// check-and-set operations, so that a lease is only granted
+// to one holder.
+func NewLeaseStore(client *Client) autoscaler.LeaseStore {
+	return &leaseStore{client}
+}
+
+type leaseStore struct {
+	*Client
+}
+
+func (s *leaseStore) Acquire(ctx context.Context, name, holder string, expires int64) (bool, error) {
+	lease := &autoscaler.Lease{Name: name, Holder: holder, Expires: expires}
+	current, err := s.pair(ctx, "leases/"+name)
+	if err == errNotFound {
+		return s.cas(ctx, "leases/"+name, lease, 0)
+	}
+	if err != nil {
+		return false, err
+	}
+	held := new(autoscaler.Lease)
+	if err := json.Unmarshal(current.Value, held); err != nil {
+		return false, err
+	}
+	if held.Holder != holder && held.Expires >= time.Now().Unix() {
+		return false, nil
+	}
+	return s.cas(ctx, "leases/"+name, lease, current.ModifyIndex)
+}
+
+func (s *leaseStore) Release(ctx context.Context, name, holder string) error {
+	current, err := s.pair(ctx, "leases/"+name)
+	if err == errNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	held := new(autoscaler.Lease)
+	if err := json.Unmarshal(current.Value, held); err != nil {
+		return err
+	}
+	if held.Holder != holder {
+		return nil
+	}
+	_, err = s.write(ctx, "DELETE", s.path("leases/"+name)+"?cas="+
+		strconv.FormatUint(current.ModifyIndex, 10), nil)
+	return err
+}
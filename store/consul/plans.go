@@ -0,0 +1,71 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// NewPlanStore returns a new plan store. The plans are
+// stored below the plan key.
+func NewPlanStore(client *Client) autoscaler.PlanStore {
+	return &planStore{client}
+}
+
+type planStore struct {
+	*Client
+}
+
+func (s *planStore) List(ctx context.Context, since int64) ([]*autoscaler.Plan, error) {
+	values, err := s.list(ctx, "plan")
+	if err != nil {
+		return nil, err
+	}
+	plans := []*autoscaler.Plan{}
+	for _, value := range values {
+		plan := new(autoscaler.Plan)
+		if err := json.Unmarshal(value, plan); err != nil {
+			return nil, err
+		}
+		if plan.Created >= since {
+			plans = append(plans, plan)
+		}
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].ID < plans[j].ID
+	})
+	return plans, nil
+}
+
+func (s *planStore) Create(ctx context.Context, plan *autoscaler.Plan) error {
+	now := time.Now()
+	plan.ID = now.UnixNano()
+	if plan.Created == 0 {
+		plan.Created = now.Unix()
+	}
+	return s.put(ctx, fmt.Sprintf("plan/%020d", plan.ID), plan, true)
+}
+
+func (s *planStore) Prune(ctx context.Context, before int64) error {
+	plans, err := s.List(ctx, 0)
+	if err != nil {
+		return err
+	}
+	for _, plan := range plans {
+		if plan.Created >= before {
+			continue
+		}
+		if err := s.delete(ctx, fmt.Sprintf("plan/%020d", plan.ID), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
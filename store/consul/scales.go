@@ -0,0 +1,108 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// NewScaleEventStore returns a new scale event store. The
+// events are stored below the scale key, and are aggregated
+// in memory, since consul does not support queries.
+func NewScaleEventStore(client *Client) autoscaler.ScaleEventStore {
+	return &scaleStore{client}
+}
+
+type scaleStore struct {
+	*Client
+}
+
+func (s *scaleStore) List(ctx context.Context, since int64) ([]*autoscaler.ScaleEvent, error) {
+	values, err := s.list(ctx, "scale")
+	if err != nil {
+		return nil, err
+	}
+	events := []*autoscaler.ScaleEvent{}
+	for _, value := range values {
+		event := new(autoscaler.ScaleEvent)
+		if err := json.Unmarshal(value, event); err != nil {
+			return nil, err
+		}
+		if event.Created >= since {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+func (s *scaleStore) Create(ctx context.Context, event *autoscaler.ScaleEvent) error {
+	now := time.Now()
+	event.ID = now.UnixNano()
+	if event.Created == 0 {
+		event.Created = now.Unix()
+	}
+	return s.put(ctx, fmt.Sprintf("scale/%020d", event.ID), event, true)
+}
+
+func (s *scaleStore) Summary(ctx context.Context, since int64) ([]*autoscaler.ScaleSummary, error) {
+	events, err := s.List(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	type group struct {
+		day    int64
+		pool   string
+		action autoscaler.ScaleAction
+	}
+	groups := map[group]*autoscaler.ScaleSummary{}
+	summary := []*autoscaler.ScaleSummary{}
+	for _, event := range events {
+		key := group{event.Created - event.Created%86400, event.Pool, event.Action}
+		sum, ok := groups[key]
+		if !ok {
+			sum = &autoscaler.ScaleSummary{Day: key.day, Pool: key.pool, Action: key.action}
+			groups[key] = sum
+			summary = append(summary, sum)
+		}
+		sum.Events++
+		sum.Count += event.Count
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		a, b := summary[i], summary[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Pool != b.Pool {
+			return a.Pool < b.Pool
+		}
+		return a.Action < b.Action
+	})
+	return summary, nil
+}
+
+func (s *scaleStore) Prune(ctx context.Context, before int64) error {
+	events, err := s.List(ctx, 0)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if event.Created >= before {
+			continue
+		}
+		if err := s.delete(ctx, fmt.Sprintf("scale/%020d", event.ID), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
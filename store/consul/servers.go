@@ -0,0 +1,247 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// NewServerStore returns a new server store. The servers are
+// stored as json encoded values, below the servers key.
+func NewServerStore(client *Client) autoscaler.ServerStore {
+	return &serverStore{client}
+}
+
+type serverStore struct {
+	*Client
+}
+
+// record is the stored representation of the server, which
+// includes the fields excluded from the server json encoding.
+type record struct {
+	*autoscaler.Server
+	SSHKey []byte `json:"ssh_key"`
+	Logs   []byte `json:"logs"`
+}
+
+func (s *serverStore) Find(ctx context.Context, name string) (*autoscaler.Server, error) {
+	rec := record{Server: new(autoscaler.Server)}
+	err := s.get(ctx, "servers/"+name, &rec)
+	if err == errNotFound {
+		return nil, autoscaler.ErrServerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decode(rec), nil
+}
+
+func (s *serverStore) List(ctx context.Context) ([]*autoscaler.Server, error) {
+	return s.filter(ctx, func(server *autoscaler.Server) bool {
+		return server.Deleted == 0
+	})
+}
+
+func (s *serverStore) ListState(ctx context.Context, state autoscaler.ServerState) ([]*autoscaler.Server, error) {
+	return s.filter(ctx, func(server *autoscaler.Server) bool {
+		return server.Deleted == 0 && server.State == state
+	})
+}
+
+func (s *serverStore) ListDeleted(ctx context.Context) ([]*autoscaler.Server, error) {
+	return s.filter(ctx, func(server *autoscaler.Server) bool {
+		return server.Deleted > 0
+	})
+}
+
+func (s *serverStore) ListByPool(ctx context.Context, pool string) ([]*autoscaler.Server, error) {
+	return s.filter(ctx, func(server *autoscaler.Server) bool {
+		return server.Deleted == 0 && server.Pool == pool
+	})
+}
+
+func (s *serverStore) ListByLabels(ctx context.Context, labels autoscaler.Labels) ([]*autoscaler.Server, error) {
+	return s.filter(ctx, func(server *autoscaler.Server) bool {
+		return server.Deleted == 0 && server.Labels.Match(labels)
+	})
+}
+
+func (s *serverStore) ListFilter(ctx context.Context, filter autoscaler.ServerFilter) ([]*autoscaler.Server, error) {
+	servers, err := s.filter(ctx, func(server *autoscaler.Server) bool {
+		return (filter.Deleted || server.Deleted == 0) &&
+			(filter.State == "" || server.State == filter.State) &&
+			(filter.Pool == "" || server.Pool == filter.Pool) &&
+			server.Labels.Match(filter.Labels)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if filter.Descending {
+		for i, j := 0, len(servers)-1; i < j; i, j = i+1, j-1 {
+			servers[i], servers[j] = servers[j], servers[i]
+		}
+	}
+	if filter.Offset >= len(servers) {
+		return []*autoscaler.Server{}, nil
+	}
+	servers = servers[filter.Offset:]
+	if filter.Limit != 0 && filter.Limit < len(servers) {
+		servers = servers[:filter.Limit]
+	}
+	return servers, nil
+}
+
+func (s *serverStore) Create(ctx context.Context, server *autoscaler.Server) error {
+	if server.Created == 0 {
+		server.Created = time.Now().Unix()
+	}
+	server.Updated = time.Now().Unix()
+	return s.put(ctx, "servers/"+server.Name, encode(server), true)
+}
+
+func (s *serverStore) Update(ctx context.Context, server *autoscaler.Server) error {
+	// the stored annotations, labels and usage are preserved,
+	// since the annotations are only changed by Annotate, the
+	// labels are assigned on create, and the usage is only
+	// changed by AddUsage.
+	current, err := s.Find(ctx, server.Name)
+	if err != nil && err != autoscaler.ErrServerNotFound {
+		return err
+	}
+	updated := *server
+	updated.Annotations = nil
+	updated.Labels = nil
+	updated.Stages = 0
+	updated.Busy = 0
+	if current != nil {
+		updated.Annotations = current.Annotations
+		updated.Labels = current.Labels
+		updated.Stages = current.Stages
+		updated.Busy = current.Busy
+	}
+	server.Updated = time.Now().Unix()
+	updated.Updated = server.Updated
+	return s.put(ctx, "servers/"+server.Name, encode(&updated), false)
+}
+
+func (s *serverStore) Annotate(ctx context.Context, server *autoscaler.Server) error {
+	current, err := s.Find(ctx, server.Name)
+	if err != nil {
+		return err
+	}
+	current.Annotations = server.Annotations
+	return s.put(ctx, "servers/"+server.Name, encode(current), false)
+}
+
+func (s *serverStore) AddUsage(ctx context.Context, name string, stages int, busy int64) error {
+	// the usage is incremented with check-and-set operations,
+	// and is retried if the server is updated concurrently.
+	for i := 0; i < 5; i++ {
+		current, err := s.pair(ctx, "servers/"+name)
+		if err == errNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rec := record{Server: new(autoscaler.Server)}
+		if err := json.Unmarshal(current.Value, &rec); err != nil {
+			return err
+		}
+		rec.Server.Stages += stages
+		rec.Server.Busy += busy
+		ok, err := s.cas(ctx, "servers/"+name, rec, current.ModifyIndex)
+		if err != nil || ok {
+			return err
+		}
+	}
+	return errModified
+}
+
+func (s *serverStore) Delete(ctx context.Context, server *autoscaler.Server) error {
+	return s.delete(ctx, "servers/"+server.Name, false)
+}
+
+func (s *serverStore) Purge(ctx context.Context, before int64) error {
+	servers, err := s.ListState(ctx, autoscaler.StateStopped)
+	if err != nil {
+		return err
+	}
+	for _, server := range servers {
+		if server.Stopped >= before {
+			continue
+		}
+		server.Deleted = time.Now().Unix()
+		if err := s.put(ctx, "servers/"+server.Name, encode(server), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *serverStore) Prune(ctx context.Context, before int64) error {
+	servers, err := s.ListDeleted(ctx)
+	if err != nil {
+		return err
+	}
+	for _, server := range servers {
+		if server.Deleted >= before {
+			continue
+		}
+		if err := s.Delete(ctx, server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helper function returns the servers that match the filter,
+// ordered by creation time and name.
+func (s *serverStore) filter(ctx context.Context, match func(*autoscaler.Server) bool) ([]*autoscaler.Server, error) {
+	values, err := s.list(ctx, "servers")
+	if err != nil {
+		return nil, err
+	}
+	servers := []*autoscaler.Server{}
+	for _, value := range values {
+		rec := record{Server: new(autoscaler.Server)}
+		if err := json.Unmarshal(value, &rec); err != nil {
+			return nil, err
+		}
+		if server := decode(rec); match(server) {
+			servers = append(servers, server)
+		}
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].Created == servers[j].Created {
+			return servers[i].Name < servers[j].Name
+		}
+		return servers[i].Created < servers[j].Created
+	})
+	return servers, nil
+}
+
+// helper function returns the stored representation of the
+// server.
+func encode(server *autoscaler.Server) record {
+	return record{
+		Server: server,
+		SSHKey: server.SSHKey,
+		Logs:   server.Logs,
+	}
+}
+
+// helper function returns the server from the stored
+// representation.
+func decode(rec record) *autoscaler.Server {
+	rec.Server.SSHKey = rec.SSHKey
+	rec.Server.Logs = rec.Logs
+	return rec.Server
+}
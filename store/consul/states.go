@@ -0,0 +1,33 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package consul
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// NewPoolStateStore returns a new pool state store. The pool
+// states are stored below the pools key.
+func NewPoolStateStore(client *Client) autoscaler.PoolStateStore {
+	return &poolStateStore{client}
+}
+
+type poolStateStore struct {
+	*Client
+}
+
+func (s *poolStateStore) Find(ctx context.Context, pool string) (*autoscaler.PoolState, error) {
+	state := new(autoscaler.PoolState)
+	err := s.get(ctx, "pools/"+pool, state)
+	return state, err
+}
+
+func (s *poolStateStore) Update(ctx context.Context, state *autoscaler.PoolState) error {
+	state.Updated = time.Now().Unix()
+	return s.put(ctx, "pools/"+state.Pool, state, false)
+}
@@ -13,12 +13,51 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-// Connect to a database and verify with a ping.
-func Connect(driver, datasource string) (*sqlx.DB, error) {
+// Connect to a database, verify with a ping, and perform the
+// database migration.
+func Connect(driver, datasource string, opts ...Option) (*sqlx.DB, error) {
+	db, err := Open(driver, datasource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := setupDatabase(db, driver); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Open connects to a database and verifies with a ping,
+// without performing the database migration.
+func Open(driver, datasource string, opts ...Option) (*sqlx.DB, error) {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	// cockroach uses the postgres wire protocol and bind
+	// variables, with its own database migrations.
+	if driver == "cockroach" {
+		driver = "postgres"
+	}
+	datasource, err := withTLS(driver, datasource, o.tls)
+	if err != nil {
+		return nil, err
+	}
 	db, err := sql.Open(driver, datasource)
 	if err != nil {
 		return nil, err
 	}
+	// the password is replaced when each connection is
+	// opened, since authentication tokens expire.
+	if o.password != nil {
+		drv := db.Driver()
+		db.Close()
+		db = sql.OpenDB(&connector{
+			driver:     drv,
+			name:       driver,
+			datasource: datasource,
+			password:   o.password,
+		})
+	}
 	switch driver {
 	case "postgres":
 		db.SetMaxIdleConns(0)
@@ -31,9 +70,6 @@ func Connect(driver, datasource string) (*sqlx.DB, error) {
 	if err := pingDatabase(dbx); err != nil {
 		return nil, err
 	}
-	if err := setupDatabase(dbx); err != nil {
-		return nil, err
-	}
 	return dbx, nil
 }
 
@@ -62,6 +98,6 @@ func pingDatabase(db *sqlx.DB) (err error) {
 
 // helper function to setup the databsae by performing automated
 // database migration steps.
-func setupDatabase(db *sqlx.DB) error {
-	return ddl.Migrate(db)
+func setupDatabase(db *sqlx.DB, dialect string) error {
+	return ddl.Migrate(db, dialect)
 }
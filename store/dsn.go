@@ -0,0 +1,77 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// helper function returns the postgres datasource in key value
+// format, converting the datasource from url format.
+func pgNormalize(datasource string) (string, error) {
+	if strings.HasPrefix(datasource, "postgres://") ||
+		strings.HasPrefix(datasource, "postgresql://") {
+		return pq.ParseURL(datasource)
+	}
+	return datasource, nil
+}
+
+// helper function appends the key value pair to the postgres
+// datasource in key value format. The last value of a key
+// takes precedence.
+func pgAppend(datasource, key, value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `'`, `\'`, -1)
+	return strings.TrimSpace(datasource + " " + key + "='" + value + "'")
+}
+
+// helper function parses the postgres datasource in key value
+// format. Values may be single quoted, and quotes and
+// backslashes are escaped with a backslash.
+func pgParse(datasource string) map[string]string {
+	params := map[string]string{}
+	s := []rune(datasource)
+	for i := 0; i < len(s); {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] != '=' && s[i] != ' ' {
+			i++
+		}
+		key := string(s[start:i])
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) || s[i] != '=' {
+			break
+		}
+		i++
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		quoted := i < len(s) && s[i] == '\''
+		if quoted {
+			i++
+		}
+		var value []rune
+		for ; i < len(s); i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				value = append(value, s[i])
+				continue
+			}
+			if (quoted && s[i] == '\'') || (!quoted && s[i] == ' ') {
+				i++
+				break
+			}
+			value = append(value, s[i])
+		}
+		params[key] = string(value)
+	}
+	return params
+}
@@ -0,0 +1,135 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPGParse(t *testing.T) {
+	datasource := `host=db.company.com port=5432 user=drone password='it\'s a \\secret' dbname=autoscaler`
+	params := pgParse(datasource)
+	want := map[string]string{
+		"host":     "db.company.com",
+		"port":     "5432",
+		"user":     "drone",
+		"password": `it's a \secret`,
+		"dbname":   "autoscaler",
+	}
+	for key, value := range want {
+		if got := params[key]; got != value {
+			t.Errorf("Want %s %q, got %q", key, value, got)
+		}
+	}
+}
+
+func TestPGAppend(t *testing.T) {
+	datasource := pgAppend("host=localhost password=old", "password", `it's new`)
+	if got, want := pgParse(datasource)["password"], "it's new"; got != want {
+		t.Errorf("Want appended value to take precedence, got %q", got)
+	}
+}
+
+func TestWithTLS_Postgres(t *testing.T) {
+	datasource, err := withTLS("postgres", "postgres://drone@localhost/autoscaler", TLS{
+		Mode: TLSVerifyFull,
+		CA:   "/path/to/ca.pem",
+		Cert: "/path/to/client.pem",
+		Key:  "/path/to/client.key",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	params := pgParse(datasource)
+	want := map[string]string{
+		"sslmode":     "verify-full",
+		"sslrootcert": "/path/to/ca.pem",
+		"sslcert":     "/path/to/client.pem",
+		"sslkey":      "/path/to/client.key",
+		"user":        "drone",
+	}
+	for key, value := range want {
+		if got := params[key]; got != value {
+			t.Errorf("Want %s %q, got %q", key, value, got)
+		}
+	}
+}
+
+func TestWithTLS_MySQLDisable(t *testing.T) {
+	datasource, err := withTLS("mysql", "drone:password@tcp(localhost:3306)/autoscaler", TLS{Mode: TLSDisable})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(datasource, "tls=false") {
+		t.Errorf("Want tls disabled, got %q", datasource)
+	}
+}
+
+func TestWithTLS_Unset(t *testing.T) {
+	datasource, err := withTLS("sqlite3", ":memory:", TLS{})
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := datasource, ":memory:"; got != want {
+		t.Errorf("Want datasource unchanged, got %q", got)
+	}
+}
+
+func TestWithTLS_Invalid(t *testing.T) {
+	if _, err := withTLS("postgres", "host=localhost", TLS{Mode: "sometimes"}); err == nil {
+		t.Errorf("Want error for invalid tls mode")
+	}
+	if _, err := withTLS("sqlite3", ":memory:", TLS{Mode: TLSRequire}); err == nil {
+		t.Errorf("Want error for unsupported driver")
+	}
+}
+
+func TestWithPassword_Postgres(t *testing.T) {
+	var gotAddr, gotUser string
+	fn := func(ctx context.Context, addr, user string) (string, error) {
+		gotAddr, gotUser = addr, user
+		return "token", nil
+	}
+	datasource, err := withPassword(context.Background(), "postgres", "host=db.company.com user=drone", fn)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := gotAddr, "db.company.com:5432"; got != want {
+		t.Errorf("Want address %s, got %s", want, got)
+	}
+	if got, want := gotUser, "drone"; got != want {
+		t.Errorf("Want user %s, got %s", want, got)
+	}
+	if got, want := pgParse(datasource)["password"], "token"; got != want {
+		t.Errorf("Want password %s, got %s", want, got)
+	}
+}
+
+func TestWithPassword_MySQL(t *testing.T) {
+	var gotAddr string
+	fn := func(ctx context.Context, addr, user string) (string, error) {
+		gotAddr = addr
+		return "token", nil
+	}
+	datasource, err := withPassword(context.Background(), "mysql", "drone@tcp(db.company.com:3306)/autoscaler", fn)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := gotAddr, "db.company.com:3306"; got != want {
+		t.Errorf("Want address %s, got %s", want, got)
+	}
+	if !strings.HasPrefix(datasource, "drone:token@") {
+		t.Errorf("Want token password, got %q", datasource)
+	}
+	if !strings.Contains(datasource, "allowCleartextPasswords=true") {
+		t.Errorf("Want cleartext passwords allowed, got %q", datasource)
+	}
+}
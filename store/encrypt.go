@@ -0,0 +1,308 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"github.com/drone/autoscaler"
+)
+
+// errInvalidSecret is returned when the encryption secret is
+// not 32 bytes.
+var errInvalidSecret = errors.New("Encryption secret must be 32 bytes")
+
+// errInvalidCiphertext is returned when the encrypted value is
+// shorter than the nonce.
+var errInvalidCiphertext = errors.New("Invalid ciphertext")
+
+// errUnknownKey is returned when the value is encrypted with a
+// key that is not configured.
+var errUnknownKey = errors.New("Encryption key not found")
+
+// prefix identifies encrypted values. Encrypted values are
+// formatted as the prefix, the key identifier, and the base64
+// encoded nonce and ciphertext, separated by colons.
+const prefix = "aesgcm:"
+
+// Encrypt returns a server store that encrypts the server
+// secrets and private keys at rest with aes-gcm, using the 32
+// byte secret. Values encrypted with the previous secrets are
+// decrypted, and re-encrypted with the secret when the server
+// is updated. Plaintext values are read as-is, and encrypted
+// when the server is updated.
+func Encrypt(store autoscaler.ServerStore, secret string, previous ...string) (autoscaler.ServerStore, error) {
+	s := &encryptStore{
+		ServerStore: store,
+		primary:     keyID(secret),
+		keys:        map[string]cipher.AEAD{},
+	}
+	for _, secret := range append([]string{secret}, previous...) {
+		gcm, err := newAEAD(secret)
+		if err != nil {
+			return nil, err
+		}
+		s.keys[keyID(secret)] = gcm
+		s.order = append(s.order, gcm)
+	}
+	return s, nil
+}
+
+// Reencrypt re-encrypts the secrets and private keys of all
+// servers, including soft-deleted servers, with the current
+// secret of the encrypting server store. It is used to rotate
+// the secret, and to encrypt the plaintext values of servers
+// created before encryption was enabled.
+func Reencrypt(ctx context.Context, store autoscaler.ServerStore) error {
+	servers, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := store.ListDeleted(ctx)
+	if err != nil {
+		return err
+	}
+	for _, server := range append(servers, deleted...) {
+		if err := store.Update(ctx, server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type encryptStore struct {
+	autoscaler.ServerStore
+	primary string
+	keys    map[string]cipher.AEAD
+	order   []cipher.AEAD // primary key first
+}
+
+func (s *encryptStore) Find(ctx context.Context, name string) (*autoscaler.Server, error) {
+	server, err := s.ServerStore.Find(ctx, name)
+	if err != nil {
+		return server, err
+	}
+	return server, s.decrypt(server)
+}
+
+func (s *encryptStore) List(ctx context.Context) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.List(ctx)
+	if err != nil {
+		return servers, err
+	}
+	return servers, s.decryptAll(servers)
+}
+
+func (s *encryptStore) ListState(ctx context.Context, state autoscaler.ServerState) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListState(ctx, state)
+	if err != nil {
+		return servers, err
+	}
+	return servers, s.decryptAll(servers)
+}
+
+func (s *encryptStore) ListDeleted(ctx context.Context) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListDeleted(ctx)
+	if err != nil {
+		return servers, err
+	}
+	return servers, s.decryptAll(servers)
+}
+
+func (s *encryptStore) ListByPool(ctx context.Context, pool string) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListByPool(ctx, pool)
+	if err != nil {
+		return servers, err
+	}
+	return servers, s.decryptAll(servers)
+}
+
+func (s *encryptStore) ListByLabels(ctx context.Context, labels autoscaler.Labels) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListByLabels(ctx, labels)
+	if err != nil {
+		return servers, err
+	}
+	return servers, s.decryptAll(servers)
+}
+
+func (s *encryptStore) ListFilter(ctx context.Context, filter autoscaler.ServerFilter) ([]*autoscaler.Server, error) {
+	servers, err := s.ServerStore.ListFilter(ctx, filter)
+	if err != nil {
+		return servers, err
+	}
+	return servers, s.decryptAll(servers)
+}
+
+func (s *encryptStore) Create(ctx context.Context, server *autoscaler.Server) error {
+	encrypted, err := s.encrypt(server)
+	if err != nil {
+		return err
+	}
+	err = s.ServerStore.Create(ctx, encrypted)
+	server.Created = encrypted.Created
+	server.Updated = encrypted.Updated
+	return err
+}
+
+func (s *encryptStore) Update(ctx context.Context, server *autoscaler.Server) error {
+	encrypted, err := s.encrypt(server)
+	if err != nil {
+		return err
+	}
+	err = s.ServerStore.Update(ctx, encrypted)
+	server.Updated = encrypted.Updated
+	return err
+}
+
+// helper function returns a copy of the server with the
+// secrets and private keys encrypted, so that the caller
+// continues to use the plaintext values. The certificates
+// are public, and are not encrypted.
+func (s *encryptStore) encrypt(server *autoscaler.Server) (*autoscaler.Server, error) {
+	encrypted := *server
+	secret, err := s.seal([]byte(server.Secret))
+	if err != nil {
+		return nil, err
+	}
+	encrypted.Secret = string(secret)
+	if encrypted.CAKey, err = s.seal(server.CAKey); err != nil {
+		return nil, err
+	}
+	if encrypted.TLSKey, err = s.seal(server.TLSKey); err != nil {
+		return nil, err
+	}
+	if encrypted.SSHKey, err = s.seal(server.SSHKey); err != nil {
+		return nil, err
+	}
+	return &encrypted, nil
+}
+
+// helper function decrypts the secrets and private keys of
+// the server.
+func (s *encryptStore) decrypt(server *autoscaler.Server) error {
+	secret, err := s.open([]byte(server.Secret))
+	if err != nil {
+		return err
+	}
+	server.Secret = string(secret)
+	if server.CAKey, err = s.open(server.CAKey); err != nil {
+		return err
+	}
+	if server.TLSKey, err = s.open(server.TLSKey); err != nil {
+		return err
+	}
+	if server.SSHKey, err = s.openSSHKey(server.SSHKey); err != nil {
+		return err
+	}
+	return nil
+}
+
+// helper function decrypts the secrets and private keys of
+// the servers.
+func (s *encryptStore) decryptAll(servers []*autoscaler.Server) error {
+	for _, server := range servers {
+		if err := s.decrypt(server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helper function encrypts the value with the primary key.
+func (s *encryptStore) seal(plaintext []byte) ([]byte, error) {
+	if len(plaintext) == 0 {
+		return plaintext, nil
+	}
+	gcm := s.keys[s.primary]
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(prefix + s.primary + ":" +
+		base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// helper function decrypts the value with the key that
+// encrypted the value. Values without the prefix are not
+// encrypted, and are returned as-is.
+func (s *encryptStore) open(value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, []byte(prefix)) {
+		return value, nil
+	}
+	parts := bytes.SplitN(value[len(prefix):], []byte(":"), 2)
+	if len(parts) != 2 {
+		return nil, errInvalidCiphertext
+	}
+	gcm, ok := s.keys[string(parts[0])]
+	if !ok {
+		return nil, errUnknownKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(parts[1]))
+	if err != nil {
+		return nil, err
+	}
+	return openAEAD(gcm, sealed)
+}
+
+// helper function decrypts the ssh private key. Keys stored
+// before the encrypted values were prefixed are the raw nonce
+// and ciphertext, and are decrypted with each key in turn.
+func (s *encryptStore) openSSHKey(value []byte) ([]byte, error) {
+	if len(value) == 0 ||
+		bytes.HasPrefix(value, []byte(prefix)) ||
+		bytes.HasPrefix(value, []byte("-----BEGIN")) {
+		return s.open(value)
+	}
+	var err error
+	for _, gcm := range s.order {
+		var plaintext []byte
+		plaintext, err = openAEAD(gcm, value)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, err
+}
+
+// helper function decrypts the nonce and ciphertext.
+func openAEAD(gcm cipher.AEAD, sealed []byte) ([]byte, error) {
+	size := gcm.NonceSize()
+	if len(sealed) < size {
+		return nil, errInvalidCiphertext
+	}
+	nonce, ciphertext := sealed[:size], sealed[size:]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// helper function returns the aes-gcm cipher for the 32 byte
+// secret.
+func newAEAD(secret string) (cipher.AEAD, error) {
+	if len(secret) != 32 {
+		return nil, errInvalidSecret
+	}
+	block, err := aes.NewCipher([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// helper function returns the identifier of the secret, which
+// is stored with the encrypted values, so that values can be
+// decrypted after the secret is rotated.
+func keyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewEventStore returns a new server event store.
+func NewEventStore(db *sqlx.DB) autoscaler.ServerEventStore {
+	return &eventStore{db}
+}
+
+type eventStore struct {
+	*sqlx.DB
+}
+
+func (db *eventStore) List(ctx context.Context, name string) ([]*autoscaler.ServerEvent, error) {
+	dest := []*autoscaler.ServerEvent{}
+	stmt, args, err := db.BindNamed(eventListStmt, map[string]interface{}{"event_server": name})
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (db *eventStore) ListSince(ctx context.Context, since int64) ([]*autoscaler.ServerEvent, error) {
+	dest := []*autoscaler.ServerEvent{}
+	stmt, args, err := db.BindNamed(eventListSinceStmt, map[string]interface{}{"event_created": since})
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (db *eventStore) Create(ctx context.Context, event *autoscaler.ServerEvent) error {
+	if event.Created == 0 {
+		event.Created = time.Now().Unix()
+	}
+	stmt, args, err := db.BindNamed(eventInsertStmt, event)
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+// Track returns a server store that records the state
+// transitions of the servers in the event store.
+func Track(store autoscaler.ServerStore, events autoscaler.ServerEventStore) autoscaler.ServerStore {
+	return &trackStore{ServerStore: store, events: events}
+}
+
+type trackStore struct {
+	autoscaler.ServerStore
+	events autoscaler.ServerEventStore
+}
+
+func (s *trackStore) Create(ctx context.Context, server *autoscaler.Server) error {
+	err := s.ServerStore.Create(ctx, server)
+	if err != nil {
+		return err
+	}
+	return s.track(ctx, server, "")
+}
+
+func (s *trackStore) Update(ctx context.Context, server *autoscaler.Server) error {
+	var from autoscaler.ServerState
+	if before, err := s.ServerStore.Find(ctx, server.Name); err == nil {
+		from = before.State
+	}
+	err := s.ServerStore.Update(ctx, server)
+	if err != nil || from == server.State {
+		return err
+	}
+	return s.track(ctx, server, from)
+}
+
+// helper function records the state transition of the
+// server. The error is recorded as the reason for servers
+// that transition to the error state.
+func (s *trackStore) track(ctx context.Context, server *autoscaler.Server, from autoscaler.ServerState) error {
+	event := &autoscaler.ServerEvent{
+		Server: server.Name,
+		From:   from,
+		To:     server.State,
+	}
+	if server.State == autoscaler.StateError {
+		event.Reason = server.Error
+	}
+	return s.events.Create(ctx, event)
+}
+
+const eventListStmt = `
+SELECT
+ event_id
+,event_server
+,event_from
+,event_to
+,event_reason
+,event_created
+FROM server_events
+WHERE event_server=:event_server
+ORDER BY event_id ASC
+`
+
+const eventListSinceStmt = `
+SELECT
+ event_id
+,event_server
+,event_from
+,event_to
+,event_reason
+,event_created
+FROM server_events
+WHERE event_created >= :event_created
+ORDER BY event_id ASC
+`
+
+const eventInsertStmt = `
+INSERT INTO server_events (
+ event_server
+,event_from
+,event_to
+,event_reason
+,event_created
+) VALUES (
+ :event_server
+,:event_from
+,:event_to
+,:event_reason
+,:event_created
+)
+`
@@ -0,0 +1,104 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+)
+
+func TestTrack(t *testing.T) {
+	conn, err := connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	events := NewEventStore(conn)
+	store := Track(NewServerStore(conn), events)
+
+	server := &autoscaler.Server{Name: "i-5203422c", State: autoscaler.StatePending}
+	if err := store.Create(context.TODO(), server); err != nil {
+		t.Error(err)
+		return
+	}
+	server.State = autoscaler.StateCreating
+	if err := store.Update(context.TODO(), server); err != nil {
+		t.Error(err)
+		return
+	}
+	// updates that do not change the state are not recorded.
+	server.Address = "54.194.252.215"
+	if err := store.Update(context.TODO(), server); err != nil {
+		t.Error(err)
+		return
+	}
+	server.State = autoscaler.StateError
+	server.Error = "insufficient capacity"
+	if err := store.Update(context.TODO(), server); err != nil {
+		t.Error(err)
+		return
+	}
+
+	list, err := events.List(context.TODO(), "i-5203422c")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(list), 3; got != want {
+		t.Errorf("Want %d events, got %d", want, got)
+		return
+	}
+	want := []autoscaler.ServerEvent{
+		{Server: "i-5203422c", From: "", To: autoscaler.StatePending},
+		{Server: "i-5203422c", From: autoscaler.StatePending, To: autoscaler.StateCreating},
+		{Server: "i-5203422c", From: autoscaler.StateCreating, To: autoscaler.StateError, Reason: "insufficient capacity"},
+	}
+	for i, event := range list {
+		if event.From != want[i].From || event.To != want[i].To || event.Reason != want[i].Reason {
+			t.Errorf("Want event %s to %s, got %s to %s", want[i].From, want[i].To, event.From, event.To)
+		}
+		if event.Created == 0 {
+			t.Errorf("Want event timestamp")
+		}
+	}
+}
+
+func TestEventStore_ListSince(t *testing.T) {
+	conn, err := connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	events := NewEventStore(conn)
+	for _, event := range []*autoscaler.ServerEvent{
+		{Server: "i-5203422c", To: autoscaler.StatePending, Created: 86400},
+		{Server: "i-5203422d", To: autoscaler.StatePending, Created: 86401},
+		{Server: "i-5203422c", To: autoscaler.StateRunning, Created: 86402},
+	} {
+		if err := events.Create(context.TODO(), event); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	list, err := events.ListSince(context.TODO(), 86401)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(list), 2; got != want {
+		t.Errorf("Want %d events, got %d", want, got)
+		return
+	}
+	if list[0].Server != "i-5203422d" || list[1].To != autoscaler.StateRunning {
+		t.Errorf("Want events of all servers ordered oldest first")
+	}
+}
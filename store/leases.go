@@ -0,0 +1,104 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewLeaseStore returns a new lease store.
+func NewLeaseStore(db *sqlx.DB) autoscaler.LeaseStore {
+	return &leaseStore{db}
+}
+
+type leaseStore struct {
+	*sqlx.DB
+}
+
+// Acquire renews the lease if held by the holder or expired,
+// and otherwise attempts to create the lease, which fails if
+// the lease exists. The lease is then read back to determine
+// the holder, so that the result does not depend on how each
+// database reports the affected rows.
+func (db *leaseStore) Acquire(ctx context.Context, name, holder string, expires int64) (bool, error) {
+	params := map[string]interface{}{
+		"lease_name":    name,
+		"lease_holder":  holder,
+		"lease_expires": expires,
+		"lease_now":     time.Now().Unix(),
+	}
+	stmt, args, err := db.BindNamed(leaseUpdateStmt, params)
+	if err != nil {
+		return false, err
+	}
+	if err := execRetry(ctx, db.DB, stmt, args...); err != nil {
+		return false, err
+	}
+	stmt, args, err = db.BindNamed(leaseInsertStmt, params)
+	if err != nil {
+		return false, err
+	}
+	// the insert fails if the lease exists, which is
+	// expected if the lease is held by another replica.
+	db.ExecContext(ctx, stmt, args...)
+
+	dest := new(autoscaler.Lease)
+	stmt, args, err = db.BindNamed(leaseFindStmt, params)
+	if err != nil {
+		return false, err
+	}
+	if err := db.GetContext(ctx, dest, stmt, args...); err != nil {
+		return false, err
+	}
+	return dest.Holder == holder && dest.Expires == expires, nil
+}
+
+func (db *leaseStore) Release(ctx context.Context, name, holder string) error {
+	stmt, args, err := db.BindNamed(leaseDeleteStmt, &autoscaler.Lease{Name: name, Holder: holder})
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+const leaseFindStmt = `
+SELECT
+ lease_name
+,lease_holder
+,lease_expires
+FROM leases
+WHERE lease_name=:lease_name
+`
+
+const leaseUpdateStmt = `
+UPDATE leases SET
+ lease_holder=:lease_holder
+,lease_expires=:lease_expires
+WHERE lease_name=:lease_name
+  AND (lease_holder=:lease_holder OR lease_expires < :lease_now)
+`
+
+const leaseInsertStmt = `
+INSERT INTO leases (
+ lease_name
+,lease_holder
+,lease_expires
+) VALUES (
+ :lease_name
+,:lease_holder
+,:lease_expires
+)
+`
+
+const leaseDeleteStmt = `
+DELETE FROM leases
+WHERE lease_name=:lease_name
+  AND lease_holder=:lease_holder
+`
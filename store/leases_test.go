@@ -0,0 +1,61 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLeases(t *testing.T) {
+	conn, err := connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		conn.Exec("DELETE FROM leases")
+		conn.Close()
+	}()
+
+	store := NewLeaseStore(conn)
+	expires := time.Now().Add(time.Minute).Unix()
+
+	held, err := store.Acquire(context.TODO(), "engine", "replica1", expires)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !held {
+		t.Errorf("Want lease acquired by the first replica")
+	}
+
+	held, _ = store.Acquire(context.TODO(), "engine", "replica2", expires)
+	if held {
+		t.Errorf("Want lease held by another replica not acquired")
+	}
+
+	held, _ = store.Acquire(context.TODO(), "engine", "replica1", expires+1)
+	if !held {
+		t.Errorf("Want lease renewed by the holder")
+	}
+
+	if err := store.Release(context.TODO(), "engine", "replica1"); err != nil {
+		t.Error(err)
+		return
+	}
+	held, _ = store.Acquire(context.TODO(), "engine", "replica2", expires)
+	if !held {
+		t.Errorf("Want released lease acquired by another replica")
+	}
+
+	// an expired lease is acquired by another replica.
+	store.Acquire(context.TODO(), "expired", "replica1", time.Now().Add(-time.Minute).Unix())
+	held, _ = store.Acquire(context.TODO(), "expired", "replica2", expires)
+	if !held {
+		t.Errorf("Want expired lease acquired by another replica")
+	}
+}
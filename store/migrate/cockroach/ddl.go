@@ -0,0 +1,7 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package cockroach
+
+//go:generate togo ddl -package cockroach -dialect postgres
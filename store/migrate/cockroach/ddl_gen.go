@@ -0,0 +1,427 @@
+package cockroach
+
+import (
+	"database/sql"
+)
+
+var migrations = []struct {
+	name string
+	stmt string
+}{
+	{
+		name: "create-table-servers",
+		stmt: createTableServers,
+	},
+	{
+		name: "create-index-server-id",
+		stmt: createIndexServerId,
+	},
+	{
+		name: "create-index-server-state",
+		stmt: createIndexServerState,
+	},
+	{
+		name: "create-table-server-events",
+		stmt: createTableServerEvents,
+	},
+	{
+		name: "create-index-server-events-server",
+		stmt: createIndexServerEventsServer,
+	},
+	{
+		name: "alter-table-servers-modify-column-secret",
+		stmt: alterTableServersModifyColumnSecret,
+	},
+	{
+		name: "create-table-leases",
+		stmt: createTableLeases,
+	},
+	{
+		name: "alter-table-servers-add-column-annotations",
+		stmt: alterTableServersAddColumnAnnotations,
+	},
+	{
+		name: "alter-table-servers-add-column-stages",
+		stmt: alterTableServersAddColumnStages,
+	},
+	{
+		name: "alter-table-servers-add-column-busy",
+		stmt: alterTableServersAddColumnBusy,
+	},
+	{
+		name: "create-table-scale-events",
+		stmt: createTableScaleEvents,
+	},
+	{
+		name: "create-index-scale-events-created",
+		stmt: createIndexScaleEventsCreated,
+	},
+	{
+		name: "alter-table-servers-add-column-labels",
+		stmt: alterTableServersAddColumnLabels,
+	},
+	{
+		name: "create-table-labels",
+		stmt: createTableLabels,
+	},
+	{
+		name: "create-index-labels-key-value",
+		stmt: createIndexLabelsKeyValue,
+	},
+	{
+		name: "create-index-servers-pool",
+		stmt: createIndexServersPool,
+	},
+	{
+		name: "create-table-audit-events",
+		stmt: createTableAuditEvents,
+	},
+	{
+		name: "create-index-audit-events-created",
+		stmt: createIndexAuditEventsCreated,
+	},
+	{
+		name: "create-table-plans",
+		stmt: createTablePlans,
+	},
+	{
+		name: "create-index-plans-created",
+		stmt: createIndexPlansCreated,
+	},
+	{
+		name: "create-table-pool-states",
+		stmt: createTablePoolStates,
+	},
+	{
+		name: "alter-table-servers-add-column-host-key",
+		stmt: alterTableServersAddColumnHostKey,
+	},
+	{
+		name: "alter-table-audit-events-add-column-query",
+		stmt: alterTableAuditEventsAddColumnQuery,
+	},
+	{
+		name: "alter-table-audit-events-add-column-target",
+		stmt: alterTableAuditEventsAddColumnTarget,
+	},
+}
+
+// Migrate performs the database migration. If the migration fails
+// and error is returned.
+func Migrate(db *sql.DB) error {
+	if err := createTable(db); err != nil {
+		return err
+	}
+	completed, err := selectCompleted(db)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	for _, migration := range migrations {
+		if _, ok := completed[migration.name]; ok {
+
+			continue
+		}
+
+		if _, err := db.Exec(migration.stmt); err != nil {
+			return err
+		}
+		if err := insertMigration(db, migration.name); err != nil {
+			return err
+		}
+
+	}
+	return nil
+}
+
+func createTable(db *sql.DB) error {
+	_, err := db.Exec(migrationTableCreate)
+	return err
+}
+
+func insertMigration(db *sql.DB, name string) error {
+	_, err := db.Exec(migrationInsert, name)
+	return err
+}
+
+func selectCompleted(db *sql.DB) (map[string]struct{}, error) {
+	migrations := map[string]struct{}{}
+	rows, err := db.Query(migrationSelect)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		migrations[name] = struct{}{}
+	}
+	return migrations, nil
+}
+
+//
+// migration table ddl and sql
+//
+
+var migrationTableCreate = `
+CREATE TABLE IF NOT EXISTS migrations (
+ name VARCHAR(255)
+,UNIQUE(name)
+)
+`
+
+var migrationInsert = `
+INSERT INTO migrations (name) VALUES ($1)
+`
+
+var migrationSelect = `
+SELECT name FROM migrations
+`
+
+//
+// 001_create_table_servers.sql
+//
+
+var createTableServers = `
+CREATE TABLE IF NOT EXISTS servers (
+ server_name      STRING(50) PRIMARY KEY
+,server_id        STRING(250)
+,server_provider  STRING(50)
+,server_state     STRING(50)
+,server_image     STRING(250)
+,server_region    STRING(50)
+,server_size      STRING(50)
+,server_platform  STRING(50)
+,server_address   STRING(250)
+,server_capacity  INT8
+,server_price     FLOAT8 DEFAULT 0
+,server_pool      STRING(50) DEFAULT ''
+,server_phase     STRING(50) DEFAULT ''
+,server_logs      BYTES
+,server_secret    STRING(50)
+,server_error     STRING
+,server_ca_key    STRING
+,server_ca_cert   STRING
+,server_tls_key   STRING
+,server_tls_cert  STRING
+,server_ssh_key   BYTES
+,server_created   INT8
+,server_updated   INT8
+,server_started   INT8
+,server_stopped   INT8
+,server_deleted   INT8 DEFAULT 0
+);
+`
+
+var createIndexServerId = `
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+`
+
+var createIndexServerState = `
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`
+
+//
+// 002_create_table_server_events.sql
+//
+
+var createTableServerEvents = `
+CREATE TABLE IF NOT EXISTS server_events (
+ event_id       INT8 DEFAULT unique_rowid() PRIMARY KEY
+,event_server   STRING(50)
+,event_from     STRING(50)
+,event_to       STRING(50)
+,event_reason   STRING
+,event_created  INT8
+);
+`
+
+var createIndexServerEventsServer = `
+CREATE INDEX IF NOT EXISTS ix_server_events_server ON server_events (event_server);
+`
+
+//
+// 003_alter_table_servers_modify_column_secret.sql
+//
+
+var alterTableServersModifyColumnSecret = `
+ALTER TABLE servers ALTER COLUMN server_secret TYPE STRING(250);
+`
+
+//
+// 004_create_table_leases.sql
+//
+
+var createTableLeases = `
+CREATE TABLE leases (
+ lease_name     STRING(250) PRIMARY KEY
+,lease_holder   STRING(250)
+,lease_expires  INT8
+);
+`
+
+//
+// 005_alter_table_servers_add_column_annotations.sql
+//
+
+var alterTableServersAddColumnAnnotations = `
+ALTER TABLE servers ADD COLUMN server_annotations STRING;
+`
+
+//
+// 006_alter_table_servers_add_column_stages.sql
+//
+
+var alterTableServersAddColumnStages = `
+ALTER TABLE servers ADD COLUMN server_stages INT8 DEFAULT 0;
+`
+
+//
+// 007_alter_table_servers_add_column_busy.sql
+//
+
+var alterTableServersAddColumnBusy = `
+ALTER TABLE servers ADD COLUMN server_busy INT8 DEFAULT 0;
+`
+
+//
+// 008_create_table_scale_events.sql
+//
+
+var createTableScaleEvents = `
+CREATE TABLE IF NOT EXISTS scale_events (
+ scale_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,scale_pool      STRING(50)
+,scale_action    STRING(50)
+,scale_count     INT8
+,scale_pending   INT8
+,scale_running   INT8
+,scale_capacity  INT8
+,scale_servers   INT8
+,scale_created   INT8
+);
+`
+
+var createIndexScaleEventsCreated = `
+CREATE INDEX IF NOT EXISTS ix_scale_events_created ON scale_events (scale_created);
+`
+
+//
+// 009_alter_table_servers_add_column_labels.sql
+//
+
+var alterTableServersAddColumnLabels = `
+ALTER TABLE servers ADD COLUMN server_labels STRING;
+`
+
+//
+// 010_create_table_labels.sql
+//
+
+var createTableLabels = `
+CREATE TABLE IF NOT EXISTS labels (
+ label_server  STRING(50)
+,label_key     STRING(50)
+,label_value   STRING(100)
+,PRIMARY KEY(label_server, label_key)
+);
+`
+
+var createIndexLabelsKeyValue = `
+CREATE INDEX IF NOT EXISTS ix_labels_key_value ON labels (label_key, label_value);
+`
+
+//
+// 011_create_index_servers_pool.sql
+//
+
+var createIndexServersPool = `
+CREATE INDEX IF NOT EXISTS ix_servers_pool ON servers (server_pool);
+`
+
+//
+// 012_create_table_audit_events.sql
+//
+
+var createTableAuditEvents = `
+CREATE TABLE IF NOT EXISTS audit_events (
+ audit_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,audit_user      STRING(250)
+,audit_scope     STRING(50)
+,audit_method    STRING(10)
+,audit_path      STRING(500)
+,audit_status    INT8
+,audit_address   STRING(100)
+,audit_created   INT8
+);
+`
+
+var createIndexAuditEventsCreated = `
+CREATE INDEX IF NOT EXISTS ix_audit_events_created ON audit_events (audit_created);
+`
+
+//
+// 013_create_table_plans.sql
+//
+
+var createTablePlans = `
+CREATE TABLE IF NOT EXISTS plans (
+ plan_id         INT8 DEFAULT unique_rowid() PRIMARY KEY
+,plan_cycle      STRING(50)
+,plan_pool       STRING(250)
+,plan_pending    INT8
+,plan_running    INT8
+,plan_capacity   INT8
+,plan_servers    INT8
+,plan_min        INT8
+,plan_max        INT8
+,plan_diff       INT8
+,plan_action     STRING(50)
+,plan_count      INT8
+,plan_reason     STRING(500)
+,plan_error      STRING(500)
+,plan_created    INT8
+);
+`
+
+var createIndexPlansCreated = `
+CREATE INDEX IF NOT EXISTS ix_plans_created ON plans (plan_created);
+`
+
+//
+// 014_create_table_pool_states.sql
+//
+
+var createTablePoolStates = `
+CREATE TABLE pool_states (
+ state_pool     STRING(250) PRIMARY KEY
+,state_paused   BOOLEAN
+,state_target   INT8
+,state_updated  INT8
+);
+`
+
+//
+// 015_alter_table_servers_add_column_host_key.sql
+//
+
+var alterTableServersAddColumnHostKey = `
+ALTER TABLE servers ADD COLUMN server_host_key STRING DEFAULT '';
+`
+
+//
+// 016_alter_table_audit_events_add_column_query.sql
+//
+
+var alterTableAuditEventsAddColumnQuery = `
+ALTER TABLE audit_events ADD COLUMN audit_query STRING(2000) DEFAULT '';
+`
+
+//
+// 017_alter_table_audit_events_add_column_target.sql
+//
+
+var alterTableAuditEventsAddColumnTarget = `
+ALTER TABLE audit_events ADD COLUMN audit_target STRING(250) DEFAULT '';
+`
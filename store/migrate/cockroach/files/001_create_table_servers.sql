@@ -0,0 +1,38 @@
+-- name: create-table-servers
+
+CREATE TABLE IF NOT EXISTS servers (
+ server_name      STRING(50) PRIMARY KEY
+,server_id        STRING(250)
+,server_provider  STRING(50)
+,server_state     STRING(50)
+,server_image     STRING(250)
+,server_region    STRING(50)
+,server_size      STRING(50)
+,server_platform  STRING(50)
+,server_address   STRING(250)
+,server_capacity  INT8
+,server_price     FLOAT8 DEFAULT 0
+,server_pool      STRING(50) DEFAULT ''
+,server_phase     STRING(50) DEFAULT ''
+,server_logs      BYTES
+,server_secret    STRING(50)
+,server_error     STRING
+,server_ca_key    STRING
+,server_ca_cert   STRING
+,server_tls_key   STRING
+,server_tls_cert  STRING
+,server_ssh_key   BYTES
+,server_created   INT8
+,server_updated   INT8
+,server_started   INT8
+,server_stopped   INT8
+,server_deleted   INT8 DEFAULT 0
+);
+
+-- name: create-index-server-id
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+
+-- name: create-index-server-state
+
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
@@ -0,0 +1,14 @@
+-- name: create-table-server-events
+
+CREATE TABLE IF NOT EXISTS server_events (
+ event_id       INT8 DEFAULT unique_rowid() PRIMARY KEY
+,event_server   STRING(50)
+,event_from     STRING(50)
+,event_to       STRING(50)
+,event_reason   STRING
+,event_created  INT8
+);
+
+-- name: create-index-server-events-server
+
+CREATE INDEX IF NOT EXISTS ix_server_events_server ON server_events (event_server);
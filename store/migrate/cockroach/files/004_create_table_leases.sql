@@ -0,0 +1,7 @@
+-- name: create-table-leases
+
+CREATE TABLE leases (
+ lease_name     STRING(250) PRIMARY KEY
+,lease_holder   STRING(250)
+,lease_expires  INT8
+);
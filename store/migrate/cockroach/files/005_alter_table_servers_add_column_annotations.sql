@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-annotations
+
+ALTER TABLE servers ADD COLUMN server_annotations STRING;
@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-stages
+
+ALTER TABLE servers ADD COLUMN server_stages INT8 DEFAULT 0;
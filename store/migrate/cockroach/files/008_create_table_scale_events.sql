@@ -0,0 +1,17 @@
+-- name: create-table-scale-events
+
+CREATE TABLE IF NOT EXISTS scale_events (
+ scale_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,scale_pool      STRING(50)
+,scale_action    STRING(50)
+,scale_count     INT8
+,scale_pending   INT8
+,scale_running   INT8
+,scale_capacity  INT8
+,scale_servers   INT8
+,scale_created   INT8
+);
+
+-- name: create-index-scale-events-created
+
+CREATE INDEX IF NOT EXISTS ix_scale_events_created ON scale_events (scale_created);
@@ -0,0 +1,12 @@
+-- name: create-table-labels
+
+CREATE TABLE IF NOT EXISTS labels (
+ label_server  STRING(50)
+,label_key     STRING(50)
+,label_value   STRING(100)
+,PRIMARY KEY(label_server, label_key)
+);
+
+-- name: create-index-labels-key-value
+
+CREATE INDEX IF NOT EXISTS ix_labels_key_value ON labels (label_key, label_value);
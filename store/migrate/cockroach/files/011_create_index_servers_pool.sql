@@ -0,0 +1,3 @@
+-- name: create-index-servers-pool
+
+CREATE INDEX IF NOT EXISTS ix_servers_pool ON servers (server_pool);
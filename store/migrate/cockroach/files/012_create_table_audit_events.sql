@@ -0,0 +1,16 @@
+-- name: create-table-audit-events
+
+CREATE TABLE IF NOT EXISTS audit_events (
+ audit_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,audit_user      STRING(250)
+,audit_scope     STRING(50)
+,audit_method    STRING(10)
+,audit_path      STRING(500)
+,audit_status    INT8
+,audit_address   STRING(100)
+,audit_created   INT8
+);
+
+-- name: create-index-audit-events-created
+
+CREATE INDEX IF NOT EXISTS ix_audit_events_created ON audit_events (audit_created);
@@ -0,0 +1,23 @@
+-- name: create-table-plans
+
+CREATE TABLE IF NOT EXISTS plans (
+ plan_id         INT8 DEFAULT unique_rowid() PRIMARY KEY
+,plan_cycle      STRING(50)
+,plan_pool       STRING(250)
+,plan_pending    INT8
+,plan_running    INT8
+,plan_capacity   INT8
+,plan_servers    INT8
+,plan_min        INT8
+,plan_max        INT8
+,plan_diff       INT8
+,plan_action     STRING(50)
+,plan_count      INT8
+,plan_reason     STRING(500)
+,plan_error      STRING(500)
+,plan_created    INT8
+);
+
+-- name: create-index-plans-created
+
+CREATE INDEX IF NOT EXISTS ix_plans_created ON plans (plan_created);
@@ -0,0 +1,8 @@
+-- name: create-table-pool-states
+
+CREATE TABLE pool_states (
+ state_pool     STRING(250) PRIMARY KEY
+,state_paused   BOOLEAN
+,state_target   INT8
+,state_updated  INT8
+);
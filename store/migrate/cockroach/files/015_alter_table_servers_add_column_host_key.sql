@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-host-key
+
+ALTER TABLE servers ADD COLUMN server_host_key STRING DEFAULT '';
@@ -0,0 +1,3 @@
+-- name: alter-table-audit-events-add-column-target
+
+ALTER TABLE audit_events ADD COLUMN audit_target STRING(250) DEFAULT '';
@@ -0,0 +1,134 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package cockroach
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Names returns the names of the migrations, in the order the
+// migrations are performed.
+func Names() []string {
+	var names []string
+	for _, migration := range migrations {
+		names = append(names, migration.name)
+	}
+	return names
+}
+
+// Completed returns the names of the completed migrations.
+func Completed(db *sql.DB) (map[string]struct{}, error) {
+	if err := createTable(db); err != nil {
+		return nil, err
+	}
+	return selectCompleted(db)
+}
+
+// Rollback reverts the last completed migration, and returns
+// the name of the reverted migration. The name is empty if no
+// migrations are completed.
+func Rollback(db *sql.DB) (string, error) {
+	completed, err := Completed(db)
+	if err != nil {
+		return "", err
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		name := migrations[i].name
+		if _, ok := completed[name]; !ok {
+			continue
+		}
+		stmt, ok := rollbacks[name]
+		if !ok {
+			return name, fmt.Errorf("Cannot revert migration %s", name)
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			return name, err
+		}
+		_, err := db.Exec(migrationDelete, name)
+		return name, err
+	}
+	return "", nil
+}
+
+var migrationDelete = `
+DELETE FROM migrations WHERE name = $1
+`
+
+// rollbacks defines the statements that revert each migration.
+var rollbacks = map[string]string{
+	"create-table-servers": `
+DROP TABLE servers;
+`,
+	"create-index-server-id": `
+DROP INDEX servers@ix_servers_id;
+`,
+	"create-index-server-state": `
+DROP INDEX servers@ix_servers_state;
+`,
+	"create-table-server-events": `
+DROP TABLE server_events;
+`,
+	"create-index-server-events-server": `
+DROP INDEX server_events@ix_server_events_server;
+`,
+	"alter-table-servers-modify-column-secret": `
+ALTER TABLE servers ALTER COLUMN server_secret TYPE STRING(50);
+`,
+	"create-table-leases": `
+DROP TABLE leases;
+`,
+	"alter-table-servers-add-column-annotations": `
+ALTER TABLE servers DROP COLUMN server_annotations;
+`,
+	"alter-table-servers-add-column-stages": `
+ALTER TABLE servers DROP COLUMN server_stages;
+`,
+	"alter-table-servers-add-column-busy": `
+ALTER TABLE servers DROP COLUMN server_busy;
+`,
+	"create-table-scale-events": `
+DROP TABLE scale_events;
+`,
+	"create-index-scale-events-created": `
+DROP INDEX scale_events@ix_scale_events_created;
+`,
+	"alter-table-servers-add-column-labels": `
+ALTER TABLE servers DROP COLUMN server_labels;
+`,
+	"create-table-labels": `
+DROP TABLE labels;
+`,
+	"create-index-labels-key-value": `
+DROP INDEX labels@ix_labels_key_value;
+`,
+	"create-index-servers-pool": `
+DROP INDEX servers@ix_servers_pool;
+`,
+	"create-table-audit-events": `
+DROP TABLE audit_events;
+`,
+	"create-index-audit-events-created": `
+DROP INDEX audit_events@ix_audit_events_created;
+`,
+	"create-table-plans": `
+DROP TABLE plans;
+`,
+	"create-index-plans-created": `
+DROP INDEX plans@ix_plans_created;
+`,
+	"create-table-pool-states": `
+DROP TABLE pool_states;
+`,
+	"alter-table-servers-add-column-host-key": `
+ALTER TABLE servers DROP COLUMN server_host_key;
+`,
+	"alter-table-audit-events-add-column-query": `
+ALTER TABLE audit_events DROP COLUMN audit_query;
+`,
+	"alter-table-audit-events-add-column-target": `
+ALTER TABLE audit_events DROP COLUMN audit_target;
+`,
+}
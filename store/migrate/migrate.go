@@ -1,6 +1,9 @@
 package ddl
 
 import (
+	"database/sql"
+
+	"github.com/drone/autoscaler/store/migrate/cockroach"
 	"github.com/drone/autoscaler/store/migrate/mysql"
 	"github.com/drone/autoscaler/store/migrate/postgres"
 	"github.com/drone/autoscaler/store/migrate/sqlite"
@@ -8,14 +11,78 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-// Migrate performs the database migration.
-func Migrate(db *sqlx.DB) error {
-	switch db.DriverName() {
+// Migration describes a database migration.
+type Migration struct {
+	Name      string
+	Completed bool
+}
+
+// Migrate performs the database migration for the dialect.
+func Migrate(db *sqlx.DB, dialect string) error {
+	return lookup(dialect).migrate(db.DB)
+}
+
+// Status returns the database migrations for the dialect, in
+// the order the migrations are performed, and whether each
+// migration is completed.
+func Status(db *sqlx.DB, dialect string) ([]Migration, error) {
+	d := lookup(dialect)
+	completed, err := d.completed(db.DB)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for _, name := range d.names() {
+		_, ok := completed[name]
+		migrations = append(migrations, Migration{
+			Name:      name,
+			Completed: ok,
+		})
+	}
+	return migrations, nil
+}
+
+// Pending returns true if the database migrations for the
+// dialect are not completed.
+func Pending(db *sqlx.DB, dialect string) (bool, error) {
+	migrations, err := Status(db, dialect)
+	if err != nil {
+		return false, err
+	}
+	for _, migration := range migrations {
+		if !migration.Completed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Rollback reverts the last completed database migration for
+// the dialect, and returns the name of the reverted migration.
+func Rollback(db *sqlx.DB, dialect string) (string, error) {
+	return lookup(dialect).rollback(db.DB)
+}
+
+// dialect defines the migration functions of a database
+// dialect.
+type dialect struct {
+	migrate   func(*sql.DB) error
+	names     func() []string
+	completed func(*sql.DB) (map[string]struct{}, error)
+	rollback  func(*sql.DB) (string, error)
+}
+
+// helper function returns the migration functions of the
+// dialect, defaulting to sqlite.
+func lookup(name string) dialect {
+	switch name {
+	case "cockroach":
+		return dialect{cockroach.Migrate, cockroach.Names, cockroach.Completed, cockroach.Rollback}
 	case "postgres":
-		return postgres.Migrate(db.DB)
+		return dialect{postgres.Migrate, postgres.Names, postgres.Completed, postgres.Rollback}
 	case "mysql":
-		return mysql.Migrate(db.DB)
+		return dialect{mysql.Migrate, mysql.Names, mysql.Completed, mysql.Rollback}
 	default:
-		return sqlite.Migrate(db.DB)
+		return dialect{sqlite.Migrate, sqlite.Names, sqlite.Completed, sqlite.Rollback}
 	}
 }
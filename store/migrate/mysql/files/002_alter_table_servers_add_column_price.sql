@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-price
+
+ALTER TABLE servers ADD COLUMN server_price DOUBLE DEFAULT 0;
@@ -0,0 +1,14 @@
+-- name: create-table-server-events
+
+CREATE TABLE server_events (
+ event_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,event_server   VARCHAR(50)
+,event_from     VARCHAR(50)
+,event_to       VARCHAR(50)
+,event_reason   BLOB
+,event_created  INTEGER
+);
+
+-- name: create-index-server-events-server
+
+CREATE INDEX ix_server_events_server ON server_events (event_server);
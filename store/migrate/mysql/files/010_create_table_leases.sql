@@ -0,0 +1,7 @@
+-- name: create-table-leases
+
+CREATE TABLE leases (
+ lease_name     VARCHAR(250) PRIMARY KEY
+,lease_holder   VARCHAR(250)
+,lease_expires  INTEGER
+);
@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-stages
+
+ALTER TABLE servers ADD COLUMN server_stages INTEGER DEFAULT 0;
@@ -0,0 +1,15 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import "testing"
+
+func TestRollbacks(t *testing.T) {
+	for _, migration := range migrations {
+		if _, ok := rollbacks[migration.name]; !ok {
+			t.Errorf("Want rollback for migration %s", migration.name)
+		}
+	}
+}
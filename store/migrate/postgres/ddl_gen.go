@@ -20,6 +20,114 @@ var migrations = []struct {
 		name: "create-index-server-state",
 		stmt: createIndexServerState,
 	},
+	{
+		name: "alter-table-servers-add-column-price",
+		stmt: alterTableServersAddColumnPrice,
+	},
+	{
+		name: "alter-table-servers-add-column-pool",
+		stmt: alterTableServersAddColumnPool,
+	},
+	{
+		name: "alter-table-servers-add-column-phase",
+		stmt: alterTableServersAddColumnPhase,
+	},
+	{
+		name: "alter-table-servers-add-column-logs",
+		stmt: alterTableServersAddColumnLogs,
+	},
+	{
+		name: "alter-table-servers-add-column-ssh-key",
+		stmt: alterTableServersAddColumnSSHKey,
+	},
+	{
+		name: "create-table-server-events",
+		stmt: createTableServerEvents,
+	},
+	{
+		name: "create-index-server-events-server",
+		stmt: createIndexServerEventsServer,
+	},
+	{
+		name: "alter-table-servers-add-column-deleted",
+		stmt: alterTableServersAddColumnDeleted,
+	},
+	{
+		name: "alter-table-servers-modify-column-secret",
+		stmt: alterTableServersModifyColumnSecret,
+	},
+	{
+		name: "create-table-leases",
+		stmt: createTableLeases,
+	},
+	{
+		name: "alter-table-servers-add-column-annotations",
+		stmt: alterTableServersAddColumnAnnotations,
+	},
+	{
+		name: "alter-table-servers-add-column-stages",
+		stmt: alterTableServersAddColumnStages,
+	},
+	{
+		name: "alter-table-servers-add-column-busy",
+		stmt: alterTableServersAddColumnBusy,
+	},
+	{
+		name: "create-table-scale-events",
+		stmt: createTableScaleEvents,
+	},
+	{
+		name: "create-index-scale-events-created",
+		stmt: createIndexScaleEventsCreated,
+	},
+	{
+		name: "alter-table-servers-add-column-labels",
+		stmt: alterTableServersAddColumnLabels,
+	},
+	{
+		name: "create-table-labels",
+		stmt: createTableLabels,
+	},
+	{
+		name: "create-index-labels-key-value",
+		stmt: createIndexLabelsKeyValue,
+	},
+	{
+		name: "create-index-servers-pool",
+		stmt: createIndexServersPool,
+	},
+	{
+		name: "create-table-audit-events",
+		stmt: createTableAuditEvents,
+	},
+	{
+		name: "create-index-audit-events-created",
+		stmt: createIndexAuditEventsCreated,
+	},
+	{
+		name: "create-table-plans",
+		stmt: createTablePlans,
+	},
+	{
+		name: "create-index-plans-created",
+		stmt: createIndexPlansCreated,
+	},
+	{
+		name: "create-table-pool-states",
+		stmt: createTablePoolStates,
+	},
+	{
+		name: "alter-table-servers-add-column-host-key",
+		stmt: alterTableServersAddColumnHostKey,
+	},
+	{
+		name: "alter-table-audit-events-add-column-query",
+		stmt: alterTableAuditEventsAddColumnQuery,
+	},
+	{
+		name: "alter-table-audit-events-add-column-target",
+		stmt: alterTableAuditEventsAddColumnTarget,
+	},
 }
 
 // Migrate performs the database migration. If the migration fails
@@ -131,3 +239,255 @@ CREATE INDEX ix_servers_id ON servers (server_id);
 var createIndexServerState = `
 CREATE INDEX ix_servers_state ON servers (server_state);
 `
+
+//
+// 002_alter_table_servers_add_column_price.sql
+//
+
+var alterTableServersAddColumnPrice = `
+ALTER TABLE servers ADD COLUMN server_price DOUBLE PRECISION DEFAULT 0;
+`
+
+//
+// 003_alter_table_servers_add_column_pool.sql
+//
+
+var alterTableServersAddColumnPool = `
+ALTER TABLE servers ADD COLUMN server_pool VARCHAR(50) DEFAULT '';
+`
+
+//
+// 004_alter_table_servers_add_column_phase.sql
+//
+
+var alterTableServersAddColumnPhase = `
+ALTER TABLE servers ADD COLUMN server_phase VARCHAR(50) DEFAULT '';
+`
+
+//
+// 005_alter_table_servers_add_column_logs.sql
+//
+
+var alterTableServersAddColumnLogs = `
+ALTER TABLE servers ADD COLUMN server_logs TEXT;
+`
+
+//
+// 006_alter_table_servers_add_column_ssh_key.sql
+//
+
+var alterTableServersAddColumnSSHKey = `
+ALTER TABLE servers ADD COLUMN server_ssh_key TEXT;
+`
+
+//
+// 007_create_table_server_events.sql
+//
+
+var createTableServerEvents = `
+CREATE TABLE server_events (
+ event_id       SERIAL PRIMARY KEY
+,event_server   VARCHAR(50)
+,event_from     VARCHAR(50)
+,event_to       VARCHAR(50)
+,event_reason   TEXT
+,event_created  INTEGER
+);
+`
+
+var createIndexServerEventsServer = `
+CREATE INDEX ix_server_events_server ON server_events (event_server);
+`
+
+//
+// 008_alter_table_servers_add_column_deleted.sql
+//
+
+var alterTableServersAddColumnDeleted = `
+ALTER TABLE servers ADD COLUMN server_deleted INTEGER DEFAULT 0;
+`
+
+//
+// 009_alter_table_servers_modify_column_secret.sql
+//
+
+var alterTableServersModifyColumnSecret = `
+ALTER TABLE servers ALTER COLUMN server_secret TYPE VARCHAR(250);
+`
+
+//
+// 010_create_table_leases.sql
+//
+
+var createTableLeases = `
+CREATE TABLE leases (
+ lease_name     VARCHAR(250) PRIMARY KEY
+,lease_holder   VARCHAR(250)
+,lease_expires  INTEGER
+);
+`
+
+//
+// 011_alter_table_servers_add_column_annotations.sql
+//
+
+var alterTableServersAddColumnAnnotations = `
+ALTER TABLE servers ADD COLUMN server_annotations TEXT;
+`
+
+//
+// 012_alter_table_servers_add_column_stages.sql
+//
+
+var alterTableServersAddColumnStages = `
+ALTER TABLE servers ADD COLUMN server_stages INTEGER DEFAULT 0;
+`
+
+//
+// 013_alter_table_servers_add_column_busy.sql
+//
+
+var alterTableServersAddColumnBusy = `
+ALTER TABLE servers ADD COLUMN server_busy INTEGER DEFAULT 0;
+`
+
+//
+// 014_create_table_scale_events.sql
+//
+
+var createTableScaleEvents = `
+CREATE TABLE scale_events (
+ scale_id        SERIAL PRIMARY KEY
+,scale_pool      VARCHAR(50)
+,scale_action    VARCHAR(50)
+,scale_count     INTEGER
+,scale_pending   INTEGER
+,scale_running   INTEGER
+,scale_capacity  INTEGER
+,scale_servers   INTEGER
+,scale_created   INTEGER
+);
+`
+
+var createIndexScaleEventsCreated = `
+CREATE INDEX ix_scale_events_created ON scale_events (scale_created);
+`
+
+//
+// 015_alter_table_servers_add_column_labels.sql
+//
+
+var alterTableServersAddColumnLabels = `
+ALTER TABLE servers ADD COLUMN server_labels TEXT;
+`
+
+//
+// 016_create_table_labels.sql
+//
+
+var createTableLabels = `
+CREATE TABLE labels (
+ label_server  VARCHAR(50)
+,label_key     VARCHAR(50)
+,label_value   VARCHAR(100)
+,PRIMARY KEY(label_server, label_key)
+);
+`
+
+var createIndexLabelsKeyValue = `
+CREATE INDEX ix_labels_key_value ON labels (label_key, label_value);
+`
+
+//
+// 017_create_index_servers_pool.sql
+//
+
+var createIndexServersPool = `
+CREATE INDEX ix_servers_pool ON servers (server_pool);
+`
+
+//
+// 018_create_table_audit_events.sql
+//
+
+var createTableAuditEvents = `
+CREATE TABLE audit_events (
+ audit_id        SERIAL PRIMARY KEY
+,audit_user      VARCHAR(250)
+,audit_scope     VARCHAR(50)
+,audit_method    VARCHAR(10)
+,audit_path      VARCHAR(500)
+,audit_status    INTEGER
+,audit_address   VARCHAR(100)
+,audit_created   INTEGER
+);
+`
+
+var createIndexAuditEventsCreated = `
+CREATE INDEX ix_audit_events_created ON audit_events (audit_created);
+`
+
+//
+// 019_create_table_plans.sql
+//
+
+var createTablePlans = `
+CREATE TABLE plans (
+ plan_id         SERIAL PRIMARY KEY
+,plan_cycle      VARCHAR(50)
+,plan_pool       VARCHAR(250)
+,plan_pending    INTEGER
+,plan_running    INTEGER
+,plan_capacity   INTEGER
+,plan_servers    INTEGER
+,plan_min        INTEGER
+,plan_max        INTEGER
+,plan_diff       INTEGER
+,plan_action     VARCHAR(50)
+,plan_count      INTEGER
+,plan_reason     VARCHAR(500)
+,plan_error      VARCHAR(500)
+,plan_created    INTEGER
+);
+`
+
+var createIndexPlansCreated = `
+CREATE INDEX ix_plans_created ON plans (plan_created);
+`
+
+//
+// 020_create_table_pool_states.sql
+//
+
+var createTablePoolStates = `
+CREATE TABLE pool_states (
+ state_pool     VARCHAR(250) PRIMARY KEY
+,state_paused   BOOLEAN
+,state_target   INTEGER
+,state_updated  INTEGER
+);
+`
+
+//
+// 021_alter_table_servers_add_column_host_key.sql
+//
+
+var alterTableServersAddColumnHostKey = `
+ALTER TABLE servers ADD COLUMN server_host_key VARCHAR(1000) DEFAULT '';
+`
+
+//
+// 022_alter_table_audit_events_add_column_query.sql
+//
+
+var alterTableAuditEventsAddColumnQuery = `
+ALTER TABLE audit_events ADD COLUMN audit_query VARCHAR(2000) DEFAULT '';
+`
+
+//
+// 023_alter_table_audit_events_add_column_target.sql
+//
+
+var alterTableAuditEventsAddColumnTarget = `
+ALTER TABLE audit_events ADD COLUMN audit_target VARCHAR(250) DEFAULT '';
+`
@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-logs
+
+ALTER TABLE servers ADD COLUMN server_logs TEXT;
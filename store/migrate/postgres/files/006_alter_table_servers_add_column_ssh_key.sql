@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-ssh-key
+
+ALTER TABLE servers ADD COLUMN server_ssh_key TEXT;
@@ -0,0 +1,3 @@
+-- name: alter-table-servers-modify-column-secret
+
+ALTER TABLE servers ALTER COLUMN server_secret TYPE VARCHAR(250);
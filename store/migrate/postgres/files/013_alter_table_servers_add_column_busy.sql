@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-busy
+
+ALTER TABLE servers ADD COLUMN server_busy INTEGER DEFAULT 0;
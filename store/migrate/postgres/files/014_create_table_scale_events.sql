@@ -0,0 +1,17 @@
+-- name: create-table-scale-events
+
+CREATE TABLE scale_events (
+ scale_id        SERIAL PRIMARY KEY
+,scale_pool      VARCHAR(50)
+,scale_action    VARCHAR(50)
+,scale_count     INTEGER
+,scale_pending   INTEGER
+,scale_running   INTEGER
+,scale_capacity  INTEGER
+,scale_servers   INTEGER
+,scale_created   INTEGER
+);
+
+-- name: create-index-scale-events-created
+
+CREATE INDEX ix_scale_events_created ON scale_events (scale_created);
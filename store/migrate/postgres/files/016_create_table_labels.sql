@@ -0,0 +1,12 @@
+-- name: create-table-labels
+
+CREATE TABLE labels (
+ label_server  VARCHAR(50)
+,label_key     VARCHAR(50)
+,label_value   VARCHAR(100)
+,PRIMARY KEY(label_server, label_key)
+);
+
+-- name: create-index-labels-key-value
+
+CREATE INDEX ix_labels_key_value ON labels (label_key, label_value);
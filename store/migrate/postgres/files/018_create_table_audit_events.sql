@@ -0,0 +1,16 @@
+-- name: create-table-audit-events
+
+CREATE TABLE audit_events (
+ audit_id        SERIAL PRIMARY KEY
+,audit_user      VARCHAR(250)
+,audit_scope     VARCHAR(50)
+,audit_method    VARCHAR(10)
+,audit_path      VARCHAR(500)
+,audit_status    INTEGER
+,audit_address   VARCHAR(100)
+,audit_created   INTEGER
+);
+
+-- name: create-index-audit-events-created
+
+CREATE INDEX ix_audit_events_created ON audit_events (audit_created);
@@ -0,0 +1,23 @@
+-- name: create-table-plans
+
+CREATE TABLE plans (
+ plan_id         SERIAL PRIMARY KEY
+,plan_cycle      VARCHAR(50)
+,plan_pool       VARCHAR(250)
+,plan_pending    INTEGER
+,plan_running    INTEGER
+,plan_capacity   INTEGER
+,plan_servers    INTEGER
+,plan_min        INTEGER
+,plan_max        INTEGER
+,plan_diff       INTEGER
+,plan_action     VARCHAR(50)
+,plan_count      INTEGER
+,plan_reason     VARCHAR(500)
+,plan_error      VARCHAR(500)
+,plan_created    INTEGER
+);
+
+-- name: create-index-plans-created
+
+CREATE INDEX ix_plans_created ON plans (plan_created);
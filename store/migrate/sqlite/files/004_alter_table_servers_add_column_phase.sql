@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-phase
+
+ALTER TABLE servers ADD COLUMN server_phase TEXT DEFAULT '';
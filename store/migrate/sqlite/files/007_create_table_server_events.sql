@@ -0,0 +1,14 @@
+-- name: create-table-server-events
+
+CREATE TABLE IF NOT EXISTS server_events (
+ event_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,event_server   TEXT
+,event_from     TEXT
+,event_to       TEXT
+,event_reason   TEXT
+,event_created  INTEGER
+);
+
+-- name: create-index-server-events-server
+
+CREATE INDEX IF NOT EXISTS ix_server_events_server ON server_events (event_server);
@@ -0,0 +1,3 @@
+-- name: alter-table-servers-add-column-deleted
+
+ALTER TABLE servers ADD COLUMN server_deleted INTEGER DEFAULT 0;
@@ -0,0 +1,7 @@
+-- name: create-table-leases
+
+CREATE TABLE leases (
+ lease_name     TEXT PRIMARY KEY
+,lease_holder   TEXT
+,lease_expires  INTEGER
+);
@@ -0,0 +1,17 @@
+-- name: create-table-scale-events
+
+CREATE TABLE IF NOT EXISTS scale_events (
+ scale_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,scale_pool      TEXT
+,scale_action    TEXT
+,scale_count     INTEGER
+,scale_pending   INTEGER
+,scale_running   INTEGER
+,scale_capacity  INTEGER
+,scale_servers   INTEGER
+,scale_created   INTEGER
+);
+
+-- name: create-index-scale-events-created
+
+CREATE INDEX IF NOT EXISTS ix_scale_events_created ON scale_events (scale_created);
@@ -0,0 +1,12 @@
+-- name: create-table-labels
+
+CREATE TABLE IF NOT EXISTS labels (
+ label_server  TEXT
+,label_key     TEXT
+,label_value   TEXT
+,PRIMARY KEY(label_server, label_key)
+);
+
+-- name: create-index-labels-key-value
+
+CREATE INDEX IF NOT EXISTS ix_labels_key_value ON labels (label_key, label_value);
@@ -0,0 +1,16 @@
+-- name: create-table-audit-events
+
+CREATE TABLE IF NOT EXISTS audit_events (
+ audit_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,audit_user      TEXT
+,audit_scope     TEXT
+,audit_method    TEXT
+,audit_path      TEXT
+,audit_status    INTEGER
+,audit_address   TEXT
+,audit_created   INTEGER
+);
+
+-- name: create-index-audit-events-created
+
+CREATE INDEX IF NOT EXISTS ix_audit_events_created ON audit_events (audit_created);
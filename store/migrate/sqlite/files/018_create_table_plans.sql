@@ -0,0 +1,23 @@
+-- name: create-table-plans
+
+CREATE TABLE IF NOT EXISTS plans (
+ plan_id         INTEGER PRIMARY KEY AUTOINCREMENT
+,plan_cycle      TEXT
+,plan_pool       TEXT
+,plan_pending    INTEGER
+,plan_running    INTEGER
+,plan_capacity   INTEGER
+,plan_servers    INTEGER
+,plan_min        INTEGER
+,plan_max        INTEGER
+,plan_diff       INTEGER
+,plan_action     TEXT
+,plan_count      INTEGER
+,plan_reason     TEXT
+,plan_error      TEXT
+,plan_created    INTEGER
+);
+
+-- name: create-index-plans-created
+
+CREATE INDEX IF NOT EXISTS ix_plans_created ON plans (plan_created);
@@ -0,0 +1,8 @@
+-- name: create-table-pool-states
+
+CREATE TABLE pool_states (
+ state_pool     TEXT PRIMARY KEY
+,state_paused   BOOLEAN
+,state_target   INTEGER
+,state_updated  INTEGER
+);
@@ -0,0 +1,3 @@
+-- name: alter-table-audit-events-add-column-query
+
+ALTER TABLE audit_events ADD COLUMN audit_query TEXT DEFAULT '';
@@ -0,0 +1,667 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Names returns the names of the migrations, in the order the
+// migrations are performed.
+func Names() []string {
+	var names []string
+	for _, migration := range migrations {
+		names = append(names, migration.name)
+	}
+	return names
+}
+
+// Completed returns the names of the completed migrations.
+func Completed(db *sql.DB) (map[string]struct{}, error) {
+	if err := createTable(db); err != nil {
+		return nil, err
+	}
+	return selectCompleted(db)
+}
+
+// Rollback reverts the last completed migration, and returns
+// the name of the reverted migration. The name is empty if no
+// migrations are completed.
+func Rollback(db *sql.DB) (string, error) {
+	completed, err := Completed(db)
+	if err != nil {
+		return "", err
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		name := migrations[i].name
+		if _, ok := completed[name]; !ok {
+			continue
+		}
+		stmt, ok := rollbacks[name]
+		if !ok {
+			return name, fmt.Errorf("Cannot revert migration %s", name)
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			return name, err
+		}
+		_, err := db.Exec(migrationDelete, name)
+		return name, err
+	}
+	return "", nil
+}
+
+var migrationDelete = `
+DELETE FROM migrations WHERE name = ?
+`
+
+// rollbacks defines the statements that revert each migration.
+var rollbacks = map[string]string{
+	"create-table-servers": `
+DROP TABLE servers;
+`,
+	"create-index-server-id": `
+DROP INDEX ix_servers_id;
+`,
+	"create-index-server-state": `
+DROP INDEX ix_servers_state;
+`,
+	"alter-table-servers-add-column-price": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"alter-table-servers-add-column-pool": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"alter-table-servers-add-column-phase": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"alter-table-servers-add-column-logs": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"alter-table-servers-add-column-ssh-key": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+,server_logs      TEXT
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase, server_logs
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"create-table-server-events": `
+DROP TABLE server_events;
+`,
+	"create-index-server-events-server": `
+DROP INDEX ix_server_events_server;
+`,
+	"alter-table-servers-add-column-deleted": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+,server_logs      TEXT
+,server_ssh_key   TEXT
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase, server_logs, server_ssh_key
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"create-table-leases": `
+DROP TABLE leases;
+`,
+	"alter-table-servers-add-column-annotations": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+,server_logs      TEXT
+,server_ssh_key   TEXT
+,server_deleted   INTEGER DEFAULT 0
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase, server_logs, server_ssh_key, server_deleted
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"alter-table-servers-add-column-stages": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+,server_logs      TEXT
+,server_ssh_key   TEXT
+,server_deleted   INTEGER DEFAULT 0
+,server_annotations TEXT
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase, server_logs, server_ssh_key, server_deleted,
+ server_annotations
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"alter-table-servers-add-column-busy": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+,server_logs      TEXT
+,server_ssh_key   TEXT
+,server_deleted   INTEGER DEFAULT 0
+,server_annotations TEXT
+,server_stages    INTEGER DEFAULT 0
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase, server_logs, server_ssh_key, server_deleted,
+ server_annotations, server_stages
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"create-table-scale-events": `
+DROP TABLE scale_events;
+`,
+	"create-index-scale-events-created": `
+DROP INDEX ix_scale_events_created;
+`,
+	"alter-table-servers-add-column-labels": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+,server_logs      TEXT
+,server_ssh_key   TEXT
+,server_deleted   INTEGER DEFAULT 0
+,server_annotations TEXT
+,server_stages    INTEGER DEFAULT 0
+,server_busy      INTEGER DEFAULT 0
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase, server_logs, server_ssh_key, server_deleted,
+ server_annotations, server_stages, server_busy
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+`,
+	"create-table-labels": `
+DROP TABLE labels;
+`,
+	"create-index-labels-key-value": `
+DROP INDEX ix_labels_key_value;
+`,
+	"create-index-servers-pool": `
+DROP INDEX ix_servers_pool;
+`,
+	"create-table-audit-events": `
+DROP TABLE audit_events;
+`,
+	"create-index-audit-events-created": `
+DROP INDEX ix_audit_events_created;
+`,
+	"create-table-plans": `
+DROP TABLE plans;
+`,
+	"create-index-plans-created": `
+DROP INDEX ix_plans_created;
+`,
+	"create-table-pool-states": `
+DROP TABLE pool_states;
+`,
+	"alter-table-servers-add-column-host-key": `
+CREATE TABLE servers_rollback (
+ server_name      TEXT PRIMARY KEY
+,server_id        TEXT
+,server_provider  TEXT
+,server_state     TEXT
+,server_image     TEXT
+,server_region    TEXT
+,server_size      TEXT
+,server_platform  TEXT
+,server_address   TEXT
+,server_capacity  INTEGER
+,server_secret    TEXT
+,server_error     TEXT
+,server_ca_key    TEXT
+,server_ca_cert   TEXT
+,server_tls_key   TEXT
+,server_tls_cert  TEXT
+,server_created   INTEGER
+,server_updated   INTEGER
+,server_started   INTEGER
+,server_stopped   INTEGER
+,server_price     REAL DEFAULT 0
+,server_pool      TEXT DEFAULT ''
+,server_phase     TEXT DEFAULT ''
+,server_logs      TEXT
+,server_ssh_key   TEXT
+,server_deleted   INTEGER DEFAULT 0
+,server_annotations TEXT
+,server_stages    INTEGER DEFAULT 0
+,server_busy      INTEGER DEFAULT 0
+,server_labels    TEXT
+);
+
+INSERT INTO servers_rollback SELECT
+ server_name, server_id, server_provider, server_state,
+ server_image, server_region, server_size, server_platform,
+ server_address, server_capacity, server_secret,
+ server_error, server_ca_key, server_ca_cert, server_tls_key,
+ server_tls_cert, server_created, server_updated,
+ server_started, server_stopped, server_price, server_pool,
+ server_phase, server_logs, server_ssh_key, server_deleted,
+ server_annotations, server_stages, server_busy,
+ server_labels
+FROM servers;
+
+DROP TABLE servers;
+
+ALTER TABLE servers_rollback RENAME TO servers;
+
+CREATE INDEX IF NOT EXISTS ix_servers_id ON servers (server_id);
+CREATE INDEX IF NOT EXISTS ix_servers_state ON servers (server_state);
+CREATE INDEX IF NOT EXISTS ix_servers_pool ON servers (server_pool);
+`,
+	"alter-table-audit-events-add-column-query": `
+CREATE TABLE audit_events_rollback (
+ audit_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,audit_user      TEXT
+,audit_scope     TEXT
+,audit_method    TEXT
+,audit_path      TEXT
+,audit_status    INTEGER
+,audit_address   TEXT
+,audit_created   INTEGER
+);
+
+INSERT INTO audit_events_rollback SELECT
+ audit_id, audit_user, audit_scope, audit_method, audit_path,
+ audit_status, audit_address, audit_created
+FROM audit_events;
+
+DROP TABLE audit_events;
+
+ALTER TABLE audit_events_rollback RENAME TO audit_events;
+
+CREATE INDEX IF NOT EXISTS ix_audit_events_created ON audit_events (audit_created);
+`,
+	"alter-table-audit-events-add-column-target": `
+CREATE TABLE audit_events_rollback (
+ audit_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,audit_user      TEXT
+,audit_scope     TEXT
+,audit_method    TEXT
+,audit_path      TEXT
+,audit_status    INTEGER
+,audit_address   TEXT
+,audit_created   INTEGER
+,audit_query     TEXT DEFAULT ''
+);
+
+INSERT INTO audit_events_rollback SELECT
+ audit_id, audit_user, audit_scope, audit_method, audit_path,
+ audit_status, audit_address, audit_created,
+ audit_query
+FROM audit_events;
+
+DROP TABLE audit_events;
+
+ALTER TABLE audit_events_rollback RENAME TO audit_events;
+
+CREATE INDEX IF NOT EXISTS ix_audit_events_created ON audit_events (audit_created);
+`,
+}
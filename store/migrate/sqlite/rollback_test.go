@@ -0,0 +1,73 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRollbacks(t *testing.T) {
+	for _, migration := range migrations {
+		if _, ok := rollbacks[migration.name]; !ok {
+			t.Errorf("Want rollback for migration %s", migration.name)
+		}
+	}
+}
+
+func TestRollback(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`INSERT INTO servers (server_name, server_state, server_pool) VALUES ('agent-1', 'running', 'default')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// revert each migration in reverse order, and verify the
+	// existing server survives the servers table rebuilds.
+	for i := len(migrations) - 1; i >= 0; i-- {
+		name, err := Rollback(db)
+		if err != nil {
+			t.Fatalf("Cannot revert migration %s: %s", name, err)
+		}
+		if got, want := name, migrations[i].name; got != want {
+			t.Fatalf("Want reverted migration %s, got %s", want, got)
+		}
+		if i < 3 {
+			continue
+		}
+		var state string
+		err = db.QueryRow(`SELECT server_state FROM servers WHERE server_name = 'agent-1'`).Scan(&state)
+		if err != nil {
+			t.Fatalf("Cannot select server after reverting %s: %s", name, err)
+		}
+		if state != "running" {
+			t.Errorf("Want server state running after reverting %s, got %s", name, state)
+		}
+	}
+
+	name, err := Rollback(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "" {
+		t.Errorf("Want no migrations to revert, got %s", name)
+	}
+
+	// the migrations must apply cleanly after a full rollback.
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+}
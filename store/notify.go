@@ -0,0 +1,131 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// notifyChannel defines the postgres notification channel.
+const notifyChannel = "drone_autoscaler"
+
+// listener settings.
+const (
+	listenMinReconnect = time.Second
+	listenMaxReconnect = time.Minute
+	listenPing         = time.Minute
+)
+
+// Notify sends a postgres notification to the replicas
+// listening for notifications.
+func Notify(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, "SELECT pg_notify($1, '')", notifyChannel)
+	return err
+}
+
+// Listen listens for postgres notifications, and calls the
+// notify function for each notification, until the context
+// is canceled. The notify function is also called when the
+// listener reconnects, since notifications sent while the
+// listener is disconnected are lost. The listener connects
+// with the same tls and password options as the database.
+func Listen(ctx context.Context, datasource string, notify func(), opts ...Option) error {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	datasource, err := withTLS("postgres", datasource, o.tls)
+	if err != nil {
+		return err
+	}
+	for {
+		err := listen(ctx, datasource, o.password, notify)
+		if err != errListenReconnect {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(listenMinReconnect):
+		}
+	}
+}
+
+// errListenReconnect is returned when the listener must be
+// recreated with a new password.
+var errListenReconnect = errors.New("listener must reconnect with a new password")
+
+// helper function listens for postgres notifications. The
+// listener reconnects with the password of the datasource,
+// which expires if the password is an authentication token,
+// in which case the listener is recreated with a new password
+// when a connection attempt fails.
+func listen(ctx context.Context, datasource string, password PasswordFunc, notify func()) error {
+	if password != nil {
+		var err error
+		datasource, err = withPassword(ctx, "postgres", datasource, password)
+		if err != nil {
+			return err
+		}
+	}
+	failed := make(chan struct{}, 1)
+	callback := func(event pq.ListenerEventType, err error) {
+		if event == pq.ListenerEventConnectionAttemptFailed && password != nil {
+			select {
+			case failed <- struct{}{}:
+			default:
+			}
+		}
+	}
+	listener := pq.NewListener(datasource, listenMinReconnect, listenMaxReconnect, callback)
+	defer listener.Close()
+	if err := listener.Listen(notifyChannel); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-failed:
+			return errListenReconnect
+		case <-listener.Notify:
+			notify()
+		case <-time.After(listenPing):
+			// the connection is checked periodically, since
+			// a lost connection is otherwise undetected.
+			go listener.Ping()
+		}
+	}
+}
+
+// NotifyEvents returns a server event store that sends a
+// postgres notification when a server event is created, so
+// that the replicas are notified of server state changes.
+func NotifyEvents(events autoscaler.ServerEventStore, db *sqlx.DB) autoscaler.ServerEventStore {
+	return &notifyStore{ServerEventStore: events, db: db}
+}
+
+type notifyStore struct {
+	autoscaler.ServerEventStore
+	db *sqlx.DB
+}
+
+func (s *notifyStore) Create(ctx context.Context, event *autoscaler.ServerEvent) error {
+	err := s.ServerEventStore.Create(ctx, event)
+	if err != nil {
+		return err
+	}
+	// the notification is best effort, since the replicas
+	// also poll at a fixed interval.
+	Notify(ctx, s.db)
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestListen_TLS(t *testing.T) {
+	err := Listen(context.Background(), "postgres://drone@db.company.com/autoscaler", func() {},
+		WithTLS(TLS{Mode: "invalid"}),
+	)
+	if err == nil {
+		t.Errorf("Want the listener to use the tls options")
+	}
+}
+
+func TestListen_Password(t *testing.T) {
+	want := errors.New("cannot fetch token")
+	err := Listen(context.Background(), "postgres://drone@db.company.com/autoscaler", func() {},
+		WithPassword(func(ctx context.Context, addr, user string) (string, error) {
+			if got, want := addr, "db.company.com:5432"; got != want {
+				t.Errorf("Want password requested for %s, got %s", want, got)
+			}
+			return "", want
+		}),
+	)
+	if err != want {
+		t.Errorf("Want the listener to use the password option, got %v", err)
+	}
+}
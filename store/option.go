@@ -0,0 +1,31 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+// Option configures the database connection.
+type Option func(*options)
+
+type options struct {
+	tls      TLS
+	password PasswordFunc
+}
+
+// WithTLS returns an option to connect to the database with
+// the tls configuration.
+func WithTLS(tls TLS) Option {
+	return func(o *options) {
+		o.tls = tls
+	}
+}
+
+// WithPassword returns an option to open each new database
+// connection with the password returned by the function,
+// replacing the password of the datasource. It is used to
+// authenticate with short-lived tokens.
+func WithPassword(fn PasswordFunc) Option {
+	return func(o *options) {
+		o.password = fn
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewPlanStore returns a new plan store.
+func NewPlanStore(db *sqlx.DB) autoscaler.PlanStore {
+	return &planStore{db}
+}
+
+type planStore struct {
+	*sqlx.DB
+}
+
+func (db *planStore) List(ctx context.Context, since int64) ([]*autoscaler.Plan, error) {
+	dest := []*autoscaler.Plan{}
+	stmt, args, err := db.BindNamed(planListStmt, &autoscaler.Plan{Created: since})
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (db *planStore) Create(ctx context.Context, plan *autoscaler.Plan) error {
+	if plan.Created == 0 {
+		plan.Created = time.Now().Unix()
+	}
+	stmt, args, err := db.BindNamed(planInsertStmt, plan)
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+func (db *planStore) Prune(ctx context.Context, before int64) error {
+	stmt, args, err := db.BindNamed(planPruneStmt, &autoscaler.Plan{Created: before})
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+const planListStmt = `
+SELECT
+ plan_id
+,plan_cycle
+,plan_pool
+,plan_pending
+,plan_running
+,plan_capacity
+,plan_servers
+,plan_min
+,plan_max
+,plan_diff
+,plan_action
+,plan_count
+,plan_reason
+,plan_error
+,plan_created
+FROM plans
+WHERE plan_created >= :plan_created
+ORDER BY plan_created ASC, plan_id ASC
+`
+
+const planInsertStmt = `
+INSERT INTO plans (
+ plan_cycle
+,plan_pool
+,plan_pending
+,plan_running
+,plan_capacity
+,plan_servers
+,plan_min
+,plan_max
+,plan_diff
+,plan_action
+,plan_count
+,plan_reason
+,plan_error
+,plan_created
+) VALUES (
+ :plan_cycle
+,:plan_pool
+,:plan_pending
+,:plan_running
+,:plan_capacity
+,:plan_servers
+,:plan_min
+,:plan_max
+,:plan_diff
+,:plan_action
+,:plan_count
+,:plan_reason
+,:plan_error
+,:plan_created
+)
+`
+
+const planPruneStmt = `
+DELETE FROM plans
+WHERE plan_created < :plan_created
+`
@@ -0,0 +1,63 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/kr/pretty"
+)
+
+func TestPlans(t *testing.T) {
+	conn, err := connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	store := NewPlanStore(conn)
+	for _, plan := range []*autoscaler.Plan{
+		{Cycle: "a", Pending: 0, Running: 1, Capacity: 2, Servers: 1, Min: 1, Max: 4, Action: autoscaler.ScaleNone, Reason: "capacity matches the build volume", Created: 86400},
+		{Cycle: "b", Pending: 3, Running: 1, Capacity: 2, Servers: 1, Min: 1, Max: 4, Diff: 1, Action: autoscaler.ScaleAlloc, Count: 1, Reason: "pending builds exceed the free capacity", Created: 86401},
+	} {
+		if err := store.Create(context.TODO(), plan); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	plans, err := store.List(context.TODO(), 86401)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(plans), 1; got != want {
+		t.Errorf("Want %d plans, got %d", want, got)
+		return
+	}
+	// the identifier is generated by the database, and is
+	// not sequential in each dialect.
+	plans[0].ID = 0
+	want := []*autoscaler.Plan{
+		{Cycle: "b", Pending: 3, Running: 1, Capacity: 2, Servers: 1, Min: 1, Max: 4, Diff: 1, Action: autoscaler.ScaleAlloc, Count: 1, Reason: "pending builds exceed the free capacity", Created: 86401},
+	}
+	if !reflect.DeepEqual(plans, want) {
+		t.Errorf("Unexpected plans")
+		pretty.Ldiff(t, plans, want)
+	}
+
+	if err := store.Prune(context.TODO(), 86401); err != nil {
+		t.Error(err)
+		return
+	}
+	plans, _ = store.List(context.TODO(), 0)
+	if got, want := len(plans), 1; got != want {
+		t.Errorf("Want %d plans after prune, got %d", want, got)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// maxRetries defines the maximum number of times a statement
+// is retried after a serialization failure.
+const maxRetries = 5
+
+// retryBackoff defines the initial backoff between retries,
+// which doubles after each attempt.
+const retryBackoff = 25 * time.Millisecond
+
+// helper function executes the statement, and retries the
+// statement if the database aborts the transaction with a
+// serialization failure. CockroachDB returns serialization
+// failures under contention, and expects the client to retry.
+func execRetry(ctx context.Context, db *sqlx.DB, stmt string, args ...interface{}) (err error) {
+	backoff := retryBackoff
+	for i := 0; i <= maxRetries; i++ {
+		_, err = db.ExecContext(ctx, stmt, args...)
+		if !retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+			backoff = backoff * 2
+		}
+	}
+	return err
+}
+
+// helper function returns true if the error is a
+// serialization failure that can be retried.
+func retryable(err error) bool {
+	pqerr, ok := err.(*pq.Error)
+	return ok && pqerr.Code == "40001"
+}
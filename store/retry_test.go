@@ -0,0 +1,29 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: nil, want: false},
+		{err: errors.New("connection refused"), want: false},
+		{err: &pq.Error{Code: "23505"}, want: false},
+		{err: &pq.Error{Code: "40001"}, want: true},
+	}
+	for _, test := range tests {
+		if got := retryable(test.err); got != test.want {
+			t.Errorf("Want retryable %v for error %v", test.want, test.err)
+		}
+	}
+}
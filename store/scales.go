@@ -0,0 +1,120 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewScaleEventStore returns a new scale event store.
+func NewScaleEventStore(db *sqlx.DB) autoscaler.ScaleEventStore {
+	return &scaleStore{db}
+}
+
+type scaleStore struct {
+	*sqlx.DB
+}
+
+func (db *scaleStore) List(ctx context.Context, since int64) ([]*autoscaler.ScaleEvent, error) {
+	dest := []*autoscaler.ScaleEvent{}
+	stmt, args, err := db.BindNamed(scaleListStmt, &autoscaler.ScaleEvent{Created: since})
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (db *scaleStore) Create(ctx context.Context, event *autoscaler.ScaleEvent) error {
+	if event.Created == 0 {
+		event.Created = time.Now().Unix()
+	}
+	stmt, args, err := db.BindNamed(scaleInsertStmt, event)
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+func (db *scaleStore) Summary(ctx context.Context, since int64) ([]*autoscaler.ScaleSummary, error) {
+	dest := []*autoscaler.ScaleSummary{}
+	stmt, args, err := db.BindNamed(scaleSummaryStmt, &autoscaler.ScaleEvent{Created: since})
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (db *scaleStore) Prune(ctx context.Context, before int64) error {
+	stmt, args, err := db.BindNamed(scalePruneStmt, &autoscaler.ScaleEvent{Created: before})
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+const scaleListStmt = `
+SELECT
+ scale_id
+,scale_pool
+,scale_action
+,scale_count
+,scale_pending
+,scale_running
+,scale_capacity
+,scale_servers
+,scale_created
+FROM scale_events
+WHERE scale_created >= :scale_created
+ORDER BY scale_created ASC, scale_id ASC
+`
+
+const scaleInsertStmt = `
+INSERT INTO scale_events (
+ scale_pool
+,scale_action
+,scale_count
+,scale_pending
+,scale_running
+,scale_capacity
+,scale_servers
+,scale_created
+) VALUES (
+ :scale_pool
+,:scale_action
+,:scale_count
+,:scale_pending
+,:scale_running
+,:scale_capacity
+,:scale_servers
+,:scale_created
+)
+`
+
+// the day is calculated with the modulo operator, which is
+// supported by each database dialect.
+const scaleSummaryStmt = `
+SELECT
+ scale_created - (scale_created % 86400) AS scale_day
+,scale_pool
+,scale_action
+,COUNT(*) AS scale_events
+,SUM(scale_count) AS scale_count
+FROM scale_events
+WHERE scale_created >= :scale_created
+GROUP BY scale_created - (scale_created % 86400), scale_pool, scale_action
+ORDER BY scale_day ASC, scale_pool ASC, scale_action ASC
+`
+
+const scalePruneStmt = `
+DELETE FROM scale_events
+WHERE scale_created < :scale_created
+`
@@ -0,0 +1,71 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/drone/autoscaler"
+	"github.com/kr/pretty"
+)
+
+func TestScaleEvents(t *testing.T) {
+	conn, err := connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	store := NewScaleEventStore(conn)
+	for _, event := range []*autoscaler.ScaleEvent{
+		{Action: autoscaler.ScaleAlloc, Count: 2, Pending: 4, Created: 86400},
+		{Action: autoscaler.ScaleAlloc, Count: 1, Pending: 1, Created: 86401},
+		{Action: autoscaler.ScaleTerminate, Count: 3, Created: 86402},
+		{Action: autoscaler.ScaleAlloc, Count: 1, Pool: "arm64", Created: 86403},
+		{Action: autoscaler.ScaleAlloc, Count: 5, Created: 172800},
+	} {
+		if err := store.Create(context.TODO(), event); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	events, err := store.List(context.TODO(), 86401)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(events), 4; got != want {
+		t.Errorf("Want %d scale events, got %d", want, got)
+	}
+
+	summary, err := store.Summary(context.TODO(), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []*autoscaler.ScaleSummary{
+		{Day: 86400, Action: autoscaler.ScaleAlloc, Events: 2, Count: 3},
+		{Day: 86400, Action: autoscaler.ScaleTerminate, Events: 1, Count: 3},
+		{Day: 86400, Pool: "arm64", Action: autoscaler.ScaleAlloc, Events: 1, Count: 1},
+		{Day: 172800, Action: autoscaler.ScaleAlloc, Events: 1, Count: 5},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("Unexpected scale summary")
+		pretty.Ldiff(t, summary, want)
+	}
+
+	if err := store.Prune(context.TODO(), 172800); err != nil {
+		t.Error(err)
+		return
+	}
+	events, _ = store.List(context.TODO(), 0)
+	if got, want := len(events), 1; got != want {
+		t.Errorf("Want %d scale events after prune, got %d", want, got)
+	}
+}
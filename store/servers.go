@@ -7,6 +7,10 @@ package store
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/drone/autoscaler"
@@ -52,15 +56,76 @@ func (db *serverStore) ListState(ctx context.Context, state autoscaler.ServerSta
 	return dest, err
 }
 
+func (db *serverStore) ListDeleted(ctx context.Context) ([]*autoscaler.Server, error) {
+	dest := []*autoscaler.Server{}
+	err := db.SelectContext(ctx, &dest, serverListDeletedStmt)
+	return dest, err
+}
+
+func (db *serverStore) ListByPool(ctx context.Context, pool string) ([]*autoscaler.Server, error) {
+	dest := []*autoscaler.Server{}
+	stmt, args, err := db.BindNamed(serverListPoolStmt, map[string]interface{}{"server_pool": pool})
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (db *serverStore) ListByLabels(ctx context.Context, labels autoscaler.Labels) ([]*autoscaler.Server, error) {
+	return db.ListFilter(ctx, autoscaler.ServerFilter{Labels: labels})
+}
+
+func (db *serverStore) ListFilter(ctx context.Context, filter autoscaler.ServerFilter) ([]*autoscaler.Server, error) {
+	dest := []*autoscaler.Server{}
+	stmt, args, err := db.BindNamed(serverFilterQuery(filter))
+	if err != nil {
+		return nil, err
+	}
+	err = db.SelectContext(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
 func (db *serverStore) Create(ctx context.Context, server *autoscaler.Server) error {
-	server.Created = time.Now().Unix()
+	// the created timestamp is preserved when importing servers.
+	if server.Created == 0 {
+		server.Created = time.Now().Unix()
+	}
 	server.Updated = time.Now().Unix()
 	stmt, args, err := db.BindNamed(serverInsertStmt, server)
 	if err != nil {
 		return err
 	}
-	_, err = db.ExecContext(ctx, stmt, args...)
-	return err
+	if len(server.Labels) == 0 {
+		return execRetry(ctx, db.DB, stmt, args...)
+	}
+
+	// the labels are indexed in the labels table, and are
+	// inserted with the server in a single transaction.
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for key, value := range server.Labels {
+		stmt, args, err := db.BindNamed(labelInsertStmt, map[string]interface{}{
+			"label_server": server.Name,
+			"label_key":    key,
+			"label_value":  value,
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (db *serverStore) Update(ctx context.Context, server *autoscaler.Server) error {
@@ -70,26 +135,138 @@ func (db *serverStore) Update(ctx context.Context, server *autoscaler.Server) er
 	if err != nil {
 		return err
 	}
-	_, err = db.ExecContext(ctx, stmt, args...)
-	return err
+	if err := execRetry(ctx, db.DB, stmt, args...); err != nil {
+		return err
+	}
+	if server.Logs == nil {
+		return nil
+	}
+	stmt, args, err = db.BindNamed(serverLogsStmt, server)
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+func (db *serverStore) Annotate(ctx context.Context, server *autoscaler.Server) error {
+	stmt, args, err := db.BindNamed(serverAnnotateStmt, server)
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+func (db *serverStore) AddUsage(ctx context.Context, name string, stages int, busy int64) error {
+	stmt, args, err := db.BindNamed(serverUsageStmt, &autoscaler.Server{Name: name, Stages: stages, Busy: busy})
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
 }
 
 func (db *serverStore) Delete(ctx context.Context, server *autoscaler.Server) error {
-	stmt, args, err := db.BindNamed(serverDeleteStmt, server)
+	stmt, args, err := db.BindNamed(labelDeleteStmt, server)
+	if err != nil {
+		return err
+	}
+	if err := execRetry(ctx, db.DB, stmt, args...); err != nil {
+		return err
+	}
+	stmt, args, err = db.BindNamed(serverDeleteStmt, server)
 	if err != nil {
 		return err
 	}
-	_, err = db.ExecContext(ctx, stmt, args...)
-	return err
+	return execRetry(ctx, db.DB, stmt, args...)
 }
 
 func (db *serverStore) Purge(ctx context.Context, before int64) error {
-	stmt, args, err := db.BindNamed(serverPurgeStmt, &autoscaler.Server{Stopped: before})
+	stmt, args, err := db.BindNamed(serverPurgeStmt, &autoscaler.Server{Stopped: before, Deleted: time.Now().Unix()})
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+func (db *serverStore) Prune(ctx context.Context, before int64) error {
+	stmt, args, err := db.BindNamed(labelPruneStmt, &autoscaler.Server{Deleted: before})
 	if err != nil {
 		return err
 	}
-	_, err = db.ExecContext(ctx, stmt, args...)
-	return err
+	if err := execRetry(ctx, db.DB, stmt, args...); err != nil {
+		return err
+	}
+	stmt, args, err = db.BindNamed(serverPruneStmt, &autoscaler.Server{Deleted: before})
+	if err != nil {
+		return err
+	}
+	return execRetry(ctx, db.DB, stmt, args...)
+}
+
+// helper function returns the select statement and the named
+// parameters of the server filter.
+func serverFilterQuery(filter autoscaler.ServerFilter) (string, map[string]interface{}) {
+	var where []string
+	params := map[string]interface{}{}
+	if !filter.Deleted {
+		where = append(where, "server_deleted = 0")
+	}
+	if filter.State != "" {
+		where = append(where, "server_state = :server_state")
+		params["server_state"] = filter.State
+	}
+	if filter.Pool != "" {
+		where = append(where, "server_pool = :server_pool")
+		params["server_pool"] = filter.Pool
+	}
+	if len(filter.Labels) != 0 {
+		where = append(where, serverLabelsClause(filter.Labels, params))
+	}
+
+	stmt := serverFilterStmt
+	if len(where) != 0 {
+		stmt += "WHERE " + strings.Join(where, "\n  AND ") + "\n"
+	}
+	if filter.Descending {
+		stmt += "ORDER BY server_created DESC, server_name DESC\n"
+	} else {
+		stmt += "ORDER BY server_created ASC, server_name ASC\n"
+	}
+
+	// an offset requires a limit in mysql and sqlite.
+	limit := filter.Limit
+	if limit == 0 && filter.Offset != 0 {
+		limit = math.MaxInt32
+	}
+	if limit != 0 {
+		stmt += fmt.Sprintf("LIMIT %d OFFSET %d\n", limit, filter.Offset)
+	}
+	return stmt, params
+}
+
+// helper function returns the where clause that matches the
+// servers with all of the labels, and adds the named parameters
+// of the labels. The labels table has a single row for each
+// server and label key, so a server that matches all of the
+// labels has one matching row per label.
+func serverLabelsClause(labels autoscaler.Labels, params map[string]interface{}) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var match []string
+	for i, key := range keys {
+		match = append(match, fmt.Sprintf("(label_key = :label_key_%d AND label_value = :label_value_%d)", i, i))
+		params[fmt.Sprintf("label_key_%d", i)] = key
+		params[fmt.Sprintf("label_value_%d", i)] = labels[key]
+	}
+	return "server_name IN (\n" +
+		"    SELECT label_server FROM labels\n" +
+		"    WHERE " + strings.Join(match, "\n       OR ") + "\n" +
+		"    GROUP BY label_server\n" +
+		fmt.Sprintf("    HAVING COUNT(*) = %d\n", len(keys)) +
+		"  )"
 }
 
 const serverFindStmt = `
@@ -104,16 +281,27 @@ SELECT
 ,server_platform
 ,server_address
 ,server_capacity
+,server_price
+,server_pool
+,server_phase
+,server_logs
 ,server_secret
 ,server_error
 ,server_ca_key
 ,server_ca_cert
 ,server_tls_key
 ,server_tls_cert
+,server_ssh_key
+,server_host_key
 ,server_created
 ,server_updated
 ,server_started
 ,server_stopped
+,server_deleted
+,server_annotations
+,server_stages
+,server_busy
+,server_labels
 FROM servers
 WHERE server_name=:server_name
 `
@@ -130,17 +318,28 @@ SELECT
 ,server_platform
 ,server_address
 ,server_capacity
+,server_price
+,server_pool
+,server_phase
 ,server_secret
 ,server_error
 ,server_ca_key
 ,server_ca_cert
 ,server_tls_key
 ,server_tls_cert
+,server_ssh_key
+,server_host_key
 ,server_created
 ,server_updated
 ,server_started
 ,server_stopped
+,server_deleted
+,server_annotations
+,server_stages
+,server_busy
+,server_labels
 FROM servers
+WHERE server_deleted = 0
 ORDER BY server_created ASC
 `
 
@@ -156,21 +355,109 @@ SELECT
 ,server_platform
 ,server_address
 ,server_capacity
+,server_price
+,server_pool
+,server_phase
 ,server_secret
 ,server_error
 ,server_ca_key
 ,server_ca_cert
 ,server_tls_key
 ,server_tls_cert
+,server_ssh_key
+,server_host_key
 ,server_created
 ,server_updated
 ,server_started
 ,server_stopped
+,server_deleted
+,server_annotations
+,server_stages
+,server_busy
+,server_labels
 FROM servers
 WHERE server_state=:server_state
+  AND server_deleted = 0
 ORDER BY server_created ASC
 `
 
+const serverListDeletedStmt = `
+SELECT
+ server_name
+,server_id
+,server_provider
+,server_state
+,server_image
+,server_region
+,server_size
+,server_platform
+,server_address
+,server_capacity
+,server_price
+,server_pool
+,server_phase
+,server_secret
+,server_error
+,server_ca_key
+,server_ca_cert
+,server_tls_key
+,server_tls_cert
+,server_ssh_key
+,server_host_key
+,server_created
+,server_updated
+,server_started
+,server_stopped
+,server_deleted
+,server_annotations
+,server_stages
+,server_busy
+,server_labels
+FROM servers
+WHERE server_deleted > 0
+ORDER BY server_created ASC
+`
+
+const serverFilterStmt = `
+SELECT
+ server_name
+,server_id
+,server_provider
+,server_state
+,server_image
+,server_region
+,server_size
+,server_platform
+,server_address
+,server_capacity
+,server_price
+,server_pool
+,server_phase
+,server_secret
+,server_error
+,server_ca_key
+,server_ca_cert
+,server_tls_key
+,server_tls_cert
+,server_ssh_key
+,server_host_key
+,server_created
+,server_updated
+,server_started
+,server_stopped
+,server_deleted
+,server_annotations
+,server_stages
+,server_busy
+,server_labels
+FROM servers
+`
+
+const serverListPoolStmt = serverFilterStmt + `WHERE server_pool=:server_pool
+  AND server_deleted = 0
+ORDER BY server_created ASC, server_name ASC
+`
+
 const serverInsertStmt = `
 INSERT INTO servers (
  server_name
@@ -183,16 +470,27 @@ INSERT INTO servers (
 ,server_platform
 ,server_address
 ,server_capacity
+,server_price
+,server_pool
+,server_phase
+,server_logs
 ,server_secret
 ,server_error
 ,server_ca_key
 ,server_ca_cert
 ,server_tls_key
 ,server_tls_cert
+,server_ssh_key
+,server_host_key
 ,server_created
 ,server_updated
 ,server_started
 ,server_stopped
+,server_deleted
+,server_annotations
+,server_stages
+,server_busy
+,server_labels
 ) VALUES (
  :server_name
 ,:server_id
@@ -204,16 +502,27 @@ INSERT INTO servers (
 ,:server_platform
 ,:server_address
 ,:server_capacity
+,:server_price
+,:server_pool
+,:server_phase
+,:server_logs
 ,:server_secret
 ,:server_error
 ,:server_ca_key
 ,:server_ca_cert
 ,:server_tls_key
 ,:server_tls_cert
+,:server_ssh_key
+,:server_host_key
 ,:server_created
 ,:server_updated
 ,:server_started
 ,:server_stopped
+,:server_deleted
+,:server_annotations
+,:server_stages
+,:server_busy
+,:server_labels
 )
 `
 
@@ -228,15 +537,49 @@ UPDATE servers SET
 ,server_platform=:server_platform
 ,server_address=:server_address
 ,server_capacity=:server_capacity
+,server_price=:server_price
+,server_pool=:server_pool
+,server_phase=:server_phase
 ,server_secret=:server_secret
 ,server_error=:server_error
 ,server_ca_key=:server_ca_key
 ,server_ca_cert=:server_ca_cert
 ,server_tls_key=:server_tls_key
 ,server_tls_cert=:server_tls_cert
+,server_ssh_key=:server_ssh_key
+,server_host_key=:server_host_key
 ,server_updated=:server_updated
 ,server_started=:server_started
 ,server_stopped=:server_stopped
+,server_deleted=:server_deleted
+WHERE server_name=:server_name
+`
+
+// the logs are excluded from the list statements, and are
+// updated separately, so that the logs are not cleared by an
+// update of a server returned by a list statement.
+const serverLogsStmt = `
+UPDATE servers SET
+ server_logs=:server_logs
+WHERE server_name=:server_name
+`
+
+// the annotations are excluded from the update statement, so
+// that annotations set with the api are not overwritten by a
+// concurrent update of the server.
+const serverAnnotateStmt = `
+UPDATE servers SET
+ server_annotations=:server_annotations
+WHERE server_name=:server_name
+`
+
+// the usage is incremented in the database, and is excluded
+// from the update statement, so that the usage is not reset
+// by a concurrent update of the server.
+const serverUsageStmt = `
+UPDATE servers SET
+ server_stages=server_stages+:server_stages
+,server_busy=server_busy+:server_busy
 WHERE server_name=:server_name
 `
 
@@ -245,7 +588,40 @@ DELETE FROM servers WHERE server_name=:server_name
 `
 
 const serverPurgeStmt = `
-DELETE FROM servers
+UPDATE servers SET
+ server_deleted=:server_deleted
 WHERE server_state = 'stopped'
   AND server_stopped < :server_stopped
+  AND server_deleted = 0
+`
+
+const serverPruneStmt = `
+DELETE FROM servers
+WHERE server_deleted > 0
+  AND server_deleted < :server_deleted
+`
+
+const labelInsertStmt = `
+INSERT INTO labels (
+ label_server
+,label_key
+,label_value
+) VALUES (
+ :label_server
+,:label_key
+,:label_value
+)
+`
+
+const labelDeleteStmt = `
+DELETE FROM labels WHERE label_server=:server_name
+`
+
+const labelPruneStmt = `
+DELETE FROM labels
+WHERE label_server IN (
+  SELECT server_name FROM servers
+  WHERE server_deleted > 0
+    AND server_deleted < :server_deleted
+)
 `
@@ -0,0 +1,112 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/drone/autoscaler"
+)
+
+// SnapshotVersion defines the version of the snapshot format.
+const SnapshotVersion = 1
+
+// ErrSnapshotVersion is returned when the snapshot format
+// version is not supported.
+var ErrSnapshotVersion = errors.New("Unsupported snapshot version")
+
+// Snapshot is the exported state of the autoscaler, which is
+// used to move the servers between databases, or to restore
+// the servers from a backup. The snapshot includes the server
+// secrets and private keys in plaintext.
+type Snapshot struct {
+	Version  int                       `json:"version"`
+	Exported int64                     `json:"exported"`
+	Servers  []*SnapshotServer         `json:"servers"`
+	Events   []*autoscaler.ServerEvent `json:"events"`
+}
+
+// SnapshotServer is the exported server, which includes the
+// fields excluded from the server json encoding.
+type SnapshotServer struct {
+	*autoscaler.Server
+	SSHKey []byte `json:"ssh_key,omitempty"`
+	Logs   []byte `json:"logs,omitempty"`
+}
+
+// Export returns a snapshot of the servers, including the
+// soft-deleted servers, and the server events.
+func Export(ctx context.Context, servers autoscaler.ServerStore, events autoscaler.ServerEventStore) (*Snapshot, error) {
+	list, err := servers.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := servers.ListDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{
+		Version:  SnapshotVersion,
+		Exported: time.Now().Unix(),
+		Servers:  []*SnapshotServer{},
+		Events:   []*autoscaler.ServerEvent{},
+	}
+	for _, server := range append(list, deleted...) {
+		// the server list excludes the logs, which are
+		// loaded separately for each server.
+		found, err := servers.Find(ctx, server.Name)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Servers = append(snapshot.Servers, &SnapshotServer{
+			Server: server,
+			SSHKey: server.SSHKey,
+			Logs:   found.Logs,
+		})
+		serverEvents, err := events.List(ctx, server.Name)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Events = append(snapshot.Events, serverEvents...)
+	}
+	return snapshot, nil
+}
+
+// Import creates the servers and server events of the
+// snapshot, and returns the number of servers imported.
+// Servers that already exist are skipped, along with their
+// events, so that an import can be safely repeated.
+func Import(ctx context.Context, servers autoscaler.ServerStore, events autoscaler.ServerEventStore, snapshot *Snapshot) (int, error) {
+	if snapshot.Version != SnapshotVersion {
+		return 0, ErrSnapshotVersion
+	}
+	imported := map[string]bool{}
+	for _, s := range snapshot.Servers {
+		if s.Server == nil {
+			continue
+		}
+		if _, err := servers.Find(ctx, s.Name); err == nil {
+			continue
+		}
+		server := *s.Server
+		server.SSHKey = s.SSHKey
+		server.Logs = s.Logs
+		if err := servers.Create(ctx, &server); err != nil {
+			return len(imported), err
+		}
+		imported[server.Name] = true
+	}
+	for _, event := range snapshot.Events {
+		if !imported[event.Server] {
+			continue
+		}
+		if err := events.Create(ctx, event); err != nil {
+			return len(imported), err
+		}
+	}
+	return len(imported), nil
+}
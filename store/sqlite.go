@@ -0,0 +1,69 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Pragmas defines the sqlite pragmas applied to the database
+// connection. Unset pragmas keep the sqlite defaults.
+type Pragmas struct {
+	JournalMode string
+	BusyTimeout time.Duration
+	Synchronous string
+}
+
+// journalModes lists the supported sqlite journal modes.
+var journalModes = map[string]bool{
+	"DELETE":   true,
+	"TRUNCATE": true,
+	"PERSIST":  true,
+	"MEMORY":   true,
+	"WAL":      true,
+	"OFF":      true,
+}
+
+// synchronousModes lists the supported sqlite synchronous
+// settings.
+var synchronousModes = map[string]bool{
+	"OFF":    true,
+	"NORMAL": true,
+	"FULL":   true,
+	"EXTRA":  true,
+}
+
+// Pragma applies the sqlite pragmas to the database. The
+// sqlite connection pool is limited to a single connection,
+// and the pragmas apply to every query run on the connection.
+// The journal mode is persisted to the database file.
+func Pragma(db *sqlx.DB, pragmas Pragmas) error {
+	var stmts []string
+	if mode := strings.ToUpper(pragmas.JournalMode); mode != "" {
+		if !journalModes[mode] {
+			return fmt.Errorf("Invalid sqlite journal mode %q", pragmas.JournalMode)
+		}
+		stmts = append(stmts, "PRAGMA journal_mode = "+mode)
+	}
+	if timeout := pragmas.BusyTimeout; timeout != 0 {
+		stmts = append(stmts, fmt.Sprintf("PRAGMA busy_timeout = %d", timeout/time.Millisecond))
+	}
+	if mode := strings.ToUpper(pragmas.Synchronous); mode != "" {
+		if !synchronousModes[mode] {
+			return fmt.Errorf("Invalid sqlite synchronous setting %q", pragmas.Synchronous)
+		}
+		stmts = append(stmts, "PRAGMA synchronous = "+mode)
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
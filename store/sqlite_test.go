@@ -0,0 +1,54 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPragma(t *testing.T) {
+	conn, err := Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	err = Pragma(conn, Pragmas{
+		JournalMode: "memory",
+		BusyTimeout: 5 * time.Second,
+		Synchronous: "normal",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var mode string
+	conn.Get(&mode, "PRAGMA journal_mode")
+	if got, want := mode, "memory"; got != want {
+		t.Errorf("Want journal mode %s, got %s", want, got)
+	}
+	var timeout int
+	conn.Get(&timeout, "PRAGMA busy_timeout")
+	if got, want := timeout, 5000; got != want {
+		t.Errorf("Want busy timeout %d, got %d", want, got)
+	}
+	var synchronous int
+	conn.Get(&synchronous, "PRAGMA synchronous")
+	if got, want := synchronous, 1; got != want {
+		t.Errorf("Want synchronous %d, got %d", want, got)
+	}
+}
+
+func TestPragma_Invalid(t *testing.T) {
+	if err := Pragma(nil, Pragmas{JournalMode: "wal; DROP TABLE servers"}); err == nil {
+		t.Errorf("Want error for invalid journal mode")
+	}
+	if err := Pragma(nil, Pragmas{Synchronous: "sometimes"}); err == nil {
+		t.Errorf("Want error for invalid synchronous setting")
+	}
+}
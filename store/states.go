@@ -0,0 +1,88 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/autoscaler"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewPoolStateStore returns a new pool state store.
+func NewPoolStateStore(db *sqlx.DB) autoscaler.PoolStateStore {
+	return &poolStateStore{db}
+}
+
+type poolStateStore struct {
+	*sqlx.DB
+}
+
+func (db *poolStateStore) Find(ctx context.Context, pool string) (*autoscaler.PoolState, error) {
+	dest := new(autoscaler.PoolState)
+	stmt, args, err := db.BindNamed(stateFindStmt, &autoscaler.PoolState{Pool: pool})
+	if err != nil {
+		return nil, err
+	}
+	err = db.GetContext(ctx, dest, stmt, args...)
+	return dest, err
+}
+
+// Update updates the pool state, and otherwise attempts to
+// create the pool state, which fails if the state exists, so
+// that the result does not depend on how each database reports
+// the affected rows.
+func (db *poolStateStore) Update(ctx context.Context, state *autoscaler.PoolState) error {
+	state.Updated = time.Now().Unix()
+	stmt, args, err := db.BindNamed(stateUpdateStmt, state)
+	if err != nil {
+		return err
+	}
+	if err := execRetry(ctx, db.DB, stmt, args...); err != nil {
+		return err
+	}
+	stmt, args, err = db.BindNamed(stateInsertStmt, state)
+	if err != nil {
+		return err
+	}
+	// the insert fails if the state exists, which is
+	// expected once the state is created.
+	db.ExecContext(ctx, stmt, args...)
+	return nil
+}
+
+const stateFindStmt = `
+SELECT
+ state_pool
+,state_paused
+,state_target
+,state_updated
+FROM pool_states
+WHERE state_pool=:state_pool
+`
+
+const stateUpdateStmt = `
+UPDATE pool_states SET
+ state_paused=:state_paused
+,state_target=:state_target
+,state_updated=:state_updated
+WHERE state_pool=:state_pool
+`
+
+const stateInsertStmt = `
+INSERT INTO pool_states (
+ state_pool
+,state_paused
+,state_target
+,state_updated
+) VALUES (
+ :state_pool
+,:state_paused
+,:state_target
+,:state_updated
+)
+`
@@ -0,0 +1,52 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/autoscaler"
+)
+
+func TestPoolStates(t *testing.T) {
+	conn, err := connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		conn.Exec("DELETE FROM pool_states")
+		conn.Close()
+	}()
+
+	store := NewPoolStateStore(conn)
+	if _, err := store.Find(context.TODO(), "default"); err == nil {
+		t.Errorf("Want error finding a pool state that does not exist")
+	}
+
+	err = store.Update(context.TODO(), &autoscaler.PoolState{Pool: "default", Paused: true, Target: -1})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = store.Update(context.TODO(), &autoscaler.PoolState{Pool: "default", Paused: false, Target: 5})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	state, err := store.Find(context.TODO(), "default")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if state.Paused {
+		t.Errorf("Want pool state updated")
+	}
+	if got, want := state.Target, 5; got != want {
+		t.Errorf("Want target %d, got %d", want, got)
+	}
+}
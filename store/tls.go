@@ -0,0 +1,170 @@
+// Copyright 2018 Drone.IO Inc
+// Use of this software is governed by the Business Source License
+// that can be found in the LICENSE file.
+
+package store
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// tls modes, named after the postgres sslmode values.
+const (
+	TLSDisable    = "disable"     // tls disabled
+	TLSRequire    = "require"     // tls required, certificate not verified
+	TLSVerifyCA   = "verify-ca"   // certificate signed by a trusted authority
+	TLSVerifyFull = "verify-full" // certificate verified, and matches the host
+)
+
+// errInvalidCA is returned when the certificate authority
+// bundle contains no valid certificates.
+var errInvalidCA = errors.New("Invalid database certificate authority")
+
+// tlsConfigs counts the registered mysql tls configurations,
+// which are registered by name.
+var tlsConfigs int32
+
+// TLS defines the tls configuration of the database
+// connection. The certificate authority bundle, client
+// certificate and client key are paths to pem encoded files.
+type TLS struct {
+	Mode string
+	CA   string
+	Cert string
+	Key  string
+}
+
+// helper function returns the datasource configured with the
+// tls settings. The datasource is returned unchanged if the
+// tls mode is not set, in which case the tls settings of the
+// datasource apply.
+func withTLS(driver, datasource string, t TLS) (string, error) {
+	if t.Mode == "" {
+		return datasource, nil
+	}
+	switch t.Mode {
+	case TLSDisable, TLSRequire, TLSVerifyCA, TLSVerifyFull:
+	default:
+		return "", fmt.Errorf("Invalid database tls mode %q", t.Mode)
+	}
+	switch driver {
+	case "postgres":
+		return pgTLS(datasource, t)
+	case "mysql":
+		return mysqlTLS(datasource, t)
+	default:
+		return "", fmt.Errorf("Database tls is not supported by %s", driver)
+	}
+}
+
+// helper function returns the postgres datasource configured
+// with the tls settings.
+func pgTLS(datasource string, t TLS) (string, error) {
+	datasource, err := pgNormalize(datasource)
+	if err != nil {
+		return "", err
+	}
+	datasource = pgAppend(datasource, "sslmode", t.Mode)
+	if t.CA != "" {
+		datasource = pgAppend(datasource, "sslrootcert", t.CA)
+	}
+	if t.Cert != "" {
+		datasource = pgAppend(datasource, "sslcert", t.Cert)
+		datasource = pgAppend(datasource, "sslkey", t.Key)
+	}
+	return datasource, nil
+}
+
+// helper function returns the mysql datasource configured with
+// the tls settings. The tls configuration is registered with
+// the mysql driver, and referenced by name.
+func mysqlTLS(datasource string, t TLS) (string, error) {
+	cfg, err := mysql.ParseDSN(datasource)
+	if err != nil {
+		return "", err
+	}
+	if t.Mode == TLSDisable {
+		cfg.TLSConfig = "false"
+		return cfg.FormatDSN(), nil
+	}
+
+	conf := new(tls.Config)
+	if t.CA != "" {
+		pem, err := ioutil.ReadFile(t.CA)
+		if err != nil {
+			return "", err
+		}
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(pem) {
+			return "", errInvalidCA
+		}
+	}
+	if t.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+		if err != nil {
+			return "", err
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	switch t.Mode {
+	case TLSRequire:
+		conf.InsecureSkipVerify = true
+	case TLSVerifyCA:
+		// the certificate chain is verified without the
+		// host name, which requires custom verification.
+		conf.InsecureSkipVerify = true
+		conf.VerifyPeerCertificate = verifyChain(conf.RootCAs)
+	case TLSVerifyFull:
+		host, _, err := net.SplitHostPort(cfg.Addr)
+		if err != nil {
+			host = cfg.Addr
+		}
+		conf.ServerName = host
+	}
+
+	name := fmt.Sprintf("autoscaler-%d", atomic.AddInt32(&tlsConfigs, 1))
+	if err := mysql.RegisterTLSConfig(name, conf); err != nil {
+		return "", err
+	}
+	cfg.TLSConfig = name
+	return cfg.FormatDSN(), nil
+}
+
+// helper function returns a function that verifies the peer
+// certificate chain with the certificate authorities, or the
+// system certificate authorities if nil, without verifying the
+// host name.
+func verifyChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(raw [][]byte, _ [][]*x509.Certificate) error {
+		if len(raw) == 0 {
+			return errors.New("Database server did not present a certificate")
+		}
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+		}
+		var leaf *x509.Certificate
+		for i, asn1 := range raw {
+			cert, err := x509.ParseCertificate(asn1)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				leaf = cert
+			} else {
+				opts.Intermediates.AddCert(cert)
+			}
+		}
+		_, err := leaf.Verify(opts)
+		return err
+	}
+}